@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"archive/zip"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/a11y"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+const decorativeImageIndicator = "-"
+
+var (
+	addA11yMetadata bool
+	editAltText     bool
+	a11yHazards     string
+	a11ySummary     string
+	a11yFlags       = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewFileFlag(true, false, &epubFile, "file", "f", "", "the epub file to audit", []string{"epub"}, true),
+			flags.NewBoolFlag(false, false, &addA11yMetadata, "metadata", "m", false, "whether or not to add the schema.org accessibility metadata to the opf, replacing any existing accessibility metadata"),
+			flags.NewBoolFlag(false, false, &editAltText, "interactive", "i", false, "whether or not to ask for the alt text of each image that is missing meaningful alt text"),
+			flags.NewStringFlag(false, false, &a11yHazards, "hazards", "", a11y.UnknownHazard, "a comma separated list of the accessibility hazards of the epub to use in the metadata (i.e. 'none' or 'noFlashingHazard,noSoundHazard')"),
+			flags.NewStringFlag(false, false, &a11ySummary, "summary", "", "", "the accessibility summary to use in the metadata instead of the generated one"),
+		},
+	}
+)
+
+// a11yCmd represents the a11y command
+var a11yCmd = &cobra.Command{
+	Use:   "a11y",
+	Short: "Audits an epub for accessibility issues and generates its accessibility metadata",
+	Example: heredoc.Doc(`To audit an epub for accessibility issues:
+	epub-lint a11y -f test.epub
+
+	To add the accessibility metadata to the opf:
+	epub-lint a11y -f test.epub -m --hazards none
+
+	To type the alt text of each image that is missing meaningful alt text and then add the accessibility metadata:
+	epub-lint a11y -f test.epub -i -m
+	`),
+	Long: heredoc.Doc(`Audits the content files of an epub for the following accessibility issues:
+	- Images that are missing alt text or that have alt text that is not meaningful like a file name or "image"
+	- html elements that are missing a lang or xml:lang attribute
+	- Headings that skip a level
+	- Tables without header cells
+	- Page break markers without a page list in the nav or NCX file
+	- Table of contents entries that are not in the same order as the spine
+
+	When metadata is specified, the schema.org accessibility metadata for EPUB Accessibility 1.1
+	(accessMode, accessModeSufficient, accessibilityFeature, accessibilityHazard, and accessibilitySummary)
+	is determined from the audit and added to the opf. Hazards can not be determined from the contents
+	of the epub, so they should be provided when known.
+
+	When interactive is specified, the alt text for each image that is missing meaningful alt text is asked for.
+	An empty response leaves the image as is, "-" marks the image as decorative, and "q" stops asking.
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		err := a11yFlags.Validate()
+		if err != nil {
+			return err
+		}
+
+		for _, hazard := range getA11yHazards() {
+			if !slices.Contains(a11y.Hazards, hazard) {
+				return fmt.Errorf("hazards must only contain the following values, but %q was provided: %s", hazard, strings.Join(a11y.Hazards, ", "))
+			}
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var report a11y.Report
+		err := epubhandler.ReadEpub(epubFile, func(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string) error {
+			htmlFiles, spineOrder, tocFile, err := getA11yFiles(zipFiles, epubInfo, opfFolder)
+			if err != nil {
+				return err
+			}
+
+			report = a11y.Audit(htmlFiles, spineOrder, tocFile)
+
+			return nil
+		})
+		if err != nil {
+			logger.WriteFatalf("failed to audit %q: %s", epubFile, err)
+		}
+
+		if len(report.Findings) == 0 {
+			logger.WriteInfo("No accessibility issues found.")
+		} else {
+			for _, finding := range report.Findings {
+				logger.WriteInfo(finding.String())
+			}
+
+			logger.WriteInfof("\nFound %d accessibility issue(s).\n", len(report.Findings))
+		}
+
+		var altTextByImage map[a11y.Image]string
+		if editAltText && len(report.Images) != 0 {
+			altTextByImage = getAltTextToSet(report.Images)
+		}
+
+		if !addA11yMetadata && len(altTextByImage) == 0 {
+			return
+		}
+
+		err = epubhandler.UpdateEpub(epubFile, func(zipFiles map[string]*zip.File, w *zip.Writer, epubInfo epubhandler.EpubInfo, opfFolder string) ([]string, error) {
+			htmlFiles, spineOrder, tocFile, err := getA11yFiles(zipFiles, epubInfo, opfFolder)
+			if err != nil {
+				return nil, err
+			}
+
+			var (
+				handledFiles []string
+				editsByFile  = make(map[string][]positions.TextEdit)
+			)
+			for image, alt := range altTextByImage {
+				editsByFile[image.File] = append(editsByFile[image.File], image.AltEdit(htmlFiles[image.File], alt))
+			}
+
+			for _, file := range slices.Sorted(maps.Keys(editsByFile)) {
+				htmlFiles[file], err = positions.ApplyEdits(file, htmlFiles[file], editsByFile[file])
+				if err != nil {
+					return nil, err
+				}
+
+				err = filehandler.WriteZipCompressedString(w, file, htmlFiles[file])
+				if err != nil {
+					return nil, err
+				}
+
+				handledFiles = append(handledFiles, file)
+			}
+
+			if !addA11yMetadata {
+				return handledFiles, nil
+			}
+
+			opfContents, err := filehandler.ReadInZipFileContents(zipFiles[epubInfo.OpfFile])
+			if err != nil {
+				return nil, err
+			}
+
+			var metadata = a11y.GetMetadata(a11y.Audit(htmlFiles, spineOrder, tocFile), getA11yHazards(), a11ySummary)
+			opfContents, err = a11y.SetOpfMetadata(opfContents, metadata, epubInfo.Version)
+			if err != nil {
+				return nil, err
+			}
+
+			err = filehandler.WriteZipCompressedString(w, epubInfo.OpfFile, opfContents)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(handledFiles, epubInfo.OpfFile), nil
+		})
+		if err != nil {
+			logger.WriteFatalf("failed to update %q: %s", epubFile, err)
+		}
+
+		if len(altTextByImage) != 0 {
+			logger.WriteInfof("Set the alt text of %d image(s).\n", len(altTextByImage))
+		}
+
+		if addA11yMetadata {
+			logger.WriteInfo("Added the accessibility metadata to the opf.")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(a11yCmd)
+
+	err := a11yFlags.AddToCmd(a11yCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
+
+// getA11yFiles gets the contents of the content files and the toc file along with the spine order and the path of the toc file
+// all relative to the root of the epub
+func getA11yFiles(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string) (map[string]string, []string, string, error) {
+	var tocFile = epubInfo.NavFile
+	if tocFile == "" {
+		tocFile = epubInfo.NcxFile
+	}
+
+	var filesToRead = maps.Clone(epubInfo.HtmlFiles)
+	if tocFile != "" {
+		filesToRead[tocFile] = struct{}{}
+		tocFile = epubhandler.GetFilePath(opfFolder, tocFile)
+	}
+
+	var htmlFiles = make(map[string]string, len(filesToRead))
+	for file := range filesToRead {
+		var filePath = epubhandler.GetFilePath(opfFolder, file)
+		zipFile, ok := zipFiles[filePath]
+		if !ok {
+			return nil, nil, "", fmt.Errorf("file from manifest not found: %q must exist", filePath)
+		}
+
+		contents, err := filehandler.ReadInZipFileContents(zipFile)
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		htmlFiles[filePath] = contents
+	}
+
+	var spineOrder = make([]string, 0, len(epubInfo.FilePathsInSpineOrder))
+	for _, file := range epubInfo.FilePathsInSpineOrder {
+		spineOrder = append(spineOrder, epubhandler.GetFilePath(opfFolder, file))
+	}
+
+	return htmlFiles, spineOrder, tocFile, nil
+}
+
+// getAltTextToSet asks for the alt text of each image returning the alt text to set by image
+func getAltTextToSet(images []a11y.Image) map[a11y.Image]string {
+	var altTextByImage = make(map[a11y.Image]string)
+	for _, image := range images {
+		resp := strings.TrimSpace(logger.GetInputString(fmt.Sprintf("Alt text for %q in %s:%d (current: %q, empty to skip, %q for decorative, q to quit): ", image.Src, image.File, image.Line, image.Alt, decorativeImageIndicator)))
+		switch resp {
+		case "":
+			continue
+		case "q", "Q":
+			return altTextByImage
+		case decorativeImageIndicator:
+			altTextByImage[image] = ""
+		default:
+			altTextByImage[image] = resp
+		}
+	}
+
+	return altTextByImage
+}
+
+func getA11yHazards() []string {
+	var hazards []string
+	for _, hazard := range strings.Split(a11yHazards, ",") {
+		if hazard = strings.TrimSpace(hazard); hazard != "" {
+			hazards = append(hazards, hazard)
+		}
+	}
+
+	return hazards
+}
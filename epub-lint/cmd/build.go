@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"path/filepath"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	epubbuilder "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-builder"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+const defaultMetadataFile = "metadata.yaml"
+
+var (
+	buildDir          string
+	buildMetadataFile string
+	buildOutputFile   string
+	buildFlags        = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewDirectoryFlag(true, false, &buildDir, "directory", "d", "", "the folder with the chapters, images, and metadata file to build the epub from"),
+			flags.NewFileFlag(false, false, &buildMetadataFile, "metadata", "m", "", "the yaml metadata file to use instead of the metadata.yaml in the source folder", []string{"yaml", "yml"}, true),
+			flags.NewFileFlag(true, false, &buildOutputFile, "output", "o", "", "the epub file to create or overwrite", []string{"epub"}, false),
+		},
+	}
+)
+
+// buildCmd represents the build command
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Creates an epub from a folder of Markdown or XHTML chapters and a yaml metadata file",
+	Example: heredoc.Doc(`To build an epub from the chapters and metadata.yaml in a folder:
+	epub-lint build -d side-story -o side-story.epub
+
+	To build an epub using a different metadata file:
+	epub-lint build -d side-story -m volume-1.yaml -o volume-1.epub
+
+	The metadata file is expected to be in the following format:
+	title: The Side Story
+	authors:
+	  - Author Name
+	language: en
+	identifier: urn:isbn:9780000000000
+	publisher: Publisher Name
+	description: A short description of the book.
+	date: 2024-01-01
+	cover: images/cover.jpg
+	css: styles/custom.css
+	chapters:
+	  - prologue.md
+	  - chapter-1.md
+	  - afterword.xhtml
+	`),
+	Long: heredoc.Doc(`Creates an EPUB 3 file with a mimetype, container.xml, OPF, nav, NCX, stylesheet, and optionally a cover page
+	from the Markdown and XHTML chapters in the specified folder.
+
+	Only the title is required in the metadata file. The language defaults to "en" and a random uuid is used
+	as the identifier when one is not provided. When chapters is not specified, all Markdown and XHTML files
+	in the folder and its subfolders are used in file name order. When css is not specified, a default stylesheet is used.
+
+	The title of each chapter comes from the title in the frontmatter of a Markdown chapter, the first h1 or h2 in
+	the chapter, or the name of the file in that order. Images, stylesheets, and fonts in the folder are added
+	to the epub at the same relative path so relative links in the chapters keep working.
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return buildFlags.Validate()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var metadataFile = buildMetadataFile
+		if metadataFile == "" {
+			metadataFile = filepath.Join(buildDir, defaultMetadataFile)
+		}
+
+		err := filehandler.FileArgExists(metadataFile, "metadata")
+		if err != nil {
+			logger.WriteFatal(err.Error())
+		}
+
+		metadataContents, err := filehandler.ReadInFileContents(metadataFile)
+		if err != nil {
+			logger.WriteFatal(err.Error())
+		}
+
+		metadata, err := epubbuilder.ParseMetadata(metadataContents)
+		if err != nil {
+			logger.WriteFatalf("failed to read metadata file %q: %s", metadataFile, err)
+		}
+
+		book, err := epubbuilder.LoadBook(buildDir, metadata, time.Now())
+		if err != nil {
+			logger.WriteFatal(err.Error())
+		}
+
+		var (
+			epubContents bytes.Buffer
+			w            = zip.NewWriter(&epubContents)
+		)
+		err = epubbuilder.WriteEpub(w, book)
+		if err != nil {
+			logger.WriteFatalf("failed to build %q: %s", buildOutputFile, err)
+		}
+
+		err = w.Close()
+		if err != nil {
+			logger.WriteFatalf("failed to finish writing %q: %s", buildOutputFile, err)
+		}
+
+		err = filehandler.WriteBinaryFileContents(buildOutputFile, epubContents.Bytes())
+		if err != nil {
+			logger.WriteFatal(err.Error())
+		}
+
+		logger.WriteInfof("Created %q with %d chapter(s).\n", buildOutputFile, len(book.Chapters))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(buildCmd)
+
+	err := buildFlags.AddToCmd(buildCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"archive/zip"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/cjk"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var cjkFlags = flags.Flags{
+	Flags: []flags.Flag{
+		flags.NewFileFlag(true, false, &epubFile, "file", "f", "", "the epub file to check the ruby annotations and writing mode of", []string{"epub"}, true),
+	},
+}
+
+// cjkCmd represents the cjk command
+var cjkCmd = &cobra.Command{
+	Use:   "cjk",
+	Short: "Checks the ruby annotations and the vertical writing setup of an epub with Japanese, Chinese, or Korean text",
+	Example: heredoc.Doc(`To check the ruby annotations and writing mode of an epub:
+	epub-lint cjk -f test.epub
+	`),
+	Long: heredoc.Doc(`Checks the content files for malformed ruby annotations (i.e. furigana) which includes:
+	- ruby elements without an rt element
+	- rt elements without base text before them or that are empty
+	- rp elements that do not wrap each rt element
+	- rt, rp, and rb elements outside of a ruby element
+	- ruby and rt elements that are not closed
+
+	It also checks that the writing-mode in the css files and the style elements and attributes of the content files
+	is consistent with the OPF file:
+	- vertical-rl text should have a spine page-progression-direction of "rtl"
+	- vertical-lr text should not have a spine page-progression-direction of "rtl"
+	- Japanese, Chinese, or Korean text with a spine page-progression-direction of "rtl" should have vertical-rl text
+	- The primary-writing-mode meta element should match the writing-mode in the css
+	- -epub-writing-mode, -webkit-writing-mode, and writing-mode should match when set in the same rule
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return cjkFlags.Validate()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var findings []structurecheck.Finding
+		err := epubhandler.ReadEpub(epubFile, func(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string) error {
+			htmlFiles, err := readManifestFiles(zipFiles, opfFolder, epubInfo.HtmlFiles)
+			if err != nil {
+				return err
+			}
+
+			cssFiles, err := readManifestFiles(zipFiles, opfFolder, epubInfo.CssFiles)
+			if err != nil {
+				return err
+			}
+
+			opfContents, err := filehandler.ReadInZipFileContents(zipFiles[epubInfo.OpfFile])
+			if err != nil {
+				return err
+			}
+
+			for _, file := range slices.Sorted(maps.Keys(htmlFiles)) {
+				findings = append(findings, cjk.CheckRuby(file, htmlFiles[file])...)
+			}
+
+			findings = append(findings, cjk.CheckWritingMode(epubInfo.OpfFile, opfContents, cssFiles, htmlFiles)...)
+			structurecheck.SortFindings(findings)
+
+			return nil
+		})
+		if err != nil {
+			logger.WriteFatalf("failed to check %q: %s", epubFile, err)
+		}
+
+		if len(findings) == 0 {
+			logger.WriteInfo("No issues found.")
+
+			return
+		}
+
+		for _, finding := range findings {
+			logger.WriteInfo(finding.String())
+		}
+
+		logger.WriteInfof("\nFound %d issue(s).\n", len(findings))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cjkCmd)
+
+	err := cjkFlags.AddToCmd(cjkCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
+
+// readManifestFiles reads in the contents of the manifest files returning them by their path in the epub
+func readManifestFiles(zipFiles map[string]*zip.File, opfFolder string, files map[string]struct{}) (map[string]string, error) {
+	var contentsByFile = make(map[string]string, len(files))
+	for file := range files {
+		var filePath = epubhandler.GetFilePath(opfFolder, file)
+		zipFile, ok := zipFiles[filePath]
+		if !ok {
+			return nil, fmt.Errorf("file from manifest not found: %q must exist", filePath)
+		}
+
+		contents, err := filehandler.ReadInZipFileContents(zipFile)
+		if err != nil {
+			return nil, err
+		}
+
+		contentsByFile[filePath] = contents
+	}
+
+	return contentsByFile, nil
+}
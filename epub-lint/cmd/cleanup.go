@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"archive/zip"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/cleanup"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cleanupRulesFile string
+	cleanupDryRun    bool
+	cleanupFlags     = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewFileFlag(true, false, &epubFile, "file", "f", "", "the epub file to remove boilerplate pages from", []string{"epub"}, true),
+			flags.NewFileFlag(false, false, &cleanupRulesFile, "rules", "r", "", "the path to a json file with the cleanup rules to use instead of the default rules", []string{"json"}, true),
+			flags.NewBoolFlag(false, false, &cleanupDryRun, "dry-run", "", false, "whether to only list the pages and images that would be removed without modifying the epub"),
+		},
+	}
+)
+
+// cleanupCmd represents the cleanup command
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Removes publisher and scanlator boilerplate pages like credit pages and ads from an epub",
+	Example: heredoc.Doc(`To remove the pages that match the default rules (i.e. the JNovels credits page):
+	epub-lint cleanup -f test.epub
+
+	To see which pages and images would be removed using custom rules:
+	epub-lint cleanup -f test.epub -r rules.json --dry-run
+
+	rules.json is expected to be in the following format:
+	{
+	  "rules": [
+	    { "name": "Group credits page", "filePattern": "credits*.xhtml" },
+	    { "name": "Site ad", "textSnippet": "visit our site" },
+	    { "name": "Known ad page", "contentHash": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08" }
+	  ]
+	}
+	`),
+	Long: heredoc.Doc(`Goes through all of the content files and removes the ones that match a cleanup rule.
+	A rule can match a page by any combination of the following and a page must match all of the values specified on a rule:
+	- filePattern: a glob pattern for the name of the file (i.e. "credits*.xhtml")
+	- contentHash: the sha256 hash of the contents of the file
+	- textSnippet: text that the page contains ignoring case, html tags, and differences in whitespace
+
+	When no rules file is provided, the default rules are used which remove the JNovels credits page.
+	Matched pages are removed from the OPF, NCX, nav, and landmarks along with any images that are only used by the removed pages.
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return cleanupFlags.Validate()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var rules = cleanup.DefaultRules
+		if cleanupRulesFile != "" {
+			rulesContents, err := filehandler.ReadInFileContents(cleanupRulesFile)
+			if err != nil {
+				logger.WriteFatal(err.Error())
+			}
+
+			rules, err = cleanup.ParseRules(rulesContents)
+			if err != nil {
+				logger.WriteFatal(err.Error())
+			}
+		}
+
+		var matches []cleanup.PageMatch
+		err := epubhandler.ReadEpub(epubFile, func(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string) error {
+			var err error
+			matches, err = cleanup.FindMatchingPages(newCleanupContext(zipFiles, epubInfo, opfFolder, map[string]string{}), rules)
+
+			return err
+		})
+		if err != nil {
+			logger.WriteFatalf("failed to find the pages to clean up in %q: %s", epubFile, err)
+		}
+
+		if len(matches) == 0 {
+			logger.WriteInfo("No pages matched the cleanup rules.")
+
+			return
+		}
+
+		if cleanupDryRun {
+			logger.WriteInfo(getCleanupSummary("The following files would be removed:", matches))
+
+			return
+		}
+
+		err = epubhandler.UpdateEpub(epubFile, func(zipFiles map[string]*zip.File, w *zip.Writer, epubInfo epubhandler.EpubInfo, opfFolder string) ([]string, error) {
+			var (
+				nameToUpdatedContents = map[string]string{}
+				ctx                   = newCleanupContext(zipFiles, epubInfo, opfFolder, nameToUpdatedContents)
+			)
+
+			handledFiles, err := cleanup.RemovePages(ctx, matches)
+			if err != nil {
+				return nil, err
+			}
+
+			for filename, updatedContents := range nameToUpdatedContents {
+				if slices.Contains(handledFiles, filename) {
+					continue
+				}
+
+				handledFiles = append(handledFiles, filename)
+
+				err = filehandler.WriteZipCompressedString(w, filename, updatedContents)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return handledFiles, nil
+		})
+		if err != nil {
+			logger.WriteFatalf("failed to clean up %q: %s", epubFile, err)
+		}
+
+		logger.WriteInfo(getCleanupSummary("Removed the following files:", matches))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cleanupCmd)
+
+	err := cleanupFlags.AddToCmd(cleanupCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
+
+func newCleanupContext(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string, nameToUpdatedContents map[string]string) cleanup.CleanupContext {
+	return cleanup.CleanupContext{
+		EpubInfo:            epubInfo,
+		OpfFolder:           opfFolder,
+		OpfFileName:         epubInfo.OpfFile,
+		NcxFileName:         filepath.Join(opfFolder, epubInfo.NcxFile),
+		UpdatedFileContents: nameToUpdatedContents,
+		GetFileContents: func(filename string) (string, error) {
+			if fileContents, ok := nameToUpdatedContents[filename]; ok {
+				return fileContents, nil
+			}
+
+			zipFile, ok := zipFiles[filename]
+			if !ok {
+				return "", fmt.Errorf("failed to find %q in the epub", filename)
+			}
+
+			return filehandler.ReadInZipFileContents(zipFile)
+		},
+	}
+}
+
+func getCleanupSummary(header string, matches []cleanup.PageMatch) string {
+	var summary strings.Builder
+	summary.WriteString(header)
+	summary.WriteString("\n")
+
+	for _, match := range matches {
+		fmt.Fprintf(&summary, "- %s (matched %q)\n", match.File, match.Rule)
+
+		for _, image := range match.Images {
+			fmt.Fprintf(&summary, "  - %s\n", image)
+		}
+	}
+
+	return summary.String()
+}
@@ -1,20 +1,3 @@
 package cmd
 
-import (
-	"archive/zip"
-	"fmt"
-)
-
 var epubFile string
-
-func validateFilesExist(opfFolder string, files map[string]struct{}, zipFiles map[string]*zip.File) error {
-	for file := range files {
-		var filePath = getFilePath(opfFolder, file)
-
-		if _, ok := zipFiles[filePath]; !ok {
-			return fmt.Errorf(`file from manifest not found: %q must exist`, filePath)
-		}
-	}
-
-	return nil
-}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	epubdiff "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-diff"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffSummary bool
+	wordDiff    bool
+	diffFlags   = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewBoolFlag(false, false, &diffSummary, "summary", "s", false, "whether to only show the counts of what changed"),
+			flags.NewBoolFlag(false, false, &wordDiff, "word-diff", "w", false, "whether to show the changes to text files as word level diffs instead of unified diffs"),
+		},
+	}
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff ORIGINAL_EPUB UPDATED_EPUB",
+	Short: "Shows the differences between two epubs",
+	Example: heredoc.Doc(`To see what changed in an epub after running a fix:
+	epub-lint diff test.epub.original test.epub
+
+	To just see the counts of what changed:
+	epub-lint diff test.epub.original test.epub --summary
+
+	To see the changes to text files as word level diffs:
+	epub-lint diff test.epub.original test.epub -w
+	`),
+	Long: heredoc.Doc(`Compares the manifest, spine, metadata, and files of two epubs.
+	Files that were added or removed are listed. Text files that were modified get a unified diff
+	(or a word level diff when specified) and images that were modified get their size and dimension changes listed.
+	`),
+	Args: cobra.ExactArgs(2),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		err := diffFlags.Validate()
+		if err != nil {
+			return err
+		}
+
+		err = filehandler.FileArgExists(args[0], "original epub")
+		if err != nil {
+			return err
+		}
+
+		return filehandler.FileArgExists(args[1], "updated epub")
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		original, err := epubdiff.ReadEpubContents(args[0])
+		if err != nil {
+			logger.WriteFatalf("failed to read %q: %s", args[0], err)
+		}
+
+		updated, err := epubdiff.ReadEpubContents(args[1])
+		if err != nil {
+			logger.WriteFatalf("failed to read %q: %s", args[1], err)
+		}
+
+		var epubDiff = epubdiff.Diff(original, updated)
+		if diffSummary {
+			logger.WriteInfo(epubDiff.Summary())
+		} else {
+			logger.WriteInfo(epubDiff.String(wordDiff))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	err := diffFlags.AddToCmd(diffCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
@@ -3,11 +3,15 @@ package cmd
 import (
 	"archive/zip"
 	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
 	potentiallyfixableissue "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/potentially-fixable-issue"
 	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/potentially-fixable-issue/fixer"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/pkg/epub"
 	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
 	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
 	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
@@ -17,87 +21,53 @@ import (
 var (
 	// this is declared globally here just for use in manuallyFixableIssue to make sure that the struct definition
 	// is satisfied even though this value is the second param for potential section breaks
-	contextBreak             string
-	runAll                   bool
-	runBrokenLines           bool
-	runSectionBreak          bool
-	runPageBreak             bool
-	runOxfordCommas          bool
-	runLackingClause         bool
-	runThoughts              bool
-	runConversation          bool
-	runNecessaryWords        bool
-	runSingleQuotes          bool
-	interactive              bool
-	logFile                  string
-	potentiallyFixableIssues = []potentiallyfixableissue.PotentiallyFixableIssue{
-		{
-			Name:           "Potential Conversation Instances",
-			GetSuggestions: potentiallyfixableissue.GetPotentialSquareBracketConversationInstances,
-			IsEnabled:      &runConversation,
-		},
-		{
-			Name:           "Potential Necessary Word Omission Instances",
-			GetSuggestions: potentiallyfixableissue.GetPotentialSquareBracketNecessaryWords,
-			IsEnabled:      &runNecessaryWords,
-		},
-		{
-			Name:           "Potential Broken Lines",
-			GetSuggestions: potentiallyfixableissue.GetPotentiallyBrokenLines,
-			IsEnabled:      &runBrokenLines,
-		},
-		{
-			Name:           "Potential Incorrect Single Quotes",
-			GetSuggestions: potentiallyfixableissue.GetPotentialIncorrectSingleQuotes,
-			IsEnabled:      &runSingleQuotes,
-		},
-		{
-			Name: "Potential Section Breaks",
-			// wrapper here allows calling the get potential section breaks logic without needing to change the function definition
-			GetSuggestions: func(text string) (map[string]string, error) {
-				return potentiallyfixableissue.GetPotentialSectionBreaks(text, contextBreak)
-			},
-			IsEnabled:                   &runSectionBreak,
-			UpdateAllInstances:          true,
-			AddCssSectionBreakIfMissing: true,
-		},
-		{
-			Name:                     "Potential Page Breaks",
-			GetSuggestions:           potentiallyfixableissue.GetPotentialPageBreaks,
-			IsEnabled:                &runPageBreak,
-			UpdateAllInstances:       true,
-			AddCssPageBreakIfMissing: true,
-		},
-		{
-			Name:           "Potential Missing Oxford Commas",
-			GetSuggestions: potentiallyfixableissue.GetPotentialMissingOxfordCommas,
-			IsEnabled:      &runOxfordCommas,
-		},
-		{
-			Name:           "Potentially Lacking Subordinate Clause Instances",
-			GetSuggestions: potentiallyfixableissue.GetPotentiallyLackingSubordinateClauseInstances,
-			IsEnabled:      &runLackingClause,
-		},
-		{
-			Name:           "Potential Thought Instances",
-			GetSuggestions: potentiallyfixableissue.GetPotentialThoughtInstances,
-			IsEnabled:      &runThoughts,
-		},
+	contextBreak           string
+	runAll                 bool
+	runBrokenLines         bool
+	runSectionBreak        bool
+	runPageBreak           bool
+	runOxfordCommas        bool
+	runLackingClause       bool
+	runThoughts            bool
+	runConversation        bool
+	runNecessaryWords      bool
+	runSingleQuotes        bool
+	runNameConsistency     bool
+	runDialoguePunctuation bool
+	seriesFolder           string
+	nameVariants           = potentiallyfixableissue.NewNameVariantTable()
+	interactive            bool
+	logFile                string
+	checkIsEnabled         = map[epub.Check]*bool{
+		epub.CheckConversation:             &runConversation,
+		epub.CheckNecessaryWords:           &runNecessaryWords,
+		epub.CheckBrokenLines:              &runBrokenLines,
+		epub.CheckSingleQuotes:             &runSingleQuotes,
+		epub.CheckSectionBreaks:            &runSectionBreak,
+		epub.CheckPageBreaks:               &runPageBreak,
+		epub.CheckOxfordCommas:             &runOxfordCommas,
+		epub.CheckLackingSubordinateClause: &runLackingClause,
+		epub.CheckThoughts:                 &runThoughts,
+		epub.CheckDialoguePunctuation:      &runDialoguePunctuation,
+		epub.CheckNameConsistency:          &runNameConsistency,
 	}
+	potentiallyFixableIssues      = getPotentiallyFixableIssues()
 	ErrOneRunBoolArgMustBeEnabled = errors.New("at least one rule to run must be enabled")
-	ErrNoCssFiles                 = errors.New("the epub must have at least 1 css file in order to handle section or page breaks")
 	contentFlags                  = flags.Flags{
 		Flags: []flags.Flag{
 			flags.NewBoolFlag(false, false, &runAll, "all", "a", false, "whether to run all of the fixable suggestions"),
 			flags.NewBoolFlag(false, false, &runBrokenLines, "broken-lines", "", false, "whether to run the logic for getting broken line suggestions"),
-			flags.NewBoolFlag(false, false, &runSectionBreak, "section-breaks", "", false, "whether to run the logic for getting section break suggestions (must be used with an epub with a css file)"),
-			flags.NewBoolFlag(false, false, &runPageBreak, "page-breaks", "", false, "whether to run the logic for getting page break suggestions (must be used with an epub with a css file)"),
+			flags.NewBoolFlag(false, false, &runSectionBreak, "section-breaks", "", false, "whether to run the logic for getting section break suggestions"),
+			flags.NewBoolFlag(false, false, &runPageBreak, "page-breaks", "", false, "whether to run the logic for getting page break suggestions"),
 			flags.NewBoolFlag(false, false, &runOxfordCommas, "oxford-commas", "", false, "whether to run the logic for getting oxford comma suggestions"),
 			flags.NewBoolFlag(false, false, &runLackingClause, "lacking-subordinate-clause", "", false, "whether to run the logic for getting potentially lacking subordinate clause suggestions"),
 			flags.NewBoolFlag(false, false, &runThoughts, "thoughts", "", false, "whether to run the logic for getting thought suggestions (words in parentheses may be instances of a person's thoughts)"),
 			flags.NewBoolFlag(false, false, &runConversation, "conversation", "", false, "whether to run the logic for getting conversation suggestions (paragraphs in square brackets may be instances of a conversation)"),
 			flags.NewBoolFlag(false, false, &runNecessaryWords, "necessary-words", "", false, "whether to run the logic for getting necessary word suggestions (words that are a subset of paragraph content are in square brackets may be instances of necessary words for a sentence)"),
 			flags.NewBoolFlag(false, false, &runSingleQuotes, "single-quotes", "", false, "whether to run the logic for getting incorrect single quote suggestions"),
+			flags.NewBoolFlag(false, false, &runDialoguePunctuation, "dialogue-punctuation", "", false, "whether to run the logic for getting dialogue punctuation suggestions (unbalanced quotes across paragraphs, nested double quotes, and commas or periods outside of closing quotes)"),
+			flags.NewBoolFlag(false, false, &runNameConsistency, "name-consistency", "", false, "whether to run the logic for getting name and honorific consistency suggestions (less common spellings of names and honorific forms get normalized to the most common one)"),
+			flags.NewDirectoryFlag(false, false, &seriesFolder, "series-folder", "", "", "a folder of epubs for other volumes in the series to include when determining the most common name and honorific forms"),
 			flags.NewBoolFlag(false, false, &interactive, "interactive", "i", false, "whether to use the terminal UI for suggesting fixes"),
 			flags.NewStringFlag(false, false, &logFile, "log-file", "", "", "the place to write debug logs to when using the TUI"),
 			flags.NewFileFlag(true, false, &epubFile, "file", "f", "", "the epub file to find manually fixable issues in", []string{"epub"}, true),
@@ -105,24 +75,37 @@ var (
 	}
 )
 
+// getPotentiallyFixableIssues gets the issues for each of the content checks in the order they are run in with whether they are enabled being
+// based on the flags. The section break indicator is read when suggestions are requested since it is only known once the user provides it.
+func getPotentiallyFixableIssues() []potentiallyfixableissue.PotentiallyFixableIssue {
+	var fixableIssues = make([]potentiallyfixableissue.PotentiallyFixableIssue, 0, len(epub.AllChecks))
+	for _, check := range epub.AllChecks {
+		var fixableIssue = epub.NewFixableIssue(check, func() string {
+			return contextBreak
+		}, nameVariants)
+		fixableIssue.IsEnabled = checkIsEnabled[check]
+
+		fixableIssues = append(fixableIssues, fixableIssue)
+	}
+
+	return fixableIssues
+}
+
 // contentCmd represents the fix content command
 var contentCmd = &cobra.Command{
 	Use:   "content",
 	Short: "Runs the specified fixable actions that require manual input to determine what to do.",
 	Example: heredoc.Doc(`To run all of the possible potential fixes:
 	epub-lint fix content -f test.epub -a
-	Note: this will require a css file to already exist in the epub
 	
 	To just fix broken paragraph endings:
 	epub-lint fix content -f test.epub --broken-lines
 
 	To just update section breaks:
 	epub-lint fix content -f test.epub --section-breaks
-	Note: this will require a css file to already exist in the epub
 
 	To just update page breaks:
 	epub-lint fix content -f test.epub --page-breaks
-	Note: this will require a css file to already exist in the epub
 
 	To just fix missing oxford commas:
 	epub-lint fix content -f test.epub --oxford-commas
@@ -133,6 +116,15 @@ var contentCmd = &cobra.Command{
 	To just fix instances of thoughts in parentheses:
 	epub-lint fix content -f test.epub --thoughts
 
+	To just fix unbalanced quotes and misplaced dialogue punctuation:
+	epub-lint fix content -f test.epub --dialogue-punctuation
+
+	To just fix inconsistent names and honorifics:
+	epub-lint fix content -f test.epub --name-consistency
+
+	To fix inconsistent names and honorifics using the other volumes in the series to determine the most common forms:
+	epub-lint fix content -f test.epub --name-consistency --series-folder ./series
+
 	To run a combination of options:
 	epub-lint fix content -f test.epub --oxford-commas --thoughts --necessary-words
 	`),
@@ -148,6 +140,17 @@ var contentCmd = &cobra.Command{
 	- Possible instances of conversation encapsulated in square brackets
 	- Possible instances of words in square brackets that may be necessary for the sentence (i.e. need to have the brackets removed)
 	- Possible instances of single quotes that should actually be double quotes (i.e. when a word is in single quotes, but is not inside of double quotes)
+	- Possible instances of unbalanced quotes in dialogue (including dialogue across multiple paragraphs), nested double quotes, and commas or periods outside of closing quotes
+	- Possible instances of less common name spellings and honorific forms (i.e. "Ryu" when "Ryuu" is used more often or "Tanaka san" when "Tanaka-san" is used more often)
+
+	When section or page breaks are updated and the epub has no css file, a stylesheet gets created in the folder of the OPF,
+	added to the manifest, and linked in the head of every content file.
+
+	In the terminal UI, suggestions can be skipped and the remaining suggestions of an issue type in a file can be
+	accepted or skipped all at once. A list of all of the suggestions can also be opened to search them, filter them
+	by issue type, see which ones have been accepted or skipped, and jump to any of them. The raw html of a suggestion
+	can be swapped for a preview that renders it and the paragraphs around it as formatted text with the change
+	highlighted. Edits are still made to the html.
 	`),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		err := contentFlags.Validate()
@@ -155,7 +158,7 @@ var contentCmd = &cobra.Command{
 			return err
 		}
 
-		if !runAll && !runBrokenLines && !runSectionBreak && !runPageBreak && !runOxfordCommas && !runLackingClause && !runConversation && !runThoughts && !runNecessaryWords && !runSingleQuotes {
+		if !runAll && !runBrokenLines && !runSectionBreak && !runPageBreak && !runOxfordCommas && !runLackingClause && !runConversation && !runThoughts && !runNecessaryWords && !runSingleQuotes && !runNameConsistency && !runDialoguePunctuation {
 			return ErrOneRunBoolArgMustBeEnabled
 		}
 
@@ -176,12 +179,12 @@ var contentCmd = &cobra.Command{
 
 		var err error
 		err = epubhandler.UpdateEpub(epubFile, func(zipFiles map[string]*zip.File, w *zip.Writer, epubInfo epubhandler.EpubInfo, opfFolder string) ([]string, error) {
-			err = validateFilesExist(opfFolder, epubInfo.HtmlFiles, zipFiles)
+			err = epubhandler.ValidateFilesExist(opfFolder, zipFiles, epubInfo.HtmlFiles)
 			if err != nil {
 				return nil, err
 			}
 
-			err = validateFilesExist(opfFolder, epubInfo.CssFiles, zipFiles)
+			err = epubhandler.ValidateFilesExist(opfFolder, zipFiles, epubInfo.CssFiles)
 			if err != nil {
 				return nil, err
 			}
@@ -191,12 +194,15 @@ var contentCmd = &cobra.Command{
 				cssFiles = append(cssFiles, cssFile)
 			}
 
-			var skipCss = runAll && len(cssFiles) == 0
-			if (runSectionBreak || runPageBreak) && len(cssFiles) == 0 {
-				return nil, ErrNoCssFiles
+			if runAll || runNameConsistency {
+				err = populateNameVariants(epubInfo, opfFolder, zipFiles)
+				if err != nil {
+					return nil, err
+				}
 			}
 
-			handler.Init(&epubInfo, runAll, skipCss, runSectionBreak, potentiallyFixableIssues, cssFiles, logFile, opfFolder, &contextBreak, func(fileName string) (string, error) {
+			// css rules are never skipped since a css file gets created when the epub does not have one
+			handler.Init(&epubInfo, runAll, false, runSectionBreak, potentiallyFixableIssues, cssFiles, logFile, opfFolder, &contextBreak, func(fileName string) (string, error) {
 				zipFile := zipFiles[fileName]
 
 				fileText, err := filehandler.ReadInZipFileContents(zipFile)
@@ -251,3 +257,71 @@ func init() {
 		logger.WriteFatal(err.Error())
 	}
 }
+
+func populateNameVariants(epubInfo epubhandler.EpubInfo, opfFolder string, zipFiles map[string]*zip.File) error {
+	for file := range epubInfo.HtmlFiles {
+		var filePath = epubhandler.GetFilePath(opfFolder, file)
+
+		fileText, err := filehandler.ReadInZipFileContents(zipFiles[filePath])
+		if err != nil {
+			return err
+		}
+
+		nameVariants.AddText(fileText)
+	}
+
+	if strings.TrimSpace(seriesFolder) == "" {
+		return nil
+	}
+
+	currentEpub, err := filepath.Abs(epubFile)
+	if err != nil {
+		return fmt.Errorf("failed to get the absolute path for %q: %w", epubFile, err)
+	}
+
+	volumes, err := filehandler.GetAllFilesWithExtInASpecificFolder(seriesFolder, ".epub")
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		var volumePath = filehandler.JoinPath(seriesFolder, volume)
+		absVolumePath, err := filepath.Abs(volumePath)
+		if err != nil {
+			return fmt.Errorf("failed to get the absolute path for %q: %w", volumePath, err)
+		}
+
+		if absVolumePath == currentEpub {
+			continue
+		}
+
+		err = addVolumeToNameVariants(volumePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func addVolumeToNameVariants(volumePath string) error {
+	r, volumeFiles, err := filehandler.GetFilesFromZip(volumePath)
+	if err != nil {
+		return fmt.Errorf("failed to get zip contents for %q: %w", volumePath, err)
+	}
+	defer filehandler.TryClose(volumePath, r)
+
+	for name, zipFile := range volumeFiles {
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".html", ".xhtml", ".htm":
+			fileText, err := filehandler.ReadInZipFileContents(zipFile)
+			if err != nil {
+				return err
+			}
+
+			nameVariants.AddText(fileText)
+		}
+	}
+
+	return nil
+}
@@ -1,14 +1,11 @@
 package cmd
 
 import (
-	"archive/zip"
-	"fmt"
-	"path/filepath"
+	"maps"
+	"slices"
 
 	"github.com/MakeNowJust/heredoc"
-	epubcheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check"
-	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
-	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/jnovels"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/pkg/epub"
 	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
 	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
 	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
@@ -32,13 +29,20 @@ var autoFixValidationCmd = &cobra.Command{
 	Use:   "validation",
 	Short: "Reads in the output of EPUBCheck and fixes as many issues as are able to be fixed without the user making any changes.",
 	Long: heredoc.Doc(`Uses the provided epub and EPUBCheck output file to fix auto fixable auto fix issues. Here is a list of all of the error codes that are currently handled:
+	- CSS-008: remove the declaration or stray closing brace that the CSS failed to parse at
+	- OPF-003: add files that are in the epub, but not in the manifest, to the manifest when their media type is known from their extension
 	- OPF-014: add scripted to the list of values in the properties attribute on the manifest item
 	- OPF-015: remove scripted to the list of values in the properties attribute on the manifest item
 	- OPF-030: add the unique identifier id to the first dc:identifier element that does not have an id already
+	- OPF-037: replace deprecated media types in the manifest with the media type for the file's extension
+	- OPF-038: replace inappropriate media types in the manifest with the media type for the file's extension
+	- OPF-043: replace the non-standard media type of a spine item with the media type for the file's extension
 	- OPF-074: remove duplicate manifest entries
 	- OPF-096: make file content reachable by removing linear attribute
+	- NAV-010: remove nav list items that link to remote resources
 	- NAV-011: fix nav file's table of contents not being in the same order as the specified reading order
 	- NCX-001: fix discrepancy in identifier between the OPF and NCX files
+	- NCX-006: fill in empty nav labels with the text of the first header or paragraph of the file the nav point links to
 	- RSC-005: seems to be a catch all error id, but the following are handled around it
 		- Update ids/attributes to have valid xml ids that conform to the xml and epub spec by removing colons and any other invalid characters with an underscore
 			and starting the value with an underscore instead of a number if it currently is started by a number
@@ -50,10 +54,19 @@ var autoFixValidationCmd = &cobra.Command{
 		- Move section elements from inside of span and paragraph tags to outside of them if they have no other siblings or other parent tags before the span and paragraph
 		- Update empty title with the text of the first header in the file or the first paragraph if there is no header and there is a paragraph
 	- RSC-007: try to fix broken file links and remove
+	- RSC-008: add referenced files that are in the epub, but not in the manifest, to the manifest
+	- RSC-009: remove the fragment identifier from references to images
+	- RSC-010: replace the media type of referenced files with the media type for the file's extension
+	- RSC-011: add linked content files that are not in the spine to the end of the spine as non-linear items
 	- RSC-012: try to fix broken links by removing the id link in the href attribute
 	- RSC-017: seems to be a catch all error id, but the following are handled around it
 		- Add missing title element with the text of the first header or, if no header is present, the first paragraph present in the file 
 	- HTM-004: try to fix broken DOCTYPEs by replacing them with the expected DOCTYPE
+	- HTM-009: replace obsolete DOCTYPEs with the HTML5 DOCTYPE
+	- HTM-010: replace misspellings of the XHTML namespace like ones with a trailing slash with the XHTML namespace
+	- HTM-011: replace named HTML entities that XML does not declare with numeric character references
+
+	Once done, the number of issues for each code is listed split by whether or not the code is handled.
 	`),
 	Example: heredoc.Doc(`
 		epub-lint fix validation -f test.epub --issues epubCheckOutput.txt
@@ -75,108 +88,32 @@ var autoFixValidationCmd = &cobra.Command{
 			logger.WriteFatal(err.Error())
 		}
 
-		validationErrors, err := epubcheck.ParseEPUBCheckOutput(validationOutput)
+		result, err := epub.FixValidationIssues(epubFile, validationOutput, epub.FixOptions{
+			CleanupJNovels: removeJNovelInfo,
+		})
 		if err != nil {
 			logger.WriteFatal(err.Error())
 		}
 
-		validationErrors.Sort()
-
-		err = epubhandler.UpdateEpub(epubFile, func(zipFiles map[string]*zip.File, w *zip.Writer, epubInfo epubhandler.EpubInfo, opfFolder string) ([]string, error) {
-			var (
-				opfFilename = epubInfo.OpfFile
-				opfFile     = zipFiles[opfFilename]
-			)
-
-			opfFileContents, err := filehandler.ReadInZipFileContents(opfFile)
-			if err != nil {
-				return nil, err
-			}
-
-			var ncxFilename = filepath.Join(opfFolder, epubInfo.NcxFile)
-			ncxFileContents, err := filehandler.ReadInZipFileContents(zipFiles[ncxFilename])
-			if err != nil {
-				return nil, err
-			}
-
-			var basenameToFilePaths = make(map[string][]string)
-			for filename := range zipFiles {
-				var basename = filepath.Base(filename)
-				if files, ok := basenameToFilePaths[basename]; ok {
-					basenameToFilePaths[basename] = append(files, filename)
-				} else {
-					basenameToFilePaths[basename] = []string{filename}
-				}
-			}
-
-			var (
-				nameToUpdatedContents = map[string]string{
-					ncxFilename: ncxFileContents,
-					opfFilename: opfFileContents,
-				}
-				handledFiles          []string
-				getFileContentsByName = func(filename string) (string, error) {
-					fileContents, ok := nameToUpdatedContents[filename]
-					if !ok {
-						zipFile, ok := zipFiles[filename]
-						if !ok {
-							return "", fmt.Errorf("failed to find %q in the epub", filename)
-						}
-
-						fileContents, err = filehandler.ReadInZipFileContents(zipFile)
-						if err != nil {
-							return "", err
-						}
-					}
-
-					return fileContents, nil
-				}
-			)
-			err = epubcheck.HandleValidationErrors(opfFolder, ncxFilename, opfFilename, nameToUpdatedContents, basenameToFilePaths, &validationErrors, getFileContentsByName, epubInfo.FilePathsInSpineOrder)
-			if err != nil {
-				return nil, err
-			}
-
-			if removeJNovelInfo {
-				handledFiles, err = jnovels.CleanupJNovelsFiles(jnovels.JNovelsCleanupContext{
-					EpubInfo:            epubInfo,
-					OpfFolder:           opfFolder,
-					OpfFileName:         opfFilename,
-					NcxFileName:         ncxFilename,
-					FileBasenameMap:     basenameToFilePaths,
-					UpdatedFileContents: nameToUpdatedContents,
-					GetFileContents:     getFileContentsByName,
-				})
-
-				if err != nil {
-					return nil, err
-				}
-			}
-
-			for filename, updatedContents := range nameToUpdatedContents {
-				var name = filepath.Base(filename)
-				if removeJNovelInfo && (name == jnovels.JnovelsFile || name == jnovels.JnovelsImage) {
-					continue
-				}
-
-				handledFiles = append(handledFiles, filename)
-
-				err = filehandler.WriteZipCompressedString(w, filename, updatedContents)
-				if err != nil {
-					return nil, err
-				}
-			}
-
-			return handledFiles, nil
-		})
-		if err != nil {
-			logger.WriteFatalf("failed to fix validation issues in %q: %s", epubFile, err)
-		}
+		writeCodeSummary("Handled codes:", result.HandledCodes, logger.WriteInfof)
+		writeCodeSummary("Unhandled codes:", result.UnhandledCodes, logger.WriteWarnf)
 
 		logger.WriteInfo("Finished fixing epub validation issues.")
 	},
 }
 
+// writeCodeSummary writes the number of issues for each code in alphabetical order when there are any
+func writeCodeSummary(heading string, codeToCount map[string]int, write func(format string, a ...any)) {
+	if len(codeToCount) == 0 {
+		return
+	}
+
+	write("%s\n", heading)
+	for _, code := range slices.Sorted(maps.Keys(codeToCount)) {
+		write("  %s: %d issue(s)\n", code, codeToCount[code])
+	}
+}
+
 func init() {
 	fixCmd.AddCommand(autoFixValidationCmd)
 
@@ -0,0 +1,75 @@
+//go:build generate_lang_profiles
+
+package cmd
+
+import (
+	"strings"
+
+	langdetect "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/lang-detect"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	corpusFolder              string
+	langProfilesFolder        string
+	generateLangProfilesFlags = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewDirectoryFlag(true, false, &corpusFolder, "corpus", "c", "", "the folder with a folder of text files for each language named after its language code (i.e. \"en\")"),
+			flags.NewDirectoryFlag(true, false, &langProfilesFolder, "out", "o", "", "the folder to write the language profiles to which should point to epub-lint's internal/lang-detect/profiles folder"),
+		},
+	}
+)
+
+// GenerateLangProfilesCmd represents the generate language profiles command
+var GenerateLangProfilesCmd = &cobra.Command{
+	Use:   "lang-profiles",
+	Short: "Generates the language profiles used for detecting the language of text from a corpus of text for each language",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return generateLangProfilesFlags.Validate()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		langs, err := filehandler.GetFoldersInCurrentFolder(corpusFolder)
+		if err != nil {
+			logger.WriteFatal(err.Error())
+		}
+
+		for _, lang := range langs {
+			var langFolder = filehandler.JoinPath(corpusFolder, lang)
+			files, err := filehandler.GetAllFilesWithExtInASpecificFolder(langFolder, ".txt")
+			if err != nil {
+				logger.WriteFatal(err.Error())
+			}
+
+			var text strings.Builder
+			for _, file := range files {
+				contents, err := filehandler.ReadInFileContents(filehandler.JoinPath(langFolder, file))
+				if err != nil {
+					logger.WriteFatal(err.Error())
+				}
+
+				text.WriteString(contents)
+				text.WriteString("\n")
+			}
+
+			var profilePath = filehandler.JoinPath(langProfilesFolder, lang+".txt")
+			err = filehandler.WriteFileContents(profilePath, strings.Join(langdetect.BuildProfile(text.String()), "\n")+"\n")
+			if err != nil {
+				logger.WriteFatal(err.Error())
+			}
+
+			logger.WriteInfof("Profile for %q saved to %q\n", lang, profilePath)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(GenerateLangProfilesCmd)
+
+	err := generateLangProfilesFlags.AddToCmd(GenerateLangProfilesCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
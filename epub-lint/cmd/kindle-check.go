@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"archive/zip"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/images"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/kindle"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/links"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+const kindleNcxFileName = "toc.ncx"
+
+var (
+	fixKindleIssues  bool
+	kindleCheckFlags = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewFileFlag(true, false, &epubFile, "file", "f", "", "the epub file to check for Kindle issues", []string{"epub"}, true),
+			flags.NewBoolFlag(false, false, &fixKindleIssues, "fix", "", false, "whether or not to fix the issues that can be safely fixed"),
+		},
+	}
+)
+
+// kindleCheckCmd represents the kindle-check command
+var kindleCheckCmd = &cobra.Command{
+	Use:   "kindle-check",
+	Short: "Checks an epub for constructs that fail or render badly when sent to Kindle and fixes the ones that can be safely fixed",
+	Example: heredoc.Doc(`To list the Kindle issues in an epub:
+	epub-lint kindle-check -f test.epub
+
+	To fix the Kindle issues that can be safely fixed:
+	epub-lint kindle-check -f test.epub --fix
+	`),
+	Long: heredoc.Doc(`Checks an epub for constructs that Send-to-Kindle rejects or that do not render well on Kindle:
+	- position: fixed in the css
+	- css animations and transitions
+	- css that Kindle does not support like flex and grid layouts, transforms, and columns
+	- images that are larger than 5 MB or in a format Kindle does not support like webp
+	- a missing NCX file which older Kindles use for the table of contents
+	- embedded fonts without a license in the font or a font license file in the epub
+
+	When fix is specified, the following are fixed:
+	- position: fixed, animation, and transition declarations are removed
+	- oversized jpeg and png images are compressed
+	- an NCX file is created from the nav file when there is no NCX file
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return kindleCheckFlags.Validate()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var findings []structurecheck.Finding
+		err := epubhandler.ReadEpub(epubFile, func(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string) error {
+			var styleFiles = maps.Clone(epubInfo.HtmlFiles)
+			maps.Copy(styleFiles, epubInfo.CssFiles)
+
+			styles, err := readManifestFiles(zipFiles, opfFolder, styleFiles)
+			if err != nil {
+				return err
+			}
+
+			for _, file := range slices.Sorted(maps.Keys(styles)) {
+				findings = append(findings, kindle.CheckStyles(file, styles[file])...)
+			}
+
+			for file := range epubInfo.ImagesFiles {
+				var filePath = epubhandler.GetFilePath(opfFolder, file)
+				zipFile, ok := zipFiles[filePath]
+				if !ok {
+					continue
+				}
+
+				data, err := filehandler.ReadInZipFileBytes(zipFile)
+				if err != nil {
+					return err
+				}
+
+				findings = append(findings, kindle.CheckImage(filePath, data)...)
+			}
+
+			var (
+				filenames      = slices.Collect(maps.Keys(zipFiles))
+				hasLicenseFile = kindle.HasLicenseFile(filenames)
+			)
+			for _, filename := range filenames {
+				if !slices.Contains(kindle.FontExts, strings.ToLower(path.Ext(filename))) {
+					continue
+				}
+
+				data, err := filehandler.ReadInZipFileBytes(zipFiles[filename])
+				if err != nil {
+					return err
+				}
+
+				findings = append(findings, kindle.CheckFont(filename, data, hasLicenseFile)...)
+			}
+
+			opfContents, err := filehandler.ReadInZipFileContents(zipFiles[epubInfo.OpfFile])
+			if err != nil {
+				return err
+			}
+
+			findings = append(findings, kindle.CheckNcx(epubInfo.OpfFile, opfContents, epubInfo.NcxFile)...)
+			structurecheck.SortFindings(findings)
+
+			return nil
+		})
+		if err != nil {
+			logger.WriteFatalf("failed to check %q for Kindle issues: %s", epubFile, err)
+		}
+
+		if len(findings) == 0 {
+			logger.WriteInfo("No Kindle issues found.")
+
+			return
+		}
+
+		var (
+			filesToFix = make(map[string]string)
+			fixable    int
+		)
+		for _, finding := range findings {
+			logger.WriteInfo(finding.String())
+
+			if kindle.IsFixable(finding) {
+				filesToFix[finding.File] = finding.Rule
+				fixable++
+			}
+		}
+
+		logger.WriteInfof("\nFound %d Kindle issue(s), %d of which can be fixed.\n", len(findings), fixable)
+
+		if !fixKindleIssues || len(filesToFix) == 0 {
+			return
+		}
+
+		var fixes int
+		err = epubhandler.UpdateEpub(epubFile, func(zipFiles map[string]*zip.File, w *zip.Writer, epubInfo epubhandler.EpubInfo, opfFolder string) ([]string, error) {
+			var handledFiles []string
+			for _, file := range slices.Sorted(maps.Keys(filesToFix)) {
+				switch filesToFix[file] {
+				case kindle.OversizedImage:
+					data, err := filehandler.ReadInZipFileBytes(zipFiles[file])
+					if err != nil {
+						return nil, err
+					}
+
+					newData, err := images.CompressImage(file, data)
+					if err != nil {
+						return nil, err
+					}
+
+					if len(newData) >= len(data) {
+						logger.WriteWarnf("%q could not be compressed\n", file)
+
+						continue
+					}
+
+					err = filehandler.WriteZipCompressedBytes(w, file, newData)
+					if err != nil {
+						return nil, err
+					}
+
+					fixes++
+				case kindle.MissingNcx:
+					if epubInfo.NavFile == "" {
+						logger.WriteWarn("An NCX file cannot be created since there is no nav file.")
+
+						continue
+					}
+
+					var (
+						navFile = epubhandler.GetFilePath(opfFolder, epubInfo.NavFile)
+						ncxFile = epubhandler.GetFilePath(opfFolder, kindleNcxFileName)
+					)
+					navContents, err := filehandler.ReadInZipFileContents(zipFiles[navFile])
+					if err != nil {
+						return nil, err
+					}
+
+					opfContents, err := filehandler.ReadInZipFileContents(zipFiles[file])
+					if err != nil {
+						return nil, err
+					}
+
+					ncxContents, err := kindle.CreateNcx(navContents, navFile, ncxFile, opfContents)
+					if err != nil {
+						return nil, err
+					}
+
+					opfContents, err = kindle.AddNcxToOpf(opfContents, links.GetRelativeLink(file, ncxFile))
+					if err != nil {
+						return nil, err
+					}
+
+					err = filehandler.WriteZipCompressedString(w, ncxFile, ncxContents)
+					if err != nil {
+						return nil, err
+					}
+
+					err = filehandler.WriteZipCompressedString(w, file, opfContents)
+					if err != nil {
+						return nil, err
+					}
+
+					handledFiles = append(handledFiles, ncxFile)
+					fixes++
+				default:
+					contents, err := filehandler.ReadInZipFileContents(zipFiles[file])
+					if err != nil {
+						return nil, err
+					}
+
+					newContents, removed := kindle.FixStyles(file, contents)
+					err = filehandler.WriteZipCompressedString(w, file, newContents)
+					if err != nil {
+						return nil, err
+					}
+
+					fixes += removed
+				}
+
+				handledFiles = append(handledFiles, file)
+			}
+
+			return handledFiles, nil
+		})
+		if err != nil {
+			logger.WriteFatalf("failed to update %q: %s", epubFile, err)
+		}
+
+		logger.WriteInfof("Fixed %d Kindle issue(s).\n", fixes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(kindleCheckCmd)
+
+	err := kindleCheckFlags.AddToCmd(kindleCheckCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
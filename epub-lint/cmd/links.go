@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"archive/zip"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/links"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fixLinks         bool
+	acceptAllRepairs bool
+	linkFileExts     = []string{".xhtml", ".html", ".htm", ".ncx", ".css"}
+	linksFlags       = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewFileFlag(true, false, &epubFile, "file", "f", "", "the epub file to check the links in", []string{"epub"}, true),
+			flags.NewBoolFlag(false, false, &fixLinks, "fix", "", false, "whether or not to repair the broken links that have a repair, asking before each repair"),
+			flags.NewBoolFlag(false, false, &acceptAllRepairs, "yes", "y", false, "whether or not to make all of the repairs without asking when fixing the broken links"),
+		},
+	}
+)
+
+// linksCmd represents the links command
+var linksCmd = &cobra.Command{
+	Use:   "links",
+	Short: "Checks that all internal links and anchors in an epub point to files and ids that exist and repairs them when possible",
+	Example: heredoc.Doc(`To list the broken links in an epub:
+	epub-lint links -f test.epub
+
+	To repair the broken links, asking before each repair:
+	epub-lint links -f test.epub --fix
+
+	To make all of the repairs without asking:
+	epub-lint links -f test.epub --fix -y
+	`),
+	Long: heredoc.Doc(`Goes through every href, src, xlink:href, and css url() in the content, nav, NCX, and css files
+	and checks that the file it points to exists in the epub and that any id it points to exists in that file.
+
+	A repair is suggested for a broken link when:
+	- The file does not exist, but a file with the same name exists elsewhere in the epub
+	- The id does not exist, but an id that only differs by case, punctuation, or a few characters exists in the file
+
+	When fix is specified, the suggested repairs are made to the epub.
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return linksFlags.Validate()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var brokenLinks []links.BrokenLink
+		err := epubhandler.ReadEpub(epubFile, func(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string) error {
+			var (
+				files         = make(map[string]string)
+				existingFiles = make(map[string]struct{}, len(zipFiles))
+			)
+			for filename, zipFile := range zipFiles {
+				existingFiles[filename] = struct{}{}
+
+				if !slices.Contains(linkFileExts, strings.ToLower(path.Ext(filename))) {
+					continue
+				}
+
+				contents, err := filehandler.ReadInZipFileContents(zipFile)
+				if err != nil {
+					return err
+				}
+
+				files[filename] = contents
+			}
+
+			brokenLinks = links.CheckLinks(files, existingFiles)
+
+			return nil
+		})
+		if err != nil {
+			logger.WriteFatalf("failed to check the links in %q: %s", epubFile, err)
+		}
+
+		if len(brokenLinks) == 0 {
+			logger.WriteInfo("No broken links found.")
+
+			return
+		}
+
+		var repairable int
+		for _, brokenLink := range brokenLinks {
+			var repair = "no repair found"
+			if brokenLink.Repair != "" {
+				repair = fmt.Sprintf("repair: %q", brokenLink.Repair)
+				repairable++
+			}
+
+			logger.WriteInfof("%s:%d:%d: %s\n  %s\n", brokenLink.File, brokenLink.Line, brokenLink.Column, brokenLink.Message(), repair)
+		}
+
+		logger.WriteInfof("\nFound %d broken link(s), %d of which can be repaired.\n", len(brokenLinks), repairable)
+
+		if !fixLinks || repairable == 0 {
+			return
+		}
+
+		var repairs = getLinkRepairsToMake(brokenLinks)
+		if len(repairs) == 0 {
+			logger.WriteInfo("No repairs made.")
+
+			return
+		}
+
+		err = epubhandler.UpdateEpub(epubFile, func(zipFiles map[string]*zip.File, w *zip.Writer, epubInfo epubhandler.EpubInfo, opfFolder string) ([]string, error) {
+			var handledFiles []string
+			for filename, fileRepairs := range repairs {
+				zipFile, ok := zipFiles[filename]
+				if !ok {
+					return nil, fmt.Errorf("failed to find %q in the epub", filename)
+				}
+
+				contents, err := filehandler.ReadInZipFileContents(zipFile)
+				if err != nil {
+					return nil, err
+				}
+
+				var edits = make([]positions.TextEdit, 0, len(fileRepairs))
+				for _, repair := range fileRepairs {
+					edits = append(edits, repair.Edit(contents))
+				}
+
+				contents, err = positions.ApplyEdits(filename, contents, edits)
+				if err != nil {
+					return nil, err
+				}
+
+				err = filehandler.WriteZipCompressedString(w, filename, contents)
+				if err != nil {
+					return nil, err
+				}
+
+				handledFiles = append(handledFiles, filename)
+			}
+
+			return handledFiles, nil
+		})
+		if err != nil {
+			logger.WriteFatalf("failed to repair the links in %q: %s", epubFile, err)
+		}
+
+		logger.WriteInfo("Finished repairing broken links.")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(linksCmd)
+
+	err := linksFlags.AddToCmd(linksCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
+
+// getLinkRepairsToMake gets the repairs to make by file asking the user about each one unless all repairs are accepted
+func getLinkRepairsToMake(brokenLinks []links.BrokenLink) map[string][]links.BrokenLink {
+	var repairs = make(map[string][]links.BrokenLink)
+	for _, brokenLink := range brokenLinks {
+		if brokenLink.Repair == "" {
+			continue
+		}
+
+		if !acceptAllRepairs {
+			resp := logger.GetInputString(fmt.Sprintf("Would you like to replace %q with %q in %s? (Y/N/Q): ", brokenLink.Value, brokenLink.Repair, brokenLink.File))
+			switch strings.ToLower(resp) {
+			case "y":
+			case "q":
+				return repairs
+			default:
+				continue
+			}
+		}
+
+		repairs[brokenLink.File] = append(repairs[brokenLink.File], brokenLink)
+	}
+
+	return repairs
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/lsp"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/pkg/epub"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lspDir          string
+	lspSectionBreak string
+	lspFlags        = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewDirectoryFlag(false, false, &lspDir, "directory", "d", "", "the folder with the unpacked epub contents which defaults to the root of the workspace the editor opens"),
+			flags.NewStringFlag(false, false, &lspSectionBreak, "section-break", "", "", "the text that marks a section break (i.e. \"* * *\") which is needed to get section break suggestions"),
+		},
+	}
+)
+
+// lspCmd represents the lsp command
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Runs a language server over stdio that reports issues in an unpacked epub and offers fixes for them",
+	Example: heredoc.Doc(`To have an editor start the language server for the workspace it opens:
+	epub-lint lsp
+
+	To serve a specific unpacked epub and get section break suggestions:
+	epub-lint unpack -f book.epub -o book
+	epub-lint lsp -d book --section-break "* * *"
+	`),
+	Long: heredoc.Doc(`Runs a language server that communicates over stdin and stdout so editors that support the
+	language server protocol can show the issues in an unpacked epub as diagnostics. Each time a file is opened,
+	changed, or saved it:
+	- Re-parses the OPF file and checks that all of the files in the manifest exist
+	- Checks the content files for duplicate ids, broken internal links, and images missing alt text
+	- Runs the content checks from fix content on the content files
+
+	Quick fixes are offered as code actions for missing alt text, duplicate ids, and broken links using the
+	same fixes as fix validation along with the suggestions for the content checks.
+	Section break suggestions are only made when a section break is provided.
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return lspFlags.Validate()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var server = lsp.NewServer(lsp.Options{
+			Root: lspDir,
+			LintOptions: epub.LintOptions{
+				SectionBreakIndicator: lspSectionBreak,
+			},
+		})
+
+		err := server.Run(os.Stdin, os.Stdout)
+		if err != nil {
+			logger.WriteFatal(err.Error())
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+
+	err := lspFlags.AddToCmd(lspCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
@@ -1,15 +1,11 @@
 package cmd
 
 import (
-	"archive/zip"
-	"fmt"
 	"strings"
 
 	"github.com/MakeNowJust/heredoc"
-	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
 	filesize "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/file-size"
-	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/images"
-	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/pkg/epub"
 	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
 	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
 	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
@@ -25,7 +21,7 @@ var (
 	optimizeFlags      = flags.Flags{
 		Flags: []flags.Flag{
 			flags.NewDirectoryFlag(false, false, &lintDir, "directory", "d", ".", "the location to run the epub linter logic"),
-			flags.NewStringFlag(false, false, &lang, "lang", "l", "en", "the language to add to the xhtml, htm, or html files if the lang is not already specified"),
+			flags.NewStringFlag(false, false, &lang, "lang", "l", "en", "the language to add to the xhtml, htm, or html files if the lang is not already specified or \"auto\" to detect the language of each book and file"),
 			flags.NewStringFlag(false, false, &removableFileTypes, "remove-types", "", ".jpg,.jpeg,.png,.gif,.bmp,.js,.html,.htm,.xhtml,.txt,.css,.xml", "A comma separated list of file extensions of files to remove if they are not in the manifest (i.e. '.jpeg,.jpg')"),
 			flags.NewBoolFlag(false, false, &verbose, "verbose", "v", false, "whether or not to show extra logs like what files were removed from the epub"),
 			flags.NewBoolFlag(false, false, &runCompressImages, "compress", "c", false, "whether or not to also compress images"),
@@ -45,13 +41,25 @@ var optimizeCmd = &cobra.Command{
 
 	To just make general modifications to all epubs in the current directory:
 	epub-lint optimize
+
+	To detect the language of each epub and its files instead of assuming English:
+	epub-lint optimize -l auto
 	`),
 	Long: heredoc.Doc(`Gets all of the .epub files in the specified directory.
 	Then it lints each epub separately making sure to compress the images if specified.
 	Some of the things that the linting includes:
-	- Replacing a list of common strings
+	- Replacing a list of common strings in files written in the Latin script, leaving ruby annotations and text in other languages like Japanese as is
 	- Adds language encoding specified if it is not present already (default is "en")
+	- When the language is "auto", detects the language of each book and each of its content files from their text
+	and uses it instead, adding the dc:language to the OPF when it is missing and warning when the declared language
+	of the OPF or a content file does not match the detected one
+	- Detects the actual encoding of the content, css, and NCX files from their byte order mark, encoding declaration,
+	or the text itself and converts them to utf-8 when they are in another encoding like Windows-1252 or Shift-JIS
+	- Repairs mojibake which is utf-8 text that was read as Windows-1252 (i.e. "â€™" instead of "’") when a file has clear signs of it like multiple "â€" or "Ã" sequences
 	- Sets encoding on content files to utf-8 to prevent errors in some readers
+
+	Each epub is rewritten one file at a time with any file that does not need to change copied over as is,
+	so even very large books with a lot of images can be optimized without holding the whole book in memory.
 	`),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		return optimizeFlags.Validate()
@@ -70,16 +78,16 @@ var optimizeCmd = &cobra.Command{
 		}
 
 		var totalBeforeFileSize, totalAfterFileSize float64
-		for _, epub := range epubs {
-			logger.WriteInfof("starting epub compressing for %s...\n", epub)
+		for _, epubName := range epubs {
+			logger.WriteInfof("starting epub compressing for %s...\n", epubName)
 
-			err = LintEpub(lintDir, epub, runCompressImages, verbose, removableFileExts)
+			err = LintEpub(lintDir, epubName, runCompressImages, verbose, removableFileExts)
 			if err != nil {
 				logger.WriteFatal(err.Error())
 			}
 
-			var originalFile = epub + ".original"
-			newKbSize, err := filehandler.GetFileSize(epub)
+			var originalFile = epubName + ".original"
+			newKbSize, err := filehandler.GetFileSize(epubName)
 			if err != nil {
 				logger.WriteFatal(err.Error())
 			}
@@ -89,7 +97,7 @@ var optimizeCmd = &cobra.Command{
 				logger.WriteFatal(err.Error())
 			}
 
-			logger.WriteInfo(filesize.FileSizeSummary(originalFile, epub, oldKbSize, newKbSize))
+			logger.WriteInfo(filesize.FileSizeSummary(originalFile, epubName, oldKbSize, newKbSize))
 
 			totalBeforeFileSize += oldKbSize
 			totalAfterFileSize += newKbSize
@@ -109,100 +117,32 @@ func init() {
 	}
 }
 
-func LintEpub(lintDir, epub string, runCompressImages, verbose bool, removableFileExts []string) error {
-	var src = filehandler.JoinPath(lintDir, epub)
-	err := epubhandler.UpdateEpub(src, func(zipFiles map[string]*zip.File, w *zip.Writer, epubInfo epubhandler.EpubInfo, opfFolder string) ([]string, error) {
-		err := validateFilesExist(opfFolder, epubInfo.HtmlFiles, zipFiles)
-		if err != nil {
-			return nil, err
-		}
-
-		err = validateFilesExist(opfFolder, epubInfo.ImagesFiles, zipFiles)
-		if err != nil {
-			return nil, err
-		}
-
-		err = validateFilesExist(opfFolder, epubInfo.OtherFiles, zipFiles)
-		if err != nil {
-			return nil, err
-		}
-
-		var handledFiles []string
-		var manifestFiles = make(map[string]struct{}, len(epubInfo.HtmlFiles)+len(epubInfo.ImagesFiles)+len(epubInfo.CssFiles)+len(epubInfo.OtherFiles))
-
-		// fix up all xhtml files first
-		for file := range epubInfo.HtmlFiles {
-			var filePath = getFilePath(opfFolder, file)
-			manifestFiles[filePath] = struct{}{}
-
-			zipFile := zipFiles[filePath]
-
-			fileText, err := filehandler.ReadInZipFileContents(zipFile)
-			if err != nil {
-				return nil, err
-			}
-
-			var newText = linter.EnsureEncodingIsPresent(fileText)
-			newText = linter.CommonStringReplace(newText)
-
-			newText = linter.EnsureLanguageIsSet(newText, lang)
-
-			err = filehandler.WriteZipCompressedString(w, filePath, newText)
-			if err != nil {
-				return nil, err
-			}
-
-			handledFiles = append(handledFiles, filePath)
-		}
-
-		if runCompressImages {
-			for imagePath := range epubInfo.ImagesFiles {
-				var filePath = filehandler.JoinPath(opfFolder, imagePath)
-				manifestFiles[filePath] = struct{}{}
-
-				imageFile := zipFiles[filePath]
-
-				data, err := filehandler.ReadInZipFileBytes(imageFile)
-				if err != nil {
-					return nil, err
-				}
-
-				newData, err := images.CompressImage(filePath, data)
-				if err != nil {
-					return nil, err
-				}
-
-				err = filehandler.WriteZipCompressedBytes(w, filePath, newData)
-				if err != nil {
-					return nil, err
-				}
-
-				handledFiles = append(handledFiles, filePath)
-			}
-		}
-
-		// handle the files that are present in the epub, but not present in the actual manifest
-		if len(removableFileExts) == 0 {
-			return handledFiles, nil
-		}
+// LintEpub optimizes the epub in the folder logging what changed and any warnings that came up
+func LintEpub(lintDir, epubName string, runCompressImages, verbose bool, removableFileExts []string) error {
+	var src = filehandler.JoinPath(lintDir, epubName)
+	result, err := epub.Optimize(src, epub.OptimizeOptions{
+		Language:          lang,
+		CompressImages:    runCompressImages,
+		RemovableFileExts: removableFileExts,
+	})
 
-		for otherPath := range epubInfo.OtherFiles {
-			manifestFiles[filehandler.JoinPath(opfFolder, otherPath)] = struct{}{}
-		}
+	if result.Language != "" {
+		logger.WriteInfof("Detected the language of %q as %q\n", src, result.Language)
+	}
 
-		for otherPath := range epubInfo.CssFiles {
-			manifestFiles[filehandler.JoinPath(opfFolder, otherPath)] = struct{}{}
+	if verbose {
+		for _, removedFile := range result.RemovedFiles {
+			logger.WriteInfof("Removed file %q from the epub since it is not in the manifest.\n", removedFile)
 		}
+	}
 
-		return epubhandler.RemoveUnusedFiles(handledFiles, zipFiles, manifestFiles, removableFileExts, verbose), nil
-	})
-	if err != nil {
-		return fmt.Errorf("failed to update epub %q: %w", src, err)
+	for _, change := range result.Changes {
+		logger.WriteInfo(change)
 	}
 
-	return nil
-}
+	for _, warning := range result.Warnings {
+		logger.WriteWarn(warning)
+	}
 
-func getFilePath(opfFolder, file string) string {
-	return filehandler.JoinPath(opfFolder, file)
+	return err
 }
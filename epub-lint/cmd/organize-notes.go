@@ -2,22 +2,47 @@ package cmd
 
 import (
 	"archive/zip"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/MakeNowJust/heredoc"
 	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
 	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
 	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
 	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
 	"github.com/spf13/cobra"
 )
 
-var organizeNotesFlags = flags.Flags{
-	Flags: []flags.Flag{
-		flags.NewFileFlag(true, false, &epubFile, "file", "f", "", "the epub file to move translator's notes to their own file in", []string{"epub"}, true),
-	},
-}
+var (
+	noteStyle              string
+	convertFootnotes       bool
+	noteIndicators         string
+	noteFileName           string
+	noteHeading            string
+	noteBacklinkText       string
+	notesConfigFile        string
+	previewNotes           bool
+	ErrInvalidNoteFileName = errors.New("the note file name from note-file or the config file must be a file name ending in .xhtml or .html without any folders")
+	organizeNotesFlags     = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewFileFlag(true, false, &epubFile, "file", "f", "", "the epub file to move translator's notes to their own file in", []string{"epub"}, true),
+			flags.NewEnumFlag(false, false, &noteStyle, "style", "", string(linter.EndnotesStyle), "how to write out the notes", linter.NoteStyles),
+			flags.NewBoolFlag(false, false, &convertFootnotes, "convert-footnotes", "", false, "whether to also convert existing footnotes (superscript number links to a notes section) to the same structure as the translator's notes"),
+			flags.NewStringFlag(false, false, &noteIndicators, "indicators", "", "", `a comma separated list of the case insensitive text that starts a translator's note (defaults to "TL Note:", "Translator's Note:", "T/N:", "TN:", "TLN:", "TL:", "Author's Note:", "Note:", and "ED:")`),
+			flags.NewStringFlag(false, false, &noteFileName, "note-file", "", "", `the name of the file to move the notes to (defaults to "tl_notes.xhtml")`),
+			flags.NewStringFlag(false, false, &noteHeading, "heading", "", "", `the heading and TOC entry of the notes file (defaults to "Translator's Notes")`),
+			flags.NewStringFlag(false, false, &noteBacklinkText, "backlink-text", "", "", `the text of the link from a note back to its reference (defaults to "Back to Reference")`),
+			flags.NewFileFlag(false, false, &notesConfigFile, "config", "c", "", "the path to a json file with the indicators, note file name, heading, and backlink text to use which are overridden by any of those flags that are set", []string{"json"}, true),
+			flags.NewBoolFlag(false, false, &previewNotes, "preview", "p", false, "whether to list every translator's note that was found and ask which ones to exclude before moving them"),
+		},
+	}
+)
 
 // organizeNotesCmd represents the move translator's notes command
 var organizeNotesCmd = &cobra.Command{
@@ -25,16 +50,61 @@ var organizeNotesCmd = &cobra.Command{
 	Short: "Moves translator's notes to their own file at the end of the epub.",
 	Example: heredoc.Doc(`Finds all translator's notes and moves them to their own file if present
 	epub-lint organize-notes -f test.epub
+
+	Finds all translator's notes and moves them to their own file as popup footnotes
+	epub-lint organize-notes -f test.epub --style popup
+
+	Finds all translator's notes and existing footnotes and keeps them as popup footnotes right after where they are referenced
+	epub-lint organize-notes -f test.epub --style inline-aside --convert-footnotes
+
+	Finds all translator's notes for a Spanish translation and lets you exclude false positives before moving them
+	epub-lint organize-notes -f test.epub --indicators "N. del T.:,Nota:" --note-file notas.xhtml --heading "Notas del Traductor" --backlink-text "Volver" -p
+
+	Uses the note settings from a config file
+	epub-lint organize-notes -f test.epub -c notes.json
+
+	notes.json is expected to be in the following format:
+	{
+	  "indicators": ["N. del T.:", "Nota:"],
+	  "fileName": "notas.xhtml",
+	  "heading": "Notas del Traductor",
+	  "backlinkText": "Volver"
+	}
 	`),
-	Long: heredoc.Doc(`Goes through all of the content files and looks for "TL Note:", "Translator's Note:", "T/N:", or "Note:"
+	Long: heredoc.Doc(`Goes through all of the content files and looks for "TL Note:", "Translator's Note:", "T/N:", or "Note:" by default
 	and moves any matches to their own file with bidirectional linking between the footnote and its reference location.
 	It also adds an entry to the TOC and spine of the epub so the "tl_notes.xhtml" file is at the end of the file's contents.
+
+	The style determines how the notes are written out:
+	- endnotes: notes are a list in "tl_notes.xhtml" and are referenced with plain superscript links
+	- popup: notes are epub:type="footnote" asides in "tl_notes.xhtml" and are referenced with epub:type="noteref" links
+	so readers like Kobo, Apple Books, and KOReader can show them in a popup
+	- inline-aside: notes are epub:type="footnote" asides right after the paragraph they are referenced in,
+	so no "tl_notes.xhtml" file is created
+
+	When converting footnotes, superscript number links to a note in another part of the epub
+	(i.e. a link to "notes.xhtml#n1" with a superscript 1 as its text) have the note they link to removed from where it was
+	and are given the same structure as the translator's notes.
+
+	The indicators, note file name, heading, and backlink text can be set via flags or a json config file
+	with flags taking precedence over the config file. When previewing, every translator's note that was found is listed
+	with a number so that false positives (i.e. a normal sentence starting with "Note:") can be excluded before any notes are moved.
 `),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		return organizeNotesFlags.Validate()
+		err := organizeNotesFlags.Validate()
+		if err != nil {
+			return err
+		}
+
+		return validateNoteFileName(noteFileName)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-		err := moveTranslatorsNotes(epubFile)
+		noteSettings, err := getNoteSettings()
+		if err != nil {
+			logger.WriteFatal(err.Error())
+		}
+
+		err = moveTranslatorsNotes(epubFile, noteSettings)
 
 		if err != nil {
 			logger.WriteFatal(err.Error())
@@ -51,9 +121,9 @@ func init() {
 	}
 }
 
-func moveTranslatorsNotes(epubFile string) error {
+func moveTranslatorsNotes(epubFile string, noteSettings epubhandler.NoteSettings) error {
 	return epubhandler.UpdateEpub(epubFile, func(zipFiles map[string]*zip.File, w *zip.Writer, epubInfo epubhandler.EpubInfo, opfFolder string) ([]string, error) {
-		err := validateFilesExist(opfFolder, epubInfo.HtmlFiles, zipFiles)
+		err := epubhandler.ValidateFilesExist(opfFolder, zipFiles, epubInfo.HtmlFiles)
 		if err != nil {
 			return nil, err
 		}
@@ -85,11 +155,29 @@ func moveTranslatorsNotes(epubFile string) error {
 			navFilename = filepath.Join(opfFolder, navFilename)
 		}
 
-		numberOfTranslatorsNotes, err := epubhandler.MoveTranslatorsNotes(epubInfo.FilePathsInSpineOrder, opfFolder, ncxFilename, epubInfo.OpfFile, navFilename, nameToUpdatedContents, getFileContentsByName)
+		if previewNotes {
+			notePreviews, err := epubhandler.PreviewTranslatorsNotes(epubInfo.FilePathsInSpineOrder, opfFolder, noteSettings, getFileContentsByName)
+			if err != nil {
+				return nil, err
+			}
+
+			noteSettings.ExcludedNotes, err = getExcludedNotes(notePreviews)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		numberOfTranslatorsNotes, removedFiles, err := epubhandler.MoveTranslatorsNotes(epubInfo.FilePathsInSpineOrder, opfFolder, ncxFilename, epubInfo.OpfFile, navFilename, noteSettings, nameToUpdatedContents, getFileContentsByName)
 		if err != nil {
 			return nil, err
 		}
 
+		// removed files are handled by not writing them to the epub
+		for _, removedFile := range removedFiles {
+			handledFiles = append(handledFiles, removedFile)
+			logger.WriteInfof("Removed %q since all of its notes were moved.\n", removedFile)
+		}
+
 		for filename, updatedContents := range nameToUpdatedContents {
 			handledFiles = append(handledFiles, filename)
 
@@ -105,7 +193,11 @@ func moveTranslatorsNotes(epubFile string) error {
 				notesPluralization = ""
 			}
 
-			logger.WriteInfof("Found %d translator's note%s.\nAdding translator's notes file.\n", numberOfTranslatorsNotes, notesPluralization)
+			if linter.NoteStyle(noteStyle) == linter.InlineAsideStyle {
+				logger.WriteInfof("Found %d translator's note%s.\n", numberOfTranslatorsNotes, notesPluralization)
+			} else {
+				logger.WriteInfof("Found %d translator's note%s.\nAdding translator's notes file.\n", numberOfTranslatorsNotes, notesPluralization)
+			}
 		} else {
 			logger.WriteInfo("No translator's notes found.")
 		}
@@ -113,3 +205,92 @@ func moveTranslatorsNotes(epubFile string) error {
 		return handledFiles, nil
 	})
 }
+
+// getNoteSettings gets the note settings from the config file if one was provided
+// with any of the note flags that were set taking precedence over it
+func getNoteSettings() (epubhandler.NoteSettings, error) {
+	var noteSettings epubhandler.NoteSettings
+	if notesConfigFile != "" {
+		configContents, err := filehandler.ReadInFileContents(notesConfigFile)
+		if err != nil {
+			return noteSettings, err
+		}
+
+		err = json.Unmarshal([]byte(configContents), &noteSettings)
+		if err != nil {
+			return noteSettings, fmt.Errorf("failed to parse the note config file %q: %w", notesConfigFile, err)
+		}
+	}
+
+	noteSettings.Style = linter.NoteStyle(noteStyle)
+	noteSettings.ConvertPublisherNotes = convertFootnotes
+
+	if strings.TrimSpace(noteIndicators) != "" {
+		noteSettings.Indicators = strings.Split(noteIndicators, ",")
+	}
+
+	if noteFileName != "" {
+		noteSettings.FileName = noteFileName
+	}
+
+	if noteHeading != "" {
+		noteSettings.Heading = noteHeading
+	}
+
+	if noteBacklinkText != "" {
+		noteSettings.BacklinkText = noteBacklinkText
+	}
+
+	// the file name can come from the config file, so it needs to be validated again after the flags have been merged in
+	return noteSettings, validateNoteFileName(noteSettings.FileName)
+}
+
+// validateNoteFileName makes sure the note file name is just the name of an html file when one is provided
+// since it gets used as is when creating the translator's notes file
+func validateNoteFileName(fileName string) error {
+	var fileNameExt = strings.ToLower(filepath.Ext(fileName))
+	if fileName != "" && (strings.ContainsAny(fileName, `/\`) || (fileNameExt != ".xhtml" && fileNameExt != ".html")) {
+		return ErrInvalidNoteFileName
+	}
+
+	return nil
+}
+
+// getExcludedNotes lists the translator's notes that were found and asks which ones should be left alone
+func getExcludedNotes(notePreviews []epubhandler.NotePreview) (map[string]map[int]struct{}, error) {
+	if len(notePreviews) == 0 {
+		return nil, nil
+	}
+
+	var notesList strings.Builder
+	for i, notePreview := range notePreviews {
+		fmt.Fprintf(&notesList, "%d. %s: %s\n", i+1, notePreview.File, notePreview.Content)
+	}
+
+	logger.WriteInfo(notesList.String())
+
+	var response = logger.GetInputString("Enter the numbers of the notes to exclude separated by commas (leave blank to move all of them):")
+
+	return parseExcludedNotes(response, notePreviews)
+}
+
+func parseExcludedNotes(response string, notePreviews []epubhandler.NotePreview) (map[string]map[int]struct{}, error) {
+	var excludedNotes = make(map[string]map[int]struct{})
+	for _, value := range strings.FieldsFunc(response, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}) {
+		noteNumber, err := strconv.Atoi(value)
+		if err != nil || noteNumber < 1 || noteNumber > len(notePreviews) {
+			return nil, fmt.Errorf("%q is not a valid note number: it must be a number between 1 and %d", value, len(notePreviews))
+		}
+
+		var notePreview = notePreviews[noteNumber-1]
+		if _, ok := excludedNotes[notePreview.File]; !ok {
+			excludedNotes[notePreview.File] = make(map[int]struct{})
+		}
+
+		excludedNotes[notePreview.File][notePreview.Index] = struct{}{}
+	}
+
+	return excludedNotes, nil
+}
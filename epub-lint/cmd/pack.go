@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	packDir     string
+	packOutput  string
+	runPackLint bool
+	packFlags   = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewDirectoryFlag(true, false, &packDir, "directory", "d", "", "the folder with the epub contents to pack"),
+			flags.NewFileFlag(true, false, &packOutput, "output", "o", "", "the epub file to create or overwrite", []string{"epub"}, false),
+			flags.NewBoolFlag(false, false, &runPackLint, "lint", "", false, "whether or not to run the same linting that optimize does on the packed epub"),
+			flags.NewStringFlag(false, false, &lang, "lang", "l", "en", "the language to add to the xhtml, htm, or html files if the lang is not already specified when linting or \"auto\" to detect it"),
+			flags.NewStringFlag(false, false, &removableFileTypes, "remove-types", "", ".jpg,.jpeg,.png,.gif,.bmp,.js,.html,.htm,.xhtml,.txt,.css,.xml", "A comma separated list of file extensions of files to remove if they are not in the manifest when linting (i.e. '.jpeg,.jpg')"),
+			flags.NewBoolFlag(false, false, &runCompressImages, "compress", "c", false, "whether or not to also compress images when linting"),
+			flags.NewBoolFlag(false, false, &verbose, "verbose", "v", false, "whether or not to show extra logs like what files were removed from the epub when linting"),
+		},
+	}
+)
+
+// packCmd represents the pack command
+var packCmd = &cobra.Command{
+	Use:   "pack",
+	Short: "Zips up a folder of epub contents into an epub with the mimetype first and uncompressed",
+	Example: heredoc.Doc(`To pack a folder back into an epub:
+	epub-lint pack -d book-contents -o book.epub
+
+	To pack a folder and run the optimize linting on the result:
+	epub-lint pack -d book-contents -o book.epub --lint
+	`),
+	Long: heredoc.Doc(`Zips up the contents of the folder into an epub making sure that the mimetype is the first file
+	and is not compressed since generic zip tools tend to break that requirement.
+
+	When the folder was created by the unpack command, the files that were in the original epub are written in their
+	original order with their original compression method and timestamps. Any new files are added afterwards in alphabetical order.
+	This means that packing an unmodified folder gives a byte-identical epub when possible.
+
+	When lint is specified, the same linting that optimize does is run on the packed epub.
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return packFlags.Validate()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var (
+			epubContents bytes.Buffer
+			w            = zip.NewWriter(&epubContents)
+		)
+		err := epubhandler.PackEpub(packDir, w)
+		if err != nil {
+			logger.WriteFatalf("failed to pack %q: %s", packDir, err)
+		}
+
+		err = w.Close()
+		if err != nil {
+			logger.WriteFatalf("failed to finish writing %q: %s", packOutput, err)
+		}
+
+		err = filehandler.WriteBinaryFileContents(packOutput, epubContents.Bytes())
+		if err != nil {
+			logger.WriteFatal(err.Error())
+		}
+
+		if runPackLint {
+			var removableFileExts []string
+			if len(removableFileTypes) != 0 {
+				removableFileExts = strings.Split(removableFileTypes, ",")
+			}
+
+			err = LintEpub(filepath.Dir(packOutput), filepath.Base(packOutput), runCompressImages, verbose, removableFileExts)
+			if err != nil {
+				logger.WriteFatal(err.Error())
+			}
+
+			// the original is just the packed epub from before linting, so it is not worth keeping around
+			err = filehandler.DeleteFile(packOutput + ".original")
+			if err != nil {
+				logger.WriteFatal(err.Error())
+			}
+		}
+
+		logger.WriteInfof("Packed %q into %q.\n", packDir, packOutput)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(packCmd)
+
+	err := packFlags.AddToCmd(packCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/MakeNowJust/heredoc"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	pagelist "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/page-list"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pageMarkerPattern string
+	charactersPerPage int
+	pageListFlags     = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewFileFlag(true, false, &epubFile, "file", "f", "", "the epub file to generate the page list for", []string{"epub"}, true),
+			flags.NewStringFlag(false, false, &pageMarkerPattern, "pattern", "p", "", "a regex that matches page markers in the text where the first capture group is the page number (i.e. '\\[Page (\\d+)\\]')"),
+			flags.NewIntFlag(false, false, &charactersPerPage, "every", "e", 0, "the number of characters per page to use to synthesize page numbers when the epub has no page markers"),
+		},
+	}
+)
+
+// pageListCmd represents the page-list command
+var pageListCmd = &cobra.Command{
+	Use:   "page-list",
+	Short: "Normalizes the page markers in an epub and generates a page list for them in the nav and NCX files",
+	Example: heredoc.Doc(`To generate a page list from the existing page markers:
+	epub-lint page-list -f test.epub
+
+	To generate a page list from page numbers in the text like "[Page 12]":
+	epub-lint page-list -f test.epub -p '\[Page (\d+)\]'
+
+	To synthesize page numbers every 2000 characters for an epub without page markers:
+	epub-lint page-list -f test.epub -e 2000
+	`),
+	Long: heredoc.Doc(`Finds the page markers in the content files in reading order and normalizes them so that they
+	are pagebreak elements with an id and a label. Page markers are:
+	- Elements with an epub:type of pagebreak or a role of doc-pagebreak
+	- Empty elements with a page id like "page_12" or "pg12"
+	- Text that matches the pattern when one is provided
+
+	A page list is then generated in the nav file for epub 3 and in the NCX file when present which
+	is what readers use to show print page numbers. Any existing page list is replaced.
+
+	When every is specified and the epub has no page markers, a page marker is added before the word
+	that starts every set number of characters of text.
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		err := pageListFlags.Validate()
+		if err != nil {
+			return err
+		}
+
+		if pageMarkerPattern != "" {
+			_, err = regexp.Compile(pageMarkerPattern)
+			if err != nil {
+				return fmt.Errorf("pattern must be a valid regex: %w", err)
+			}
+		}
+
+		if charactersPerPage < 0 {
+			return errors.New("every must be greater than or equal to 0")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var (
+			updatedFiles = make(map[string]string)
+			markers      []pagelist.PageMarker
+		)
+		err := epubhandler.ReadEpub(epubFile, func(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string) error {
+			var (
+				navFile     = epubhandler.GetFilePath(opfFolder, epubInfo.NavFile)
+				spineOrder  []string
+				spineFiles  = make(map[string]string, len(epubInfo.FilePathsInSpineOrder))
+				normalizer  = pagelist.Normalizer{Version: epubInfo.Version}
+				synthesizer = pagelist.Synthesizer{Every: charactersPerPage, Version: epubInfo.Version}
+			)
+			if pageMarkerPattern != "" {
+				normalizer.Pattern = regexp.MustCompile(pageMarkerPattern)
+			}
+
+			for _, file := range epubInfo.FilePathsInSpineOrder {
+				var filePath = epubhandler.GetFilePath(opfFolder, file)
+				if epubInfo.NavFile != "" && filePath == navFile {
+					continue
+				}
+
+				zipFile, ok := zipFiles[filePath]
+				if !ok {
+					return fmt.Errorf("file from manifest not found: %q must exist", filePath)
+				}
+
+				contents, err := filehandler.ReadInZipFileContents(zipFile)
+				if err != nil {
+					return err
+				}
+
+				spineOrder = append(spineOrder, filePath)
+				spineFiles[filePath] = contents
+			}
+
+			for _, file := range spineOrder {
+				newContents, fileMarkers := normalizer.Normalize(file, spineFiles[file])
+				if newContents != spineFiles[file] {
+					spineFiles[file] = newContents
+					updatedFiles[file] = newContents
+				}
+
+				markers = append(markers, fileMarkers...)
+			}
+
+			if charactersPerPage != 0 {
+				if len(markers) != 0 {
+					return fmt.Errorf("found %d existing page marker(s), so page numbers will not be synthesized", len(markers))
+				}
+
+				for _, file := range spineOrder {
+					newContents, fileMarkers := synthesizer.Synthesize(file, spineFiles[file])
+					if newContents != spineFiles[file] {
+						spineFiles[file] = newContents
+						updatedFiles[file] = newContents
+					}
+
+					markers = append(markers, fileMarkers...)
+				}
+			}
+
+			if len(markers) == 0 {
+				return nil
+			}
+
+			if epubInfo.NavFile != "" {
+				navContents, err := filehandler.ReadInZipFileContents(zipFiles[navFile])
+				if err != nil {
+					return err
+				}
+
+				updatedFiles[navFile], err = pagelist.SetNavPageList(navContents, navFile, markers)
+				if err != nil {
+					return fmt.Errorf("failed to add the page list to %q: %w", navFile, err)
+				}
+			}
+
+			if epubInfo.NcxFile != "" {
+				var ncxFile = epubhandler.GetFilePath(opfFolder, epubInfo.NcxFile)
+				ncxContents, err := filehandler.ReadInZipFileContents(zipFiles[ncxFile])
+				if err != nil {
+					return err
+				}
+
+				updatedFiles[ncxFile], err = pagelist.SetNcxPageList(ncxContents, ncxFile, markers, pagelist.NewReadingOrder(spineOrder, spineFiles))
+				if err != nil {
+					return fmt.Errorf("failed to add the page list to %q: %w", ncxFile, err)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.WriteFatalf("failed to generate the page list for %q: %s", epubFile, err)
+		}
+
+		if len(markers) == 0 {
+			logger.WriteInfo("No page markers found. Use pattern to match page numbers in the text or every to synthesize page numbers.")
+
+			return
+		}
+
+		err = epubhandler.UpdateEpub(epubFile, func(zipFiles map[string]*zip.File, w *zip.Writer, epubInfo epubhandler.EpubInfo, opfFolder string) ([]string, error) {
+			var handledFiles = slices.Sorted(maps.Keys(updatedFiles))
+			for _, file := range handledFiles {
+				err := filehandler.WriteZipCompressedString(w, file, updatedFiles[file])
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return handledFiles, nil
+		})
+		if err != nil {
+			logger.WriteFatalf("failed to update %q: %s", epubFile, err)
+		}
+
+		logger.WriteInfof("Generated a page list with %d page(s) from %q to %q.\n", len(markers), markers[0].Label, markers[len(markers)-1].Label)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pageListCmd)
+
+	err := pageListFlags.AddToCmd(pageListCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
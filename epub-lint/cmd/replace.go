@@ -59,7 +59,7 @@ var replaceCmd = &cobra.Command{
 		}
 
 		err = epubhandler.UpdateEpub(epubFile, func(zipFiles map[string]*zip.File, w *zip.Writer, epubInfo epubhandler.EpubInfo, opfFolder string) ([]string, error) {
-			err = validateFilesExist(opfFolder, epubInfo.HtmlFiles, zipFiles)
+			err = epubhandler.ValidateFilesExist(opfFolder, zipFiles, epubInfo.HtmlFiles)
 			if err != nil {
 				return nil, err
 			}
@@ -67,7 +67,7 @@ var replaceCmd = &cobra.Command{
 			var handledFiles []string
 
 			for file := range epubInfo.HtmlFiles {
-				var filePath = getFilePath(opfFolder, file)
+				var filePath = epubhandler.GetFilePath(opfFolder, file)
 				zipFile := zipFiles[filePath]
 
 				fileText, err := filehandler.ReadInZipFileContents(zipFile)
@@ -75,7 +75,7 @@ var replaceCmd = &cobra.Command{
 					return nil, err
 				}
 
-				var newText = linter.CommonStringReplace(fileText)
+				var newText = linter.ApplyToLanguage(fileText, "", linter.LatinScriptLanguages, linter.CommonStringReplace)
 				newText = linter.ExtraStringReplace(newText, extraTextReplacements, numHits)
 
 				err = filehandler.WriteZipCompressedString(w, filePath, newText)
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/stats"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+const (
+	tableFormat = "table"
+	jsonFormat  = "json"
+)
+
+var (
+	statsDir                    string
+	statsFormat                 string
+	wordsPerMinute              int
+	ErrFileOrDirectoryRequired  = errors.New("either file or directory must be specified")
+	ErrFileAndDirectoryProvided = errors.New("only one of file or directory can be specified")
+	statsFlags                  = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewFileFlag(false, false, &epubFile, "file", "f", "", "the epub file to get the stats for", []string{"epub"}, true),
+			flags.NewDirectoryFlag(false, false, &statsDir, "directory", "d", "", "the folder of epub files to get the stats for"),
+			flags.NewEnumFlag(false, false, &statsFormat, "format", "", tableFormat, "the format to output the stats in", []string{tableFormat, jsonFormat}),
+			flags.NewIntFlag(false, false, &wordsPerMinute, "wpm", "", stats.DefaultWordsPerMinute, "the words per minute to use when estimating the reading time"),
+		},
+	}
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Reports the word counts, readability scores, and file size overhead of an epub or folder of epubs",
+	Example: heredoc.Doc(`To get the stats for a single epub:
+	epub-lint stats -f test.epub
+
+	To get the stats for all of the epubs in a folder as json:
+	epub-lint stats -d folder --format json
+
+	To estimate the reading time using a different reading speed:
+	epub-lint stats -f test.epub --wpm 300
+	`),
+	Long: heredoc.Doc(`Goes through the content files of the epub in spine order and reports the following for each
+	content file and the whole book:
+	- Word, character, and paragraph counts
+	- Average sentence length
+	- Dialogue ratio (the percentage of words inside of double quotes)
+	- Flesch reading ease and Flesch-Kincaid grade level
+	- Estimated reading time
+
+	It also reports the amount of images and their size along with the size of the css and font files.
+	When a directory is specified, a summary of the totals for each book is also included.
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		err := statsFlags.Validate()
+		if err != nil {
+			return err
+		}
+
+		var (
+			hasFile      = strings.TrimSpace(epubFile) != ""
+			hasDirectory = strings.TrimSpace(statsDir) != ""
+		)
+		if !hasFile && !hasDirectory {
+			return ErrFileOrDirectoryRequired
+		} else if hasFile && hasDirectory {
+			return ErrFileAndDirectoryProvided
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var epubs = []string{epubFile}
+		if strings.TrimSpace(statsDir) != "" {
+			epubNames, err := filehandler.GetAllFilesWithExtInASpecificFolder(statsDir, ".epub")
+			if err != nil {
+				logger.WriteFatal(err.Error())
+			}
+
+			epubs = make([]string, len(epubNames))
+			for i, epubName := range epubNames {
+				epubs[i] = filehandler.JoinPath(statsDir, epubName)
+			}
+		}
+
+		var bookStats = make([]stats.BookStats, 0, len(epubs))
+		for _, epub := range epubs {
+			err := epubhandler.ReadEpub(epub, func(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string) error {
+				currentStats, err := stats.GetBookStats(epub, zipFiles, epubInfo, opfFolder, wordsPerMinute)
+				if err != nil {
+					return err
+				}
+
+				bookStats = append(bookStats, currentStats)
+
+				return nil
+			})
+			if err != nil {
+				logger.WriteFatalf("failed to get stats for %q: %s", epub, err)
+			}
+		}
+
+		if statsFormat == jsonFormat {
+			jsonBytes, err := json.MarshalIndent(bookStats, "", "  ")
+			if err != nil {
+				logger.WriteFatalf("failed to convert stats to json: %s", err)
+			}
+
+			logger.WriteInfo(string(jsonBytes))
+
+			return
+		}
+
+		err := stats.WriteTable(os.Stdout, bookStats)
+		if err != nil {
+			logger.WriteFatalf("failed to write stats table: %s", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	err := statsFlags.AddToCmd(statsCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	unpackDir   string
+	unpackFlags = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewFileFlag(true, false, &epubFile, "file", "f", "", "the epub file to unpack", []string{"epub"}, true),
+			flags.NewStringFlag(false, false, &unpackDir, "output", "o", "", "the folder to unpack the epub into which must be empty or not exist (defaults to the name of the epub without its extension)"),
+		},
+	}
+)
+
+// unpackCmd represents the unpack command
+var unpackCmd = &cobra.Command{
+	Use:   "unpack",
+	Short: "Extracts the contents of an epub into a folder so they can be edited by hand",
+	Example: heredoc.Doc(`To unpack an epub into a folder named after it:
+	epub-lint unpack -f book.epub
+
+	To unpack an epub into a specific folder:
+	epub-lint unpack -f book.epub -o book-contents
+	`),
+	Long: heredoc.Doc(`Extracts all of the files in the epub into the specified folder.
+	A .epub-lint-pack.json file is also created in the folder to keep track of the order, compression method,
+	and timestamps of the files in the epub so that the pack command can recreate the epub as closely as possible.
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return unpackFlags.Validate()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var destDir = unpackDir
+		if strings.TrimSpace(destDir) == "" {
+			destDir = strings.TrimSuffix(epubFile, filepath.Ext(epubFile))
+		}
+
+		err := epubhandler.UnpackEpub(epubFile, destDir)
+		if err != nil {
+			logger.WriteFatalf("failed to unpack %q into %q: %s", epubFile, destDir, err)
+		}
+
+		logger.WriteInfof("Unpacked %q into %q.\n", epubFile, destDir)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unpackCmd)
+
+	err := unpackFlags.AddToCmd(unpackCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
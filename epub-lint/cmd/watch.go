@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/watch"
+	"github.com/pjkaufman/go-go-gadgets/pkg/cli/flags"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/pjkaufman/go-go-gadgets/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchDir      string
+	watchInterval int
+	watchFlags    = flags.Flags{
+		Flags: []flags.Flag{
+			flags.NewDirectoryFlag(true, false, &watchDir, "directory", "d", "", "the folder with the unpacked epub contents to watch"),
+			flags.NewIntFlag(false, false, &watchInterval, "interval", "i", 500, "how often to check for file changes in milliseconds"),
+		},
+	}
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watches an unpacked epub folder for changes and validates its structure on each change",
+	Example: heredoc.Doc(`To watch an unpacked epub for changes:
+	epub-lint unpack -f book.epub -o book
+	epub-lint watch -d book
+
+	To check for changes every 2 seconds:
+	epub-lint watch -d book -i 2000
+	`),
+	Long: heredoc.Doc(`Checks the unpacked epub folder for file changes and each time files are added, removed, or modified it:
+	- Re-parses the OPF file
+	- Checks that all of the files in the manifest exist
+	- Checks the content files for duplicate ids, broken internal links, and images missing alt text
+
+	All issues are listed when watching starts. After that, only the issues that are new or that were resolved
+	since the last check are listed. Press Ctrl+C to stop watching.
+	`),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		err := watchFlags.Validate()
+		if err != nil {
+			return err
+		}
+
+		if watchInterval <= 0 {
+			return fmt.Errorf("interval must be greater than 0, but was %d", watchInterval)
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		snapshot, err := watch.TakeSnapshot(watchDir)
+		if err != nil {
+			logger.WriteFatal(err.Error())
+		}
+
+		var findings = checkUnpackedEpub(watchDir, snapshot)
+		if len(findings) == 0 {
+			logger.WriteInfo("No issues found.")
+		} else {
+			for _, finding := range findings {
+				logger.WriteInfo(finding.String())
+			}
+		}
+
+		logger.WriteInfof("Watching %q for changes...\n", watchDir)
+
+		var (
+			ticker    = time.NewTicker(time.Duration(watchInterval) * time.Millisecond)
+			interrupt = make(chan os.Signal, 1)
+		)
+		defer ticker.Stop()
+		signal.Notify(interrupt, os.Interrupt)
+
+		for {
+			select {
+			case <-interrupt:
+				logger.WriteInfo("Stopped watching.")
+
+				return
+			case <-ticker.C:
+				currentSnapshot, err := watch.TakeSnapshot(watchDir)
+				if err != nil {
+					logger.WriteWarn(err.Error())
+
+					continue
+				}
+
+				var changedFiles = watch.GetChangedFiles(snapshot, currentSnapshot)
+				if len(changedFiles) == 0 {
+					continue
+				}
+
+				snapshot = currentSnapshot
+
+				var (
+					currentFindings = checkUnpackedEpub(watchDir, snapshot)
+					added, resolved = watch.DiffFindings(findings, currentFindings)
+				)
+				findings = currentFindings
+
+				logger.WriteInfof("\n[%s] Changed: %s\n", time.Now().Format(time.TimeOnly), strings.Join(changedFiles, ", "))
+
+				for _, finding := range resolved {
+					logger.WriteInfo("  fixed: " + finding.String())
+				}
+
+				for _, finding := range added {
+					logger.WriteWarn("  new:   " + finding.String())
+				}
+
+				if len(findings) == 0 {
+					logger.WriteInfo("No issues found.")
+				} else if len(added) == 0 && len(resolved) == 0 {
+					logger.WriteInfof("No change in issues (%d remaining).\n", len(findings))
+				} else {
+					logger.WriteInfof("%d issue(s) remaining.\n", len(findings))
+				}
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	err := watchFlags.AddToCmd(watchCmd)
+	if err != nil {
+		logger.WriteFatal(err.Error())
+	}
+}
+
+// checkUnpackedEpub validates the opf and manifest of the unpacked epub and runs structural checks on its content files
+func checkUnpackedEpub(dir string, snapshot watch.Snapshot) []structurecheck.Finding {
+	var (
+		files       = snapshot.Files()
+		opfFilename string
+	)
+	for file := range files {
+		if strings.HasSuffix(file, ".opf") && (opfFilename == "" || file < opfFilename) {
+			opfFilename = file
+		}
+	}
+
+	if opfFilename == "" {
+		return []structurecheck.Finding{{File: dir, Rule: structurecheck.ManifestFile, Message: "failed to find the opf file"}}
+	}
+
+	opfContents, err := filehandler.ReadInFileContents(filepath.Join(dir, opfFilename))
+	if err != nil {
+		return []structurecheck.Finding{{File: opfFilename, Rule: structurecheck.ManifestFile, Message: err.Error()}}
+	}
+
+	epubInfo, err := epubhandler.ParseOpfFile(opfContents, opfFilename)
+	if err != nil {
+		return []structurecheck.Finding{{File: opfFilename, Rule: structurecheck.ManifestFile, Message: err.Error()}}
+	}
+
+	var (
+		findings  []structurecheck.Finding
+		opfFolder = filehandler.GetFileFolder(opfFilename)
+	)
+	for _, manifestFiles := range []map[string]struct{}{epubInfo.HtmlFiles, epubInfo.ImagesFiles, epubInfo.CssFiles, epubInfo.OtherFiles} {
+		err = epubhandler.ValidateFilesExist(opfFolder, files, manifestFiles)
+		if err == nil {
+			continue
+		}
+
+		for _, missingFile := range strings.Split(err.Error(), "\n") {
+			findings = append(findings, structurecheck.Finding{File: opfFilename, Rule: structurecheck.ManifestFile, Message: missingFile})
+		}
+	}
+
+	var htmlFiles = make(map[string]string, len(epubInfo.HtmlFiles))
+	for file := range epubInfo.HtmlFiles {
+		var filePath = epubhandler.GetFilePath(opfFolder, file)
+		if _, exists := files[filePath]; !exists {
+			continue
+		}
+
+		contents, err := filehandler.ReadInFileContents(filepath.Join(dir, filePath))
+		if err != nil {
+			findings = append(findings, structurecheck.Finding{File: filePath, Rule: structurecheck.ManifestFile, Message: err.Error()})
+
+			continue
+		}
+
+		htmlFiles[filePath] = contents
+	}
+
+	return append(findings, structurecheck.CheckFiles(htmlFiles, files)...)
+}
@@ -0,0 +1,315 @@
+package a11y
+
+import (
+	"fmt"
+	"html"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/links"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+)
+
+const (
+	ImageAlt     = "image-alt"
+	Language     = "language"
+	HeadingOrder = "heading-order"
+	TableHeaders = "table-headers"
+	PageList     = "page-list"
+	ReadingOrder = "reading-order"
+)
+
+var (
+	imgTagRegex         = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	altAttrRegex        = regexp.MustCompile(`(?i)\salt\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	srcAttrRegex        = regexp.MustCompile(`(?i)\ssrc\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	presentationRegex   = regexp.MustCompile(`(?i)\srole\s*=\s*["'](?:presentation|none)["']`)
+	htmlTagRegex        = regexp.MustCompile(`(?i)<html\b[^>]*>`)
+	langAttrRegex       = regexp.MustCompile(`\slang\s*=\s*["'][^"']+["']`)
+	xmlLangAttrRegex    = regexp.MustCompile(`\sxml:lang\s*=\s*["'][^"']+["']`)
+	headingRegex        = regexp.MustCompile(`(?i)<h([1-6])\b`)
+	tableRegex          = regexp.MustCompile(`(?is)<table\b[^>]*>.*?</table>`)
+	tableHeaderRegex    = regexp.MustCompile(`(?i)<th\b`)
+	pageBreakRegex      = regexp.MustCompile(`(?i)(?:epub:type\s*=\s*["'][^"']*\bpagebreak\b|role\s*=\s*["']doc-pagebreak["'])`)
+	navPageListRegex    = regexp.MustCompile(`epub:type\s*=\s*["'][^"']*\bpage-list\b`)
+	ncxPageListRegex    = regexp.MustCompile(`<pageList\b`)
+	ncxNavMapRegex      = regexp.MustCompile(`(?s)<navMap\b.*?</navMap>`)
+	imageFileNameRegex  = regexp.MustCompile(`(?i)^[\w\-. ]+\.(?:jpe?g|png|gif|svg|webp|bmp)$`)
+	genericAltTextRegex = regexp.MustCompile(`(?i)^(?:image|img|picture|pic|photo|illustration|graphic|figure|insert|untitled|alt|\d+)$`)
+)
+
+// Image is an image that is missing meaningful alt text
+type Image struct {
+	// File is the path of the file the image is in relative to the root of the epub
+	File string
+	Src  string
+	// Alt is the current alt text of the image which is empty when the image has no alt attribute
+	Alt    string
+	HasAlt bool
+	// Start and End are the indexes of the start and end of the img tag in the file
+	Start, End   int
+	Line, Column int
+}
+
+// AltEdit gets the text edit that sets the alt text of the image.
+// An empty alt marks the image as decorative, so a presentation role is added when it is missing.
+func (i Image) AltEdit(contents, alt string) positions.TextEdit {
+	var (
+		newTag     = contents[i.Start:i.End]
+		escapedAlt = html.EscapeString(alt)
+	)
+	if indices := altAttrRegex.FindStringSubmatchIndex(newTag); indices != nil {
+		var valueStart, valueEnd = indices[2], indices[3]
+		if valueStart == -1 {
+			valueStart, valueEnd = indices[4], indices[5]
+		}
+
+		newTag = newTag[:valueStart] + escapedAlt + newTag[valueEnd:]
+	} else {
+		newTag = epubhandler.AddTagAttribute(newTag, `alt="`+escapedAlt+`"`)
+	}
+
+	if alt == "" && !presentationRegex.MatchString(newTag) {
+		newTag = epubhandler.AddTagAttribute(newTag, `role="presentation"`)
+	}
+
+	return positions.TextEdit{
+		Range: positions.Range{
+			Start: positions.IndexToPosition(contents, i.Start),
+			End:   positions.IndexToPosition(contents, i.End),
+		},
+		NewText: newTag,
+	}
+}
+
+// Report is the result of an accessibility audit of an epub
+type Report struct {
+	Findings []structurecheck.Finding
+	// Images are the images that are missing meaningful alt text
+	Images         []Image
+	ImageCount     int
+	HeadingCount   int
+	TableCount     int
+	PageBreakCount int
+	HasToc         bool
+	HasPageList    bool
+}
+
+// HasIssues returns whether any findings for the rule were found
+func (r Report) HasIssues(rule string) bool {
+	for _, finding := range r.Findings {
+		if finding.Rule == rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Audit checks the html files for images without meaningful alt text, missing language attributes,
+// skipped heading levels, and tables without headers. It also checks that a page list exists when there
+// are page break markers and that the table of contents is in the same order as the spine.
+// htmlFiles is the contents of the html files by their path relative to the root of the epub,
+// spineOrder is the path of each spine item relative to the root of the epub, and tocFile is the path
+// to the nav file for epub 3 or the ncx file for epub 2 which should be in htmlFiles.
+func Audit(htmlFiles map[string]string, spineOrder []string, tocFile string) Report {
+	var report Report
+	for _, file := range slices.Sorted(maps.Keys(htmlFiles)) {
+		var contents = htmlFiles[file]
+		if file == tocFile {
+			report.HasPageList = navPageListRegex.MatchString(contents) || ncxPageListRegex.MatchString(contents)
+
+			if strings.HasSuffix(file, ".ncx") {
+				continue
+			}
+		}
+
+		report.checkImages(file, contents)
+		report.checkLanguage(file, contents)
+		report.checkHeadings(file, contents)
+		report.checkTables(file, contents)
+		report.PageBreakCount += len(pageBreakRegex.FindAllStringIndex(contents, -1))
+	}
+
+	if report.PageBreakCount != 0 && !report.HasPageList {
+		report.Findings = append(report.Findings, structurecheck.Finding{
+			File:    tocFile,
+			Rule:    PageList,
+			Message: fmt.Sprintf("there are %d page break marker(s), but no page list", report.PageBreakCount),
+		})
+	}
+
+	if tocContents, ok := htmlFiles[tocFile]; ok {
+		report.checkReadingOrder(tocFile, tocContents, spineOrder)
+	}
+
+	structurecheck.SortFindings(report.Findings)
+
+	return report
+}
+
+func (r *Report) checkImages(file, contents string) {
+	for _, indices := range imgTagRegex.FindAllStringIndex(contents, -1) {
+		r.ImageCount++
+
+		var (
+			tag            = contents[indices[0]:indices[1]]
+			src            = epubhandler.GetTagAttributeValue(srcAttrRegex, tag)
+			alt            = epubhandler.GetTagAttributeValue(altAttrRegex, tag)
+			hasAlt         = altAttrRegex.MatchString(tag)
+			isDecorative   = hasAlt && strings.TrimSpace(alt) == "" && presentationRegex.MatchString(tag)
+			altDescription = getAltIssue(alt, src, hasAlt)
+		)
+		if isDecorative || altDescription == "" {
+			continue
+		}
+
+		var position = positions.IndexToPosition(contents, indices[0])
+		r.Images = append(r.Images, Image{
+			File:   file,
+			Src:    src,
+			Alt:    alt,
+			HasAlt: hasAlt,
+			Start:  indices[0],
+			End:    indices[1],
+			Line:   position.Line,
+			Column: position.Column,
+		})
+
+		r.addFinding(file, contents, indices[0], ImageAlt, fmt.Sprintf("img %q %s", src, altDescription))
+	}
+}
+
+func (r *Report) checkLanguage(file, contents string) {
+	var indices = htmlTagRegex.FindStringIndex(contents)
+	if indices == nil {
+		return
+	}
+
+	var tag = contents[indices[0]:indices[1]]
+	if !langAttrRegex.MatchString(tag) {
+		r.addFinding(file, contents, indices[0], Language, "html element is missing a lang attribute")
+	}
+
+	if !xmlLangAttrRegex.MatchString(tag) {
+		r.addFinding(file, contents, indices[0], Language, "html element is missing an xml:lang attribute")
+	}
+}
+
+func (r *Report) checkHeadings(file, contents string) {
+	var previousLevel int
+	for _, indices := range headingRegex.FindAllStringSubmatchIndex(contents, -1) {
+		r.HeadingCount++
+
+		level, _ := strconv.Atoi(contents[indices[2]:indices[3]])
+		if previousLevel != 0 && level > previousLevel+1 {
+			r.addFinding(file, contents, indices[0], HeadingOrder, fmt.Sprintf("heading level skips from h%d to h%d", previousLevel, level))
+		}
+
+		previousLevel = level
+	}
+}
+
+func (r *Report) checkTables(file, contents string) {
+	for _, indices := range tableRegex.FindAllStringIndex(contents, -1) {
+		r.TableCount++
+
+		var table = contents[indices[0]:indices[1]]
+		if presentationRegex.MatchString(table[:strings.Index(table, ">")]) || tableHeaderRegex.MatchString(table) {
+			continue
+		}
+
+		r.addFinding(file, contents, indices[0], TableHeaders, "table has no header cells")
+	}
+}
+
+func (r *Report) checkReadingOrder(tocFile, tocContents string, spineOrder []string) {
+	var (
+		tocStart, tocEnd = getTocBounds(tocContents)
+		spineIndexes     = make(map[string]int, len(spineOrder))
+		previousIndex    = -1
+		previousTarget   string
+	)
+	if tocStart == -1 {
+		return
+	}
+
+	r.HasToc = true
+
+	for i, file := range spineOrder {
+		if _, exists := spineIndexes[file]; !exists {
+			spineIndexes[file] = i
+		}
+	}
+
+	for _, link := range links.GetLinks(tocFile, tocContents) {
+		if link.Start < tocStart || link.Start > tocEnd {
+			continue
+		}
+
+		var (
+			target, _   = links.ResolveLink(tocFile, link.Value)
+			index, isOk = spineIndexes[target]
+		)
+		if !isOk {
+			r.addFinding(tocFile, tocContents, link.Start, ReadingOrder, fmt.Sprintf("table of contents entry %q is not in the spine", link.Value))
+
+			continue
+		}
+
+		if index < previousIndex {
+			r.addFinding(tocFile, tocContents, link.Start, ReadingOrder, fmt.Sprintf("table of contents entry %q comes before %q in the spine, but after it in the table of contents", target, previousTarget))
+
+			continue
+		}
+
+		previousIndex = index
+		previousTarget = target
+	}
+}
+
+func (r *Report) addFinding(file, contents string, index int, rule, message string) {
+	var position = positions.IndexToPosition(contents, index)
+
+	r.Findings = append(r.Findings, structurecheck.Finding{
+		File:    file,
+		Line:    position.Line,
+		Column:  position.Column,
+		Rule:    rule,
+		Message: message,
+	})
+}
+
+// getTocBounds gets the start and end indexes of the table of contents in the nav or ncx file
+func getTocBounds(tocContents string) (int, int) {
+	if indices := ncxNavMapRegex.FindStringIndex(tocContents); indices != nil {
+		return indices[0], indices[1]
+	}
+
+	return epubhandler.GetNavTOCContentPositionInfo(tocContents)
+}
+
+// getAltIssue describes why the alt text is not meaningful returning an empty string when it is meaningful
+func getAltIssue(alt, src string, hasAlt bool) string {
+	if !hasAlt {
+		return "is missing an alt attribute"
+	}
+
+	alt = strings.TrimSpace(alt)
+	switch {
+	case alt == "":
+		return `has an empty alt attribute without role="presentation", so it is unclear if it is decorative`
+	case strings.EqualFold(alt, path.Base(src)), strings.EqualFold(alt, strings.TrimSuffix(path.Base(src), path.Ext(src))), imageFileNameRegex.MatchString(alt):
+		return fmt.Sprintf("has alt text %q that is a file name", alt)
+	case genericAltTextRegex.MatchString(alt):
+		return fmt.Sprintf("has generic alt text %q", alt)
+	}
+
+	return ""
+}
@@ -0,0 +1,93 @@
+//go:build unit
+
+package a11y_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/a11y"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	auditNav = `<html xmlns="http://www.w3.org/1999/xhtml" lang="en" xml:lang="en">
+<body>
+<nav epub:type="toc"><ol>
+<li><a href="Text/chapter2.xhtml">Chapter 2</a></li>
+<li><a href="Text/chapter1.xhtml#start">Chapter 1</a></li>
+<li><a href="Text/extra.xhtml">Extra</a></li>
+</ol></nav>
+</body>
+</html>`
+	auditChapterOne = `<html xmlns="http://www.w3.org/1999/xhtml" lang="en">
+<body>
+<h1 id="start">Chapter 1</h1>
+<h3>Skipped</h3>
+<img src="../Images/map.jpg"/>
+<img src="../Images/ship.jpg" alt="ship.jpg"/>
+<img src="../Images/divider.png" alt="" role="presentation"/>
+<span epub:type="pagebreak" id="page1"/>
+</body>
+</html>`
+	auditChapterTwo = `<html xmlns="http://www.w3.org/1999/xhtml" lang="en" xml:lang="en">
+<body>
+<h2>Chapter 2</h2>
+<h3>Section</h3>
+<img src="../Images/hero.jpg" alt="The hero holding a sword"/>
+<img src="../Images/villain.jpg" alt=""/>
+<table><tr><td>1</td></tr></table>
+<table><tr><th>Name</th></tr></table>
+</body>
+</html>`
+)
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+
+	var report = a11y.Audit(map[string]string{
+		"OEBPS/nav.xhtml":           auditNav,
+		"OEBPS/Text/chapter1.xhtml": auditChapterOne,
+		"OEBPS/Text/chapter2.xhtml": auditChapterTwo,
+	}, []string{"OEBPS/Text/chapter1.xhtml", "OEBPS/Text/chapter2.xhtml"}, "OEBPS/nav.xhtml")
+
+	assert.Equal(t, []structurecheck.Finding{
+		{File: "OEBPS/Text/chapter1.xhtml", Line: 1, Column: 1, Rule: a11y.Language, Message: "html element is missing an xml:lang attribute"},
+		{File: "OEBPS/Text/chapter1.xhtml", Line: 4, Column: 1, Rule: a11y.HeadingOrder, Message: "heading level skips from h1 to h3"},
+		{File: "OEBPS/Text/chapter1.xhtml", Line: 5, Column: 1, Rule: a11y.ImageAlt, Message: `img "../Images/map.jpg" is missing an alt attribute`},
+		{File: "OEBPS/Text/chapter1.xhtml", Line: 6, Column: 1, Rule: a11y.ImageAlt, Message: `img "../Images/ship.jpg" has alt text "ship.jpg" that is a file name`},
+		{File: "OEBPS/Text/chapter2.xhtml", Line: 6, Column: 1, Rule: a11y.ImageAlt, Message: `img "../Images/villain.jpg" has an empty alt attribute without role="presentation", so it is unclear if it is decorative`},
+		{File: "OEBPS/Text/chapter2.xhtml", Line: 7, Column: 1, Rule: a11y.TableHeaders, Message: "table has no header cells"},
+		{File: "OEBPS/nav.xhtml", Rule: a11y.PageList, Message: "there are 1 page break marker(s), but no page list"},
+		{File: "OEBPS/nav.xhtml", Line: 5, Column: 14, Rule: a11y.ReadingOrder, Message: `table of contents entry "OEBPS/Text/chapter1.xhtml" comes before "OEBPS/Text/chapter2.xhtml" in the spine, but after it in the table of contents`},
+		{File: "OEBPS/nav.xhtml", Line: 6, Column: 14, Rule: a11y.ReadingOrder, Message: `table of contents entry "Text/extra.xhtml" is not in the spine`},
+	}, report.Findings)
+
+	assert.Equal(t, 5, report.ImageCount)
+	assert.Equal(t, 4, report.HeadingCount)
+	assert.Equal(t, 2, report.TableCount)
+	assert.Equal(t, 1, report.PageBreakCount)
+	assert.True(t, report.HasToc)
+	assert.False(t, report.HasPageList)
+	assert.Len(t, report.Images, 3)
+}
+
+func TestImageAltEdit(t *testing.T) {
+	t.Parallel()
+
+	var report = a11y.Audit(map[string]string{
+		"OEBPS/Text/chapter1.xhtml": auditChapterOne,
+	}, nil, "")
+	require.Len(t, report.Images, 2)
+
+	updatedChapter, err := positions.ApplyEdits("OEBPS/Text/chapter1.xhtml", auditChapterOne, []positions.TextEdit{
+		report.Images[0].AltEdit(auditChapterOne, `A map of "the" island`),
+		report.Images[1].AltEdit(auditChapterOne, ""),
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, updatedChapter, `<img src="../Images/map.jpg" alt="A map of &#34;the&#34; island"/>`)
+	assert.Contains(t, updatedChapter, `<img src="../Images/ship.jpg" alt="" role="presentation"/>`)
+}
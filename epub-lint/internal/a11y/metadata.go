@@ -0,0 +1,206 @@
+package a11y
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+)
+
+const (
+	UnknownHazard          = "unknown"
+	none                   = "none"
+	schemaPrefix           = "schema:"
+	accessModeProperty     = "accessMode"
+	accessModeSuffProperty = "accessModeSufficient"
+	featureProperty        = "accessibilityFeature"
+	hazardProperty         = "accessibilityHazard"
+	summaryProperty        = "accessibilitySummary"
+	textualMode            = "textual"
+	visualMode             = "visual"
+	textualAndVisual       = textualMode + "," + visualMode
+)
+
+var (
+	// Hazards are the allowed values for the accessibilityHazard metadata
+	Hazards = []string{
+		none, UnknownHazard,
+		"flashing", "noFlashingHazard", "unknownFlashingHazard",
+		"motionSimulation", "noMotionSimulationHazard", "unknownMotionSimulationHazard",
+		"sound", "noSoundHazard", "unknownSoundHazard",
+	}
+	ErrNoMetadataEnd = errors.New("metadata is incorrectly formatted since it has no closing metadata element")
+)
+
+// Metadata is the schema.org accessibility metadata of an epub
+type Metadata struct {
+	AccessModes           []string
+	AccessModesSufficient []string
+	Features              []string
+	Hazards               []string
+	Summary               string
+}
+
+// GetMetadata determines the schema.org accessibility metadata of the epub based on the audit report.
+// Hazards can not be determined from the contents of the epub, so they need to be provided and default
+// to unknown when there are none. When summary is empty, one is generated from the report.
+func GetMetadata(report Report, hazards []string, summary string) Metadata {
+	var metadata = Metadata{
+		AccessModes:           []string{textualMode},
+		AccessModesSufficient: []string{textualMode},
+		Hazards:               hazards,
+		Summary:               summary,
+	}
+
+	if len(metadata.Hazards) == 0 {
+		metadata.Hazards = []string{UnknownHazard}
+	}
+
+	var hasAllAltText = len(report.Images) == 0
+	if report.ImageCount != 0 {
+		metadata.AccessModes = append(metadata.AccessModes, visualMode)
+		metadata.AccessModesSufficient = []string{textualAndVisual}
+
+		if hasAllAltText {
+			metadata.AccessModesSufficient = append(metadata.AccessModesSufficient, textualMode)
+			metadata.Features = append(metadata.Features, "alternativeText")
+		}
+	}
+
+	var (
+		hasStructuredHeadings = report.HeadingCount != 0 && !report.HasIssues(HeadingOrder)
+		hasReadingOrder       = report.HasToc && !report.HasIssues(ReadingOrder)
+	)
+	if report.HasToc {
+		metadata.Features = append(metadata.Features, "tableOfContents")
+	}
+
+	if hasReadingOrder {
+		metadata.Features = append(metadata.Features, "readingOrder")
+	}
+
+	if hasStructuredHeadings {
+		metadata.Features = append(metadata.Features, "structuralNavigation")
+	}
+
+	if report.PageBreakCount != 0 {
+		metadata.Features = append(metadata.Features, "pageBreakMarkers")
+	}
+
+	if report.HasPageList {
+		metadata.Features = append(metadata.Features, "pageNavigation", "printPageNumbers")
+	}
+
+	if len(metadata.Features) == 0 {
+		metadata.Features = []string{none}
+	}
+
+	if metadata.Summary == "" {
+		metadata.Summary = getSummary(report, metadata.Hazards, hasAllAltText, hasStructuredHeadings)
+	}
+
+	return metadata
+}
+
+// SetOpfMetadata replaces any existing schema.org accessibility metadata in the opf with the provided metadata.
+// Epub 3 uses meta elements with a property attribute while epub 2 uses meta elements with name and content attributes.
+func SetOpfMetadata(opfContents string, metadata Metadata, version int) (string, error) {
+	opf, err := epubdoc.ParseOpf(opfContents)
+	if err != nil {
+		return opfContents, err
+	}
+
+	var metadataEl = opf.Metadata()
+	if metadataEl == nil || metadataEl.Unclosed() {
+		return opfContents, ErrNoMetadataEnd
+	}
+
+	for _, meta := range opf.MetadataElements("meta") {
+		if isAccessibilityMeta(meta) {
+			meta.Remove()
+		}
+	}
+
+	var addMetaElements = func(property string, values ...string) {
+		for _, value := range values {
+			var meta *epubdoc.Node
+			if version == 2 {
+				meta = epubdoc.NewElement("meta", epubdoc.Attr{Name: "name", Value: schemaPrefix + property}, epubdoc.Attr{Name: "content", Value: value})
+			} else {
+				meta = epubdoc.NewElement("meta", epubdoc.Attr{Name: "property", Value: schemaPrefix + property})
+				meta.SetText(value)
+			}
+
+			metadataEl.AppendIndentedChild(meta)
+		}
+	}
+
+	addMetaElements(accessModeProperty, metadata.AccessModes...)
+	addMetaElements(accessModeSuffProperty, metadata.AccessModesSufficient...)
+	addMetaElements(featureProperty, metadata.Features...)
+	addMetaElements(hazardProperty, metadata.Hazards...)
+	addMetaElements(summaryProperty, metadata.Summary)
+
+	return opf.String(), nil
+}
+
+// isAccessibilityMeta returns whether the meta element is schema.org accessibility metadata in either the epub 2 or epub 3 format
+func isAccessibilityMeta(meta *epubdoc.Node) bool {
+	for _, attr := range []string{"property", "name"} {
+		if strings.HasPrefix(meta.AttrValue(attr), schemaPrefix+"access") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getSummary(report Report, hazards []string, hasAllAltText, hasStructuredHeadings bool) string {
+	var features []string
+	if report.HasToc {
+		features = append(features, "a table of contents")
+	}
+
+	if hasStructuredHeadings {
+		features = append(features, "structured headings")
+	}
+
+	if report.ImageCount != 0 && hasAllAltText {
+		features = append(features, "alternative text for all images")
+	}
+
+	if report.HasPageList {
+		features = append(features, "a page list")
+	}
+
+	var summary strings.Builder
+	if len(features) == 0 {
+		summary.WriteString("This publication has no additional accessibility features.")
+	} else {
+		summary.WriteString("This publication includes ")
+		if len(features) == 1 {
+			summary.WriteString(features[0])
+		} else {
+			summary.WriteString(strings.Join(features[:len(features)-1], ", "))
+			if len(features) > 2 {
+				summary.WriteString(",")
+			}
+
+			summary.WriteString(" and ")
+			summary.WriteString(features[len(features)-1])
+		}
+
+		summary.WriteString(".")
+	}
+
+	if report.ImageCount != 0 && !hasAllAltText {
+		summary.WriteString(" Some images do not have meaningful alternative text.")
+	}
+
+	if slices.Contains(hazards, UnknownHazard) {
+		summary.WriteString(" It has not been checked for hazards.")
+	}
+
+	return summary.String()
+}
@@ -0,0 +1,163 @@
+//go:build unit
+
+package a11y_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/a11y"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type getMetadataTestCase struct {
+	report           a11y.Report
+	hazards          []string
+	summary          string
+	expectedMetadata a11y.Metadata
+}
+
+var getMetadataTestCases = map[string]getMetadataTestCase{
+	"text only epub with no toc or headings has no features and unknown hazards": {
+		expectedMetadata: a11y.Metadata{
+			AccessModes:           []string{"textual"},
+			AccessModesSufficient: []string{"textual"},
+			Features:              []string{"none"},
+			Hazards:               []string{"unknown"},
+			Summary:               "This publication has no additional accessibility features. It has not been checked for hazards.",
+		},
+	},
+	"epub with images that all have alt text and a page list has all of the features": {
+		report: a11y.Report{
+			ImageCount:     2,
+			HeadingCount:   3,
+			PageBreakCount: 10,
+			HasToc:         true,
+			HasPageList:    true,
+		},
+		hazards: []string{"none"},
+		expectedMetadata: a11y.Metadata{
+			AccessModes:           []string{"textual", "visual"},
+			AccessModesSufficient: []string{"textual,visual", "textual"},
+			Features:              []string{"alternativeText", "tableOfContents", "readingOrder", "structuralNavigation", "pageBreakMarkers", "pageNavigation", "printPageNumbers"},
+			Hazards:               []string{"none"},
+			Summary:               "This publication includes a table of contents, structured headings, alternative text for all images, and a page list.",
+		},
+	},
+	"epub with images missing alt text and reading order issues does not claim those features": {
+		report: a11y.Report{
+			Findings: []structurecheck.Finding{
+				{Rule: a11y.ReadingOrder},
+				{Rule: a11y.HeadingOrder},
+			},
+			Images:       []a11y.Image{{Src: "cover.jpg"}},
+			ImageCount:   1,
+			HeadingCount: 1,
+			HasToc:       true,
+		},
+		hazards: []string{"noFlashingHazard", "noSoundHazard"},
+		summary: "A custom summary.",
+		expectedMetadata: a11y.Metadata{
+			AccessModes:           []string{"textual", "visual"},
+			AccessModesSufficient: []string{"textual,visual"},
+			Features:              []string{"tableOfContents"},
+			Hazards:               []string{"noFlashingHazard", "noSoundHazard"},
+			Summary:               "A custom summary.",
+		},
+	},
+}
+
+func TestGetMetadata(t *testing.T) {
+	for name, args := range getMetadataTestCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, args.expectedMetadata, a11y.GetMetadata(args.report, args.hazards, args.summary))
+		})
+	}
+}
+
+type setOpfMetadataTestCase struct {
+	opfContents      string
+	version          int
+	expectedContents string
+	expectedErr      error
+}
+
+var (
+	testMetadata = a11y.Metadata{
+		AccessModes:           []string{"textual"},
+		AccessModesSufficient: []string{"textual"},
+		Features:              []string{"tableOfContents"},
+		Hazards:               []string{"none"},
+		Summary:               "Includes a table of contents & headings.",
+	}
+	setOpfMetadataTestCases = map[string]setOpfMetadataTestCase{
+		"epub 3 metadata is added before the end of the metadata and replaces existing accessibility metadata": {
+			opfContents: `<package version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>Test</dc:title>
+    <meta property="schema:accessMode">visual</meta>
+    <meta property="schema:accessibilitySummary">Old summary.</meta>
+    <meta property="dcterms:modified">2024-01-01T00:00:00Z</meta>
+  </metadata>
+</package>`,
+			version: 3,
+			expectedContents: `<package version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>Test</dc:title>
+    <meta property="dcterms:modified">2024-01-01T00:00:00Z</meta>
+    <meta property="schema:accessMode">textual</meta>
+    <meta property="schema:accessModeSufficient">textual</meta>
+    <meta property="schema:accessibilityFeature">tableOfContents</meta>
+    <meta property="schema:accessibilityHazard">none</meta>
+    <meta property="schema:accessibilitySummary">Includes a table of contents &amp; headings.</meta>
+  </metadata>
+</package>`,
+		},
+		"epub 2 metadata uses name and content attributes": {
+			opfContents: `<package version="2.0">
+	<metadata>
+		<dc:title>Test</dc:title>
+		<meta name="schema:accessibilityHazard" content="unknown"/>
+	</metadata>
+</package>`,
+			version: 2,
+			expectedContents: `<package version="2.0">
+	<metadata>
+		<dc:title>Test</dc:title>
+		<meta name="schema:accessMode" content="textual"/>
+		<meta name="schema:accessModeSufficient" content="textual"/>
+		<meta name="schema:accessibilityFeature" content="tableOfContents"/>
+		<meta name="schema:accessibilityHazard" content="none"/>
+		<meta name="schema:accessibilitySummary" content="Includes a table of contents &amp; headings."/>
+	</metadata>
+</package>`,
+		},
+		"metadata on a single line is added without new lines": {
+			opfContents:      `<package version="3.0"><metadata><dc:title>Test</dc:title></metadata></package>`,
+			version:          3,
+			expectedContents: `<package version="3.0"><metadata><dc:title>Test</dc:title><meta property="schema:accessMode">textual</meta><meta property="schema:accessModeSufficient">textual</meta><meta property="schema:accessibilityFeature">tableOfContents</meta><meta property="schema:accessibilityHazard">none</meta><meta property="schema:accessibilitySummary">Includes a table of contents &amp; headings.</meta></metadata></package>`,
+		},
+		"missing end of metadata results in an error": {
+			opfContents:      `<package version="3.0"><metadata><dc:title>Test</dc:title></package>`,
+			version:          3,
+			expectedContents: `<package version="3.0"><metadata><dc:title>Test</dc:title></package>`,
+			expectedErr:      a11y.ErrNoMetadataEnd,
+		},
+	}
+)
+
+func TestSetOpfMetadata(t *testing.T) {
+	for name, args := range setOpfMetadataTestCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := a11y.SetOpfMetadata(args.opfContents, testMetadata, args.version)
+			if args.expectedErr != nil {
+				require.ErrorIs(t, err, args.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, args.expectedContents, actual)
+		})
+	}
+}
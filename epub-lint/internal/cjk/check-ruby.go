@@ -0,0 +1,169 @@
+package cjk
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+)
+
+const (
+	RubyMissingRt   = "ruby-missing-rt"
+	RubyMissingBase = "ruby-missing-base"
+	RubyEmptyRt     = "ruby-empty-rt"
+	RubyRp          = "ruby-rp"
+	RubyMisplaced   = "ruby-misplaced"
+	RubyUnclosed    = "ruby-unclosed"
+)
+
+var (
+	rubyTagRegex = regexp.MustCompile(`(?i)<(/?)(ruby|rtc|rt|rp|rb)\b[^>]*?(/?)>`)
+)
+
+type rubyElement struct {
+	start        int
+	rtCount      int
+	rpCount      int
+	hasBase      bool
+	child        string
+	childStart   int
+	childHasText bool
+}
+
+// CheckRuby checks the ruby annotations in the file for ruby elements without an rt or base text, empty rt elements,
+// rp elements that do not wrap each rt, rt, rp, and rb elements outside of a ruby element, and elements that are not closed
+func CheckRuby(file, contents string) []structurecheck.Finding {
+	var (
+		findings []structurecheck.Finding
+		rubies   []*rubyElement
+		lastEnd  int
+	)
+	for _, indices := range rubyTagRegex.FindAllStringSubmatchIndex(contents, -1) {
+		var (
+			isClosing     = indices[3] != indices[2]
+			name          = strings.ToLower(contents[indices[4]:indices[5]])
+			isSelfClosing = indices[7] != indices[6]
+			ruby          *rubyElement
+		)
+		if len(rubies) != 0 {
+			ruby = rubies[len(rubies)-1]
+			if hasText(contents[lastEnd:indices[0]]) {
+				switch ruby.child {
+				case "", "rb":
+					ruby.hasBase = true
+				default:
+					ruby.childHasText = true
+				}
+			}
+		}
+
+		lastEnd = indices[1]
+
+		if name == "ruby" {
+			if isClosing {
+				if ruby == nil {
+					findings = append(findings, structurecheck.NewFinding(file, contents, indices[0], RubyMisplaced, "closing ruby tag has no opening ruby tag"))
+
+					continue
+				}
+
+				findings = ruby.checkUnclosedChild(file, contents, findings)
+				if ruby.rtCount == 0 {
+					findings = append(findings, structurecheck.NewFinding(file, contents, ruby.start, RubyMissingRt, "ruby has no rt element"))
+				}
+
+				if ruby.rpCount != 0 && ruby.rpCount != 2*ruby.rtCount {
+					findings = append(findings, structurecheck.NewFinding(file, contents, ruby.start, RubyRp, fmt.Sprintf("ruby has %d rp element(s) for %d rt element(s), but each rt should have an rp before and after it", ruby.rpCount, ruby.rtCount)))
+				}
+
+				rubies = rubies[:len(rubies)-1]
+				if len(rubies) != 0 && (rubies[len(rubies)-1].child == "" || rubies[len(rubies)-1].child == "rb") {
+					// a nested ruby is the base text of the ruby it is in
+					rubies[len(rubies)-1].hasBase = true
+				}
+			} else if !isSelfClosing {
+				rubies = append(rubies, &rubyElement{start: indices[0]})
+			}
+
+			continue
+		}
+
+		if name == "rtc" {
+			continue
+		}
+
+		if ruby == nil {
+			if !isClosing {
+				findings = append(findings, structurecheck.NewFinding(file, contents, indices[0], RubyMisplaced, fmt.Sprintf("%s is not inside of a ruby element", name)))
+			}
+
+			continue
+		}
+
+		if isClosing {
+			if ruby.child != name {
+				continue
+			}
+
+			if name == "rt" && !ruby.childHasText {
+				findings = append(findings, structurecheck.NewFinding(file, contents, ruby.childStart, RubyEmptyRt, "rt is empty"))
+			}
+
+			ruby.child = ""
+
+			continue
+		}
+
+		findings = ruby.checkUnclosedChild(file, contents, findings)
+
+		switch name {
+		case "rt":
+			ruby.rtCount++
+			if !ruby.hasBase {
+				findings = append(findings, structurecheck.NewFinding(file, contents, indices[0], RubyMissingBase, "rt has no base text before it"))
+			}
+
+			ruby.hasBase = false
+		case "rp":
+			ruby.rpCount++
+		}
+
+		if isSelfClosing {
+			if name == "rt" {
+				findings = append(findings, structurecheck.NewFinding(file, contents, indices[0], RubyEmptyRt, "rt is empty"))
+			}
+
+			continue
+		}
+
+		ruby.child = name
+		ruby.childStart = indices[0]
+		ruby.childHasText = false
+	}
+
+	for _, ruby := range rubies {
+		findings = append(findings, structurecheck.NewFinding(file, contents, ruby.start, RubyUnclosed, "ruby is not closed"))
+	}
+
+	structurecheck.SortFindings(findings)
+
+	return findings
+}
+
+// checkUnclosedChild adds a finding when the current child of the ruby element was not closed before the next element started
+func (r *rubyElement) checkUnclosedChild(file, contents string, findings []structurecheck.Finding) []structurecheck.Finding {
+	if r.child == "" {
+		return findings
+	}
+
+	findings = append(findings, structurecheck.NewFinding(file, contents, r.childStart, RubyUnclosed, fmt.Sprintf("%s is not closed", r.child)))
+	r.child = ""
+
+	return findings
+}
+
+func hasText(contents string) bool {
+	return strings.TrimSpace(linter.HtmlTagRegex.ReplaceAllString(contents, "")) != ""
+}
@@ -0,0 +1,77 @@
+//go:build unit
+
+package cjk_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/cjk"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/stretchr/testify/assert"
+)
+
+type checkRubyTestCase struct {
+	contents         string
+	expectedFindings []structurecheck.Finding
+}
+
+var checkRubyTestCases = map[string]checkRubyTestCase{
+	"valid ruby with and without rp elements has no findings": {
+		contents: `<p><ruby>漢<rp>(</rp><rt>かん</rt><rp>)</rp>字<rp>(</rp><rt>じ</rt><rp>)</rp></ruby>と<ruby><rb>東京</rb><rt>とうきょう</rt></ruby></p>`,
+	},
+	"ruby without an rt is a finding": {
+		contents: `<p><ruby>漢字</ruby></p>`,
+		expectedFindings: []structurecheck.Finding{
+			{File: "a.xhtml", Line: 1, Column: 4, Rule: cjk.RubyMissingRt, Message: "ruby has no rt element"},
+		},
+	},
+	"rt without base text and empty rt are findings": {
+		contents: `<ruby><rt>かん</rt></ruby>
+<ruby>字<rt></rt><rt/></ruby>`,
+		expectedFindings: []structurecheck.Finding{
+			{File: "a.xhtml", Line: 1, Column: 7, Rule: cjk.RubyMissingBase, Message: "rt has no base text before it"},
+			{File: "a.xhtml", Line: 2, Column: 8, Rule: cjk.RubyEmptyRt, Message: "rt is empty"},
+			{File: "a.xhtml", Line: 2, Column: 17, Rule: cjk.RubyMissingBase, Message: "rt has no base text before it"},
+			{File: "a.xhtml", Line: 2, Column: 17, Rule: cjk.RubyEmptyRt, Message: "rt is empty"},
+		},
+	},
+	"rp that does not wrap each rt is a finding": {
+		contents: `<ruby>漢<rp>(</rp><rt>かん</rt></ruby>`,
+		expectedFindings: []structurecheck.Finding{
+			{File: "a.xhtml", Line: 1, Column: 1, Rule: cjk.RubyRp, Message: "ruby has 1 rp element(s) for 1 rt element(s), but each rt should have an rp before and after it"},
+		},
+	},
+	"rt and rp outside of ruby and an extra closing ruby tag are findings": {
+		contents: `<p>漢<rt>かん</rt><rp>(</rp></ruby></p>`,
+		expectedFindings: []structurecheck.Finding{
+			{File: "a.xhtml", Line: 1, Column: 5, Rule: cjk.RubyMisplaced, Message: "rt is not inside of a ruby element"},
+			{File: "a.xhtml", Line: 1, Column: 16, Rule: cjk.RubyMisplaced, Message: "rp is not inside of a ruby element"},
+			{File: "a.xhtml", Line: 1, Column: 26, Rule: cjk.RubyMisplaced, Message: "closing ruby tag has no opening ruby tag"},
+		},
+	},
+	"unclosed rt and ruby are findings": {
+		contents: `<ruby>漢<rt>かん<rt>じ</ruby>
+<ruby>字<rt>じ</rt>`,
+		expectedFindings: []structurecheck.Finding{
+			{File: "a.xhtml", Line: 1, Column: 8, Rule: cjk.RubyUnclosed, Message: "rt is not closed"},
+			{File: "a.xhtml", Line: 1, Column: 14, Rule: cjk.RubyMissingBase, Message: "rt has no base text before it"},
+			{File: "a.xhtml", Line: 1, Column: 14, Rule: cjk.RubyUnclosed, Message: "rt is not closed"},
+			{File: "a.xhtml", Line: 2, Column: 1, Rule: cjk.RubyUnclosed, Message: "ruby is not closed"},
+		},
+	},
+	"nested ruby is checked separately": {
+		contents: `<ruby><ruby>漢<rt>かん</rt></ruby><rt>kan</rt></ruby>`,
+	},
+}
+
+func TestCheckRuby(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range checkRubyTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, args.expectedFindings, cjk.CheckRuby("a.xhtml", args.contents))
+		})
+	}
+}
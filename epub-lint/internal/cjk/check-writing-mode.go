@@ -0,0 +1,164 @@
+package cjk
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+)
+
+const (
+	WritingModeMismatch      = "writing-mode-mismatch"
+	PageProgressionDirection = "page-progression-direction"
+	PrimaryWritingMode       = "primary-writing-mode"
+
+	horizontalTb = "horizontal-tb"
+	verticalRl   = "vertical-rl"
+	verticalLr   = "vertical-lr"
+)
+
+var (
+	cssRuleBodyRegex       = regexp.MustCompile(`\{[^{}]*\}`)
+	styleElementRegex      = regexp.MustCompile(`(?is)<style\b[^>]*>(.*?)</style>`)
+	styleAttributeRegex    = regexp.MustCompile(`(?i)\sstyle\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	writingModeDeclRegex   = regexp.MustCompile(`(?i)(?:^|[^\w-])((?:-epub-|-webkit-)?writing-mode)\s*:\s*([\w-]+)`)
+	spineTagRegex          = regexp.MustCompile(`(?i)<spine\b[^>]*>`)
+	primaryWritingModeMeta = regexp.MustCompile(`(?i)<meta\b[^>]*\sname\s*=\s*["']primary-writing-mode["'][^>]*>`)
+	legacyWritingModes     = map[string]string{
+		"lr":    horizontalTb,
+		"lr-tb": horizontalTb,
+		"rl":    horizontalTb,
+		"rl-tb": horizontalTb,
+		"tb":    verticalRl,
+		"tb-rl": verticalRl,
+		"tb-lr": verticalLr,
+	}
+)
+
+type writingModeDeclaration struct {
+	file     string
+	index    int
+	property string
+	value    string
+}
+
+// CheckWritingMode checks that the writing-mode in the css files and the style elements and attributes of the html files
+// is consistent with the page-progression-direction of the spine and the primary-writing-mode of the opf file.
+// It also checks that the prefixed and unprefixed writing-mode properties in the same rule have the same value.
+func CheckWritingMode(opfFile, opfContents string, cssFiles, htmlFiles map[string]string) []structurecheck.Finding {
+	var (
+		findings     []structurecheck.Finding
+		declarations []writingModeDeclaration
+	)
+	for _, file := range slices.Sorted(maps.Keys(cssFiles)) {
+		for _, indices := range cssRuleBodyRegex.FindAllStringIndex(cssFiles[file], -1) {
+			declarations, findings = checkRuleBody(file, cssFiles[file], indices[0], indices[1], declarations, findings)
+		}
+	}
+
+	var isCjk = isCjkLanguage(linter.GetOpfLanguage(opfContents))
+
+	for _, file := range slices.Sorted(maps.Keys(htmlFiles)) {
+		var contents = htmlFiles[file]
+		isCjk = isCjk || isCjkLanguage(linter.GetLanguage(contents))
+
+		for _, indices := range styleElementRegex.FindAllStringSubmatchIndex(contents, -1) {
+			for _, bodyIndices := range cssRuleBodyRegex.FindAllStringIndex(contents[indices[2]:indices[3]], -1) {
+				declarations, findings = checkRuleBody(file, contents, indices[2]+bodyIndices[0], indices[2]+bodyIndices[1], declarations, findings)
+			}
+		}
+
+		for _, indices := range styleAttributeRegex.FindAllStringSubmatchIndex(contents, -1) {
+			var start, end = indices[2], indices[3]
+			if start == -1 {
+				start, end = indices[4], indices[5]
+			}
+
+			declarations, findings = checkRuleBody(file, contents, start, end, declarations, findings)
+		}
+	}
+
+	var firstVerticalRl, firstVerticalLr *writingModeDeclaration
+	for i, declaration := range declarations {
+		if declaration.value == verticalRl && firstVerticalRl == nil {
+			firstVerticalRl = &declarations[i]
+		} else if declaration.value == verticalLr && firstVerticalLr == nil {
+			firstVerticalLr = &declarations[i]
+		}
+	}
+
+	var (
+		spineIndex               = spineTagRegex.FindStringIndex(opfContents)
+		pageProgressionDirection string
+	)
+	if spineIndex != nil {
+		pageProgressionDirection, _, _, _ = epubhandler.GetAttributeValue(opfContents[spineIndex[0]:spineIndex[1]], "page-progression-direction")
+
+		var displayDirection = "not set"
+		if pageProgressionDirection != "" {
+			displayDirection = fmt.Sprintf("%q", pageProgressionDirection)
+		}
+
+		if firstVerticalRl != nil && pageProgressionDirection != "rtl" {
+			findings = append(findings, structurecheck.NewFinding(opfFile, opfContents, spineIndex[0], PageProgressionDirection, fmt.Sprintf(`spine page-progression-direction is %s, but %q sets %s to %s which reads right to left, so it should be "rtl"`, displayDirection, firstVerticalRl.file, firstVerticalRl.property, verticalRl)))
+		} else if firstVerticalRl == nil && firstVerticalLr != nil && pageProgressionDirection == "rtl" {
+			findings = append(findings, structurecheck.NewFinding(opfFile, opfContents, spineIndex[0], PageProgressionDirection, fmt.Sprintf(`spine page-progression-direction is "rtl", but %q sets %s to %s which reads left to right, so it should be "ltr"`, firstVerticalLr.file, firstVerticalLr.property, verticalLr)))
+		} else if firstVerticalRl == nil && firstVerticalLr == nil && pageProgressionDirection == "rtl" && isCjk {
+			findings = append(findings, structurecheck.NewFinding(opfFile, opfContents, spineIndex[0], PageProgressionDirection, `spine page-progression-direction is "rtl", but no css sets writing-mode to vertical-rl, so the text will be horizontal while the pages turn right to left`))
+		}
+	}
+
+	if metaIndex := primaryWritingModeMeta.FindStringIndex(opfContents); metaIndex != nil {
+		var primaryWritingMode, _, _, _ = epubhandler.GetAttributeValue(opfContents[metaIndex[0]:metaIndex[1]], "content")
+		if strings.HasPrefix(primaryWritingMode, "vertical") && firstVerticalRl == nil && firstVerticalLr == nil {
+			findings = append(findings, structurecheck.NewFinding(opfFile, opfContents, metaIndex[0], PrimaryWritingMode, fmt.Sprintf("primary-writing-mode is %q, but no css sets writing-mode to a vertical value", primaryWritingMode)))
+		} else if strings.HasPrefix(primaryWritingMode, "horizontal") && firstVerticalRl != nil {
+			findings = append(findings, structurecheck.NewFinding(opfFile, opfContents, metaIndex[0], PrimaryWritingMode, fmt.Sprintf("primary-writing-mode is %q, but %q sets %s to %s", primaryWritingMode, firstVerticalRl.file, firstVerticalRl.property, verticalRl)))
+		}
+	}
+
+	structurecheck.SortFindings(findings)
+
+	return findings
+}
+
+// checkRuleBody gets the writing-mode declarations in the rule body and adds a finding for any that have
+// a different value than the ones before them in the same rule body
+func checkRuleBody(file, contents string, start, end int, declarations []writingModeDeclaration, findings []structurecheck.Finding) ([]writingModeDeclaration, []structurecheck.Finding) {
+	var ruleDeclarations []writingModeDeclaration
+	for _, indices := range writingModeDeclRegex.FindAllStringSubmatchIndex(contents[start:end], -1) {
+		var value = strings.ToLower(contents[start+indices[4] : start+indices[5]])
+		if legacyValue, ok := legacyWritingModes[value]; ok {
+			value = legacyValue
+		} else if value != horizontalTb && value != verticalRl && value != verticalLr {
+			continue
+		}
+
+		var declaration = writingModeDeclaration{
+			file:     file,
+			index:    start + indices[2],
+			property: strings.ToLower(contents[start+indices[2] : start+indices[3]]),
+			value:    value,
+		}
+		for _, other := range ruleDeclarations {
+			if other.value != declaration.value {
+				findings = append(findings, structurecheck.NewFinding(file, contents, declaration.index, WritingModeMismatch, fmt.Sprintf("%s is %s, but %s is %s in the same rule", declaration.property, declaration.value, other.property, other.value)))
+
+				break
+			}
+		}
+
+		ruleDeclarations = append(ruleDeclarations, declaration)
+	}
+
+	return append(declarations, ruleDeclarations...), findings
+}
+
+func isCjkLanguage(lang string) bool {
+	return lang != "" && linter.LanguageMatches(lang, linter.CjkLanguages)
+}
@@ -0,0 +1,109 @@
+//go:build unit
+
+package cjk_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/cjk"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	rtlOpf = `<package>
+<metadata><dc:language>ja</dc:language></metadata>
+<spine page-progression-direction="rtl" toc="ncx">
+</spine>
+</package>`
+	unsetOpf = `<package>
+<metadata><meta name="primary-writing-mode" content="horizontal-lr"/></metadata>
+<spine toc="ncx">
+</spine>
+</package>`
+	verticalCss = `body {
+  -epub-writing-mode: vertical-rl;
+  writing-mode: vertical-rl;
+}`
+)
+
+type checkWritingModeTestCase struct {
+	opfContents      string
+	cssFiles         map[string]string
+	htmlFiles        map[string]string
+	expectedFindings []structurecheck.Finding
+}
+
+var checkWritingModeTestCases = map[string]checkWritingModeTestCase{
+	"vertical-rl with a rtl page progression direction has no findings": {
+		opfContents: rtlOpf,
+		cssFiles:    map[string]string{"OEBPS/style.css": verticalCss},
+	},
+	"vertical-rl without a rtl page progression direction is a finding along with a horizontal primary writing mode": {
+		opfContents: unsetOpf,
+		cssFiles:    map[string]string{"OEBPS/style.css": verticalCss},
+		expectedFindings: []structurecheck.Finding{
+			{File: "OEBPS/content.opf", Line: 2, Column: 11, Rule: cjk.PrimaryWritingMode, Message: `primary-writing-mode is "horizontal-lr", but "OEBPS/style.css" sets -epub-writing-mode to vertical-rl`},
+			{File: "OEBPS/content.opf", Line: 3, Column: 1, Rule: cjk.PageProgressionDirection, Message: `spine page-progression-direction is not set, but "OEBPS/style.css" sets -epub-writing-mode to vertical-rl which reads right to left, so it should be "rtl"`},
+		},
+	},
+	"legacy vertical values in a style element and a style attribute are checked": {
+		opfContents: `<spine page-progression-direction="ltr">`,
+		htmlFiles: map[string]string{
+			"OEBPS/a.xhtml": `<html><head><style>p { -webkit-writing-mode: tb-rl; }</style></head><body><p style="writing-mode: horizontal-tb">text</p></body></html>`,
+		},
+		expectedFindings: []structurecheck.Finding{
+			{File: "OEBPS/content.opf", Line: 1, Column: 1, Rule: cjk.PageProgressionDirection, Message: `spine page-progression-direction is "ltr", but "OEBPS/a.xhtml" sets -webkit-writing-mode to vertical-rl which reads right to left, so it should be "rtl"`},
+		},
+	},
+	"prefixed and unprefixed writing modes that differ in the same rule are a finding": {
+		opfContents: rtlOpf,
+		cssFiles: map[string]string{"OEBPS/style.css": `body {
+  -epub-writing-mode: horizontal-tb;
+  writing-mode: vertical-rl;
+}`},
+		expectedFindings: []structurecheck.Finding{
+			{File: "OEBPS/style.css", Line: 3, Column: 3, Rule: cjk.WritingModeMismatch, Message: "writing-mode is vertical-rl, but -epub-writing-mode is horizontal-tb in the same rule"},
+		},
+	},
+	"vertical-lr with a rtl page progression direction is a finding": {
+		opfContents: rtlOpf,
+		cssFiles:    map[string]string{"OEBPS/style.css": `html { writing-mode: vertical-lr; }`},
+		expectedFindings: []structurecheck.Finding{
+			{File: "OEBPS/content.opf", Line: 3, Column: 1, Rule: cjk.PageProgressionDirection, Message: `spine page-progression-direction is "rtl", but "OEBPS/style.css" sets writing-mode to vertical-lr which reads left to right, so it should be "ltr"`},
+		},
+	},
+	"rtl page progression direction without vertical writing in Japanese content is a finding": {
+		opfContents: rtlOpf,
+		htmlFiles: map[string]string{
+			"OEBPS/a.xhtml": `<html xml:lang="ja"><body><p>テキスト</p></body></html>`,
+		},
+		expectedFindings: []structurecheck.Finding{
+			{File: "OEBPS/content.opf", Line: 3, Column: 1, Rule: cjk.PageProgressionDirection, Message: `spine page-progression-direction is "rtl", but no css sets writing-mode to vertical-rl, so the text will be horizontal while the pages turn right to left`},
+		},
+	},
+	"rtl page progression direction without vertical writing in Arabic content has no findings": {
+		opfContents: `<spine page-progression-direction="rtl">`,
+		htmlFiles: map[string]string{
+			"OEBPS/a.xhtml": `<html lang="ar"><body><p>نص</p></body></html>`,
+		},
+	},
+	"vertical primary writing mode without vertical writing is a finding": {
+		opfContents: `<meta name="primary-writing-mode" content="vertical-rl"/>`,
+		expectedFindings: []structurecheck.Finding{
+			{File: "OEBPS/content.opf", Line: 1, Column: 1, Rule: cjk.PrimaryWritingMode, Message: `primary-writing-mode is "vertical-rl", but no css sets writing-mode to a vertical value`},
+		},
+	},
+}
+
+func TestCheckWritingMode(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range checkWritingModeTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, args.expectedFindings, cjk.CheckWritingMode("OEBPS/content.opf", args.opfContents, args.cssFiles, args.htmlFiles))
+		})
+	}
+}
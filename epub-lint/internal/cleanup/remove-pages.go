@@ -0,0 +1,313 @@
+package cleanup
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	rulefixes "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/rule-fixes"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+type CleanupContext struct {
+	EpubInfo            epubhandler.EpubInfo
+	OpfFolder           string
+	OpfFileName         string
+	NcxFileName         string
+	UpdatedFileContents map[string]string
+	GetFileContents     func(string) (string, error)
+}
+
+// PageMatch is a page that matched a cleanup rule along with the images that are only used by pages that are being removed
+type PageMatch struct {
+	File   string
+	Rule   string
+	Images []string
+}
+
+var imageRefRegex = regexp.MustCompile(`(?i)<(?:img|image)\b[^>]*?\s(?:src|xlink:href|href)=["']([^"']+)["']`)
+
+// FindMatchingPages gets the pages in the epub that match one of the rules in spine order followed by any pages not in the spine.
+// The images in a matched page are included in the match when no page that is being kept or css file references them.
+func FindMatchingPages(ctx CleanupContext, rules []Rule) ([]PageMatch, error) {
+	var (
+		matches      []PageMatch
+		keptContents []string
+	)
+	for _, file := range getPagesInOrder(ctx.EpubInfo) {
+		var filePath = filehandler.JoinPath(ctx.OpfFolder, file)
+		contents, err := ctx.GetFileContents(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		var matchingRule = -1
+		for i, rule := range rules {
+			if rule.Matches(filePath, contents) {
+				matchingRule = i
+				break
+			}
+		}
+
+		if matchingRule == -1 {
+			// the nav and toc files have their references to removed files updated, so they do not keep an image from being removed
+			if file != ctx.EpubInfo.NavFile && file != ctx.EpubInfo.TocFile {
+				keptContents = append(keptContents, contents)
+			}
+
+			continue
+		}
+
+		matches = append(matches, PageMatch{
+			File:   filePath,
+			Rule:   rules[matchingRule].Name,
+			Images: getImagePaths(filePath, contents, ctx.EpubInfo.ImagesFiles, ctx.OpfFolder),
+		})
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	for file := range ctx.EpubInfo.CssFiles {
+		contents, err := ctx.GetFileContents(filehandler.JoinPath(ctx.OpfFolder, file))
+		if err != nil {
+			return nil, err
+		}
+
+		keptContents = append(keptContents, contents)
+	}
+
+	var removedImages = make(map[string]struct{})
+	for i, match := range matches {
+		var images []string
+		for _, image := range match.Images {
+			if _, alreadyRemoved := removedImages[image]; alreadyRemoved || isReferenced(path.Base(image), keptContents) {
+				continue
+			}
+
+			removedImages[image] = struct{}{}
+			images = append(images, image)
+		}
+
+		matches[i].Images = images
+	}
+
+	return matches, nil
+}
+
+// RemovePages removes the matched pages and their images from the OPF, NCX, nav, and landmarks
+// returning the files that were removed
+func RemovePages(ctx CleanupContext, matches []PageMatch) ([]string, error) {
+	var removedFiles []string
+	for _, match := range matches {
+		err := removeFile(ctx, match.File, true)
+		if err != nil {
+			return removedFiles, err
+		}
+
+		removedFiles = append(removedFiles, match.File)
+
+		for _, image := range match.Images {
+			err = removeFile(ctx, image, false)
+			if err != nil {
+				return removedFiles, err
+			}
+
+			removedFiles = append(removedFiles, image)
+		}
+	}
+
+	return removedFiles, nil
+}
+
+func removeFile(ctx CleanupContext, filePath string, isPage bool) error {
+	opfContents, err := ctx.GetFileContents(ctx.OpfFileName)
+	if err != nil {
+		return err
+	}
+
+	relativeOpfPath, err := filepath.Rel(filepath.Dir(ctx.OpfFileName), filePath)
+	if err != nil {
+		return fmt.Errorf("failed to determine relative path between opf file %q and file %q: %w", ctx.OpfFileName, filePath, err)
+	}
+
+	opfContents, err = epubhandler.RemoveFileFromOpf(opfContents, relativeOpfPath)
+	if err != nil {
+		return fmt.Errorf("failed to remove file %q from opf: %w", filePath, err)
+	}
+
+	ctx.UpdatedFileContents[ctx.OpfFileName] = removeGuideReferences(opfContents, relativeOpfPath)
+
+	err = removeFromNavFiles(ctx, filePath, isPage)
+	if err != nil {
+		return err
+	}
+
+	if !isPage || ctx.EpubInfo.NcxFile == "" {
+		return nil
+	}
+
+	ncxContents, err := ctx.GetFileContents(ctx.NcxFileName)
+	if err != nil {
+		return err
+	}
+
+	relativeNcxPath, err := filepath.Rel(filepath.Dir(ctx.NcxFileName), filePath)
+	if err != nil {
+		return fmt.Errorf("failed to determine relative path between ncx file %q and file %q: %w", ctx.NcxFileName, filePath, err)
+	}
+
+	var updatedNcx = epubhandler.RemoveFileFromNcx(ncxContents, relativeNcxPath)
+	if updatedNcx != ncxContents {
+		edits := rulefixes.FixPlayOrder(updatedNcx)
+		if len(edits) != 0 {
+			updatedNcx, err = positions.ApplyEdits(ctx.NcxFileName, updatedNcx, edits)
+			if err != nil {
+				return err
+			}
+		}
+
+		ctx.UpdatedFileContents[ctx.NcxFileName] = updatedNcx
+	}
+
+	return nil
+}
+
+// removeFromNavFiles removes all entries for a page from the nav and toc files including landmarks
+// and points cover and toc landmarks that reference a removed image back to the cover and toc files
+func removeFromNavFiles(ctx CleanupContext, filePath string, isPage bool) error {
+	var navFiles []string
+	if ctx.EpubInfo.NavFile != "" {
+		navFiles = append(navFiles, ctx.EpubInfo.NavFile)
+	}
+
+	if ctx.EpubInfo.TocFile != "" && ctx.EpubInfo.TocFile != ctx.EpubInfo.NavFile && isPage {
+		navFiles = append(navFiles, ctx.EpubInfo.TocFile)
+	}
+
+	for _, navFile := range navFiles {
+		var (
+			navFilePath   = filehandler.JoinPath(ctx.OpfFolder, navFile)
+			navFolderPath = filepath.Dir(navFilePath) // used instead of the file path as that results in an additional "../" being added
+		)
+		if navFilePath == filePath {
+			continue
+		}
+
+		contents, err := ctx.GetFileContents(navFilePath)
+		if err != nil {
+			return err
+		}
+
+		relativeFilePath, err := filepath.Rel(navFolderPath, filePath)
+		if err != nil {
+			return fmt.Errorf("failed to determine relative path between nav file %q and file %q: %w", navFilePath, filePath, err)
+		}
+
+		var updatedContents = contents
+		if isPage {
+			for {
+				var removedEntry = epubhandler.RemoveFileFromNav(updatedContents, relativeFilePath)
+				if removedEntry == updatedContents {
+					break
+				}
+
+				updatedContents = removedEntry
+			}
+		} else {
+			relativeCoverPath, err := getRelativePath(navFolderPath, ctx.OpfFolder, ctx.EpubInfo.CoverFile)
+			if err != nil {
+				return err
+			}
+
+			relativeTocPath, err := getRelativePath(navFolderPath, ctx.OpfFolder, ctx.EpubInfo.TocFile)
+			if err != nil {
+				return err
+			}
+
+			updatedContents = epubhandler.UpdateLandmarks(updatedContents, relativeFilePath, relativeCoverPath, relativeTocPath)
+		}
+
+		if updatedContents != contents {
+			ctx.UpdatedFileContents[navFilePath] = updatedContents
+		}
+	}
+
+	return nil
+}
+
+func getRelativePath(fromFolder, opfFolder, file string) (string, error) {
+	if file == "" {
+		return "", nil
+	}
+
+	var filePath = filehandler.JoinPath(opfFolder, file)
+	relativePath, err := filepath.Rel(fromFolder, filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine relative path between folder %q and file %q: %w", fromFolder, filePath, err)
+	}
+
+	return relativePath, nil
+}
+
+// removeGuideReferences removes any guide references to the file from the opf
+func removeGuideReferences(opfContents, relativeFilePath string) string {
+	var guideReferenceRegex = regexp.MustCompile(`[ \t]*<reference\b[^>]*\bhref=["']` + regexp.QuoteMeta(relativeFilePath) + `(?:#[^"']*)?["'][^>]*/>[ \t]*\r?\n?`)
+
+	return guideReferenceRegex.ReplaceAllString(opfContents, "")
+}
+
+func getPagesInOrder(epubInfo epubhandler.EpubInfo) []string {
+	var (
+		pages    = slices.Clone(epubInfo.FilePathsInSpineOrder)
+		nonSpine []string
+	)
+	for file := range epubInfo.HtmlFiles {
+		if !slices.Contains(epubInfo.FilePathsInSpineOrder, file) {
+			nonSpine = append(nonSpine, file)
+		}
+	}
+
+	slices.Sort(nonSpine)
+
+	return append(pages, nonSpine...)
+}
+
+// getImagePaths gets the full paths of the manifest images that the page references
+func getImagePaths(filePath, contents string, imageFiles map[string]struct{}, opfFolder string) []string {
+	var images []string
+	for _, groups := range imageRefRegex.FindAllStringSubmatch(contents, -1) {
+		var src, _, _ = strings.Cut(groups[1], "#")
+		if strings.Contains(src, ":") {
+			continue
+		}
+
+		var imagePath = filehandler.JoinPath(path.Dir(filePath), src)
+		relativeImagePath, err := filepath.Rel(filehandler.JoinPath(opfFolder), imagePath)
+		if err != nil {
+			continue
+		}
+
+		if _, isManifestImage := imageFiles[filepath.ToSlash(relativeImagePath)]; isManifestImage && !slices.Contains(images, imagePath) {
+			images = append(images, imagePath)
+		}
+	}
+
+	return images
+}
+
+func isReferenced(fileName string, contents []string) bool {
+	for _, fileContents := range contents {
+		if strings.Contains(fileContents, fileName) {
+			return true
+		}
+	}
+
+	return false
+}
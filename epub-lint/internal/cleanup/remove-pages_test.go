@@ -0,0 +1,149 @@
+//go:build unit
+
+package cleanup_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/cleanup"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cleanupOpf = `<?xml version="1.0"?>
+<package>
+  <manifest>
+    <item id="credits" href="Text/credits.xhtml" />
+    <item id="credits-img" href="Images/credits.png" />
+    <item id="shared-img" href="Images/logo.png" />
+    <item id="chap1" href="Text/ch1.xhtml" />
+  </manifest>
+  <spine>
+    <itemref idref="credits" />
+    <itemref idref="chap1" />
+  </spine>
+  <guide>
+    <reference type="acknowledgements" title="Credits" href="Text/credits.xhtml" />
+    <reference type="text" title="Start" href="Text/ch1.xhtml" />
+  </guide>
+</package>
+`
+	cleanupOpfAfterRemoval = `<?xml version="1.0"?>
+<package>
+  <manifest>
+    <item id="shared-img" href="Images/logo.png" />
+    <item id="chap1" href="Text/ch1.xhtml" />
+  </manifest>
+  <spine>
+    <itemref idref="chap1" />
+  </spine>
+  <guide>
+    <reference type="text" title="Start" href="Text/ch1.xhtml" />
+  </guide>
+</package>
+`
+	cleanupNcx = `<ncx>
+<navMap>
+  <navPoint id="navPoint-1" playOrder="1">
+    <content src="Text/credits.xhtml"/>
+  </navPoint>
+  <navPoint id="navPoint-2" playOrder="2">
+    <content src="Text/ch1.xhtml"/>
+  </navPoint>
+</navMap>
+</ncx>
+`
+	cleanupNcxAfterRemoval = `<ncx>
+<navMap>
+  <navPoint id="navPoint-2" playOrder="1">
+    <content src="Text/ch1.xhtml"/>
+  </navPoint>
+</navMap>
+</ncx>
+`
+	creditsPageWithImages = `<html><body>
+<img src="../Images/credits.png" alt="" />
+<img src="../Images/logo.png" alt="" />
+<p>Visit our site for more translations!</p>
+</body></html>`
+	chapterPage = `<html><body>
+<img src="../Images/logo.png" alt="" />
+<p>Chapter 1</p>
+</body></html>`
+)
+
+func TestFindMatchingPagesAndRemovePages(t *testing.T) {
+	t.Parallel()
+
+	var (
+		files = map[string]string{
+			"OEBPS/content.opf":        cleanupOpf,
+			"OEBPS/toc.ncx":            cleanupNcx,
+			"OEBPS/Text/credits.xhtml": creditsPageWithImages,
+			"OEBPS/Text/ch1.xhtml":     chapterPage,
+		}
+		ctx = cleanup.CleanupContext{
+			EpubInfo: epubhandler.EpubInfo{
+				HtmlFiles:             map[string]struct{}{"Text/credits.xhtml": {}, "Text/ch1.xhtml": {}},
+				ImagesFiles:           map[string]struct{}{"Images/credits.png": {}, "Images/logo.png": {}},
+				CssFiles:              map[string]struct{}{},
+				NcxFile:               "toc.ncx",
+				OpfFile:               "OEBPS/content.opf",
+				FilePathsInSpineOrder: []string{"Text/credits.xhtml", "Text/ch1.xhtml"},
+			},
+			OpfFolder:           "OEBPS",
+			OpfFileName:         "OEBPS/content.opf",
+			NcxFileName:         "OEBPS/toc.ncx",
+			UpdatedFileContents: map[string]string{},
+		}
+	)
+	ctx.GetFileContents = func(filename string) (string, error) {
+		if contents, ok := ctx.UpdatedFileContents[filename]; ok {
+			return contents, nil
+		}
+
+		if contents, ok := files[filename]; ok {
+			return contents, nil
+		}
+
+		return "", fmt.Errorf("failed to find %q", filename)
+	}
+
+	matches, err := cleanup.FindMatchingPages(ctx, []cleanup.Rule{{Name: "Site ad", TextSnippet: "visit our site"}})
+	require.NoError(t, err)
+	assert.Equal(t, []cleanup.PageMatch{
+		{
+			File:   "OEBPS/Text/credits.xhtml",
+			Rule:   "Site ad",
+			Images: []string{"OEBPS/Images/credits.png"},
+		},
+	}, matches)
+
+	removedFiles, err := cleanup.RemovePages(ctx, matches)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OEBPS/Text/credits.xhtml", "OEBPS/Images/credits.png"}, removedFiles)
+	assert.Equal(t, cleanupOpfAfterRemoval, ctx.UpdatedFileContents["OEBPS/content.opf"])
+	assert.Equal(t, cleanupNcxAfterRemoval, ctx.UpdatedFileContents["OEBPS/toc.ncx"])
+}
+
+func TestFindMatchingPagesNoMatches(t *testing.T) {
+	t.Parallel()
+
+	var ctx = cleanup.CleanupContext{
+		EpubInfo: epubhandler.EpubInfo{
+			HtmlFiles:             map[string]struct{}{"Text/ch1.xhtml": {}},
+			FilePathsInSpineOrder: []string{"Text/ch1.xhtml"},
+		},
+		OpfFolder: "OEBPS",
+		GetFileContents: func(string) (string, error) {
+			return chapterPage, nil
+		},
+	}
+
+	matches, err := cleanup.FindMatchingPages(ctx, cleanup.DefaultRules)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
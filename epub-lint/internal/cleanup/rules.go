@@ -0,0 +1,119 @@
+package cleanup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/jnovels"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+)
+
+// Rule determines which pages are boilerplate that should be removed.
+// A page matches a rule when it matches all of the conditions that are set on the rule.
+type Rule struct {
+	Name string `json:"name"`
+	// FilePattern is a glob pattern that is matched against the name of the page (i.e. "credits*.xhtml")
+	FilePattern string `json:"filePattern,omitempty"`
+	// ContentHash is the sha256 hash of the contents of the page in hexadecimal
+	ContentHash string `json:"contentHash,omitempty"`
+	// TextSnippet is text that the page must contain ignoring case, html tags, and differences in whitespace
+	TextSnippet string `json:"textSnippet,omitempty"`
+}
+
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+var (
+	DefaultRules = []Rule{
+		{
+			Name:        "JNovels credits page",
+			FilePattern: jnovels.JnovelsFile,
+		},
+	}
+	ErrNoRules         = errors.New("at least one cleanup rule must be provided")
+	headOrScriptsRegex = regexp.MustCompile(`(?is)<(head|script|style)\b.*?</(head|script|style)>`)
+)
+
+// ParseRules parses the cleanup rules out of the json contents
+func ParseRules(contents string) ([]Rule, error) {
+	var rules rulesFile
+	err := json.Unmarshal([]byte(contents), &rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cleanup rules: %w", err)
+	}
+
+	if len(rules.Rules) == 0 {
+		return nil, ErrNoRules
+	}
+
+	for i, rule := range rules.Rules {
+		err = rule.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("cleanup rule %d is invalid: %w", i+1, err)
+		}
+	}
+
+	return rules.Rules, nil
+}
+
+// Validate makes sure that the rule has at least one condition and that its file pattern is a valid glob
+func (r Rule) Validate() error {
+	if r.FilePattern == "" && r.ContentHash == "" && strings.TrimSpace(r.TextSnippet) == "" {
+		return fmt.Errorf("rule %q must have at least one of filePattern, contentHash, or textSnippet", r.Name)
+	}
+
+	if r.FilePattern != "" {
+		_, err := path.Match(r.FilePattern, "")
+		if err != nil {
+			return fmt.Errorf("rule %q has an invalid file pattern %q: %w", r.Name, r.FilePattern, err)
+		}
+	}
+
+	return nil
+}
+
+// Matches determines whether the page with the provided file path and contents matches all of the rule's conditions
+func (r Rule) Matches(filePath, contents string) bool {
+	if r.FilePattern != "" {
+		matches, err := path.Match(r.FilePattern, path.Base(filePath))
+		if err != nil || !matches {
+			return false
+		}
+	}
+
+	if r.ContentHash != "" && !strings.EqualFold(r.ContentHash, GetContentHash(contents)) {
+		return false
+	}
+
+	if strings.TrimSpace(r.TextSnippet) != "" && !strings.Contains(normalizeText(getPageText(contents)), normalizeText(r.TextSnippet)) {
+		return false
+	}
+
+	return true
+}
+
+// GetContentHash gets the sha256 hash of the contents in hexadecimal
+func GetContentHash(contents string) string {
+	var hash = sha256.Sum256([]byte(contents))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func getPageText(contents string) string {
+	contents = headOrScriptsRegex.ReplaceAllString(contents, " ")
+	contents = linter.HtmlTagRegex.ReplaceAllString(contents, " ")
+
+	return html.UnescapeString(contents)
+}
+
+func normalizeText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,97 @@
+//go:build unit
+
+package cleanup_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/cleanup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const creditsPage = `<html><head><title>Visit Our Site</title></head><body>
+<p>Translated by the Group.</p>
+<p>Please   <b>visit</b>
+our site for more!</p>
+</body></html>`
+
+type ruleMatchesTestCase struct {
+	rule            cleanup.Rule
+	filePath        string
+	expectedMatches bool
+}
+
+var ruleMatchesTestCases = map[string]ruleMatchesTestCase{
+	"a file pattern matches against the name of the file": {
+		rule:            cleanup.Rule{FilePattern: "credits*.xhtml"},
+		filePath:        "OEBPS/Text/credits-1.xhtml",
+		expectedMatches: true,
+	},
+	"a file pattern that does not match the name of the file does not match": {
+		rule:            cleanup.Rule{FilePattern: "credits*.xhtml"},
+		filePath:        "OEBPS/Text/chapter-1.xhtml",
+		expectedMatches: false,
+	},
+	"a text snippet ignores case, html tags, and whitespace differences": {
+		rule:            cleanup.Rule{TextSnippet: "Please visit our SITE"},
+		filePath:        "OEBPS/Text/chapter-1.xhtml",
+		expectedMatches: true,
+	},
+	"a text snippet does not match text in the head of the page": {
+		rule:            cleanup.Rule{TextSnippet: "visit our site for more! visit our site"},
+		filePath:        "OEBPS/Text/chapter-1.xhtml",
+		expectedMatches: false,
+	},
+	"a content hash that is not the sha256 hash of the contents does not match": {
+		rule:            cleanup.Rule{ContentHash: "3F2A4E2E4D0F2E0A4B1C3D5E6F708192A3B4C5D6E7F8091A2B3C4D5E6F708192"},
+		filePath:        "OEBPS/Text/chapter-1.xhtml",
+		expectedMatches: false,
+	},
+	"all conditions on a rule must match": {
+		rule:            cleanup.Rule{FilePattern: "chapter*.xhtml", TextSnippet: "translated by the group"},
+		filePath:        "OEBPS/Text/credits.xhtml",
+		expectedMatches: false,
+	},
+}
+
+func TestRuleMatches(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range ruleMatchesTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, args.expectedMatches, args.rule.Matches(args.filePath, creditsPage))
+		})
+	}
+}
+
+func TestRuleMatchesContentHash(t *testing.T) {
+	t.Parallel()
+
+	var rule = cleanup.Rule{ContentHash: cleanup.GetContentHash(creditsPage)}
+
+	assert.True(t, rule.Matches("OEBPS/Text/credits.xhtml", creditsPage))
+	assert.False(t, rule.Matches("OEBPS/Text/credits.xhtml", creditsPage+"\n"))
+}
+
+func TestParseRules(t *testing.T) {
+	t.Parallel()
+
+	rules, err := cleanup.ParseRules(`{"rules": [{"name": "Credits", "filePattern": "credits*.xhtml"}, {"name": "Ad", "textSnippet": "visit our site"}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, []cleanup.Rule{
+		{Name: "Credits", FilePattern: "credits*.xhtml"},
+		{Name: "Ad", TextSnippet: "visit our site"},
+	}, rules)
+
+	_, err = cleanup.ParseRules(`{"rules": []}`)
+	assert.ErrorIs(t, err, cleanup.ErrNoRules)
+
+	_, err = cleanup.ParseRules(`{"rules": [{"name": "Empty"}]}`)
+	assert.EqualError(t, err, `cleanup rule 1 is invalid: rule "Empty" must have at least one of filePattern, contentHash, or textSnippet`)
+
+	_, err = cleanup.ParseRules(`{"rules": [{"name": "Bad Pattern", "filePattern": "[credits"}]}`)
+	assert.EqualError(t, err, `cleanup rule 1 is invalid: rule "Bad Pattern" has an invalid file pattern "[credits": syntax error in pattern`)
+}
@@ -0,0 +1,357 @@
+package epubbuilder
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"html"
+	"path"
+	"strings"
+	"time"
+
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+const (
+	OpfFolder       = "OEBPS"
+	opfFileName     = "content.opf"
+	navFileName     = "nav.xhtml"
+	ncxFileName     = "toc.ncx"
+	coverPageName   = "cover.xhtml"
+	DefaultCssPath  = "styles/stylesheet.css"
+	mimetype        = "application/epub+zip"
+	containerPath   = "META-INF/container.xml"
+	containerFormat = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="%s" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+	opfContents = `<?xml version="1.0" encoding="utf-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="%[1]s">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+%[2]s</metadata>
+<manifest>
+</manifest>
+<spine toc="ncx">
+</spine>
+<guide>
+%[3]s</guide>
+</package>
+`
+	navContents = `<?xml version="1.0" encoding="utf-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="%[1]s" xml:lang="%[1]s">
+<head>
+  <title>Table of Contents</title>
+</head>
+<body>
+<nav epub:type="toc" id="toc">
+<h1>Table of Contents</h1>
+<ol>
+</ol>
+</nav>
+<nav epub:type="landmarks" id="landmarks" hidden="">
+<ol>
+%[2]s</ol>
+</nav>
+</body>
+</html>
+`
+	ncxContents = `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+<head>
+  <meta name="dtb:uid" content="%[1]s"/>
+  <meta name="dtb:depth" content="1"/>
+  <meta name="dtb:totalPageCount" content="0"/>
+  <meta name="dtb:maxPageNumber" content="0"/>
+</head>
+<docTitle>
+  <text>%[2]s</text>
+</docTitle>
+<navMap>
+</navMap>
+</ncx>
+`
+	coverContents = `<?xml version="1.0" encoding="utf-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="%[1]s" xml:lang="%[1]s">
+<head>
+  <title>Cover</title>
+  <link rel="stylesheet" type="text/css" href="%[2]s"/>
+</head>
+<body epub:type="cover">
+<div class="cover"><img src="%[3]s" alt="%[4]s"/></div>
+</body>
+</html>
+`
+	DefaultCss = `body {
+  margin: 0 5pt;
+  text-align: justify;
+}
+
+h1, h2, h3 {
+  text-align: center;
+  page-break-after: avoid;
+  break-after: avoid;
+}
+
+p {
+  margin: 0;
+  text-indent: 1.5em;
+}
+
+hr {
+  border: none;
+  text-align: center;
+}
+
+hr::after {
+  content: "* * *";
+}
+
+img {
+  max-width: 100%;
+}
+
+div.cover {
+  text-align: center;
+}
+
+div.cover img {
+  height: 100%;
+}
+`
+)
+
+var (
+	ErrNoChapters        = errors.New("at least one chapter is required to build an epub")
+	ErrFileNameCollision = errors.New("file names in an epub must be unique")
+	mediaTypes           = map[string]string{
+		".css":   "text/css",
+		".gif":   "image/gif",
+		".jpeg":  "image/jpeg",
+		".jpg":   "image/jpeg",
+		".png":   "image/png",
+		".svg":   "image/svg+xml",
+		".webp":  "image/webp",
+		".otf":   "font/otf",
+		".ttf":   "font/ttf",
+		".woff":  "font/woff",
+		".woff2": "font/woff2",
+	}
+)
+
+// Resource is a non-chapter file like an image or stylesheet to include in the book
+type Resource struct {
+	// FileName is the path of the file relative to the opf folder
+	FileName string
+	Contents []byte
+}
+
+// Book is everything needed to write out an epub
+type Book struct {
+	Metadata  Metadata
+	Chapters  []Chapter
+	Resources []Resource
+	// CssPath is the path of the stylesheet relative to the opf folder
+	CssPath string
+	// CoverPath is the path of the cover image relative to the opf folder if there is one
+	CoverPath string
+	Modified  time.Time
+}
+
+// GetMediaType gets the media type of a resource based on its extension
+func GetMediaType(fileName string) (string, bool) {
+	mediaType, ok := mediaTypes[strings.ToLower(path.Ext(fileName))]
+
+	return mediaType, ok
+}
+
+// WriteEpub writes out the book as an epub 3 with an ncx for backwards compatibility
+func WriteEpub(w *zip.Writer, book Book) error {
+	if len(book.Chapters) == 0 {
+		return ErrNoChapters
+	}
+
+	err := validateFileNames(book)
+	if err != nil {
+		return err
+	}
+
+	var (
+		opfPath   = filehandler.JoinPath(OpfFolder, opfFileName)
+		language  = book.Metadata.Language
+		title     = linter.EscapeText(book.Metadata.Title)
+		landmarks strings.Builder
+		guide     strings.Builder
+		ncx       = fmt.Sprintf(ncxContents, html.EscapeString(book.Metadata.Identifier), title)
+		spine     = book.Chapters
+	)
+
+	if book.CoverPath != "" {
+		spine = append([]Chapter{{
+			FileName: coverPageName,
+			Title:    "Cover",
+			Contents: fmt.Sprintf(coverContents, language, book.CssPath, book.CoverPath, html.EscapeString(book.Metadata.Title)),
+		}}, spine...)
+
+		fmt.Fprintf(&landmarks, "  <li><a epub:type=\"cover\" href=%q>Cover</a></li>\n", coverPageName)
+		fmt.Fprintf(&guide, "  <reference type=\"cover\" title=\"Cover\" href=%q/>\n", coverPageName)
+	}
+
+	fmt.Fprintf(&landmarks, "  <li><a epub:type=\"toc\" href=%q>Table of Contents</a></li>\n", navFileName+"#toc")
+	fmt.Fprintf(&landmarks, "  <li><a epub:type=\"bodymatter\" href=%q>Start of Content</a></li>\n", book.Chapters[0].FileName)
+	fmt.Fprintf(&guide, "  <reference type=\"toc\" title=\"Table of Contents\" href=%q/>\n", navFileName)
+	fmt.Fprintf(&guide, "  <reference type=\"text\" title=\"Start of Content\" href=%q/>\n", book.Chapters[0].FileName)
+
+	var (
+		opf = fmt.Sprintf(opfContents, language, getOpfMetadata(book), guide.String())
+		nav = fmt.Sprintf(navContents, language, landmarks.String())
+	)
+
+	opf = addManifestItem(opf, navFileName, "nav", "application/xhtml+xml", "nav")
+	opf = addManifestItem(opf, ncxFileName, "ncx", "application/x-dtbncx+xml", "")
+
+	for i, resource := range book.Resources {
+		mediaType, ok := GetMediaType(resource.FileName)
+		if !ok {
+			return fmt.Errorf("failed to determine the media type of %q", resource.FileName)
+		}
+
+		var id, properties = fmt.Sprintf("resource-%d", i+1), ""
+		if resource.FileName == book.CoverPath {
+			id, properties = "cover-image", "cover-image"
+		}
+
+		opf = addManifestItem(opf, resource.FileName, id, mediaType, properties)
+	}
+
+	if book.CoverPath != "" {
+		opf = epubhandler.AddFileToOpf(opf, coverPageName, "cover", "application/xhtml+xml")
+		ncx = epubhandler.AddFileToNcx(ncx, coverPageName, "Cover", "navPoint-cover")
+	}
+
+	for i, chapter := range book.Chapters {
+		var id = fmt.Sprintf("chapter-%d", i+1)
+
+		opf = epubhandler.AddFileToOpf(opf, chapter.FileName, id, "application/xhtml+xml")
+		nav = epubhandler.AddFileToNav(nav, chapter.FileName, linter.EscapeText(chapter.Title))
+		ncx = epubhandler.AddFileToNcx(ncx, chapter.FileName, linter.EscapeText(chapter.Title), "navPoint-"+id)
+	}
+
+	err = filehandler.WriteZipUncompressedString(w, "mimetype", mimetype)
+	if err != nil {
+		return fmt.Errorf("failed to write the mimetype to the epub: %w", err)
+	}
+
+	var files = []Resource{
+		{FileName: containerPath, Contents: []byte(fmt.Sprintf(containerFormat, opfPath))},
+		{FileName: opfPath, Contents: []byte(opf)},
+		{FileName: filehandler.JoinPath(OpfFolder, navFileName), Contents: []byte(nav)},
+		{FileName: filehandler.JoinPath(OpfFolder, ncxFileName), Contents: []byte(ncx)},
+	}
+	for _, chapter := range spine {
+		files = append(files, Resource{FileName: filehandler.JoinPath(OpfFolder, chapter.FileName), Contents: []byte(chapter.Contents)})
+	}
+
+	for _, resource := range book.Resources {
+		files = append(files, Resource{FileName: filehandler.JoinPath(OpfFolder, resource.FileName), Contents: resource.Contents})
+	}
+
+	for _, file := range files {
+		err = filehandler.WriteZipCompressedBytes(w, file.FileName, file.Contents)
+		if err != nil {
+			return fmt.Errorf("failed to write %q to the epub: %w", file.FileName, err)
+		}
+	}
+
+	return nil
+}
+
+// getGeneratedFileNames gets the names of the files generated for the book relative to the opf folder
+// along with what they are for
+func getGeneratedFileNames(hasCover bool) map[string]string {
+	var generatedFileNames = map[string]string{
+		opfFileName: "the opf file",
+		navFileName: "the nav file",
+		ncxFileName: "the ncx file",
+	}
+
+	if hasCover {
+		generatedFileNames[coverPageName] = "the cover page"
+	}
+
+	return generatedFileNames
+}
+
+// validateFileNames makes sure that no chapter or resource would overwrite a generated file or another
+// chapter or resource when written to the epub
+func validateFileNames(book Book) error {
+	var fileNames = getGeneratedFileNames(book.CoverPath != "")
+	for _, chapter := range book.Chapters {
+		if existing, exists := fileNames[chapter.FileName]; exists {
+			return fmt.Errorf("%w: chapter %q would have the same file name as %s", ErrFileNameCollision, chapter.FileName, existing)
+		}
+
+		fileNames[chapter.FileName] = fmt.Sprintf("chapter %q", chapter.FileName)
+	}
+
+	for _, resource := range book.Resources {
+		if existing, exists := fileNames[resource.FileName]; exists {
+			return fmt.Errorf("%w: resource %q would have the same file name as %s", ErrFileNameCollision, resource.FileName, existing)
+		}
+
+		fileNames[resource.FileName] = fmt.Sprintf("resource %q", resource.FileName)
+	}
+
+	return nil
+}
+
+func getOpfMetadata(book Book) string {
+	var metadata strings.Builder
+	fmt.Fprintf(&metadata, "  <dc:identifier id=\"book-id\">%s</dc:identifier>\n", linter.EscapeText(book.Metadata.Identifier))
+	fmt.Fprintf(&metadata, "  <dc:title>%s</dc:title>\n", linter.EscapeText(book.Metadata.Title))
+	fmt.Fprintf(&metadata, "  <dc:language>%s</dc:language>\n", linter.EscapeText(book.Metadata.Language))
+
+	for i, author := range book.Metadata.Authors {
+		fmt.Fprintf(&metadata, "  <dc:creator id=\"creator-%d\">%s</dc:creator>\n", i+1, linter.EscapeText(author))
+		fmt.Fprintf(&metadata, "  <meta refines=\"#creator-%d\" property=\"role\" scheme=\"marc:relators\">aut</meta>\n", i+1)
+	}
+
+	for _, element := range []struct{ name, value string }{
+		{"publisher", book.Metadata.Publisher},
+		{"description", book.Metadata.Description},
+		{"date", book.Metadata.Date},
+	} {
+		if element.value != "" {
+			fmt.Fprintf(&metadata, "  <dc:%[1]s>%[2]s</dc:%[1]s>\n", element.name, linter.EscapeText(element.value))
+		}
+	}
+
+	fmt.Fprintf(&metadata, "  <meta property=\"dcterms:modified\">%s</meta>\n", book.Modified.UTC().Format(time.RFC3339))
+
+	if book.CoverPath != "" {
+		metadata.WriteString("  <meta name=\"cover\" content=\"cover-image\"/>\n")
+	}
+
+	return metadata.String()
+}
+
+// addManifestItem adds an item to the manifest without adding it to the spine
+func addManifestItem(opf, fileName, id, mediaType, properties string) string {
+	var propertiesAttr string
+	if properties != "" {
+		propertiesAttr = fmt.Sprintf(" properties=%q", properties)
+	}
+
+	var manifestIndex = strings.Index(opf, epubhandler.ManifestEndTag)
+	if manifestIndex == -1 {
+		return opf
+	}
+
+	return opf[:manifestIndex] + fmt.Sprintf("  <item id=%q href=%q media-type=%q%s/>\n", id, fileName, mediaType, propertiesAttr) + opf[manifestIndex:]
+}
@@ -0,0 +1,219 @@
+//go:build unit
+
+package epubbuilder_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	epubbuilder "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-builder"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const expectedOpf = `<?xml version="1.0" encoding="utf-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="en">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <dc:identifier id="book-id">urn:isbn:9780000000000</dc:identifier>
+  <dc:title>Tom &amp; Jerry's Side Story</dc:title>
+  <dc:language>en</dc:language>
+  <dc:creator id="creator-1">Author Name</dc:creator>
+  <meta refines="#creator-1" property="role" scheme="marc:relators">aut</meta>
+  <dc:publisher>Publisher</dc:publisher>
+  <meta property="dcterms:modified">2024-01-02T03:04:05Z</meta>
+  <meta name="cover" content="cover-image"/>
+</metadata>
+<manifest>
+  <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+  <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+  <item id="resource-1" href="styles/stylesheet.css" media-type="text/css"/>
+  <item id="cover-image" href="images/cover.jpg" media-type="image/jpeg" properties="cover-image"/>
+  <item id="cover" href="cover.xhtml" media-type="application/xhtml+xml"/>
+  <item id="chapter-1" href="prologue.xhtml" media-type="application/xhtml+xml"/>
+  <item id="chapter-2" href="chapter-1.xhtml" media-type="application/xhtml+xml"/>
+</manifest>
+<spine toc="ncx">
+  <itemref idref="cover"/>
+  <itemref idref="chapter-1"/>
+  <itemref idref="chapter-2"/>
+</spine>
+<guide>
+  <reference type="cover" title="Cover" href="cover.xhtml"/>
+  <reference type="toc" title="Table of Contents" href="nav.xhtml"/>
+  <reference type="text" title="Start of Content" href="prologue.xhtml"/>
+</guide>
+</package>
+`
+
+const expectedNav = `<?xml version="1.0" encoding="utf-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="en" xml:lang="en">
+<head>
+  <title>Table of Contents</title>
+</head>
+<body>
+<nav epub:type="toc" id="toc">
+<h1>Table of Contents</h1>
+<ol>
+  <li><a href="prologue.xhtml">Prologue</a></li>
+  <li><a href="chapter-1.xhtml">Chapter 1 &amp; More</a></li>
+</ol>
+</nav>
+<nav epub:type="landmarks" id="landmarks" hidden="">
+<ol>
+  <li><a epub:type="cover" href="cover.xhtml">Cover</a></li>
+  <li><a epub:type="toc" href="nav.xhtml#toc">Table of Contents</a></li>
+  <li><a epub:type="bodymatter" href="prologue.xhtml">Start of Content</a></li>
+</ol>
+</nav>
+</body>
+</html>
+`
+
+func TestWriteEpub(t *testing.T) {
+	t.Parallel()
+
+	var book = epubbuilder.Book{
+		Metadata: epubbuilder.Metadata{
+			Title:      "Tom & Jerry's Side Story",
+			Authors:    []string{"Author Name"},
+			Language:   "en",
+			Identifier: "urn:isbn:9780000000000",
+			Publisher:  "Publisher",
+		},
+		Chapters: []epubbuilder.Chapter{
+			{FileName: "prologue.xhtml", Title: "Prologue", Contents: "prologue contents"},
+			{FileName: "chapter-1.xhtml", Title: "Chapter 1 & More", Contents: "chapter contents"},
+		},
+		Resources: []epubbuilder.Resource{
+			{FileName: epubbuilder.DefaultCssPath, Contents: []byte(epubbuilder.DefaultCss)},
+			{FileName: "images/cover.jpg", Contents: []byte("not a real image")},
+		},
+		CssPath:   epubbuilder.DefaultCssPath,
+		CoverPath: "images/cover.jpg",
+		Modified:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	var epub bytes.Buffer
+	w := zip.NewWriter(&epub)
+	require.NoError(t, epubbuilder.WriteEpub(w, book))
+	require.NoError(t, w.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(epub.Bytes()), int64(epub.Len()))
+	require.NoError(t, err)
+
+	var (
+		fileNames []string
+		files     = make(map[string]string)
+	)
+	for _, file := range r.File {
+		fileNames = append(fileNames, file.Name)
+
+		contents, err := filehandler.ReadInZipFileContents(file)
+		require.NoError(t, err)
+
+		files[file.Name] = contents
+	}
+
+	assert.Equal(t, "mimetype", r.File[0].Name)
+	assert.Equal(t, zip.Store, r.File[0].Method)
+	assert.Equal(t, "application/epub+zip", files["mimetype"])
+	assert.ElementsMatch(t, []string{
+		"mimetype",
+		"META-INF/container.xml",
+		"OEBPS/content.opf",
+		"OEBPS/nav.xhtml",
+		"OEBPS/toc.ncx",
+		"OEBPS/cover.xhtml",
+		"OEBPS/prologue.xhtml",
+		"OEBPS/chapter-1.xhtml",
+		"OEBPS/styles/stylesheet.css",
+		"OEBPS/images/cover.jpg",
+	}, fileNames)
+	assert.Contains(t, files["META-INF/container.xml"], `full-path="OEBPS/content.opf"`)
+	assert.Equal(t, expectedOpf, files["OEBPS/content.opf"])
+	assert.Equal(t, expectedNav, files["OEBPS/nav.xhtml"])
+	assert.Contains(t, files["OEBPS/cover.xhtml"], `<img src="images/cover.jpg" alt="Tom &amp; Jerry&#39;s Side Story"/>`)
+	assert.Contains(t, files["OEBPS/toc.ncx"], `<navPoint id="navPoint-chapter-2" playOrder="3">`)
+
+	epubInfo, err := epubhandler.ParseOpfFile(files["OEBPS/content.opf"], "OEBPS/content.opf")
+	require.NoError(t, err)
+	assert.Equal(t, 3, epubInfo.Version)
+	assert.Equal(t, "nav.xhtml", epubInfo.NavFile)
+	assert.Equal(t, "toc.ncx", epubInfo.NcxFile)
+	assert.Equal(t, "cover.xhtml", epubInfo.CoverFile)
+	assert.Equal(t, []string{"cover.xhtml", "prologue.xhtml", "chapter-1.xhtml"}, epubInfo.FilePathsInSpineOrder)
+}
+
+func TestWriteEpubNoChapters(t *testing.T) {
+	t.Parallel()
+
+	var epub bytes.Buffer
+	err := epubbuilder.WriteEpub(zip.NewWriter(&epub), epubbuilder.Book{})
+	assert.ErrorIs(t, err, epubbuilder.ErrNoChapters)
+}
+
+type writeEpubFileNameCollisionTestCase struct {
+	chapters      []epubbuilder.Chapter
+	resources     []epubbuilder.Resource
+	coverPath     string
+	expectedError string
+}
+
+var writeEpubFileNameCollisionTestCases = map[string]writeEpubFileNameCollisionTestCase{
+	"A chapter with the same name as the nav file should result in an error": {
+		chapters: []epubbuilder.Chapter{
+			{FileName: "nav.xhtml", Title: "Nav"},
+		},
+		expectedError: `chapter "nav.xhtml" would have the same file name as the nav file`,
+	},
+	"A chapter with the same name as the cover page should result in an error when there is a cover": {
+		chapters: []epubbuilder.Chapter{
+			{FileName: "cover.xhtml", Title: "Cover"},
+		},
+		resources: []epubbuilder.Resource{
+			{FileName: "images/cover.jpg"},
+		},
+		coverPath:     "images/cover.jpg",
+		expectedError: `chapter "cover.xhtml" would have the same file name as the cover page`,
+	},
+	"A resource with the same name as the ncx file should result in an error": {
+		chapters: []epubbuilder.Chapter{
+			{FileName: "chapter-1.xhtml", Title: "Chapter 1"},
+		},
+		resources: []epubbuilder.Resource{
+			{FileName: "toc.ncx"},
+		},
+		expectedError: `resource "toc.ncx" would have the same file name as the ncx file`,
+	},
+	"Two chapters with the same name should result in an error": {
+		chapters: []epubbuilder.Chapter{
+			{FileName: "chapter-1.xhtml", Title: "Chapter 1"},
+			{FileName: "chapter-1.xhtml", Title: "Chapter 1 Again"},
+		},
+		expectedError: `chapter "chapter-1.xhtml" would have the same file name as chapter "chapter-1.xhtml"`,
+	},
+}
+
+func TestWriteEpubFileNameCollision(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range writeEpubFileNameCollisionTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var epub bytes.Buffer
+			err := epubbuilder.WriteEpub(zip.NewWriter(&epub), epubbuilder.Book{
+				Chapters:  tc.chapters,
+				Resources: tc.resources,
+				CoverPath: tc.coverPath,
+			})
+			assert.ErrorIs(t, err, epubbuilder.ErrFileNameCollision)
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package epubbuilder
+
+import (
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/adrg/frontmatter"
+	"github.com/gomarkdown/markdown"
+	mdhtml "github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+)
+
+const chapterContents = `<?xml version="1.0" encoding="utf-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="%[1]s" xml:lang="%[1]s">
+<head>
+  <title>%[2]s</title>
+  <link rel="stylesheet" type="text/css" href="%[3]s"/>
+</head>
+<body>
+%[4]s
+</body>
+</html>
+`
+
+var (
+	MarkdownExts = []string{".md", ".markdown"}
+	XhtmlExts    = []string{".xhtml", ".html", ".htm"}
+
+	headingRegex     = regexp.MustCompile(`(?is)<h[1-2][^>]*>(.*?)</h[1-2]>`)
+	bodyRegex        = regexp.MustCompile(`(?is)<body[^>]*>(.*)</body>`)
+	namedEntityRegex = regexp.MustCompile(`&([a-zA-Z][a-zA-Z0-9]*);`)
+	xmlNamedEntities = []string{"amp", "lt", "gt", "quot", "apos"}
+)
+
+type chapterMetadata struct {
+	Title string `yaml:"title"`
+}
+
+// Chapter is a content file of the book along with the title to use for it in the table of contents
+type Chapter struct {
+	// FileName is the path of the chapter relative to the opf folder
+	FileName string
+	Title    string
+	Contents string
+}
+
+// NewChapter converts the Markdown or XHTML source of a chapter into an XHTML chapter.
+// The title comes from the markdown frontmatter, the first h1 or h2, or the file name in that order.
+func NewChapter(sourcePath, contents, language, cssPath string) (Chapter, error) {
+	var (
+		ext      = strings.ToLower(path.Ext(sourcePath))
+		fileName = strings.TrimSuffix(sourcePath, path.Ext(sourcePath)) + ".xhtml"
+		title    string
+		body     string
+	)
+	switch {
+	case slices.Contains(MarkdownExts, ext):
+		var metadata chapterMetadata
+		mdContents, err := frontmatter.Parse(strings.NewReader(contents), &metadata)
+		if err != nil {
+			return Chapter{}, fmt.Errorf("failed to get the frontmatter for %q: %w", sourcePath, err)
+		}
+
+		title = metadata.Title
+		body = mdToXhtml(mdContents)
+	case slices.Contains(XhtmlExts, ext):
+		body = contents
+		if bodyMatch := bodyRegex.FindStringSubmatch(contents); bodyMatch != nil {
+			body = bodyMatch[1]
+		}
+
+		body = strings.TrimSpace(replaceNamedEntities(body))
+	default:
+		return Chapter{}, fmt.Errorf("%q is not a markdown or xhtml file", sourcePath)
+	}
+
+	if title == "" {
+		title = getTitle(body, sourcePath)
+	}
+
+	relativeCssPath := strings.Repeat("../", strings.Count(fileName, "/")) + cssPath
+
+	return Chapter{
+		FileName: fileName,
+		Title:    title,
+		Contents: fmt.Sprintf(chapterContents, language, linter.EscapeText(title), relativeCssPath, body),
+	}, nil
+}
+
+func mdToXhtml(md []byte) string {
+	var (
+		p        = parser.NewWithExtensions(parser.CommonExtensions | parser.AutoHeadingIDs)
+		renderer = mdhtml.NewRenderer(mdhtml.RendererOptions{Flags: mdhtml.CommonFlags | mdhtml.UseXHTML})
+	)
+
+	return strings.TrimSpace(replaceNamedEntities(string(markdown.ToHTML(md, p, renderer))))
+}
+
+// replaceNamedEntities replaces the html named entities that are not defined in xml like &ldquo; with their characters
+func replaceNamedEntities(text string) string {
+	return namedEntityRegex.ReplaceAllStringFunc(text, func(entity string) string {
+		if slices.Contains(xmlNamedEntities, entity[1:len(entity)-1]) {
+			return entity
+		}
+
+		return html.UnescapeString(entity)
+	})
+}
+
+func getTitle(body, sourcePath string) string {
+	if headingMatch := headingRegex.FindStringSubmatch(body); headingMatch != nil {
+		var title = strings.Join(strings.Fields(html.UnescapeString(linter.HtmlTagRegex.ReplaceAllString(headingMatch[1], ""))), " ")
+		if title != "" {
+			return title
+		}
+	}
+
+	return strings.TrimSuffix(path.Base(sourcePath), path.Ext(sourcePath))
+}
@@ -0,0 +1,119 @@
+//go:build unit
+
+package epubbuilder_test
+
+import (
+	"testing"
+
+	epubbuilder "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-builder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type newChapterTestCase struct {
+	sourcePath       string
+	contents         string
+	expectedFileName string
+	expectedTitle    string
+	expectedContents string
+}
+
+var newChapterTestCases = map[string]newChapterTestCase{
+	"a markdown chapter uses the title from its frontmatter and has html entities converted to characters": {
+		sourcePath: "prologue.md",
+		contents: `---
+title: Prologue
+---
+# The Beginning
+
+"Hello" -- world
+
+***
+`,
+		expectedFileName: "prologue.xhtml",
+		expectedTitle:    "Prologue",
+		expectedContents: `<?xml version="1.0" encoding="utf-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="en" xml:lang="en">
+<head>
+  <title>Prologue</title>
+  <link rel="stylesheet" type="text/css" href="styles/stylesheet.css"/>
+</head>
+<body>
+<h1 id="the-beginning">The Beginning</h1>
+
+<p>“Hello” – world</p>
+
+<hr />
+</body>
+</html>
+`,
+	},
+	"a markdown chapter without frontmatter in a subfolder uses its first heading as the title and a relative stylesheet path": {
+		sourcePath: "part-1/chapter-1.md",
+		contents: `## Chapter 1: *The* Start
+
+Some text & more text.
+`,
+		expectedFileName: "part-1/chapter-1.xhtml",
+		expectedTitle:    "Chapter 1: The Start",
+		expectedContents: `<?xml version="1.0" encoding="utf-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="en" xml:lang="en">
+<head>
+  <title>Chapter 1: The Start</title>
+  <link rel="stylesheet" type="text/css" href="../styles/stylesheet.css"/>
+</head>
+<body>
+<h2 id="chapter-1-the-start">Chapter 1: <em>The</em> Start</h2>
+
+<p>Some text &amp; more text.</p>
+</body>
+</html>
+`,
+	},
+	"an xhtml chapter has its body rewrapped and falls back to the file name for the title when it has no heading": {
+		sourcePath: "afterword.html",
+		contents: `<html><head><title>Old Title</title></head><body>
+<p>Thanks&nbsp;for reading &amp; see you next time!</p>
+</body></html>`,
+		expectedFileName: "afterword.xhtml",
+		expectedTitle:    "afterword",
+		expectedContents: `<?xml version="1.0" encoding="utf-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="en" xml:lang="en">
+<head>
+  <title>afterword</title>
+  <link rel="stylesheet" type="text/css" href="styles/stylesheet.css"/>
+</head>
+<body>
+<p>Thanks` + "\u00a0" + `for reading &amp; see you next time!</p>
+</body>
+</html>
+`,
+	},
+}
+
+func TestNewChapter(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range newChapterTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			chapter, err := epubbuilder.NewChapter(args.sourcePath, args.contents, "en", epubbuilder.DefaultCssPath)
+			require.NoError(t, err)
+
+			assert.Equal(t, args.expectedFileName, chapter.FileName)
+			assert.Equal(t, args.expectedTitle, chapter.Title)
+			assert.Equal(t, args.expectedContents, chapter.Contents)
+		})
+	}
+}
+
+func TestNewChapterUnsupportedFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := epubbuilder.NewChapter("notes.txt", "some notes", "en", epubbuilder.DefaultCssPath)
+	assert.EqualError(t, err, `"notes.txt" is not a markdown or xhtml file`)
+}
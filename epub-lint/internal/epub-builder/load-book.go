@@ -0,0 +1,122 @@
+package epubbuilder
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+// LoadBook reads in the chapters and resources in the source folder and converts them into a book.
+// Files and folders that start with a "." are ignored.
+func LoadBook(dir string, metadata Metadata, modified time.Time) (Book, error) {
+	var (
+		chapterSources []string
+		resourcePaths  []string
+	)
+	err := filepath.WalkDir(dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(d.Name(), ".") && filePath != dir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		relativePath = filepath.ToSlash(relativePath)
+
+		var ext = strings.ToLower(path.Ext(relativePath))
+		if slices.Contains(MarkdownExts, ext) || slices.Contains(XhtmlExts, ext) {
+			chapterSources = append(chapterSources, relativePath)
+		} else if _, isResource := GetMediaType(relativePath); isResource {
+			resourcePaths = append(resourcePaths, relativePath)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Book{}, fmt.Errorf("failed to get the files in %q: %w", dir, err)
+	}
+
+	var book = Book{
+		Metadata:  metadata,
+		CssPath:   DefaultCssPath,
+		CoverPath: metadata.Cover,
+		Modified:  modified,
+	}
+
+	if metadata.Css != "" {
+		if !slices.Contains(resourcePaths, metadata.Css) {
+			return book, fmt.Errorf("failed to find stylesheet %q in %q", metadata.Css, dir)
+		}
+
+		book.CssPath = metadata.Css
+	} else if !slices.Contains(resourcePaths, DefaultCssPath) {
+		book.Resources = append(book.Resources, Resource{
+			FileName: DefaultCssPath,
+			Contents: []byte(DefaultCss),
+		})
+	}
+
+	if metadata.Cover != "" && !slices.Contains(resourcePaths, metadata.Cover) {
+		return book, fmt.Errorf("failed to find cover image %q in %q", metadata.Cover, dir)
+	}
+
+	for _, resourcePath := range resourcePaths {
+		contents, err := filehandler.ReadInBinaryFileContents(filepath.Join(dir, resourcePath))
+		if err != nil {
+			return book, err
+		}
+
+		book.Resources = append(book.Resources, Resource{
+			FileName: resourcePath,
+			Contents: contents,
+		})
+	}
+
+	if len(metadata.Chapters) != 0 {
+		chapterSources = metadata.Chapters
+	} else {
+		slices.Sort(chapterSources)
+	}
+
+	var chapterFiles = getGeneratedFileNames(book.CoverPath != "")
+	for _, chapterSource := range chapterSources {
+		contents, err := filehandler.ReadInFileContents(filepath.Join(dir, chapterSource))
+		if err != nil {
+			return book, err
+		}
+
+		chapter, err := NewChapter(chapterSource, contents, metadata.Language, book.CssPath)
+		if err != nil {
+			return book, err
+		}
+
+		if otherSource, exists := chapterFiles[chapter.FileName]; exists {
+			return book, fmt.Errorf("chapter %q would have the same file name as %s: please rename it", chapterSource, otherSource)
+		}
+
+		chapterFiles[chapter.FileName] = fmt.Sprintf("%q", chapterSource)
+		book.Chapters = append(book.Chapters, chapter)
+	}
+
+	return book, nil
+}
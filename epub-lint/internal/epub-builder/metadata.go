@@ -0,0 +1,72 @@
+package epubbuilder
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const DefaultLanguage = "en"
+
+var ErrMissingTitle = errors.New("the metadata must have a title")
+
+// Metadata is the information about the book that gets read in from the yaml metadata file
+type Metadata struct {
+	Title       string   `yaml:"title"`
+	Authors     []string `yaml:"authors"`
+	Language    string   `yaml:"language"`
+	Identifier  string   `yaml:"identifier"`
+	Publisher   string   `yaml:"publisher"`
+	Description string   `yaml:"description"`
+	Date        string   `yaml:"date"`
+	// Cover is the path to the cover image relative to the source folder
+	Cover string `yaml:"cover"`
+	// Css is the path to the stylesheet relative to the source folder which replaces the default stylesheet
+	Css string `yaml:"css"`
+	// Chapters is the paths of the chapters relative to the source folder in reading order.
+	// When it is empty, all chapters in the source folder are used in file name order.
+	Chapters []string `yaml:"chapters"`
+}
+
+// ParseMetadata parses the yaml metadata and fills in the language and identifier when they are not provided
+func ParseMetadata(contents string) (Metadata, error) {
+	var metadata Metadata
+	err := yaml.Unmarshal([]byte(contents), &metadata)
+	if err != nil {
+		return metadata, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+
+	if strings.TrimSpace(metadata.Title) == "" {
+		return metadata, ErrMissingTitle
+	}
+
+	if metadata.Language == "" {
+		metadata.Language = DefaultLanguage
+	}
+
+	if metadata.Identifier == "" {
+		metadata.Identifier, err = newUuidIdentifier()
+		if err != nil {
+			return metadata, err
+		}
+	}
+
+	return metadata, nil
+}
+
+// newUuidIdentifier creates a random version 4 uuid urn to use as the book's unique identifier
+func newUuidIdentifier() (string, error) {
+	var uuid [16]byte
+	_, err := rand.Read(uuid[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to generate a book identifier: %w", err)
+	}
+
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16]), nil
+}
@@ -0,0 +1,58 @@
+//go:build unit
+
+package epubbuilder_test
+
+import (
+	"regexp"
+	"testing"
+
+	epubbuilder "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-builder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var uuidRegex = regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestParseMetadata(t *testing.T) {
+	t.Parallel()
+
+	metadata, err := epubbuilder.ParseMetadata(`title: The Side Story
+authors:
+  - First Author
+  - Second Author
+language: ja
+identifier: urn:isbn:9780000000000
+cover: images/cover.jpg
+chapters:
+  - prologue.md
+  - chapter-1.xhtml
+`)
+	require.NoError(t, err)
+	assert.Equal(t, epubbuilder.Metadata{
+		Title:      "The Side Story",
+		Authors:    []string{"First Author", "Second Author"},
+		Language:   "ja",
+		Identifier: "urn:isbn:9780000000000",
+		Cover:      "images/cover.jpg",
+		Chapters:   []string{"prologue.md", "chapter-1.xhtml"},
+	}, metadata)
+}
+
+func TestParseMetadataDefaults(t *testing.T) {
+	t.Parallel()
+
+	metadata, err := epubbuilder.ParseMetadata("title: The Side Story\n")
+	require.NoError(t, err)
+	assert.Equal(t, epubbuilder.DefaultLanguage, metadata.Language)
+	assert.Regexp(t, uuidRegex, metadata.Identifier)
+}
+
+func TestParseMetadataErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := epubbuilder.ParseMetadata("authors: [Someone]\n")
+	assert.ErrorIs(t, err, epubbuilder.ErrMissingTitle)
+
+	_, err = epubbuilder.ParseMetadata("title: [unclosed\n")
+	assert.ErrorContains(t, err, "failed to parse metadata")
+}
@@ -13,4 +13,5 @@ const (
 	unexpectedSectionEl     = "Error while parsing file: element \"section\" not allowed here"
 	emptyTitleEl            = "Error while parsing file: Element \"title\" must not be empty."
 	noTitleEl               = "Warning while parsing file: The \"head\" element should have a \"title\" child element."
+	html5Doctype            = "<!DOCTYPE html>"
 )
@@ -0,0 +1,6 @@
+//go:build unit
+
+package epubcheck
+
+// HandledCodes exposes the codes that are summarized as handled to the tests
+var HandledCodes = handledCodes
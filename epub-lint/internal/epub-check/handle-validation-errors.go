@@ -1,7 +1,9 @@
 package epubcheck
 
 import (
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
@@ -15,6 +17,13 @@ func HandleValidationErrors(opfFolder, ncxFilename, opfFilename string, nameToUp
 		elementNameToNumber         = make(map[string]int)
 		fileToChanges               = make(map[string]positions.TextDocumentEdit)
 		hasHandledPlayOrder         bool
+		handledEntityFiles          = make(map[string]struct{})
+		// the remote links of nav files are removed after all of the other edits since removing them can change
+		// most of the nav file which would throw off the positions of the other nav edits
+		navFilesWithRemoteLinks []string
+		// the manifest and spine fixes are made after all of the other edits since adding elements to the OPF
+		// would throw off the positions of the other OPF issues
+		filesToAddToManifest, filesToFixMediaTypeFor, filesToAddToSpine []string
 	)
 	for i := 0; i < len(validationErrors.ValidationIssues); i++ {
 		var (
@@ -378,11 +387,153 @@ func HandleValidationErrors(opfFolder, ncxFilename, opfFilename string, nameToUp
 				fileUpdated = message.FilePath
 				edits = append(edits, update)
 			}
+		case "OPF-003", "RSC-008":
+			resource, foundResource := getFirstQuotedValue(message.Message, -1)
+			if !foundResource {
+				continue
+			}
+
+			var filePath = getEpubFilePath(resource, message.FilePath, basenameToFilePaths)
+			if filePath != "" && filePath != opfFilename && !slices.Contains(filesToAddToManifest, filePath) {
+				filesToAddToManifest = append(filesToAddToManifest, filePath)
+			}
+		case "OPF-037", "OPF-038", "OPF-043":
+			if message.Location == nil {
+				continue
+			}
+
+			fileContent, err = getContentByFileName(opfFilename)
+			if err != nil {
+				return err
+			}
+
+			var filePath = rulefixes.GetManifestItemPathAtPosition(fileContent, opfFolder, message.Location.Line, message.Location.Column)
+			if filePath != "" && !slices.Contains(filesToFixMediaTypeFor, filePath) {
+				filesToFixMediaTypeFor = append(filesToFixMediaTypeFor, filePath)
+			}
+		case "RSC-010", "RSC-011":
+			if message.Location == nil {
+				continue
+			}
+
+			fileContent, err = getContentByFileName(message.FilePath)
+			if err != nil {
+				return err
+			}
+
+			var filePath = rulefixes.GetLinkTargetAtPosition(fileContent, message.FilePath, message.Location.Line, message.Location.Column)
+			if filePath == "" {
+				continue
+			}
+
+			if message.Code == "RSC-010" && !slices.Contains(filesToFixMediaTypeFor, filePath) {
+				filesToFixMediaTypeFor = append(filesToFixMediaTypeFor, filePath)
+			} else if message.Code == "RSC-011" && !slices.Contains(filesToAddToSpine, filePath) {
+				filesToAddToSpine = append(filesToAddToSpine, filePath)
+			}
+		case "RSC-009":
+			if message.Location == nil {
+				continue
+			}
+
+			fileContent, err = getContentByFileName(message.FilePath)
+			if err != nil {
+				return err
+			}
+
+			update := rulefixes.RemoveLinkId(fileContent, message.Location.Line, message.Location.Column)
+			if !update.IsEmpty() {
+				fileUpdated = message.FilePath
+				edits = append(edits, update)
+			}
+		case "CSS-008":
+			if message.Location == nil {
+				continue
+			}
+
+			fileContent, err = getContentByFileName(message.FilePath)
+			if err != nil {
+				return err
+			}
+
+			update := rulefixes.FixCssParseError(message.Location.Line, message.Location.Column, fileContent)
+			if !update.IsEmpty() {
+				fileUpdated = message.FilePath
+				edits = append(edits, update)
+			}
+		case "HTM-009":
+			fileContent, err = getContentByFileName(message.FilePath)
+			if err != nil {
+				return err
+			}
+
+			update := rulefixes.FixIrregularDoctype(fileContent, html5Doctype)
+			if !update.IsEmpty() {
+				fileUpdated = message.FilePath
+				edits = append(edits, update)
+			}
+		case "HTM-010":
+			namespace, foundNamespace := getFirstQuotedValue(message.Message, -1)
+			if !foundNamespace {
+				continue
+			}
+
+			fileContent, err = getContentByFileName(message.FilePath)
+			if err != nil {
+				return err
+			}
+
+			update := rulefixes.FixXhtmlNamespace(fileContent, namespace)
+			if !update.IsEmpty() {
+				fileUpdated = message.FilePath
+				edits = append(edits, update)
+			}
+		case "HTM-011":
+			// all of the undeclared entities in the file get replaced at once, so the file only needs to be handled once
+			if _, handled := handledEntityFiles[message.FilePath]; handled {
+				continue
+			}
+
+			handledEntityFiles[message.FilePath] = struct{}{}
+
+			fileContent, err = getContentByFileName(message.FilePath)
+			if err != nil {
+				return err
+			}
+
+			fileUpdated = message.FilePath
+			edits = rulefixes.ReplaceUndeclaredEntities(fileContent)
+		case "NAV-010":
+			// all of the remote links in the nav file get removed at once, so the file only needs to be handled once
+			if !slices.Contains(navFilesWithRemoteLinks, message.FilePath) {
+				navFilesWithRemoteLinks = append(navFilesWithRemoteLinks, message.FilePath)
+			}
+		case "NCX-006":
+			if message.Location == nil {
+				continue
+			}
+
+			fileContent, err = getContentByFileName(message.FilePath)
+			if err != nil {
+				return err
+			}
+
+			update := rulefixes.FixEmptyNavLabel(message.Location.Line, message.Location.Column, fileContent, path.Dir(message.FilePath), getContentByFileName)
+			if !update.IsEmpty() {
+				fileUpdated = message.FilePath
+				edits = append(edits, update)
+			}
 		}
 
 		if len(edits) != 0 {
 			if existingUpdates, ok := fileToChanges[fileUpdated]; ok {
-				existingUpdates.Edits = append(existingUpdates.Edits, edits...)
+				// the same edit can be found for multiple issues, but it should only be applied once
+				for _, edit := range edits {
+					if !slices.Contains(existingUpdates.Edits, edit) {
+						existingUpdates.Edits = append(existingUpdates.Edits, edit)
+					}
+				}
+
 				fileToChanges[fileUpdated] = existingUpdates
 			} else {
 				fileToChanges[fileUpdated] = positions.TextDocumentEdit{
@@ -408,9 +559,115 @@ func HandleValidationErrors(opfFolder, ncxFilename, opfFilename string, nameToUp
 		nameToUpdatedContents[filePath] = updatedContents
 	}
 
+	err = removeRemoteNavLinks(navFilesWithRemoteLinks, nameToUpdatedContents, getContentByFileName)
+	if err != nil {
+		return err
+	}
+
+	return fixManifestAndSpine(opfFolder, opfFilename, nameToUpdatedContents, getContentByFileName, filesToAddToManifest, filesToFixMediaTypeFor, filesToAddToSpine)
+}
+
+// removeRemoteNavLinks removes the remote links from the nav files based on their contents after all of the other edits have been made
+func removeRemoteNavLinks(navFiles []string, nameToUpdatedContents map[string]string, getContentByFileName func(string) (string, error)) error {
+	for _, navFile := range navFiles {
+		navContents, err := getContentByFileName(navFile)
+		if err != nil {
+			return err
+		}
+
+		update, err := rulefixes.RemoveRemoteNavLinks(navContents)
+		if err != nil {
+			return err
+		}
+
+		err = applyEdit(navFile, navContents, update, nameToUpdatedContents)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// fixManifestAndSpine adds the missing manifest items, fixes the media types of manifest items, and adds the linked files that are
+// not in the spine to the spine in that order so files that get added to the manifest can have their media type fixed and be added to the spine
+func fixManifestAndSpine(opfFolder, opfFilename string, nameToUpdatedContents map[string]string, getContentByFileName func(string) (string, error), filesToAddToManifest, filesToFixMediaTypeFor, filesToAddToSpine []string) error {
+	var fixes []func(string) (positions.TextEdit, error)
+	for _, filePath := range filesToAddToManifest {
+		fixes = append(fixes, func(opfContents string) (positions.TextEdit, error) {
+			return rulefixes.AddMissingManifestItem(opfContents, opfFolder, filePath)
+		})
+	}
+
+	for _, filePath := range filesToFixMediaTypeFor {
+		fixes = append(fixes, func(opfContents string) (positions.TextEdit, error) {
+			return rulefixes.FixManifestMediaType(opfContents, opfFolder, filePath)
+		})
+	}
+
+	for _, filePath := range filesToAddToSpine {
+		fixes = append(fixes, func(opfContents string) (positions.TextEdit, error) {
+			return rulefixes.AddNonLinearSpineItem(opfContents, opfFolder, filePath)
+		})
+	}
+
+	for _, fix := range fixes {
+		opfContents, err := getContentByFileName(opfFilename)
+		if err != nil {
+			return err
+		}
+
+		update, err := fix(opfContents)
+		if err != nil {
+			return err
+		}
+
+		err = applyEdit(opfFilename, opfContents, update, nameToUpdatedContents)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyEdit applies the edit to the file contents right away updating the contents of the file when the edit is not empty
+func applyEdit(filePath, fileContent string, edit positions.TextEdit, nameToUpdatedContents map[string]string) error {
+	if edit.IsEmpty() {
+		return nil
+	}
+
+	updatedContents, err := positions.ApplyEdits(filePath, fileContent, []positions.TextEdit{edit})
+	if err != nil {
+		return err
+	}
+
+	nameToUpdatedContents[filePath] = updatedContents
+
+	return nil
+}
+
+// getEpubFilePath gets the path relative to the root of the epub for the resource which is either already that path
+// or is relative to the current file returning an empty string when the resource is not in the epub
+func getEpubFilePath(resource, currentFile string, basenameToFilePaths map[string][]string) string {
+	resource, _, _ = strings.Cut(resource, "#")
+	if resource == "" {
+		return ""
+	}
+
+	var filePaths = basenameToFilePaths[path.Base(resource)]
+	if slices.Contains(filePaths, resource) {
+		return resource
+	}
+
+	var relativeToCurrentFile = path.Join(path.Dir(currentFile), resource)
+	if slices.Contains(filePaths, relativeToCurrentFile) {
+		return relativeToCurrentFile
+	}
+
+	return ""
+}
+
 // getFirstQuotedValue takes in a message and a potential start index
 // if start index is -1 then it will find the first double quote itself
 func getFirstQuotedValue(message string, startIndex int) (string, bool) {
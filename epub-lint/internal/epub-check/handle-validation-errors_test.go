@@ -141,6 +141,48 @@ var (
 	xhtmlOutOfOrderNavOriginal string
 	//go:embed testdata/nav-11/out-of-order-nav_updated.xhtml
 	xhtmlOutOfOrderNavExpected string
+	//go:embed testdata/opf-3/missing-manifest-item.opf
+	opfMissingManifestItemOriginal string
+	//go:embed testdata/opf-3/missing-manifest-item_updated.opf
+	opfMissingManifestItemExpected string
+	//go:embed testdata/opf-3/chapter1.xhtml
+	xhtmlUnlistedLinksOriginal string
+	//go:embed testdata/opf-37/deprecated-media-types.opf
+	opfDeprecatedMediaTypesOriginal string
+	//go:embed testdata/opf-37/deprecated-media-types_updated.opf
+	opfDeprecatedMediaTypesExpected string
+	//go:embed testdata/opf-38/inappropriate-media-types.opf
+	opfInappropriateMediaTypesOriginal string
+	//go:embed testdata/opf-38/inappropriate-media-types_updated.opf
+	opfInappropriateMediaTypesExpected string
+	//go:embed testdata/opf-74/duplicate-manifest-entries.opf
+	opfDuplicateManifestEntriesOriginal string
+	//go:embed testdata/opf-74/duplicate-manifest-entries_updated.opf
+	opfDuplicateManifestEntriesExpected string
+	//go:embed testdata/rsc-9/image-fragment.xhtml
+	xhtmlImageFragmentOriginal string
+	//go:embed testdata/rsc-9/image-fragment_updated.xhtml
+	xhtmlImageFragmentExpected string
+	//go:embed testdata/css-8/parse-errors.css
+	cssParseErrorsOriginal string
+	//go:embed testdata/css-8/parse-errors_updated.css
+	cssParseErrorsExpected string
+	//go:embed testdata/htm-10/invalid-markup.html
+	htmlInvalidMarkupOriginal string
+	//go:embed testdata/htm-10/invalid-markup_updated.html
+	htmlInvalidMarkupExpected string
+	//go:embed testdata/nav-10/remote-links-nav.xhtml
+	xhtmlRemoteLinksNavOriginal string
+	//go:embed testdata/nav-10/remote-links-nav_updated.xhtml
+	xhtmlRemoteLinksNavExpected string
+	//go:embed testdata/nav-10/remote-links-out-of-order-nav.xhtml
+	xhtmlRemoteLinksOutOfOrderNavOriginal string
+	//go:embed testdata/nav-10/remote-links-out-of-order-nav_updated.xhtml
+	xhtmlRemoteLinksOutOfOrderNavExpected string
+	//go:embed testdata/ncx-6/empty-nav-label.ncx
+	ncxEmptyNavLabelOriginal string
+	//go:embed testdata/ncx-6/empty-nav-label_updated.ncx
+	ncxEmptyNavLabelExpected string
 )
 
 func createTestCaseFileHandlerFunction(validFilesToContent map[string]string, currentContents map[string]string) func(string) (string, error) {
@@ -1709,6 +1751,626 @@ var handleValidationErrorTestCases = map[string]handleValidationErrorTestCase{
 			"OPS/Text/file.html": htmlFileReferencedDoesNotExistDoubleScriptOriginal,
 		},
 	},
+	"OPF 3, RSC 8, and RSC 11: Files missing from the manifest get added to it and linked files missing from the spine get added to the end of it as non-linear items": {
+		opfFolder:   "OPS",
+		opfFilename: "OPS/content.opf",
+		ncxFilename: "OPS/toc.ncx",
+		expectedFileState: map[string]string{
+			"OPS/content.opf": opfMissingManifestItemExpected,
+		},
+		basenameToFilePaths: map[string][]string{
+			"cover.jpg":      {"OPS/Images/cover.jpg"},
+			"appendix.xhtml": {"OPS/Text/appendix.xhtml"},
+			"notes.xhtml":    {"OPS/Text/notes.xhtml"},
+		},
+		validationErrors: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "OPF-003",
+					FilePath: "OPS/Images/cover.jpg",
+					Message:  `Item "OPS/Images/cover.jpg" exists in the EPUB, but is not declared in the OPF manifest.`,
+				},
+				{
+					Code:     "RSC-008",
+					FilePath: "OPS/Text/chapter1.xhtml",
+					Message:  `Referenced resource "OPS/Text/appendix.xhtml" is not declared in the OPF manifest.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 84,
+					},
+				},
+				{
+					Code:     "RSC-011",
+					FilePath: "OPS/Text/chapter1.xhtml",
+					Message:  `Found a reference to a resource that is not a spine item.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 41,
+					},
+				},
+				{
+					Code:     "RSC-011",
+					FilePath: "OPS/Text/chapter1.xhtml",
+					Message:  `Found a reference to a resource that is not a spine item.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 84,
+					},
+				},
+			},
+		},
+		expectedErrorState: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "OPF-003",
+					FilePath: "OPS/Images/cover.jpg",
+					Message:  `Item "OPS/Images/cover.jpg" exists in the EPUB, but is not declared in the OPF manifest.`,
+				},
+				{
+					Code:     "RSC-008",
+					FilePath: "OPS/Text/chapter1.xhtml",
+					Message:  `Referenced resource "OPS/Text/appendix.xhtml" is not declared in the OPF manifest.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 84,
+					},
+				},
+				{
+					Code:     "RSC-011",
+					FilePath: "OPS/Text/chapter1.xhtml",
+					Message:  `Found a reference to a resource that is not a spine item.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 41,
+					},
+				},
+				{
+					Code:     "RSC-011",
+					FilePath: "OPS/Text/chapter1.xhtml",
+					Message:  `Found a reference to a resource that is not a spine item.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 84,
+					},
+				},
+			},
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/content.opf":         opfMissingManifestItemOriginal,
+			"OPS/Text/chapter1.xhtml": xhtmlUnlistedLinksOriginal,
+		},
+	},
+	"OPF 37 and OPF 43: Deprecated media types in the manifest get replaced with the media type for the file extension": {
+		opfFolder:   "OPS",
+		opfFilename: "OPS/content.opf",
+		ncxFilename: "OPS/toc.ncx",
+		expectedFileState: map[string]string{
+			"OPS/content.opf": opfDeprecatedMediaTypesExpected,
+		},
+		validationErrors: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "OPF-043",
+					FilePath: "OPS/content.opf",
+					Message:  `Spine item with non-standard media-type "text/x-oeb1-document" with no fallback.`,
+					Location: &epubcheck.Position{
+						Line:   14,
+						Column: 31,
+					},
+				},
+				{
+					Code:     "OPF-037",
+					FilePath: "OPS/content.opf",
+					Message:  `Found deprecated media-type "text/x-oeb1-css".`,
+					Location: &epubcheck.Position{
+						Line:   11,
+						Column: 75,
+					},
+				},
+				{
+					Code:     "OPF-037",
+					FilePath: "OPS/content.opf",
+					Message:  `Found deprecated media-type "text/x-oeb1-document".`,
+					Location: &epubcheck.Position{
+						Line:   10,
+						Column: 85,
+					},
+				},
+			},
+		},
+		expectedErrorState: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "OPF-043",
+					FilePath: "OPS/content.opf",
+					Message:  `Spine item with non-standard media-type "text/x-oeb1-document" with no fallback.`,
+					Location: &epubcheck.Position{
+						Line:   14,
+						Column: 31,
+					},
+				},
+				{
+					Code:     "OPF-037",
+					FilePath: "OPS/content.opf",
+					Message:  `Found deprecated media-type "text/x-oeb1-css".`,
+					Location: &epubcheck.Position{
+						Line:   11,
+						Column: 75,
+					},
+				},
+				{
+					Code:     "OPF-037",
+					FilePath: "OPS/content.opf",
+					Message:  `Found deprecated media-type "text/x-oeb1-document".`,
+					Location: &epubcheck.Position{
+						Line:   10,
+						Column: 85,
+					},
+				},
+			},
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/content.opf": opfDeprecatedMediaTypesOriginal,
+		},
+	},
+	"OPF 38 and RSC 10: Inappropriate media types in the manifest and media types of linked files that do not match the file get replaced with the media type for the file extension": {
+		opfFolder:   "OPS",
+		opfFilename: "OPS/content.opf",
+		ncxFilename: "OPS/toc.ncx",
+		expectedFileState: map[string]string{
+			"OPS/content.opf": opfInappropriateMediaTypesExpected,
+		},
+		validationErrors: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "OPF-038",
+					FilePath: "OPS/content.opf",
+					Message:  `Media type "image/jpg" is not appropriate for the item.`,
+					Location: &epubcheck.Position{
+						Line:   12,
+						Column: 70,
+					},
+				},
+				{
+					Code:     "RSC-010",
+					FilePath: "OPS/Text/chapter1.xhtml",
+					Message:  `Reference to non-standard resource type found.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 41,
+					},
+				},
+			},
+		},
+		expectedErrorState: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "OPF-038",
+					FilePath: "OPS/content.opf",
+					Message:  `Media type "image/jpg" is not appropriate for the item.`,
+					Location: &epubcheck.Position{
+						Line:   12,
+						Column: 70,
+					},
+				},
+				{
+					Code:     "RSC-010",
+					FilePath: "OPS/Text/chapter1.xhtml",
+					Message:  `Reference to non-standard resource type found.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 41,
+					},
+				},
+			},
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/content.opf":         opfInappropriateMediaTypesOriginal,
+			"OPS/Text/chapter1.xhtml": xhtmlUnlistedLinksOriginal,
+		},
+	},
+	"OPF 74: Duplicate manifest entries get removed along with their spine items": {
+		opfFolder:   "OPS",
+		opfFilename: "OPS/content.opf",
+		ncxFilename: "OPS/toc.ncx",
+		expectedFileState: map[string]string{
+			"OPS/content.opf": opfDuplicateManifestEntriesExpected,
+		},
+		validationErrors: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "OPF-074",
+					FilePath: "OPS/content.opf",
+					Message:  `Package resource "OPS/Text/chapter1.xhtml" is declared in several manifest item.`,
+					Location: &epubcheck.Position{
+						Line:   11,
+						Column: 93,
+					},
+				},
+			},
+		},
+		expectedErrorState: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "OPF-074",
+					FilePath: "OPS/content.opf",
+					Message:  `Package resource "OPS/Text/chapter1.xhtml" is declared in several manifest item.`,
+					Location: &epubcheck.Position{
+						Line:   11,
+						Column: 93,
+					},
+				},
+			},
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/content.opf": opfDuplicateManifestEntriesOriginal,
+		},
+	},
+	"RSC 9: Fragment identifiers get removed from references to images": {
+		opfFolder:   "OPS",
+		opfFilename: "OPS/content.opf",
+		ncxFilename: "OPS/toc.ncx",
+		expectedFileState: map[string]string{
+			"OPS/Text/chapter1.xhtml": xhtmlImageFragmentExpected,
+		},
+		validationErrors: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "RSC-009",
+					FilePath: "OPS/Text/chapter1.xhtml",
+					Message:  `A non-content image resource is referenced with a fragment identifier.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 67,
+					},
+				},
+			},
+		},
+		expectedErrorState: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "RSC-009",
+					FilePath: "OPS/Text/chapter1.xhtml",
+					Message:  `A non-content image resource is referenced with a fragment identifier.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 67,
+					},
+				},
+			},
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/Text/chapter1.xhtml": xhtmlImageFragmentOriginal,
+		},
+	},
+	"CSS 8: Declarations and closing braces that cannot be parsed get removed": {
+		opfFolder:   "OPS",
+		opfFilename: "OPS/content.opf",
+		ncxFilename: "OPS/toc.ncx",
+		expectedFileState: map[string]string{
+			"OPS/Styles/style.css": cssParseErrorsExpected,
+		},
+		validationErrors: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "CSS-008",
+					FilePath: "OPS/Styles/style.css",
+					Message:  `An error occurred while parsing the CSS: Token ";" not allowed here, expecting ":".`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 12,
+					},
+				},
+				{
+					Code:     "CSS-008",
+					FilePath: "OPS/Styles/style.css",
+					Message:  `An error occurred while parsing the CSS: Token "}" not allowed here.`,
+					Location: &epubcheck.Position{
+						Line:   5,
+						Column: 1,
+					},
+				},
+				{
+					Code:     "CSS-008",
+					FilePath: "OPS/Styles/style.css",
+					Message:  `An error occurred while parsing the CSS: Token ";" not allowed here, expecting ":".`,
+					Location: &epubcheck.Position{
+						Line:   3,
+						Column: 18,
+					},
+				},
+			},
+		},
+		expectedErrorState: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "CSS-008",
+					FilePath: "OPS/Styles/style.css",
+					Message:  `An error occurred while parsing the CSS: Token ";" not allowed here, expecting ":".`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 12,
+					},
+				},
+				{
+					Code:     "CSS-008",
+					FilePath: "OPS/Styles/style.css",
+					Message:  `An error occurred while parsing the CSS: Token "}" not allowed here.`,
+					Location: &epubcheck.Position{
+						Line:   5,
+						Column: 1,
+					},
+				},
+				{
+					Code:     "CSS-008",
+					FilePath: "OPS/Styles/style.css",
+					Message:  `An error occurred while parsing the CSS: Token ";" not allowed here, expecting ":".`,
+					Location: &epubcheck.Position{
+						Line:   3,
+						Column: 18,
+					},
+				},
+			},
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/Styles/style.css": cssParseErrorsOriginal,
+		},
+	},
+	"HTM 9, HTM 10, and HTM 11: An irregular doctype, a misspelled XHTML namespace, and undeclared entities get fixed in the same file": {
+		opfFolder:   "OPS",
+		opfFilename: "OPS/content.opf",
+		ncxFilename: "OPS/toc.ncx",
+		expectedFileState: map[string]string{
+			"OPS/Text/chapter1.html": htmlInvalidMarkupExpected,
+		},
+		validationErrors: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "HTM-011",
+					FilePath: "OPS/Text/chapter1.html",
+					Message:  `Entity is undeclared.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 12,
+					},
+				},
+				{
+					Code:     "HTM-011",
+					FilePath: "OPS/Text/chapter1.html",
+					Message:  `Entity is undeclared.`,
+					Location: &epubcheck.Position{
+						Line:   8,
+						Column: 16,
+					},
+				},
+				{
+					Code:     "HTM-010",
+					FilePath: "OPS/Text/chapter1.html",
+					Message:  `Namespace uri "https://www.w3.org/1999/xhtml/" was found.`,
+					Location: &epubcheck.Position{
+						Line:   3,
+						Column: 60,
+					},
+				},
+				{
+					Code:     "HTM-009",
+					FilePath: "OPS/Text/chapter1.html",
+					Message:  `The DOCTYPE provided is obsolete or irregular and can be removed.`,
+					Location: &epubcheck.Position{
+						Line:   2,
+						Column: 101,
+					},
+				},
+			},
+		},
+		expectedErrorState: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "HTM-011",
+					FilePath: "OPS/Text/chapter1.html",
+					Message:  `Entity is undeclared.`,
+					Location: &epubcheck.Position{
+						Line:   9,
+						Column: 12,
+					},
+				},
+				{
+					Code:     "HTM-011",
+					FilePath: "OPS/Text/chapter1.html",
+					Message:  `Entity is undeclared.`,
+					Location: &epubcheck.Position{
+						Line:   8,
+						Column: 16,
+					},
+				},
+				{
+					Code:     "HTM-010",
+					FilePath: "OPS/Text/chapter1.html",
+					Message:  `Namespace uri "https://www.w3.org/1999/xhtml/" was found.`,
+					Location: &epubcheck.Position{
+						Line:   3,
+						Column: 60,
+					},
+				},
+				{
+					Code:     "HTM-009",
+					FilePath: "OPS/Text/chapter1.html",
+					Message:  `The DOCTYPE provided is obsolete or irregular and can be removed.`,
+					Location: &epubcheck.Position{
+						Line:   2,
+						Column: 101,
+					},
+				},
+			},
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/Text/chapter1.html": htmlInvalidMarkupOriginal,
+		},
+	},
+	"NAV 10: Links to remote resources get removed from the nav": {
+		opfFolder:   "OPS",
+		opfFilename: "OPS/content.opf",
+		ncxFilename: "OPS/toc.ncx",
+		expectedFileState: map[string]string{
+			"OPS/nav.xhtml": xhtmlRemoteLinksNavExpected,
+		},
+		validationErrors: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "NAV-010",
+					FilePath: "OPS/nav.xhtml",
+					Message:  `"toc" nav must not link to remote resources; found link to "http://example.com/newsletter".`,
+					Location: &epubcheck.Position{
+						Line:   14,
+						Column: 54,
+					},
+				},
+				{
+					Code:     "NAV-010",
+					FilePath: "OPS/nav.xhtml",
+					Message:  `"toc" nav must not link to remote resources; found link to "https://example.com/bonus".`,
+					Location: &epubcheck.Position{
+						Line:   12,
+						Column: 50,
+					},
+				},
+			},
+		},
+		expectedErrorState: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "NAV-010",
+					FilePath: "OPS/nav.xhtml",
+					Message:  `"toc" nav must not link to remote resources; found link to "http://example.com/newsletter".`,
+					Location: &epubcheck.Position{
+						Line:   14,
+						Column: 54,
+					},
+				},
+				{
+					Code:     "NAV-010",
+					FilePath: "OPS/nav.xhtml",
+					Message:  `"toc" nav must not link to remote resources; found link to "https://example.com/bonus".`,
+					Location: &epubcheck.Position{
+						Line:   12,
+						Column: 50,
+					},
+				},
+			},
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/nav.xhtml": xhtmlRemoteLinksNavOriginal,
+		},
+	},
+	"NAV 10 and NAV 11: Remote links get removed from the nav after it is put in reading order": {
+		opfFolder:   "OPS",
+		opfFilename: "OPS/content.opf",
+		ncxFilename: "OPS/toc.ncx",
+		expectedFileState: map[string]string{
+			"OPS/nav.xhtml": xhtmlRemoteLinksOutOfOrderNavExpected,
+		},
+		validationErrors: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "NAV-011",
+					FilePath: "OPS/nav.xhtml",
+					Message:  `"toc" nav must be in reading order; link target "OPS/Text/chapter1.xhtml" is before the previous link’s target in spine order.`,
+				},
+				{
+					Code:     "NAV-010",
+					FilePath: "OPS/nav.xhtml",
+					Message:  `"toc" nav must not link to remote resources; found link to "https://example.com/bonus".`,
+					Location: &epubcheck.Position{
+						Line:   12,
+						Column: 50,
+					},
+				},
+			},
+		},
+		expectedErrorState: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "NAV-011",
+					FilePath: "OPS/nav.xhtml",
+					Message:  `"toc" nav must be in reading order; link target "OPS/Text/chapter1.xhtml" is before the previous link’s target in spine order.`,
+				},
+				{
+					Code:     "NAV-010",
+					FilePath: "OPS/nav.xhtml",
+					Message:  `"toc" nav must not link to remote resources; found link to "https://example.com/bonus".`,
+					Location: &epubcheck.Position{
+						Line:   12,
+						Column: 50,
+					},
+				},
+			},
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/nav.xhtml": xhtmlRemoteLinksOutOfOrderNavOriginal,
+		},
+		spineOrder: []string{
+			"Text/chapter1.xhtml",
+			"Text/chapter2.xhtml",
+			"Text/chapter3.xhtml",
+		},
+	},
+	"NCX 6: Empty nav labels get the text of the first header or paragraph of the file they point to": {
+		opfFolder:   "OPS",
+		opfFilename: "OPS/content.opf",
+		ncxFilename: "OPS/toc.ncx",
+		expectedFileState: map[string]string{
+			"OPS/toc.ncx": ncxEmptyNavLabelExpected,
+		},
+		validationErrors: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "NCX-006",
+					FilePath: "OPS/toc.ncx",
+					Message:  `Empty or whitespace-only text of navLabel.`,
+					Location: &epubcheck.Position{
+						Line:   18,
+						Column: 15,
+					},
+				},
+				{
+					Code:     "NCX-006",
+					FilePath: "OPS/toc.ncx",
+					Message:  `Empty or whitespace-only text of navLabel.`,
+					Location: &epubcheck.Position{
+						Line:   12,
+						Column: 16,
+					},
+				},
+			},
+		},
+		expectedErrorState: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "NCX-006",
+					FilePath: "OPS/toc.ncx",
+					Message:  `Empty or whitespace-only text of navLabel.`,
+					Location: &epubcheck.Position{
+						Line:   18,
+						Column: 15,
+					},
+				},
+				{
+					Code:     "NCX-006",
+					FilePath: "OPS/toc.ncx",
+					Message:  `Empty or whitespace-only text of navLabel.`,
+					Location: &epubcheck.Position{
+						Line:   12,
+						Column: 16,
+					},
+				},
+			},
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/toc.ncx":             ncxEmptyNavLabelOriginal,
+			"OPS/Text/chapter1.xhtml": `<html xmlns="http://www.w3.org/1999/xhtml"><body><h2>Chapter 1: Beginnings</h2></body></html>`,
+			"OPS/Text/chapter2.xhtml": `<html xmlns="http://www.w3.org/1999/xhtml"><body><p id="start">It was a dark &amp; stormy night.</p></body></html>`,
+		},
+	},
 }
 
 func TestHandleValidationErrors(t *testing.T) {
@@ -22,36 +22,39 @@ func ParseEPUBCheckOutput(logContents string) (ValidationErrors, error) {
 		}
 		code := line[start+1 : end]
 
-		// Find the .epub/ marker for file path
-		epubIdx := strings.Index(line, ".epub/")
+		// Find the .epub marker for file path which is left off when the issue is for the epub as a whole
+		epubIdx := strings.Index(line, ".epub")
 		if epubIdx == -1 {
 			continue
 		}
-		// Find the next '(' after .epub/ which marks the start of (line,column)
-		pathStart := epubIdx + len(".epub/")
-		pathEnd := strings.Index(line[pathStart:], "(")
+
+		// The file path ends at the '(' that marks the start of (line,column) or at the ':' before the message
+		// when there is no location for the issue
+		rest := strings.TrimPrefix(line[epubIdx+len(".epub"):], "/")
+		pathEnd := strings.IndexAny(rest, "(:")
 		if pathEnd == -1 {
 			continue
 		}
-		filePath := line[pathStart : pathStart+pathEnd]
+		filePath := rest[:pathEnd]
+		rest = rest[pathEnd:]
 
 		// Get line and column (between '(' and ')' after file path)
-		locStart := pathStart + pathEnd + 1
-		locEnd := strings.Index(line[locStart:], ")")
-		if locEnd == -1 {
-			continue
-		}
-		locStr := line[locStart : locStart+locEnd]
-		locParts := strings.SplitN(locStr, ",", 2)
 		lineNum, colNum := -1, -1
-		if len(locParts) == 2 {
-			lineNum, _ = strconv.Atoi(strings.TrimSpace(locParts[0]))
-			colNum, _ = strconv.Atoi(strings.TrimSpace(locParts[1]))
+		if rest[0] == '(' {
+			locEnd := strings.Index(rest, ")")
+			if locEnd == -1 {
+				continue
+			}
+			locParts := strings.SplitN(rest[1:locEnd], ",", 2)
+			if len(locParts) == 2 {
+				lineNum, _ = strconv.Atoi(strings.TrimSpace(locParts[0]))
+				colNum, _ = strconv.Atoi(strings.TrimSpace(locParts[1]))
+			}
+			rest = rest[locEnd+1:]
 		}
 
-		// Message: after the final "):"
-		afterLoc := line[locStart+locEnd:]
-		_, after, ok := strings.Cut(afterLoc, ":")
+		// Message: after the ':' following the file path and location
+		_, after, ok := strings.Cut(rest, ":")
 		if !ok {
 			continue
 		}
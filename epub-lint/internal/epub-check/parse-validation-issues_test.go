@@ -66,6 +66,32 @@ ERROR(RSC-007): /home/user/Documents/Book.epub/chapter2.html(15,20): Referenced
 			},
 		},
 	},
+	"A validation issue without a location results in nil Position": {
+		input: `WARNING(OPF-003): /home/user/Documents/Book.epub/OEBPS/Images/cover.jpg: Item "OEBPS/Images/cover.jpg" exists in the EPUB, but is not declared in the OPF manifest.`,
+		expected: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "OPF-003",
+					FilePath: "OEBPS/Images/cover.jpg",
+					Location: nil,
+					Message:  `Item "OEBPS/Images/cover.jpg" exists in the EPUB, but is not declared in the OPF manifest.`,
+				},
+			},
+		},
+	},
+	"A validation issue for the epub as a whole has no file path": {
+		input: `WARNING(OPF-003): /home/user/Documents/Book.epub: Item "OEBPS/Images/cover.jpg" exists in the EPUB, but is not declared in the OPF manifest.`,
+		expected: epubcheck.ValidationErrors{
+			ValidationIssues: []epubcheck.ValidationError{
+				{
+					Code:     "OPF-003",
+					FilePath: "",
+					Location: nil,
+					Message:  `Item "OEBPS/Images/cover.jpg" exists in the EPUB, but is not declared in the OPF manifest.`,
+				},
+			},
+		},
+	},
 	"Validation issues for duplicate id references should be cut down to a single instance per file per id": {
 		input: `ERROR(RSC-005): /home/user/Documents/Book.epub/OPS/section-0009.html(15,54): Error while parsing file: Duplicate ID "auto_bookmark_toc_9"
 ERROR(RSC-005): /home/user/Documents/Book.epub/OPS/section-0009.html(14,54): Error while parsing file: Duplicate ID "auto_bookmark_toc_9"`,
@@ -121,3 +121,36 @@ func GetPositionOffset(contents string, line, column int) int {
 
 	return byteOffset
 }
+
+// GetTextEdit gets a single edit that turns the original contents into the updated contents
+// by replacing only what is between their common prefix and suffix
+func GetTextEdit(original, updated string) TextEdit {
+	var edit TextEdit
+	if original == updated {
+		return edit
+	}
+
+	var prefix int
+	for prefix < len(original) && prefix < len(updated) && original[prefix] == updated[prefix] {
+		prefix++
+	}
+
+	for prefix > 0 && prefix < len(original) && !utf8.RuneStart(original[prefix]) {
+		prefix--
+	}
+
+	var suffix int
+	for suffix < len(original)-prefix && suffix < len(updated)-prefix && original[len(original)-1-suffix] == updated[len(updated)-1-suffix] {
+		suffix++
+	}
+
+	for suffix > 0 && !utf8.RuneStart(original[len(original)-suffix]) {
+		suffix--
+	}
+
+	edit.Range.Start = IndexToPosition(original, prefix)
+	edit.Range.End = IndexToPosition(original, len(original)-suffix)
+	edit.NewText = updated[prefix : len(updated)-suffix]
+
+	return edit
+}
@@ -0,0 +1,78 @@
+package rulefixes
+
+import (
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+)
+
+// FixCssParseError removes the stray closing brace or the declaration at the position that the CSS could not be parsed at
+// since readers skip what they cannot parse anyway. Nothing is removed when the position is outside of a rule's declarations.
+func FixCssParseError(line, column int, contents string) (edit positions.TextEdit) {
+	offset := positions.GetPositionOffset(contents, line, column)
+	if offset == -1 || offset >= len(contents) {
+		return
+	}
+
+	var depth = getCssBlockDepth(contents[:offset])
+	if contents[offset] == '}' && depth == 0 {
+		edit.Range.Start = positions.IndexToPosition(contents, offset)
+		edit.Range.End = positions.IndexToPosition(contents, offset+1)
+
+		return
+	}
+
+	if depth == 0 {
+		return
+	}
+
+	var startOfDeclaration = max(strings.LastIndex(contents[:offset], ";"), strings.LastIndex(contents[:offset], "{")) + 1
+	endOfDeclaration := strings.IndexAny(contents[offset:], ";{}")
+	if endOfDeclaration == -1 {
+		return
+	}
+
+	endOfDeclaration += offset
+	switch contents[endOfDeclaration] {
+	case '{': // the next rule starts before the declaration ends, so it is not safe to remove anything
+		return
+	case ';':
+		endOfDeclaration++
+	}
+
+	var declaration = contents[startOfDeclaration:endOfDeclaration]
+	if strings.TrimSpace(declaration) == "" {
+		return
+	}
+
+	startOfDeclaration += len(declaration) - len(strings.TrimLeft(declaration, " \t\r\n"))
+	startOfDeclaration, endOfDeclaration = epubhandler.GetLineBoundsIfEmpty(contents, startOfDeclaration, endOfDeclaration)
+
+	edit.Range.Start = positions.IndexToPosition(contents, startOfDeclaration)
+	edit.Range.End = positions.IndexToPosition(contents, endOfDeclaration)
+
+	return
+}
+
+// getCssBlockDepth gets how many blocks are open at the end of the css skipping over comments
+func getCssBlockDepth(css string) int {
+	var depth int
+	for i := 0; i < len(css); i++ {
+		switch {
+		case strings.HasPrefix(css[i:], "/*"):
+			endOfComment := strings.Index(css[i+2:], "*/")
+			if endOfComment == -1 {
+				return depth
+			}
+
+			i += endOfComment + 3
+		case css[i] == '{':
+			depth++
+		case css[i] == '}' && depth > 0:
+			depth--
+		}
+	}
+
+	return depth
+}
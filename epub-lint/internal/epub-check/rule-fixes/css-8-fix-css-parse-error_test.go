@@ -0,0 +1,106 @@
+//go:build unit
+
+package rulefixes_test
+
+import (
+	"testing"
+
+	rulefixes "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/rule-fixes"
+)
+
+type fixCssParseErrorTestCase struct {
+	inputText      string
+	line           int
+	column         int
+	expectedOutput string
+}
+
+var fixCssParseErrorTestCases = map[string]fixCssParseErrorTestCase{
+	"A declaration that cannot be parsed gets removed along with its line when it is on its own line": {
+		inputText: `p {
+  margin: 0;
+  color red;
+  text-indent: 1em;
+}`,
+		line:   3,
+		column: 9,
+		expectedOutput: `p {
+  margin: 0;
+  text-indent: 1em;
+}`,
+	},
+	"A declaration without a semicolon at the end of a rule gets removed": {
+		inputText:      `p { margin: 0; color red }`,
+		line:           1,
+		column:         22,
+		expectedOutput: `p { margin: 0; }`,
+	},
+	"A stray closing brace gets removed": {
+		inputText: `p {
+  margin: 0;
+}
+}
+h1 {
+  font-size: 2em;
+}`,
+		line:   4,
+		column: 1,
+		expectedOutput: `p {
+  margin: 0;
+}
+
+h1 {
+  font-size: 2em;
+}`,
+	},
+	"Braces in comments are ignored when checking whether a closing brace is stray": {
+		inputText: `/* } */
+p {
+  color red;
+}`,
+		line:   3,
+		column: 3,
+		expectedOutput: `/* } */
+p {
+}`,
+	},
+	"A position outside of a rule results in no changes": {
+		inputText: `p {
+  margin: 0;
+}
+h1 {}`,
+		line:   4,
+		column: 1,
+		expectedOutput: `p {
+  margin: 0;
+}
+h1 {}`,
+	},
+	"A declaration that runs into the next rule results in no changes": {
+		inputText: `p {
+  color red
+h1 {
+  font-size: 2em;
+}`,
+		line:   2,
+		column: 9,
+		expectedOutput: `p {
+  color red
+h1 {
+  font-size: 2em;
+}`,
+	},
+}
+
+func TestFixCssParseError(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range fixCssParseErrorTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			edit := rulefixes.FixCssParseError(args.line, args.column, args.inputText)
+
+			checkFinalOutputMatches(t, args.inputText, args.expectedOutput, edit)
+		})
+	}
+}
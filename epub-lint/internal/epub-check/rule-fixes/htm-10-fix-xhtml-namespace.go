@@ -0,0 +1,42 @@
+package rulefixes
+
+import (
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+)
+
+const xhtmlNamespace = "http://www.w3.org/1999/xhtml"
+
+// FixXhtmlNamespace replaces the namespace with the XHTML namespace when it is a misspelling of it
+// like having a trailing slash, using https, or using different casing
+func FixXhtmlNamespace(contents, namespace string) (edit positions.TextEdit) {
+	if namespace == xhtmlNamespace || !isXhtmlNamespaceVariant(namespace) {
+		return
+	}
+
+	for _, quote := range []string{`"`, `'`} {
+		startOfNamespace := strings.Index(contents, "xmlns="+quote+namespace+quote)
+		if startOfNamespace == -1 {
+			continue
+		}
+
+		startOfNamespace += len("xmlns=" + quote)
+
+		edit.Range.Start = positions.IndexToPosition(contents, startOfNamespace)
+		edit.Range.End = positions.IndexToPosition(contents, startOfNamespace+len(namespace))
+		edit.NewText = xhtmlNamespace
+
+		return
+	}
+
+	return
+}
+
+func isXhtmlNamespaceVariant(namespace string) bool {
+	namespace = strings.ToLower(strings.TrimSpace(namespace))
+	namespace = strings.TrimRight(namespace, "/")
+	namespace = strings.Replace(namespace, "https://", "http://", 1)
+
+	return namespace == xhtmlNamespace
+}
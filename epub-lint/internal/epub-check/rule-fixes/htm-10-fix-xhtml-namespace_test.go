@@ -0,0 +1,46 @@
+//go:build unit
+
+package rulefixes_test
+
+import (
+	"testing"
+
+	rulefixes "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/rule-fixes"
+)
+
+type fixXhtmlNamespaceTestCase struct {
+	inputText      string
+	namespace      string
+	expectedOutput string
+}
+
+var fixXhtmlNamespaceTestCases = map[string]fixXhtmlNamespaceTestCase{
+	"A namespace with a trailing slash gets replaced with the XHTML namespace": {
+		inputText:      `<html xmlns="http://www.w3.org/1999/xhtml/" xml:lang="en">`,
+		namespace:      "http://www.w3.org/1999/xhtml/",
+		expectedOutput: `<html xmlns="http://www.w3.org/1999/xhtml" xml:lang="en">`,
+	},
+	"A namespace using https and different casing gets replaced with the XHTML namespace": {
+		inputText:      `<html xmlns='https://www.W3.org/1999/XHTML' xml:lang="en">`,
+		namespace:      "https://www.W3.org/1999/XHTML",
+		expectedOutput: `<html xmlns='http://www.w3.org/1999/xhtml' xml:lang="en">`,
+	},
+	"A namespace that is not a variant of the XHTML namespace results in no changes": {
+		inputText:      `<html xmlns="http://www.w3.org/2000/svg">`,
+		namespace:      "http://www.w3.org/2000/svg",
+		expectedOutput: `<html xmlns="http://www.w3.org/2000/svg">`,
+	},
+}
+
+func TestFixXhtmlNamespace(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range fixXhtmlNamespaceTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			edit := rulefixes.FixXhtmlNamespace(args.inputText, args.namespace)
+
+			checkFinalOutputMatches(t, args.inputText, args.expectedOutput, edit)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package rulefixes
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+)
+
+var namedEntityRegex = regexp.MustCompile(`&([A-Za-z][A-Za-z0-9]*);`)
+
+// xmlEntities are the entities that XML declares which means they are fine to leave as is
+var xmlEntities = map[string]struct{}{
+	"amp":  {},
+	"lt":   {},
+	"gt":   {},
+	"quot": {},
+	"apos": {},
+}
+
+// ReplaceUndeclaredEntities replaces the named HTML entities that XML does not declare (i.e. "&nbsp;")
+// with numeric character references since XHTML files do not have a DTD that declares them.
+// Entities that are not known HTML entities are left as is.
+func ReplaceUndeclaredEntities(contents string) (edits []positions.TextEdit) {
+	for _, indices := range namedEntityRegex.FindAllStringSubmatchIndex(contents, -1) {
+		if _, ok := xmlEntities[contents[indices[2]:indices[3]]]; ok {
+			continue
+		}
+
+		var (
+			entity = contents[indices[0]:indices[1]]
+			value  = html.UnescapeString(entity)
+		)
+		// entities that are not known only get partially decoded when they start with a known entity, so the semicolon is left behind
+		if value == entity || strings.HasSuffix(value, ";") {
+			continue
+		}
+
+		var newText strings.Builder
+		for _, char := range value {
+			fmt.Fprintf(&newText, "&#%d;", char)
+		}
+
+		edits = append(edits, positions.TextEdit{
+			Range: positions.Range{
+				Start: positions.IndexToPosition(contents, indices[0]),
+				End:   positions.IndexToPosition(contents, indices[1]),
+			},
+			NewText: newText.String(),
+		})
+	}
+
+	return
+}
@@ -0,0 +1,42 @@
+//go:build unit
+
+package rulefixes_test
+
+import (
+	"testing"
+
+	rulefixes "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/rule-fixes"
+)
+
+type replaceUndeclaredEntitiesTestCase struct {
+	inputText      string
+	expectedOutput string
+}
+
+var replaceUndeclaredEntitiesTestCases = map[string]replaceUndeclaredEntitiesTestCase{
+	"HTML named entities get replaced with numeric character references": {
+		inputText:      `<p>Caf&eacute;&nbsp;&mdash; open</p>`,
+		expectedOutput: `<p>Caf&#233;&#160;&#8212; open</p>`,
+	},
+	"The entities that XML declares are left alone": {
+		inputText:      `<p>&lt;a&gt; &amp; &quot;b&quot; &apos;c&apos;</p>`,
+		expectedOutput: `<p>&lt;a&gt; &amp; &quot;b&quot; &apos;c&apos;</p>`,
+	},
+	"Unknown entities and numeric character references are left alone": {
+		inputText:      `<p>&notanentity; &#233; &#x2014;</p>`,
+		expectedOutput: `<p>&notanentity; &#233; &#x2014;</p>`,
+	},
+}
+
+func TestReplaceUndeclaredEntities(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range replaceUndeclaredEntitiesTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			edits := rulefixes.ReplaceUndeclaredEntities(args.inputText)
+
+			checkFinalOutputMatches(t, args.inputText, args.expectedOutput, edits...)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package rulefixes
+
+import (
+	"path"
+	"strings"
+)
+
+const xhtmlMediaType = "application/xhtml+xml"
+
+var extToMediaType = map[string]string{
+	".xhtml": xhtmlMediaType,
+	".html":  xhtmlMediaType,
+	".htm":   xhtmlMediaType,
+	".ncx":   "application/x-dtbncx+xml",
+	".css":   "text/css",
+	".js":    "application/javascript",
+	".gif":   "image/gif",
+	".jpeg":  "image/jpeg",
+	".jpg":   "image/jpeg",
+	".png":   "image/png",
+	".svg":   "image/svg+xml",
+	".webp":  "image/webp",
+	".otf":   "font/otf",
+	".ttf":   "font/ttf",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+	".mp3":   "audio/mpeg",
+	".m4a":   "audio/mp4",
+	".smil":  "application/smil+xml",
+}
+
+// getMediaType gets the core media type for the file based on its extension
+// returning an empty string when the extension is not a known one
+func getMediaType(file string) string {
+	return extToMediaType[strings.ToLower(path.Ext(file))]
+}
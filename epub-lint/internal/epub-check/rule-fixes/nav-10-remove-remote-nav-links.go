@@ -0,0 +1,30 @@
+package rulefixes
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+)
+
+// RemoveRemoteNavLinks removes the list items in the nav file that link to remote resources
+// since nav elements are only allowed to link to files in the epub
+func RemoveRemoteNavLinks(navContents string) (positions.TextEdit, error) {
+	nav, err := epubdoc.ParseNav(navContents)
+	if err != nil {
+		return positions.TextEdit{}, err
+	}
+
+	nav.RemoveListItems(func(href string) bool {
+		if strings.HasPrefix(href, "//") {
+			return true
+		}
+
+		hrefUrl, err := url.Parse(strings.TrimSpace(href))
+
+		return err == nil && hrefUrl.Scheme != ""
+	})
+
+	return positions.GetTextEdit(navContents, nav.String()), nil
+}
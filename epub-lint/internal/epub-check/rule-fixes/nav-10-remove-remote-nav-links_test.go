@@ -0,0 +1,76 @@
+//go:build unit
+
+package rulefixes_test
+
+import (
+	"testing"
+
+	rulefixes "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/rule-fixes"
+	"github.com/stretchr/testify/require"
+)
+
+type removeRemoteNavLinksTestCase struct {
+	inputText      string
+	expectedOutput string
+}
+
+var removeRemoteNavLinksTestCases = map[string]removeRemoteNavLinksTestCase{
+	"List items with links to remote resources get removed": {
+		inputText: `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<body>
+  <nav epub:type="toc">
+    <ol>
+      <li><a href="Text/chapter1.xhtml">Chapter 1</a></li>
+      <li><a href="https://example.com">Website</a></li>
+      <li><a href="//example.com/extras">Extras</a></li>
+      <li><a href="Text/chapter2.xhtml">Chapter 2</a></li>
+    </ol>
+  </nav>
+</body>
+</html>`,
+		expectedOutput: `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<body>
+  <nav epub:type="toc">
+    <ol>
+      <li><a href="Text/chapter1.xhtml">Chapter 1</a></li>
+      <li><a href="Text/chapter2.xhtml">Chapter 2</a></li>
+    </ol>
+  </nav>
+</body>
+</html>`,
+	},
+	"A nav without remote links results in no changes": {
+		inputText: `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<body>
+  <nav epub:type="toc">
+    <ol>
+      <li><a href="Text/chapter1.xhtml#start">Chapter 1</a></li>
+    </ol>
+  </nav>
+</body>
+</html>`,
+		expectedOutput: `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<body>
+  <nav epub:type="toc">
+    <ol>
+      <li><a href="Text/chapter1.xhtml#start">Chapter 1</a></li>
+    </ol>
+  </nav>
+</body>
+</html>`,
+	},
+}
+
+func TestRemoveRemoteNavLinks(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range removeRemoteNavLinksTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			edit, err := rulefixes.RemoveRemoteNavLinks(args.inputText)
+
+			require.NoError(t, err)
+			checkFinalOutputMatches(t, args.inputText, args.expectedOutput, edit)
+		})
+	}
+}
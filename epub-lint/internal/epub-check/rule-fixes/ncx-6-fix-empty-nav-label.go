@@ -0,0 +1,71 @@
+package rulefixes
+
+import (
+	"html"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+var ncxTextRegex = regexp.MustCompile(`<text\s*/>|<text>\s*</text>`)
+
+// FixEmptyNavLabel sets the empty text of the nav label of the navPoint at the position to the text of the first header
+// of the file it points to or the first paragraph if there are no headers. The ncx folder is used to get the path of that file
+// relative to the root of the epub.
+func FixEmptyNavLabel(line, column int, ncxContents, ncxFolder string, getContentByFileName func(string) (string, error)) (edit positions.TextEdit) {
+	offset := positions.GetPositionOffset(ncxContents, line, column)
+	if offset == -1 {
+		return
+	}
+
+	startOfNavPoint := strings.LastIndex(ncxContents[:min(offset+len("<navPoint"), len(ncxContents))], "<navPoint")
+	if startOfNavPoint == -1 {
+		return
+	}
+
+	// the nav label and content of the navPoint come before any child navPoints
+	var navPoint = ncxContents[startOfNavPoint:]
+	if endOfNavPoint := strings.Index(navPoint[1:], "<navPoint"); endOfNavPoint != -1 {
+		navPoint = navPoint[:endOfNavPoint+1]
+	}
+
+	textIndices := ncxTextRegex.FindStringIndex(navPoint)
+	if textIndices == nil {
+		return
+	}
+
+	startOfContent := strings.Index(navPoint, "<content")
+	if startOfContent == -1 {
+		return
+	}
+
+	src, _, _, err := epubhandler.GetAttributeValue(navPoint[startOfContent:], "src")
+	if err != nil {
+		return
+	}
+
+	src, _, _ = strings.Cut(src, "#")
+	if src == "" {
+		return
+	}
+
+	contents, err := getContentByFileName(filehandler.JoinPath(ncxFolder, path.Clean(src)))
+	if err != nil { // the file may be missing which is a separate issue, so there is nothing to base the label on
+		return
+	}
+
+	title := firstHeaderOrParagraphText(contents)
+	if strings.TrimSpace(title) == "" {
+		return
+	}
+
+	edit.Range.Start = positions.IndexToPosition(ncxContents, startOfNavPoint+textIndices[0])
+	edit.Range.End = positions.IndexToPosition(ncxContents, startOfNavPoint+textIndices[1])
+	edit.NewText = "<text>" + html.EscapeString(title) + "</text>"
+
+	return
+}
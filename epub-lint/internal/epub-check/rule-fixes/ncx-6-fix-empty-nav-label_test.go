@@ -0,0 +1,104 @@
+//go:build unit
+
+package rulefixes_test
+
+import (
+	"fmt"
+	"testing"
+
+	rulefixes "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/rule-fixes"
+)
+
+type fixEmptyNavLabelTestCase struct {
+	inputText      string
+	line           int
+	column         int
+	expectedOutput string
+}
+
+var fixEmptyNavLabelFiles = map[string]string{
+	"OEBPS/Text/chapter1.xhtml": `<html><body><h1>Chapter 1: Fish &amp; Chips</h1><p>Text</p></body></html>`,
+	"OEBPS/Text/chapter2.xhtml": `<html><body><p>The second chapter</p></body></html>`,
+}
+
+var fixEmptyNavLabelTestCases = map[string]fixEmptyNavLabelTestCase{
+	"An empty self-closing text element gets the text of the first header of the file the navPoint points to": {
+		inputText: `<navMap>
+  <navPoint id="navPoint1" playOrder="1">
+    <navLabel>
+      <text/>
+    </navLabel>
+    <content src="Text/chapter1.xhtml#start"/>
+  </navPoint>
+</navMap>`,
+		line:   2,
+		column: 3,
+		expectedOutput: `<navMap>
+  <navPoint id="navPoint1" playOrder="1">
+    <navLabel>
+      <text>Chapter 1: Fish &amp; Chips</text>
+    </navLabel>
+    <content src="Text/chapter1.xhtml#start"/>
+  </navPoint>
+</navMap>`,
+	},
+	"An empty text element gets the text of the first paragraph when the file has no headers": {
+		inputText: `<navMap>
+  <navPoint id="navPoint1" playOrder="1">
+    <navLabel><text>Chapter 1</text></navLabel>
+    <content src="Text/chapter1.xhtml"/>
+  </navPoint>
+  <navPoint id="navPoint2" playOrder="2">
+    <navLabel><text> </text></navLabel>
+    <content src="Text/chapter2.xhtml"/>
+  </navPoint>
+</navMap>`,
+		line:   7,
+		column: 15,
+		expectedOutput: `<navMap>
+  <navPoint id="navPoint1" playOrder="1">
+    <navLabel><text>Chapter 1</text></navLabel>
+    <content src="Text/chapter1.xhtml"/>
+  </navPoint>
+  <navPoint id="navPoint2" playOrder="2">
+    <navLabel><text>The second chapter</text></navLabel>
+    <content src="Text/chapter2.xhtml"/>
+  </navPoint>
+</navMap>`,
+	},
+	"A navPoint pointing to a file that does not exist results in no changes": {
+		inputText: `<navMap>
+  <navPoint id="navPoint1" playOrder="1">
+    <navLabel><text/></navLabel>
+    <content src="Text/missing.xhtml"/>
+  </navPoint>
+</navMap>`,
+		line:   2,
+		column: 3,
+		expectedOutput: `<navMap>
+  <navPoint id="navPoint1" playOrder="1">
+    <navLabel><text/></navLabel>
+    <content src="Text/missing.xhtml"/>
+  </navPoint>
+</navMap>`,
+	},
+}
+
+func TestFixEmptyNavLabel(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range fixEmptyNavLabelTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			edit := rulefixes.FixEmptyNavLabel(args.line, args.column, args.inputText, "OEBPS", func(fileName string) (string, error) {
+				if contents, ok := fixEmptyNavLabelFiles[fileName]; ok {
+					return contents, nil
+				}
+
+				return "", fmt.Errorf("file %q does not exist", fileName)
+			})
+
+			checkFinalOutputMatches(t, args.inputText, args.expectedOutput, edit)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package rulefixes
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+// AddMissingManifestItem adds a manifest item for the file, which is its path relative to the root of the epub,
+// with an id based on its name and a media type based on its extension. Files that are already in the manifest
+// or that have an unknown extension are skipped.
+func AddMissingManifestItem(opfContents, opfFolder, filePath string) (positions.TextEdit, error) {
+	var (
+		edit      positions.TextEdit
+		mediaType = getMediaType(filePath)
+	)
+	if mediaType == "" {
+		return edit, nil
+	}
+
+	opf, err := epubdoc.ParseOpf(opfContents)
+	if err != nil {
+		return edit, err
+	}
+
+	var ids = make(map[string]struct{})
+	for _, item := range opf.ManifestItems() {
+		if getManifestItemPath(opfFolder, item.Href()) == filePath {
+			return edit, nil
+		}
+
+		ids[item.Id()] = struct{}{}
+	}
+
+	href, err := filepath.Rel(opfFolder, filePath)
+	if err != nil {
+		return edit, fmt.Errorf("failed to get the path of %q relative to %q: %w", filePath, opfFolder, err)
+	}
+
+	var (
+		baseId = convertToValidID(path.Base(filePath))
+		id     = baseId
+	)
+	for i := 2; ; i++ {
+		if _, exists := ids[id]; !exists {
+			break
+		}
+
+		id = fmt.Sprintf("%s_%d", baseId, i)
+	}
+
+	_, err = opf.AddManifestItem(id, escapeHref(filepath.ToSlash(href)), mediaType)
+	if err != nil {
+		return edit, err
+	}
+
+	return positions.GetTextEdit(opfContents, opf.String()), nil
+}
+
+// escapeHref percent-encodes each segment of the path so it is a valid url
+func escapeHref(href string) string {
+	var segments = strings.Split(href, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// getManifestItemPath gets the path of the manifest item relative to the root of the epub
+func getManifestItemPath(opfFolder, href string) string {
+	if unescapedHref, err := url.PathUnescape(href); err == nil {
+		href = unescapedHref
+	}
+
+	return filehandler.JoinPath(opfFolder, href)
+}
@@ -0,0 +1,104 @@
+//go:build unit
+
+package rulefixes_test
+
+import (
+	"testing"
+
+	rulefixes "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/rule-fixes"
+	"github.com/stretchr/testify/require"
+)
+
+type addMissingManifestItemTestCase struct {
+	inputText      string
+	opfFolder      string
+	filePath       string
+	expectedOutput string
+}
+
+var addMissingManifestItemTestCases = map[string]addMissingManifestItemTestCase{
+	"A file that is not in the manifest gets added with a path relative to the opf folder and a media type based on its extension": {
+		inputText: `<package version="3.0">
+  <manifest>
+    <item id="chapter1" href="Text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+</package>`,
+		opfFolder: "OEBPS",
+		filePath:  "OEBPS/Images/cover image.jpg",
+		expectedOutput: `<package version="3.0">
+  <manifest>
+    <item id="chapter1" href="Text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="cover_image_jpg" href="Images/cover%20image.jpg" media-type="image/jpeg"/>
+  </manifest>
+</package>`,
+	},
+	"A file whose id is already used gets a number added to the end of its id": {
+		inputText: `<package version="3.0">
+  <manifest>
+    <item id="chapter1_xhtml" href="Text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+</package>`,
+		opfFolder: "OEBPS",
+		filePath:  "OEBPS/Extra/chapter1.xhtml",
+		expectedOutput: `<package version="3.0">
+  <manifest>
+    <item id="chapter1_xhtml" href="Text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="chapter1_xhtml_2" href="Extra/chapter1.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+</package>`,
+	},
+	"A file whose escaped path is already in the manifest is not added again": {
+		inputText: `<package version="3.0">
+  <manifest>
+    <item id="cover" href="Images/cover%20image.jpg" media-type="image/jpeg"/>
+  </manifest>
+</package>`,
+		opfFolder: "OEBPS",
+		filePath:  "OEBPS/Images/cover image.jpg",
+		expectedOutput: `<package version="3.0">
+  <manifest>
+    <item id="cover" href="Images/cover%20image.jpg" media-type="image/jpeg"/>
+  </manifest>
+</package>`,
+	},
+	"A file that is already in the manifest is not added again": {
+		inputText: `<package version="3.0">
+  <manifest>
+    <item id="chapter1" href="Text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+</package>`,
+		opfFolder: "OEBPS",
+		filePath:  "OEBPS/Text/chapter1.xhtml",
+		expectedOutput: `<package version="3.0">
+  <manifest>
+    <item id="chapter1" href="Text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+</package>`,
+	},
+	"A file with an unknown extension is not added": {
+		inputText: `<package version="3.0">
+  <manifest>
+  </manifest>
+</package>`,
+		opfFolder: "OEBPS",
+		filePath:  "OEBPS/notes.txt",
+		expectedOutput: `<package version="3.0">
+  <manifest>
+  </manifest>
+</package>`,
+	},
+}
+
+func TestAddMissingManifestItem(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range addMissingManifestItemTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			edit, err := rulefixes.AddMissingManifestItem(args.inputText, args.opfFolder, args.filePath)
+
+			require.NoError(t, err)
+			checkFinalOutputMatches(t, args.inputText, args.expectedOutput, edit)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package rulefixes
+
+import (
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+)
+
+// FixManifestMediaType updates the media type of the manifest items for the file, which is its path relative to the root of the epub,
+// to the core media type for its extension when they have a different one
+func FixManifestMediaType(opfContents, opfFolder, filePath string) (positions.TextEdit, error) {
+	var (
+		edit      positions.TextEdit
+		mediaType = getMediaType(filePath)
+	)
+	if mediaType == "" {
+		return edit, nil
+	}
+
+	opf, err := epubdoc.ParseOpf(opfContents)
+	if err != nil {
+		return edit, err
+	}
+
+	for _, item := range opf.ManifestItems() {
+		if getManifestItemPath(opfFolder, item.Href()) == filePath && item.MediaType() != mediaType {
+			item.SetAttr("media-type", mediaType)
+		}
+	}
+
+	return positions.GetTextEdit(opfContents, opf.String()), nil
+}
+
+// GetManifestItemPathAtPosition gets the path relative to the root of the epub of the file for the manifest item
+// or spine itemref at the position returning an empty string when there is neither
+func GetManifestItemPathAtPosition(opfContents, opfFolder string, line, column int) string {
+	offset := positions.GetPositionOffset(opfContents, line, column)
+	if offset == -1 {
+		return ""
+	}
+
+	startOfElement := strings.LastIndex(opfContents[:offset], "<")
+	if startOfElement == -1 {
+		return ""
+	}
+
+	endOfElement := strings.Index(opfContents[startOfElement:], ">")
+	if endOfElement == -1 {
+		return ""
+	}
+
+	var element = opfContents[startOfElement : startOfElement+endOfElement+1]
+	if strings.HasPrefix(element, "<itemref") {
+		idref, _, _, err := epubhandler.GetAttributeValue(element, "idref")
+		if err != nil {
+			return ""
+		}
+
+		opf, err := epubdoc.ParseOpf(opfContents)
+		if err != nil {
+			return ""
+		}
+
+		item, ok := opf.ManifestItemById(idref)
+		if !ok {
+			return ""
+		}
+
+		return getManifestItemPath(opfFolder, item.Href())
+	} else if strings.HasPrefix(element, "<item") {
+		href, _, _, err := epubhandler.GetAttributeValue(element, "href")
+		if err != nil {
+			return ""
+		}
+
+		return getManifestItemPath(opfFolder, href)
+	}
+
+	return ""
+}
@@ -0,0 +1,83 @@
+//go:build unit
+
+package rulefixes_test
+
+import (
+	"testing"
+
+	rulefixes "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/rule-fixes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixManifestMediaTypeTestCase struct {
+	inputText      string
+	filePath       string
+	expectedOutput string
+}
+
+const manifestMediaTypeOpf = `<package version="3.0">
+  <manifest>
+    <item id="chapter1" href="Text/chapter1.html" media-type="text/x-oeb1-document"/>
+    <item id="style" href="Styles/style.css" media-type="text/x-oeb1-css"/>
+  </manifest>
+  <spine>
+    <itemref idref="chapter1"/>
+  </spine>
+</package>`
+
+var fixManifestMediaTypeTestCases = map[string]fixManifestMediaTypeTestCase{
+	"A deprecated media type for a content document gets replaced with the XHTML media type": {
+		inputText: manifestMediaTypeOpf,
+		filePath:  "OEBPS/Text/chapter1.html",
+		expectedOutput: `<package version="3.0">
+  <manifest>
+    <item id="chapter1" href="Text/chapter1.html" media-type="application/xhtml+xml"/>
+    <item id="style" href="Styles/style.css" media-type="text/x-oeb1-css"/>
+  </manifest>
+  <spine>
+    <itemref idref="chapter1"/>
+  </spine>
+</package>`,
+	},
+	"A deprecated media type for a stylesheet gets replaced with the CSS media type": {
+		inputText: manifestMediaTypeOpf,
+		filePath:  "OEBPS/Styles/style.css",
+		expectedOutput: `<package version="3.0">
+  <manifest>
+    <item id="chapter1" href="Text/chapter1.html" media-type="text/x-oeb1-document"/>
+    <item id="style" href="Styles/style.css" media-type="text/css"/>
+  </manifest>
+  <spine>
+    <itemref idref="chapter1"/>
+  </spine>
+</package>`,
+	},
+	"A file that is not in the manifest results in no changes": {
+		inputText:      manifestMediaTypeOpf,
+		filePath:       "OEBPS/Text/chapter2.html",
+		expectedOutput: manifestMediaTypeOpf,
+	},
+}
+
+func TestFixManifestMediaType(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range fixManifestMediaTypeTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			edit, err := rulefixes.FixManifestMediaType(args.inputText, "OEBPS", args.filePath)
+
+			require.NoError(t, err)
+			checkFinalOutputMatches(t, args.inputText, args.expectedOutput, edit)
+		})
+	}
+}
+
+func TestGetManifestItemPathAtPosition(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "OEBPS/Styles/style.css", rulefixes.GetManifestItemPathAtPosition(manifestMediaTypeOpf, "OEBPS", 4, 87))
+	assert.Equal(t, "OEBPS/Text/chapter1.html", rulefixes.GetManifestItemPathAtPosition(manifestMediaTypeOpf, "OEBPS", 7, 30))
+	assert.Equal(t, "", rulefixes.GetManifestItemPathAtPosition(manifestMediaTypeOpf, "OEBPS", 2, 13))
+}
@@ -0,0 +1,88 @@
+package rulefixes
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+var linkAttributeRegex = regexp.MustCompile(`(?i)\s(?:href|src|xlink:href)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+// AddNonLinearSpineItem adds an itemref with linear set to "no" to the end of the spine for the content document,
+// which is its path relative to the root of the epub, so that links to it point to a file in the spine
+func AddNonLinearSpineItem(opfContents, opfFolder, filePath string) (positions.TextEdit, error) {
+	var edit positions.TextEdit
+
+	opf, err := epubdoc.ParseOpf(opfContents)
+	if err != nil {
+		return edit, err
+	}
+
+	var id string
+	for _, item := range opf.ManifestItems() {
+		if getManifestItemPath(opfFolder, item.Href()) == filePath && item.MediaType() == xhtmlMediaType {
+			id = item.Id()
+			break
+		}
+	}
+
+	if id == "" {
+		return edit, nil
+	}
+
+	for _, itemref := range opf.SpineItems() {
+		if itemref.Idref() == id {
+			return edit, nil
+		}
+	}
+
+	itemref, err := opf.AddSpineItem(id)
+	if err != nil {
+		return edit, err
+	}
+
+	itemref.SetAttr("linear", "no")
+
+	return positions.GetTextEdit(opfContents, opf.String()), nil
+}
+
+// GetLinkTargetAtPosition gets the path relative to the root of the epub of the file that the link in the element at the position
+// points to returning an empty string when there is no link or it is an external one
+func GetLinkTargetAtPosition(contents, currentFile string, line, column int) string {
+	offset := positions.GetPositionOffset(contents, line, column)
+	if offset == -1 {
+		return ""
+	}
+
+	startOfElement := strings.LastIndex(contents[:offset], "<")
+	if startOfElement == -1 {
+		return ""
+	}
+
+	endOfElement := strings.Index(contents[startOfElement:], ">")
+	if endOfElement == -1 {
+		return ""
+	}
+
+	groups := linkAttributeRegex.FindStringSubmatch(contents[startOfElement : startOfElement+endOfElement+1])
+	if groups == nil {
+		return ""
+	}
+
+	link, _, _ := strings.Cut(groups[1]+groups[2], "#")
+	if link == "" || strings.HasPrefix(link, "//") {
+		return ""
+	}
+
+	linkUrl, err := url.Parse(link)
+	if err != nil || linkUrl.Scheme != "" {
+		return ""
+	}
+
+	return filehandler.JoinPath(path.Dir(currentFile), linkUrl.Path)
+}
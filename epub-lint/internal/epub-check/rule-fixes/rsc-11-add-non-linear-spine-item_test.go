@@ -0,0 +1,115 @@
+//go:build unit
+
+package rulefixes_test
+
+import (
+	"testing"
+
+	rulefixes "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/rule-fixes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type addNonLinearSpineItemTestCase struct {
+	inputText      string
+	filePath       string
+	expectedOutput string
+}
+
+const nonLinearSpineItemOpf = `<package version="3.0">
+  <manifest>
+    <item id="chapter1" href="Text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="notes" href="Text/notes.xhtml" media-type="application/xhtml+xml"/>
+    <item id="cover" href="Images/cover.jpg" media-type="image/jpeg"/>
+  </manifest>
+  <spine>
+    <itemref idref="chapter1"/>
+  </spine>
+</package>`
+
+var addNonLinearSpineItemTestCases = map[string]addNonLinearSpineItemTestCase{
+	"A content document that is not in the spine gets added to the end of the spine as a non-linear item": {
+		inputText: nonLinearSpineItemOpf,
+		filePath:  "OEBPS/Text/notes.xhtml",
+		expectedOutput: `<package version="3.0">
+  <manifest>
+    <item id="chapter1" href="Text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="notes" href="Text/notes.xhtml" media-type="application/xhtml+xml"/>
+    <item id="cover" href="Images/cover.jpg" media-type="image/jpeg"/>
+  </manifest>
+  <spine>
+    <itemref idref="chapter1"/>
+    <itemref idref="notes" linear="no"/>
+  </spine>
+</package>`,
+	},
+	"A content document that is already in the spine results in no changes": {
+		inputText:      nonLinearSpineItemOpf,
+		filePath:       "OEBPS/Text/chapter1.xhtml",
+		expectedOutput: nonLinearSpineItemOpf,
+	},
+	"A file that is not a content document results in no changes": {
+		inputText:      nonLinearSpineItemOpf,
+		filePath:       "OEBPS/Images/cover.jpg",
+		expectedOutput: nonLinearSpineItemOpf,
+	},
+}
+
+func TestAddNonLinearSpineItem(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range addNonLinearSpineItemTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			edit, err := rulefixes.AddNonLinearSpineItem(args.inputText, "OEBPS", args.filePath)
+
+			require.NoError(t, err)
+			checkFinalOutputMatches(t, args.inputText, args.expectedOutput, edit)
+		})
+	}
+}
+
+type getLinkTargetAtPositionTestCase struct {
+	contents       string
+	line           int
+	column         int
+	expectedTarget string
+}
+
+var getLinkTargetAtPositionTestCases = map[string]getLinkTargetAtPositionTestCase{
+	"A relative link gets resolved from the folder of the current file without its fragment": {
+		contents:       `<p>See <a href="../Text/notes.xhtml#note1">note</a></p>`,
+		line:           1,
+		column:         42,
+		expectedTarget: "OEBPS/Text/notes.xhtml",
+	},
+	"An image source gets resolved from the folder of the current file": {
+		contents:       `<img src="cover.jpg" alt=""/>`,
+		line:           1,
+		column:         29,
+		expectedTarget: "OEBPS/Text/cover.jpg",
+	},
+	"An external link results in no target": {
+		contents:       `<a href="https://example.com/notes.xhtml">note</a>`,
+		line:           1,
+		column:         41,
+		expectedTarget: "",
+	},
+	"An element without a link results in no target": {
+		contents:       `<p class="note">note</p>`,
+		line:           1,
+		column:         16,
+		expectedTarget: "",
+	},
+}
+
+func TestGetLinkTargetAtPosition(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range getLinkTargetAtPositionTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, args.expectedTarget, rulefixes.GetLinkTargetAtPosition(args.contents, "OEBPS/Text/chapter1.xhtml", args.line, args.column))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+//go:build unit
+
+package epubcheck_test
+
+import (
+	"testing"
+
+	epubcheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHandledCodesGetFixed makes sure that each code summarized as handled gets fixed by HandleValidationErrors
+// by running just the issues for that code from the HandleValidationErrors test cases and checking that a file gets updated.
+// It does not run in parallel since the HandleValidationErrors test cases update their validation issues in place.
+func TestHandledCodesGetFixed(t *testing.T) {
+	for code := range epubcheck.HandledCodes {
+		t.Run(code, func(t *testing.T) {
+			var foundIssues, fixedIssues bool
+			for _, tc := range handleValidationErrorTestCases {
+				var validationErrors epubcheck.ValidationErrors
+				for _, issue := range tc.validationErrors.ValidationIssues {
+					if issue.Code == code {
+						validationErrors.ValidationIssues = append(validationErrors.ValidationIssues, issue)
+					}
+				}
+
+				if len(validationErrors.ValidationIssues) == 0 {
+					continue
+				}
+
+				foundIssues = true
+
+				var (
+					nameToUpdatedFileContents = map[string]string{}
+					basenameToFilePaths       = tc.basenameToFilePaths
+				)
+				if basenameToFilePaths == nil {
+					basenameToFilePaths = map[string][]string{}
+				}
+
+				err := epubcheck.HandleValidationErrors(tc.opfFolder, tc.ncxFilename, tc.opfFilename, nameToUpdatedFileContents, basenameToFilePaths, &validationErrors, createTestCaseFileHandlerFunction(tc.validFilesToInitialContent, nameToUpdatedFileContents), tc.spineOrder)
+				require.NoError(t, err)
+
+				if len(nameToUpdatedFileContents) != 0 {
+					fixedIssues = true
+					break
+				}
+			}
+
+			require.True(t, foundIssues, "expected a HandleValidationErrors test case to have an issue for %q", code)
+			assert.True(t, fixedIssues, "expected HandleValidationErrors to update a file for the issues for %q", code)
+		})
+	}
+}
@@ -77,3 +77,59 @@ func (ve *ValidationErrors) Sort() {
 		return msgI.Location.Column > msgJ.Location.Column
 	})
 }
+
+// There is a test that makes sure HandleValidationErrors fixes issues for each of these codes.
+// There is a test that makes sure these stay in sync with the cases in HandleValidationErrors.
+var handledCodes = map[string]struct{}{
+	"CSS-008": {},
+	"HTM-004": {},
+	"HTM-009": {},
+	"HTM-010": {},
+	"HTM-011": {},
+	"NAV-010": {},
+	"NAV-011": {},
+	"NCX-001": {},
+	"NCX-006": {},
+	"OPF-003": {},
+	"OPF-014": {},
+	"OPF-015": {},
+	"OPF-030": {},
+	"OPF-037": {},
+	"OPF-038": {},
+	"OPF-043": {},
+	"OPF-074": {},
+	"OPF-096": {},
+	"RSC-005": {},
+	"RSC-007": {},
+	"RSC-008": {},
+	"RSC-009": {},
+	"RSC-010": {},
+	"RSC-011": {},
+	"RSC-012": {},
+	"RSC-017": {},
+}
+
+// CodeSummary is the number of validation issues for each code split by whether or not there are fixes for the code
+type CodeSummary struct {
+	Handled   map[string]int
+	Unhandled map[string]int
+}
+
+// SummarizeCodes counts the validation issues for each code which needs to happen before they are handled
+// since handling them can remove and add issues
+func (ve *ValidationErrors) SummarizeCodes() CodeSummary {
+	var summary = CodeSummary{
+		Handled:   make(map[string]int),
+		Unhandled: make(map[string]int),
+	}
+
+	for _, issue := range ve.ValidationIssues {
+		if _, ok := handledCodes[issue.Code]; ok {
+			summary.Handled[issue.Code]++
+		} else {
+			summary.Unhandled[issue.Code]++
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,336 @@
+package epubdiff
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"image"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	stringdiff "github.com/pjkaufman/go-go-gadgets/pkg/string-diff"
+)
+
+type ChangeType string
+
+const (
+	Added    ChangeType = "added"
+	Removed  ChangeType = "removed"
+	Modified ChangeType = "modified"
+
+	contextLines = 3
+)
+
+var textExts = map[string]struct{}{
+	".xhtml": {}, ".html": {}, ".htm": {}, ".css": {}, ".opf": {}, ".ncx": {},
+	".xml": {}, ".txt": {}, ".js": {}, ".svg": {},
+}
+
+// EpubContents is the parsed opf info and the contents of every file in an epub
+type EpubContents struct {
+	Name      string
+	Info      epubhandler.EpubInfo
+	OpfFolder string
+	// Files is a map of the full path of the file in the zip to its contents
+	Files map[string][]byte
+}
+
+type FileDiff struct {
+	Path                          string
+	Change                        ChangeType
+	IsText                        bool
+	OriginalText, UpdatedText     string
+	OriginalSize, UpdatedSize     int
+	OriginalWidth, OriginalHeight int
+	UpdatedWidth, UpdatedHeight   int
+}
+
+type EpubDiff struct {
+	OriginalName, UpdatedName string
+	ManifestAdded             []string
+	ManifestRemoved           []string
+	OriginalSpine             string
+	UpdatedSpine              string
+	OriginalMetadata          string
+	UpdatedMetadata           string
+	Files                     []FileDiff
+	UnchangedFiles            int
+}
+
+func ReadEpubContents(src string) (EpubContents, error) {
+	var contents = EpubContents{
+		Name:  src,
+		Files: make(map[string][]byte),
+	}
+
+	err := epubhandler.ReadEpub(src, func(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string) error {
+		contents.Info = epubInfo
+		contents.OpfFolder = opfFolder
+
+		for name, zipFile := range zipFiles {
+			data, err := filehandler.ReadInZipFileBytes(zipFile)
+			if err != nil {
+				return err
+			}
+
+			contents.Files[name] = data
+		}
+
+		return nil
+	})
+
+	return contents, err
+}
+
+// Diff compares the manifest, spine, metadata, and files of the original and updated epubs
+func Diff(original, updated EpubContents) EpubDiff {
+	var (
+		epubDiff = EpubDiff{
+			OriginalName:     original.Name,
+			UpdatedName:      updated.Name,
+			OriginalSpine:    strings.Join(original.Info.FilePathsInSpineOrder, "\n"),
+			UpdatedSpine:     strings.Join(updated.Info.FilePathsInSpineOrder, "\n"),
+			OriginalMetadata: getMetadata(string(original.Files[original.Info.OpfFile])),
+			UpdatedMetadata:  getMetadata(string(updated.Files[updated.Info.OpfFile])),
+		}
+		originalManifest = getManifestFiles(original)
+		updatedManifest  = getManifestFiles(updated)
+	)
+
+	for file := range updatedManifest {
+		if _, ok := originalManifest[file]; !ok {
+			epubDiff.ManifestAdded = append(epubDiff.ManifestAdded, file)
+		}
+	}
+
+	for file := range originalManifest {
+		if _, ok := updatedManifest[file]; !ok {
+			epubDiff.ManifestRemoved = append(epubDiff.ManifestRemoved, file)
+		}
+	}
+
+	slices.Sort(epubDiff.ManifestAdded)
+	slices.Sort(epubDiff.ManifestRemoved)
+
+	for name, originalData := range original.Files {
+		updatedData, ok := updated.Files[name]
+		if !ok {
+			epubDiff.Files = append(epubDiff.Files, newFileDiff(name, Removed, originalData, nil))
+		} else if bytes.Equal(originalData, updatedData) {
+			epubDiff.UnchangedFiles++
+		} else {
+			epubDiff.Files = append(epubDiff.Files, newFileDiff(name, Modified, originalData, updatedData))
+		}
+	}
+
+	for name, updatedData := range updated.Files {
+		if _, ok := original.Files[name]; !ok {
+			epubDiff.Files = append(epubDiff.Files, newFileDiff(name, Added, nil, updatedData))
+		}
+	}
+
+	slices.SortFunc(epubDiff.Files, func(a, b FileDiff) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return epubDiff
+}
+
+// Summary gets the counts of the differences between the two epubs
+func (d EpubDiff) Summary() string {
+	var counts = map[ChangeType]int{}
+	for _, file := range d.Files {
+		counts[file.Change]++
+	}
+
+	return fmt.Sprintf(`Manifest: %d added, %d removed
+Spine: %s
+Metadata: %s
+Files: %d added, %d removed, %d modified, %d unchanged
+`, len(d.ManifestAdded), len(d.ManifestRemoved), changedText(d.OriginalSpine, d.UpdatedSpine), changedText(d.OriginalMetadata, d.UpdatedMetadata),
+		counts[Added], counts[Removed], counts[Modified], d.UnchangedFiles)
+}
+
+// String gets the full diff of the two epubs with text files either shown as unified diffs or
+// as word level diffs when wordDiff is true
+func (d EpubDiff) String(wordDiff bool) string {
+	var output strings.Builder
+	fmt.Fprintf(&output, "Comparing %q to %q\n\n", d.OriginalName, d.UpdatedName)
+
+	if len(d.ManifestAdded) != 0 || len(d.ManifestRemoved) != 0 {
+		output.WriteString("Manifest:\n")
+		for _, file := range d.ManifestAdded {
+			fmt.Fprintf(&output, "+ %s\n", file)
+		}
+
+		for _, file := range d.ManifestRemoved {
+			fmt.Fprintf(&output, "- %s\n", file)
+		}
+
+		output.WriteString("\n")
+	}
+
+	if d.OriginalSpine != d.UpdatedSpine {
+		output.WriteString("Spine:\n")
+		output.WriteString(renderTextDiff("spine", d.OriginalSpine, d.UpdatedSpine, wordDiff))
+		output.WriteString("\n")
+	}
+
+	if d.OriginalMetadata != d.UpdatedMetadata {
+		output.WriteString("Metadata:\n")
+		output.WriteString(renderTextDiff("metadata", d.OriginalMetadata, d.UpdatedMetadata, wordDiff))
+		output.WriteString("\n")
+	}
+
+	for _, file := range d.Files {
+		switch file.Change {
+		case Added:
+			fmt.Fprintf(&output, "Added %s (%d bytes)\n", file.Path, file.UpdatedSize)
+		case Removed:
+			fmt.Fprintf(&output, "Removed %s (%d bytes)\n", file.Path, file.OriginalSize)
+		case Modified:
+			fmt.Fprintf(&output, "Modified %s (%d bytes -> %d bytes, %+d)\n", file.Path, file.OriginalSize, file.UpdatedSize, file.UpdatedSize-file.OriginalSize)
+
+			if file.IsText {
+				output.WriteString(renderTextDiff(file.Path, file.OriginalText, file.UpdatedText, wordDiff))
+			} else if file.OriginalWidth != file.UpdatedWidth || file.OriginalHeight != file.UpdatedHeight {
+				fmt.Fprintf(&output, "Dimensions: %dx%d -> %dx%d\n", file.OriginalWidth, file.OriginalHeight, file.UpdatedWidth, file.UpdatedHeight)
+			}
+		}
+	}
+
+	return output.String()
+}
+
+func newFileDiff(path string, change ChangeType, originalData, updatedData []byte) FileDiff {
+	var fileDiff = FileDiff{
+		Path:         path,
+		Change:       change,
+		OriginalSize: len(originalData),
+		UpdatedSize:  len(updatedData),
+	}
+
+	if _, isText := textExts[strings.ToLower(filepath.Ext(path))]; isText || path == "mimetype" {
+		fileDiff.IsText = true
+		fileDiff.OriginalText = string(originalData)
+		fileDiff.UpdatedText = string(updatedData)
+
+		return fileDiff
+	}
+
+	fileDiff.OriginalWidth, fileDiff.OriginalHeight = getImageDimensions(originalData)
+	fileDiff.UpdatedWidth, fileDiff.UpdatedHeight = getImageDimensions(updatedData)
+
+	return fileDiff
+}
+
+// renderTextDiff gets the unified diff of the text or the word level diff of each changed section when wordDiff is true.
+// Word level diffs fall back to the unified diff for text that cannot be displayed as a word level diff.
+func renderTextDiff(name, original, updated string, wordDiff bool) string {
+	if !wordDiff {
+		return stringdiff.GetUnifiedDiff("a/"+name, "b/"+name, original, updated, contextLines)
+	}
+
+	var output strings.Builder
+	for _, hunk := range stringdiff.GetDiffHunks(original, updated, contextLines) {
+		var (
+			removed, added []string
+			flushChanges   = func() error {
+				if len(removed) == 0 && len(added) == 0 {
+					return nil
+				}
+
+				prettyDiff, err := stringdiff.GetPrettyDiffString(strings.Join(removed, "\n"), strings.Join(added, "\n"))
+				if err != nil {
+					return err
+				}
+
+				output.WriteString(prettyDiff)
+				output.WriteString("\n")
+				removed, added = nil, nil
+
+				return nil
+			}
+		)
+
+		output.WriteString(hunk.Header())
+		output.WriteString("\n")
+
+		for _, line := range hunk.Lines {
+			var err error
+			switch line[0] {
+			case '-':
+				removed = append(removed, line[1:])
+			case '+':
+				added = append(added, line[1:])
+			default:
+				err = flushChanges()
+				output.WriteString(line[1:])
+				output.WriteString("\n")
+			}
+
+			if err != nil {
+				return stringdiff.GetUnifiedDiff("a/"+name, "b/"+name, original, updated, contextLines)
+			}
+		}
+
+		if flushChanges() != nil {
+			return stringdiff.GetUnifiedDiff("a/"+name, "b/"+name, original, updated, contextLines)
+		}
+	}
+
+	return output.String()
+}
+
+func getManifestFiles(contents EpubContents) map[string]struct{} {
+	var manifestFiles = make(map[string]struct{})
+	for _, files := range []map[string]struct{}{contents.Info.HtmlFiles, contents.Info.ImagesFiles, contents.Info.CssFiles, contents.Info.OtherFiles} {
+		for file := range files {
+			manifestFiles[file] = struct{}{}
+		}
+	}
+
+	return manifestFiles
+}
+
+func getMetadata(opfContents string) string {
+	var startIndex = strings.Index(opfContents, "<metadata")
+	if startIndex == -1 {
+		return ""
+	}
+
+	var endIndex = strings.Index(opfContents[startIndex:], "</metadata>")
+	if endIndex == -1 {
+		return ""
+	}
+
+	return opfContents[startIndex : startIndex+endIndex+len("</metadata>")]
+}
+
+func getImageDimensions(data []byte) (int, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0
+	}
+
+	return config.Width, config.Height
+}
+
+func changedText(original, updated string) string {
+	if original == updated {
+		return "unchanged"
+	}
+
+	return "changed"
+}
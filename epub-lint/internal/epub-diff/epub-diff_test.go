@@ -0,0 +1,119 @@
+//go:build unit
+
+package epubdiff_test
+
+import (
+	"testing"
+
+	epubdiff "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-diff"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	originalOpf = `<package version="3.0">
+<metadata>
+<dc:title>Title</dc:title>
+</metadata>
+</package>`
+	updatedOpf = `<package version="3.0">
+<metadata>
+<dc:title>New Title</dc:title>
+</metadata>
+</package>`
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	var (
+		original = epubdiff.EpubContents{
+			Name: "original.epub",
+			Info: epubhandler.EpubInfo{
+				HtmlFiles:             map[string]struct{}{"chapter1.xhtml": {}, "chapter2.xhtml": {}},
+				ImagesFiles:           map[string]struct{}{"cover.jpg": {}},
+				OpfFile:               "OEBPS/content.opf",
+				FilePathsInSpineOrder: []string{"chapter1.xhtml", "chapter2.xhtml"},
+			},
+			Files: map[string][]byte{
+				"mimetype":              []byte("application/epub+zip"),
+				"OEBPS/content.opf":     []byte(originalOpf),
+				"OEBPS/chapter1.xhtml":  []byte("<p>Hello</p>\n<p>World</p>"),
+				"OEBPS/chapter2.xhtml":  []byte("<p>Goodbye</p>"),
+				"OEBPS/cover.jpg":       []byte("not a real image"),
+				"OEBPS/unused-file.txt": []byte("remove me"),
+			},
+		}
+		updated = epubdiff.EpubContents{
+			Name: "updated.epub",
+			Info: epubhandler.EpubInfo{
+				HtmlFiles:             map[string]struct{}{"chapter1.xhtml": {}, "tl_notes.xhtml": {}},
+				ImagesFiles:           map[string]struct{}{"cover.jpg": {}},
+				OpfFile:               "OEBPS/content.opf",
+				FilePathsInSpineOrder: []string{"chapter1.xhtml", "tl_notes.xhtml"},
+			},
+			Files: map[string][]byte{
+				"mimetype":             []byte("application/epub+zip"),
+				"OEBPS/content.opf":    []byte(updatedOpf),
+				"OEBPS/chapter1.xhtml": []byte("<p>Hello</p>\n<p>World!</p>"),
+				"OEBPS/tl_notes.xhtml": []byte("<p>Notes</p>"),
+				"OEBPS/cover.jpg":      []byte("not a real image"),
+			},
+		}
+	)
+
+	actual := epubdiff.Diff(original, updated)
+
+	assert.Equal(t, []string{"tl_notes.xhtml"}, actual.ManifestAdded)
+	assert.Equal(t, []string{"chapter2.xhtml"}, actual.ManifestRemoved)
+	assert.Equal(t, 2, actual.UnchangedFiles)
+	assert.Equal(t, `Manifest: 1 added, 1 removed
+Spine: changed
+Metadata: changed
+Files: 1 added, 2 removed, 2 modified, 2 unchanged
+`, actual.Summary())
+	assert.Equal(t, `Comparing "original.epub" to "updated.epub"
+
+Manifest:
++ tl_notes.xhtml
+- chapter2.xhtml
+
+Spine:
+--- a/spine
++++ b/spine
+@@ -1,2 +1,2 @@
+ chapter1.xhtml
+-chapter2.xhtml
++tl_notes.xhtml
+
+Metadata:
+--- a/metadata
++++ b/metadata
+@@ -1,3 +1,3 @@
+ <metadata>
+-<dc:title>Title</dc:title>
++<dc:title>New Title</dc:title>
+ </metadata>
+
+Modified OEBPS/chapter1.xhtml (25 bytes -> 26 bytes, +1)
+--- a/OEBPS/chapter1.xhtml
++++ b/OEBPS/chapter1.xhtml
+@@ -1,2 +1,2 @@
+ <p>Hello</p>
+-<p>World</p>
++<p>World!</p>
+Removed OEBPS/chapter2.xhtml (14 bytes)
+Modified OEBPS/content.opf (84 bytes -> 88 bytes, +4)
+--- a/OEBPS/content.opf
++++ b/OEBPS/content.opf
+@@ -1,5 +1,5 @@
+ <package version="3.0">
+ <metadata>
+-<dc:title>Title</dc:title>
++<dc:title>New Title</dc:title>
+ </metadata>
+ </package>
+Added OEBPS/tl_notes.xhtml (12 bytes)
+Removed OEBPS/unused-file.txt (9 bytes)
+`, actual.String(false))
+}
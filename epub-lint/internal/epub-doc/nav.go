@@ -0,0 +1,159 @@
+package epubdoc
+
+import "errors"
+
+const epubTypeAttr = "epub:type"
+
+var (
+	ErrNoNavToc  = errors.New("nav file has no toc nav element")
+	ErrNoTocList = errors.New("nav file toc has no list")
+)
+
+// Nav is an EPUB 3 navigation document
+type Nav struct {
+	Document *Node
+}
+
+// NavLink is an anchor in one of the nav elements of a navigation document
+type NavLink struct {
+	*Node
+}
+
+// ParseNav parses the contents of a navigation document
+func ParseNav(contents string) (*Nav, error) {
+	doc, err := Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Nav{
+		Document: doc,
+	}, nil
+}
+
+// String writes the navigation document back out keeping the formatting of anything that was not changed
+func (n *Nav) String() string {
+	return n.Document.String()
+}
+
+// NavElement gets the first element with the provided epub:type (i.e. "toc", "landmarks", or "page-list")
+func (n *Nav) NavElement(epubType string) *Node {
+	return n.Document.Find(func(node *Node) bool {
+		return node.Type == ElementNode && node.HasAttrToken(epubTypeAttr, epubType)
+	})
+}
+
+func (n *Nav) Toc() *Node {
+	return n.NavElement("toc")
+}
+
+func (n *Nav) Landmarks() *Node {
+	return n.NavElement("landmarks")
+}
+
+func (n *Nav) PageList() *Node {
+	return n.NavElement("page-list")
+}
+
+// TocList gets the top-level list of the toc which is an ol, but a ul is accepted as well
+func (n *Nav) TocList() *Node {
+	var toc = n.Toc()
+	if toc == nil {
+		return nil
+	}
+
+	if list := toc.FindElement("ol"); list != nil {
+		return list
+	}
+
+	return toc.FindElement("ul")
+}
+
+// TocLinks gets all of the links in the toc
+func (n *Nav) TocLinks() []NavLink {
+	return getLinks(n.Toc())
+}
+
+// LandmarkLinks gets all of the links in the landmarks
+func (n *Nav) LandmarkLinks() []NavLink {
+	return getLinks(n.Landmarks())
+}
+
+// AddTocEntry adds a list item that links to the href at the end of the toc where the title is markup that is already escaped
+func (n *Nav) AddTocEntry(href, title string) error {
+	if n.Toc() == nil {
+		return ErrNoNavToc
+	}
+
+	var list = n.TocList()
+	if list == nil {
+		return ErrNoTocList
+	}
+
+	titleNodes, err := ParseFragment(title)
+	if err != nil {
+		return err
+	}
+
+	var (
+		li = NewElement("li")
+		a  = NewElement("a", Attr{Name: "href", Value: href})
+	)
+	for _, node := range titleNodes {
+		a.AppendChild(node)
+	}
+
+	li.AppendChild(a)
+	list.AppendIndentedChild(li)
+
+	return nil
+}
+
+// RemoveListItems removes the list items whose first element is a link with an href that matches
+// returning how many were removed
+func (n *Nav) RemoveListItems(matches func(href string) bool) int {
+	var removed int
+	for _, li := range n.Document.FindElements("li") {
+		var elements = li.Elements()
+		if len(elements) == 0 || !elements[0].Is("a") {
+			continue
+		}
+
+		if href, ok := elements[0].Attr("href"); ok && matches(href) {
+			li.Remove()
+			removed++
+		}
+	}
+
+	return removed
+}
+
+func (l NavLink) Href() string {
+	return l.AttrValue("href")
+}
+
+func (l NavLink) SetHref(href string) {
+	l.SetAttr("href", href)
+}
+
+func (l NavLink) EpubType() string {
+	return l.AttrValue(epubTypeAttr)
+}
+
+// Title gets the text of the link without any markup
+func (l NavLink) Title() string {
+	return l.Text()
+}
+
+func getLinks(navEl *Node) []NavLink {
+	if navEl == nil {
+		return nil
+	}
+
+	var links []NavLink
+	for _, el := range navEl.FindElements("a") {
+		links = append(links, NavLink{el})
+	}
+
+	return links
+}
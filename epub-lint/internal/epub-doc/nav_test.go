@@ -0,0 +1,89 @@
+//go:build unit
+
+package epubdoc_test
+
+import (
+	"strings"
+	"testing"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const navContents = `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<body>
+  <nav epub:type="toc" id="toc">
+    <ol>
+      <li><a href="chapter1.xhtml">Chapter <b>1</b></a></li>
+      <li><a href="chapter2.xhtml">Chapter 2</a></li>
+    </ol>
+  </nav>
+  <nav epub:type="landmarks" hidden="">
+    <ol>
+      <li><a epub:type="cover" href="cover.xhtml">Cover</a></li>
+      <li><a epub:type="toc bodymatter" href="chapter1.xhtml">Start</a></li>
+    </ol>
+  </nav>
+</body>
+</html>`
+
+func TestParseNav(t *testing.T) {
+	t.Parallel()
+
+	nav, err := epubdoc.ParseNav(navContents)
+	require.NoError(t, err)
+
+	assert.Equal(t, navContents, nav.String())
+	assert.Equal(t, "toc", nav.Toc().AttrValue("id"))
+	assert.NotNil(t, nav.TocList())
+	assert.Nil(t, nav.PageList())
+
+	var tocLinks = nav.TocLinks()
+	require.Len(t, tocLinks, 2)
+	assert.Equal(t, "Chapter 1", tocLinks[0].Title())
+
+	var landmarkLinks = nav.LandmarkLinks()
+	require.Len(t, landmarkLinks, 2)
+	assert.Equal(t, "cover", landmarkLinks[0].EpubType())
+}
+
+func TestNavMutations(t *testing.T) {
+	t.Parallel()
+
+	nav, err := epubdoc.ParseNav(navContents)
+	require.NoError(t, err)
+
+	require.NoError(t, nav.AddTocEntry("notes.xhtml", "Notes &amp; <i>More</i>"))
+
+	assert.Equal(t, 1, nav.RemoveListItems(func(href string) bool {
+		return strings.HasSuffix(href, "chapter2.xhtml")
+	}))
+
+	nav.LandmarkLinks()[0].SetHref("new-cover.xhtml")
+
+	assert.Equal(t, `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<body>
+  <nav epub:type="toc" id="toc">
+    <ol>
+      <li><a href="chapter1.xhtml">Chapter <b>1</b></a></li>
+      <li><a href="notes.xhtml">Notes &amp; <i>More</i></a></li>
+    </ol>
+  </nav>
+  <nav epub:type="landmarks" hidden="">
+    <ol>
+      <li><a epub:type="cover" href="new-cover.xhtml">Cover</a></li>
+      <li><a epub:type="toc bodymatter" href="chapter1.xhtml">Start</a></li>
+    </ol>
+  </nav>
+</body>
+</html>`, nav.String())
+
+	nav, err = epubdoc.ParseNav(`<html><body><nav epub:type="toc"><h1>Contents</h1></nav></body></html>`)
+	require.NoError(t, err)
+	assert.ErrorIs(t, nav.AddTocEntry("notes.xhtml", "Notes"), epubdoc.ErrNoTocList)
+
+	nav, err = epubdoc.ParseNav(`<html><body></body></html>`)
+	require.NoError(t, err)
+	assert.ErrorIs(t, nav.AddTocEntry("notes.xhtml", "Notes"), epubdoc.ErrNoNavToc)
+}
@@ -0,0 +1,200 @@
+package epubdoc
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	ErrNoNcx    = errors.New("ncx file has no ncx element")
+	ErrNoNavMap = errors.New("ncx file has no navMap element")
+)
+
+// Ncx is an NCX table of contents document
+type Ncx struct {
+	Document *Node
+	Root     *Node
+}
+
+// NavPoint is a navPoint element in the navMap
+type NavPoint struct {
+	*Node
+}
+
+// ParseNcx parses the contents of an NCX file
+func ParseNcx(contents string) (*Ncx, error) {
+	doc, err := Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	var root = doc.FindElement("ncx")
+	if root == nil {
+		return nil, ErrNoNcx
+	}
+
+	return &Ncx{
+		Document: doc,
+		Root:     root,
+	}, nil
+}
+
+// String writes the NCX back out keeping the formatting of anything that was not changed
+func (n *Ncx) String() string {
+	return n.Document.String()
+}
+
+func (n *Ncx) NavMap() *Node {
+	return n.Root.FindElement("navMap")
+}
+
+// HeadMeta gets the meta element in the head with the provided name (i.e. "dtb:uid" or "dtb:totalPageCount")
+func (n *Ncx) HeadMeta(name string) *Node {
+	var head = n.Root.Element("head")
+	if head == nil {
+		return nil
+	}
+
+	for _, meta := range head.ElementsNamed("meta") {
+		if meta.AttrValue("name") == name {
+			return meta
+		}
+	}
+
+	return nil
+}
+
+// UidMeta gets the meta element in the head that has the dtb:uid
+func (n *Ncx) UidMeta() *Node {
+	return n.HeadMeta("dtb:uid")
+}
+
+// Uid gets the dtb:uid which is meant to match the unique identifier of the OPF
+func (n *Ncx) Uid() string {
+	var meta = n.UidMeta()
+	if meta == nil {
+		return ""
+	}
+
+	return meta.AttrValue("content")
+}
+
+func (n *Ncx) DocTitle() string {
+	var docTitle = n.Root.Element("docTitle")
+	if docTitle == nil {
+		return ""
+	}
+
+	return textOf(docTitle)
+}
+
+// NavPoints gets all of the nav points in the navMap including nested ones in document order
+func (n *Ncx) NavPoints() []NavPoint {
+	var navMap = n.NavMap()
+	if navMap == nil {
+		return nil
+	}
+
+	var navPoints []NavPoint
+	for _, el := range navMap.FindElements("navPoint") {
+		navPoints = append(navPoints, NavPoint{el})
+	}
+
+	return navPoints
+}
+
+// AddNavPoint adds a nav point to the end of the navMap with a play order after all of the existing nav points
+func (n *Ncx) AddNavPoint(id, label, src string) (NavPoint, error) {
+	var navMap = n.NavMap()
+	if navMap == nil {
+		return NavPoint{}, ErrNoNavMap
+	}
+
+	var (
+		navPoint = NewElement("navPoint", Attr{Name: "id", Value: id}, Attr{Name: "playOrder", Value: strconv.Itoa(len(n.Root.FindElements("navPoint")) + 1)})
+		navLabel = NewElement("navLabel")
+		text     = NewElement("text")
+	)
+	text.SetText(label)
+	navLabel.AppendChild(text)
+	navPoint.AppendChild(navLabel)
+	navPoint.AppendChild(NewElement("content", Attr{Name: "src", Value: src}))
+
+	formatNewElement(navPoint, "", navMap.getChildIndentUnit())
+	navMap.AppendIndentedChild(navPoint)
+
+	return NavPoint{navPoint}, nil
+}
+
+// RemoveNavPoints removes the nav points whose content has the provided src returning how many were removed
+func (n *Ncx) RemoveNavPoints(src string) int {
+	var removed int
+	for _, navPoint := range n.NavPoints() {
+		if navPoint.Parent != nil && navPoint.Src() == src {
+			navPoint.Remove()
+			removed++
+		}
+	}
+
+	return removed
+}
+
+func (p NavPoint) Id() string {
+	return p.AttrValue("id")
+}
+
+func (p NavPoint) PlayOrder() string {
+	return p.AttrValue("playOrder")
+}
+
+// Label gets the text of the nav label
+func (p NavPoint) Label() string {
+	var navLabel = p.Element("navLabel")
+	if navLabel == nil {
+		return ""
+	}
+
+	return textOf(navLabel)
+}
+
+// Content gets the content element that has the src of the nav point
+func (p NavPoint) Content() *Node {
+	return p.Element("content")
+}
+
+func (p NavPoint) Src() string {
+	var content = p.Content()
+	if content == nil {
+		return ""
+	}
+
+	return content.AttrValue("src")
+}
+
+// textOf gets the text of the first text element in the node
+func textOf(node *Node) string {
+	var text = node.Element("text")
+	if text == nil {
+		return ""
+	}
+
+	return text.Text()
+}
+
+// formatNewElement puts each child element of a new element on its own line with one more level of indentation
+// than the element where indent is the indentation of the element relative to where it will be added
+func formatNewElement(el *Node, indent, unit string) {
+	var children = el.Elements()
+	if len(children) == 0 {
+		return
+	}
+
+	el.Children = nil
+	for _, child := range children {
+		child.Parent = nil
+		formatNewElement(child, indent+unit, unit)
+		el.insertAt(len(el.Children), &Node{Type: TextNode, Data: "\n" + indent + unit, Start: -1, End: -1}, child)
+	}
+
+	el.insertAt(len(el.Children), &Node{Type: TextNode, Data: "\n" + indent, Start: -1, End: -1})
+}
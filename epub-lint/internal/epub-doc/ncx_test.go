@@ -0,0 +1,91 @@
+//go:build unit
+
+package epubdoc_test
+
+import (
+	"testing"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ncxContents = `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+	<head>
+		<meta name="dtb:uid" content="urn:uuid:1234"/>
+	</head>
+	<docTitle><text>Tom &amp; Jerry</text></docTitle>
+	<navMap>
+		<navPoint id="np1" playOrder="1">
+			<navLabel><text>Part 1</text></navLabel>
+			<content src="Text/part1.xhtml"/>
+			<navPoint id="np2" playOrder="2">
+				<navLabel><text>Chapter 1</text></navLabel>
+				<content src="Text/chapter1.xhtml#start"/>
+			</navPoint>
+		</navPoint>
+	</navMap>
+</ncx>`
+
+func TestParseNcx(t *testing.T) {
+	t.Parallel()
+
+	ncx, err := epubdoc.ParseNcx(ncxContents)
+	require.NoError(t, err)
+
+	assert.Equal(t, ncxContents, ncx.String())
+	assert.Equal(t, "urn:uuid:1234", ncx.Uid())
+	assert.Equal(t, ncx.UidMeta(), ncx.HeadMeta("dtb:uid"))
+	assert.Nil(t, ncx.HeadMeta("dtb:totalPageCount"))
+	assert.Equal(t, "Tom & Jerry", ncx.DocTitle())
+
+	var navPoints = ncx.NavPoints()
+	require.Len(t, navPoints, 2)
+	assert.Equal(t, "np2", navPoints[1].Id())
+	assert.Equal(t, "2", navPoints[1].PlayOrder())
+	assert.Equal(t, "Chapter 1", navPoints[1].Label())
+	assert.Equal(t, "Text/chapter1.xhtml#start", navPoints[1].Src())
+
+	_, err = epubdoc.ParseNcx(`<package></package>`)
+	assert.ErrorIs(t, err, epubdoc.ErrNoNcx)
+}
+
+func TestNcxMutations(t *testing.T) {
+	t.Parallel()
+
+	ncx, err := epubdoc.ParseNcx(ncxContents)
+	require.NoError(t, err)
+
+	_, err = ncx.AddNavPoint("np3", "Notes & Afterword", "Text/notes.xhtml")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, ncx.RemoveNavPoints("Text/chapter1.xhtml#start"))
+	assert.Zero(t, ncx.RemoveNavPoints("Text/missing.xhtml"))
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+	<head>
+		<meta name="dtb:uid" content="urn:uuid:1234"/>
+	</head>
+	<docTitle><text>Tom &amp; Jerry</text></docTitle>
+	<navMap>
+		<navPoint id="np1" playOrder="1">
+			<navLabel><text>Part 1</text></navLabel>
+			<content src="Text/part1.xhtml"/>
+		</navPoint>
+		<navPoint id="np3" playOrder="3">
+			<navLabel>
+				<text>Notes &amp; Afterword</text>
+			</navLabel>
+			<content src="Text/notes.xhtml"/>
+		</navPoint>
+	</navMap>
+</ncx>`, ncx.String())
+
+	ncx, err = epubdoc.ParseNcx(`<ncx><head/></ncx>`)
+	require.NoError(t, err)
+
+	_, err = ncx.AddNavPoint("np1", "Chapter 1", "chapter1.xhtml")
+	assert.ErrorIs(t, err, epubdoc.ErrNoNavMap)
+}
@@ -0,0 +1,839 @@
+package epubdoc
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+type NodeType int
+
+const (
+	DocumentNode NodeType = iota
+	ElementNode
+	TextNode
+	CommentNode
+	ProcessingInstructionNode
+	DirectiveNode
+	CDataNode
+)
+
+// Attr is an attribute of an element where raw is the attribute as it was written including its leading whitespace
+// which lets untouched attributes keep their quotes and spacing
+type Attr struct {
+	Name  string
+	Value string
+	raw   string
+}
+
+// Node is a node in a document that keeps enough of the source it was parsed from
+// for any part of the document that is not changed to be written back out exactly as it was
+type Node struct {
+	Type NodeType
+	// Name is the name of an element as it was written (i.e. "dc:title")
+	Name  string
+	Attrs []Attr
+	// Data is the source of a non-element node where text is left escaped and the other node types include their delimiters
+	Data     string
+	Children []*Node
+	Parent   *Node
+	// Start and End are the offsets of the node in the text it was parsed from which are -1 for nodes that were not parsed
+	Start, End int
+	// tagEnd is the whitespace and ">" or "/>" that end the start tag as written
+	tagEnd string
+	// endTag is the end tag as written which is empty for self-closing, unclosed, and new elements
+	endTag   string
+	unclosed bool
+}
+
+var (
+	attrValueEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	textEscaper      = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+// Parse parses the text into a document. It is tolerant of the kinds of malformed markup found in epubs:
+// end tags that do not match an open element are kept as text and elements that are never closed are closed at the
+// end of the document. An error is only returned when a tag, comment, or similar construct is never terminated.
+func Parse(text string) (*Node, error) {
+	var (
+		doc   = &Node{Type: DocumentNode, Start: 0, End: len(text)}
+		stack = []*Node{doc}
+		i     int
+	)
+	for i < len(text) {
+		var current = stack[len(stack)-1]
+		if text[i] != '<' {
+			var end = strings.IndexByte(text[i:], '<')
+			if end == -1 {
+				end = len(text)
+			} else {
+				end += i
+			}
+
+			current.appendParsed(&Node{Type: TextNode, Data: text[i:end], Start: i, End: end})
+			i = end
+
+			continue
+		}
+
+		var (
+			nodeType   NodeType
+			terminator string
+		)
+		switch {
+		case strings.HasPrefix(text[i:], "<!--"):
+			nodeType, terminator = CommentNode, "-->"
+		case strings.HasPrefix(text[i:], "<![CDATA["):
+			nodeType, terminator = CDataNode, "]]>"
+		case strings.HasPrefix(text[i:], "<?"):
+			nodeType, terminator = ProcessingInstructionNode, "?>"
+		case strings.HasPrefix(text[i:], "<!"):
+			var end = getDirectiveEnd(text, i)
+			if end == -1 {
+				return nil, fmt.Errorf("directive at offset %d is not terminated", i)
+			}
+
+			current.appendParsed(&Node{Type: DirectiveNode, Data: text[i:end], Start: i, End: end})
+			i = end
+
+			continue
+		case strings.HasPrefix(text[i:], "</"):
+			var end = strings.IndexByte(text[i:], '>')
+			if end == -1 {
+				return nil, fmt.Errorf("end tag at offset %d is not terminated", i)
+			}
+
+			end += i + 1
+
+			var (
+				name     = strings.TrimSpace(text[i+2 : end-1])
+				matching = -1
+			)
+			for j := len(stack) - 1; j > 0; j-- {
+				if stack[j].Name == name {
+					matching = j
+
+					break
+				}
+			}
+
+			if matching == -1 {
+				current.appendParsed(&Node{Type: TextNode, Data: text[i:end], Start: i, End: end})
+			} else {
+				for j := len(stack) - 1; j > matching; j-- {
+					stack[j].unclosed = true
+					stack[j].End = i
+				}
+
+				stack[matching].endTag = text[i:end]
+				stack[matching].End = end
+				stack = stack[:matching]
+			}
+
+			i = end
+
+			continue
+		default:
+			el, end, selfClosing, err := parseStartTag(text, i)
+			if err != nil {
+				return nil, err
+			}
+
+			if el == nil {
+				current.appendParsed(&Node{Type: TextNode, Data: "<", Start: i, End: i + 1})
+				i++
+
+				continue
+			}
+
+			current.appendParsed(el)
+			if !selfClosing {
+				stack = append(stack, el)
+			}
+
+			i = end
+
+			continue
+		}
+
+		var end = strings.Index(text[i:], terminator)
+		if end == -1 {
+			return nil, fmt.Errorf("%q at offset %d is not terminated", text[i:min(i+9, len(text))], i)
+		}
+
+		end += i + len(terminator)
+		current.appendParsed(&Node{Type: nodeType, Data: text[i:end], Start: i, End: end})
+		i = end
+	}
+
+	for j := len(stack) - 1; j > 0; j-- {
+		stack[j].unclosed = true
+		stack[j].End = len(text)
+	}
+
+	return doc, nil
+}
+
+// ParseFragment parses markup that is already escaped into nodes that can be added to a document
+func ParseFragment(text string) ([]*Node, error) {
+	doc, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes = doc.Children
+	for _, node := range nodes {
+		node.Parent = nil
+		node.walk(func(n *Node) {
+			n.Start, n.End = -1, -1
+		})
+	}
+
+	return nodes, nil
+}
+
+// NewElement creates an element with the provided attributes in the order they are provided
+func NewElement(name string, attrs ...Attr) *Node {
+	var el = &Node{Type: ElementNode, Name: name, Start: -1, End: -1}
+	for _, attr := range attrs {
+		el.SetAttr(attr.Name, attr.Value)
+	}
+
+	return el
+}
+
+// NewText creates a text node with the text escaped
+func NewText(text string) *Node {
+	return &Node{Type: TextNode, Data: textEscaper.Replace(text), Start: -1, End: -1}
+}
+
+// String writes the node and its descendants back out as markup
+func (n *Node) String() string {
+	var sb strings.Builder
+	n.write(&sb)
+
+	return sb.String()
+}
+
+// LocalName is the name of the element without its namespace prefix
+func (n *Node) LocalName() string {
+	if _, after, ok := strings.Cut(n.Name, ":"); ok {
+		return after
+	}
+
+	return n.Name
+}
+
+// Unclosed is whether the element was never closed in the text it was parsed from
+func (n *Node) Unclosed() bool {
+	return n.unclosed
+}
+
+// Is checks whether the node is an element with the provided name where a name without a prefix
+// matches the local name of the element so "title" matches "dc:title" but "dc:title" does not match "title"
+func (n *Node) Is(name string) bool {
+	if n.Type != ElementNode {
+		return false
+	}
+
+	if strings.Contains(name, ":") {
+		return n.Name == name
+	}
+
+	return n.LocalName() == name
+}
+
+// Attr gets the unescaped value of the attribute and whether it is present on the element
+func (n *Node) Attr(name string) (string, bool) {
+	for _, attr := range n.Attrs {
+		if attr.Name == name {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// AttrValue gets the unescaped value of the attribute or an empty string when it is not present
+func (n *Node) AttrValue(name string) string {
+	value, _ := n.Attr(name)
+
+	return value
+}
+
+// HasAttrToken checks whether the whitespace separated attribute value contains the token (i.e. epub:type="toc")
+func (n *Node) HasAttrToken(name, token string) bool {
+	return slices.Contains(strings.Fields(n.AttrValue(name)), token)
+}
+
+// SetAttr sets the attribute value keeping the position, spacing, and quotes of the attribute if it already exists
+// and adding it after the other attributes if it does not
+func (n *Node) SetAttr(name, value string) {
+	for i, attr := range n.Attrs {
+		if attr.Name != name {
+			continue
+		}
+
+		if attr.Value == value {
+			return
+		}
+
+		var quote = `"`
+		if attr.raw != "" {
+			var rawValue = attr.raw[strings.IndexByte(attr.raw, '=')+1:]
+			rawValue = strings.TrimLeft(rawValue, " \t\r\n")
+			if strings.HasPrefix(rawValue, "'") && !strings.Contains(value, "'") {
+				quote = "'"
+			}
+
+			var leading = attr.raw[:len(attr.raw)-len(strings.TrimLeft(attr.raw, " \t\r\n"))]
+			n.Attrs[i].raw = leading + name + "=" + quote + attrValueEscaper.Replace(value) + quote
+		}
+
+		n.Attrs[i].Value = value
+
+		return
+	}
+
+	n.Attrs = append(n.Attrs, Attr{Name: name, Value: value})
+}
+
+// RemoveAttr removes the attribute along with its leading whitespace
+func (n *Node) RemoveAttr(name string) {
+	n.Attrs = slices.DeleteFunc(n.Attrs, func(attr Attr) bool {
+		return attr.Name == name
+	})
+}
+
+// Elements gets the child elements of the node
+func (n *Node) Elements() []*Node {
+	var elements []*Node
+	for _, child := range n.Children {
+		if child.Type == ElementNode {
+			elements = append(elements, child)
+		}
+	}
+
+	return elements
+}
+
+// ElementsNamed gets the child elements of the node with the provided name
+func (n *Node) ElementsNamed(name string) []*Node {
+	var elements []*Node
+	for _, child := range n.Children {
+		if child.Is(name) {
+			elements = append(elements, child)
+		}
+	}
+
+	return elements
+}
+
+// Element gets the first child element with the provided name
+func (n *Node) Element(name string) *Node {
+	for _, child := range n.Children {
+		if child.Is(name) {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// Find gets the first descendant in document order that matches
+func (n *Node) Find(matches func(*Node) bool) *Node {
+	for _, child := range n.Children {
+		if matches(child) {
+			return child
+		}
+
+		if found := child.Find(matches); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// FindAll gets all descendants in document order that match
+func (n *Node) FindAll(matches func(*Node) bool) []*Node {
+	var found []*Node
+	for _, child := range n.Children {
+		child.walk(func(node *Node) {
+			if matches(node) {
+				found = append(found, node)
+			}
+		})
+	}
+
+	return found
+}
+
+// FindElement gets the first descendant element with the provided name
+func (n *Node) FindElement(name string) *Node {
+	return n.Find(func(node *Node) bool {
+		return node.Is(name)
+	})
+}
+
+// FindElements gets all descendant elements with the provided name
+func (n *Node) FindElements(name string) []*Node {
+	return n.FindAll(func(node *Node) bool {
+		return node.Is(name)
+	})
+}
+
+// Ancestor gets the closest ancestor element with the provided name
+func (n *Node) Ancestor(name string) *Node {
+	for parent := n.Parent; parent != nil; parent = parent.Parent {
+		if parent.Is(name) {
+			return parent
+		}
+	}
+
+	return nil
+}
+
+// Text gets the unescaped text of the node and its descendants
+func (n *Node) Text() string {
+	var sb strings.Builder
+	n.walk(func(node *Node) {
+		switch node.Type {
+		case TextNode:
+			sb.WriteString(html.UnescapeString(node.Data))
+		case CDataNode:
+			sb.WriteString(strings.TrimSuffix(strings.TrimPrefix(node.Data, "<![CDATA["), "]]>"))
+		}
+	})
+
+	return sb.String()
+}
+
+// SetText replaces the children of the node with the text
+func (n *Node) SetText(text string) {
+	for _, child := range n.Children {
+		child.Parent = nil
+	}
+
+	n.Children = nil
+	n.AppendChild(NewText(text))
+}
+
+// AppendChild adds the node as the last child of the node without adding any whitespace
+func (n *Node) AppendChild(child *Node) {
+	n.insertAt(len(n.Children), child)
+}
+
+// InsertBefore adds the node before the reference node without adding any whitespace
+func (n *Node) InsertBefore(child, ref *Node) {
+	n.insertAt(n.indexOf(ref), child)
+}
+
+// InsertAfter adds the node after the reference node without adding any whitespace
+func (n *Node) InsertAfter(child, ref *Node) {
+	n.insertAt(n.indexOf(ref)+1, child)
+}
+
+// AppendIndentedChild adds the node as the last child of the node on its own line using the indentation
+// of the existing children or, when there are none, one more level of indentation than the node itself
+func (n *Node) AppendIndentedChild(child *Node) {
+	for i := len(n.Children) - 1; i >= 0; i-- {
+		if n.Children[i].Type != TextNode {
+			n.InsertIndentedAfter(child, n.Children[i])
+
+			return
+		}
+	}
+
+	if len(n.Children) == 1 && strings.TrimSpace(n.Children[0].Data) == "" && strings.Contains(n.Children[0].Data, "\n") {
+		var (
+			parentIndent, _ = n.getIndent()
+			indent          = parentIndent + getIndentUnit(parentIndent)
+		)
+		indentNode(child, indent)
+		n.insertAt(0, &Node{Type: TextNode, Data: "\n" + indent, Start: -1, End: -1}, child)
+
+		return
+	}
+
+	if indent, onOwnLine := n.getIndent(); onOwnLine && len(n.Children) == 0 {
+		var childIndent = indent + getIndentUnit(indent)
+		indentNode(child, childIndent)
+		n.insertAt(0, &Node{Type: TextNode, Data: "\n" + childIndent, Start: -1, End: -1}, child, &Node{Type: TextNode, Data: "\n" + indent, Start: -1, End: -1})
+
+		return
+	}
+
+	n.AppendChild(child)
+}
+
+// InsertIndentedAfter adds the node after the reference node on its own line with the same indentation as the reference node.
+// When the reference node is on the same line as the end tag of the node, the end tag is moved to its own line.
+func (n *Node) InsertIndentedAfter(child, ref *Node) {
+	var indent, onOwnLine = ref.getIndent()
+	if !onOwnLine {
+		n.InsertAfter(child, ref)
+
+		return
+	}
+
+	indentNode(child, indent)
+
+	var (
+		index = n.indexOf(ref) + 1
+		nodes = []*Node{{Type: TextNode, Data: "\n" + indent, Start: -1, End: -1}, child}
+	)
+	if n.Type == ElementNode && !n.hasNewlineAfter(index) {
+		var parentIndent, _ = n.getIndent()
+		nodes = append(nodes, &Node{Type: TextNode, Data: "\n" + parentIndent, Start: -1, End: -1})
+	}
+
+	n.insertAt(index, nodes...)
+}
+
+// Remove removes the node from its parent along with its line when nothing else is on that line
+func (n *Node) Remove() {
+	var parent = n.Parent
+	if parent == nil {
+		return
+	}
+
+	var index = slices.Index(parent.Children, n)
+	if index == -1 {
+		n.Parent = nil
+
+		return
+	}
+
+	if index > 0 && parent.Children[index-1].Type == TextNode {
+		var (
+			prev        = parent.Children[index-1]
+			lastNewline = strings.LastIndexByte(prev.Data, '\n')
+			next        *Node
+			nextData    string
+		)
+		if index+1 < len(parent.Children) {
+			next = parent.Children[index+1]
+			if next.Type == TextNode {
+				nextData = next.Data
+			}
+		}
+
+		var (
+			lineEnd        = strings.IndexByte(nextData, '\n')
+			emptyLineStart = lastNewline != -1 && strings.TrimSpace(prev.Data[lastNewline:]) == ""
+			emptyLineEnd   = (lineEnd != -1 && strings.TrimSpace(nextData[:lineEnd]) == "") || (parent.Type == DocumentNode && index+1 == len(parent.Children))
+		)
+		if emptyLineStart && emptyLineEnd {
+			prev.Data = prev.Data[:lastNewline]
+			if lineEnd != -1 {
+				next.Data = nextData[lineEnd:]
+			}
+		}
+	}
+
+	parent.Children = slices.Delete(parent.Children, index, index+1)
+	n.Parent = nil
+
+	parent.Children = slices.DeleteFunc(parent.Children, func(child *Node) bool {
+		return child.Type == TextNode && child.Data == ""
+	})
+}
+
+func (n *Node) write(sb *strings.Builder) {
+	if n.Type != ElementNode {
+		sb.WriteString(n.Data)
+		for _, child := range n.Children {
+			child.write(sb)
+		}
+
+		return
+	}
+
+	sb.WriteString("<" + n.Name)
+	for _, attr := range n.Attrs {
+		if attr.raw != "" {
+			sb.WriteString(attr.raw)
+		} else {
+			sb.WriteString(" " + attr.Name + `="` + attrValueEscaper.Replace(attr.Value) + `"`)
+		}
+	}
+
+	var selfClosing = strings.HasSuffix(n.tagEnd, "/>") || (n.tagEnd == "" && n.endTag == "")
+	switch {
+	case selfClosing && len(n.Children) == 0:
+		if n.tagEnd == "" {
+			sb.WriteString("/>")
+		} else {
+			sb.WriteString(n.tagEnd)
+		}
+
+		return
+	case strings.HasSuffix(n.tagEnd, "/>"):
+		sb.WriteString(strings.TrimRight(strings.TrimSuffix(n.tagEnd, "/>"), " \t\r\n") + ">")
+	case n.tagEnd == "":
+		sb.WriteString(">")
+	default:
+		sb.WriteString(n.tagEnd)
+	}
+
+	for _, child := range n.Children {
+		child.write(sb)
+	}
+
+	if n.endTag != "" {
+		sb.WriteString(n.endTag)
+	} else if !n.unclosed {
+		sb.WriteString("</" + n.Name + ">")
+	}
+}
+
+func (n *Node) walk(visit func(*Node)) {
+	visit(n)
+	for _, child := range n.Children {
+		child.walk(visit)
+	}
+}
+
+func (n *Node) appendParsed(child *Node) {
+	child.Parent = n
+	n.Children = append(n.Children, child)
+}
+
+func (n *Node) indexOf(child *Node) int {
+	var index = slices.Index(n.Children, child)
+	if index == -1 {
+		return len(n.Children)
+	}
+
+	return index
+}
+
+func (n *Node) insertAt(index int, children ...*Node) {
+	for _, child := range children {
+		if child.Parent != nil {
+			child.Remove()
+		}
+
+		child.Parent = n
+	}
+
+	n.Children = slices.Insert(n.Children, index, children...)
+}
+
+// getIndent gets the whitespace before the node on its line and whether the node is the first thing on its line
+func (n *Node) getIndent() (string, bool) {
+	if n.Parent == nil {
+		return "", false
+	}
+
+	var index = n.Parent.indexOf(n)
+	if index == 0 {
+		if n.Parent.Type == DocumentNode {
+			return "", true
+		}
+
+		return "", false
+	}
+
+	var prev = n.Parent.Children[index-1]
+	if prev.Type != TextNode {
+		return "", false
+	}
+
+	var lastNewline = strings.LastIndexByte(prev.Data, '\n')
+	if lastNewline == -1 {
+		if n.Parent.Type == DocumentNode && index == 1 && strings.TrimSpace(prev.Data) == "" {
+			return prev.Data, true
+		}
+
+		return "", false
+	}
+
+	var indent = prev.Data[lastNewline+1:]
+	if strings.TrimLeft(indent, " \t") != "" {
+		return "", false
+	}
+
+	return indent, true
+}
+
+func (n *Node) hasNewlineAfter(index int) bool {
+	for _, child := range n.Children[index:] {
+		if child.Type != TextNode {
+			return true
+		}
+
+		if strings.Contains(child.Data, "\n") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getChildIndentUnit gets a single level of indentation based on how the children of the node are indented
+func (n *Node) getChildIndentUnit() string {
+	var indent, _ = n.getIndent()
+	for _, child := range n.Children {
+		if child.Type == TextNode && strings.Contains(child.Data, "\n") && strings.TrimSpace(child.Data) == "" {
+			var childIndent = child.Data[strings.LastIndexByte(child.Data, '\n')+1:]
+			if strings.HasPrefix(childIndent, indent) && len(childIndent) > len(indent) {
+				return childIndent[len(indent):]
+			}
+		}
+	}
+
+	return getIndentUnit(indent)
+}
+
+// getIndentUnit gets a single level of indentation based on the indentation it is nested in
+func getIndentUnit(indent string) string {
+	if strings.Contains(indent, "\t") {
+		return "\t"
+	}
+
+	return "  "
+}
+
+// indentNode adds the indentation after each line break inside of the node so multi-line nodes line up with where they are added
+func indentNode(node *Node, indent string) {
+	if indent == "" {
+		return
+	}
+
+	node.walk(func(n *Node) {
+		if n.Type == TextNode && strings.TrimSpace(n.Data) == "" {
+			n.Data = strings.ReplaceAll(n.Data, "\n", "\n"+indent)
+		}
+	})
+}
+
+func getDirectiveEnd(text string, start int) int {
+	var (
+		depth int
+		quote byte
+	)
+	for i := start + 2; i < len(text); i++ {
+		var char = text[i]
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '[':
+			depth++
+		case char == ']':
+			depth--
+		case char == '>' && depth <= 0:
+			return i + 1
+		}
+	}
+
+	return -1
+}
+
+// parseStartTag parses the start tag at the index returning a nil element when the "<" is not the start of a tag
+func parseStartTag(text string, start int) (*Node, int, bool, error) {
+	var i = start + 1
+	for i < len(text) && !isNameEnd(text[i]) {
+		i++
+	}
+
+	if i == start+1 || !isNameStart(text[start+1]) {
+		return nil, 0, false, nil
+	}
+
+	var el = &Node{Type: ElementNode, Name: text[start+1 : i], Start: start}
+	for {
+		var attrStart = i
+		for i < len(text) && isSpace(text[i]) {
+			i++
+		}
+
+		if i >= len(text) {
+			return nil, 0, false, fmt.Errorf("start tag %q at offset %d is not terminated", el.Name, start)
+		}
+
+		if text[i] == '>' {
+			el.tagEnd = text[attrStart : i+1]
+			el.End = i + 1
+
+			return el, i + 1, false, nil
+		}
+
+		if strings.HasPrefix(text[i:], "/>") {
+			el.tagEnd = text[attrStart : i+2]
+			el.End = i + 2
+
+			return el, i + 2, true, nil
+		}
+
+		var nameStart = i
+		for i < len(text) && !isNameEnd(text[i]) && text[i] != '=' {
+			i++
+		}
+
+		if i == nameStart {
+			// a stray character like a lone "/" is skipped so parsing can continue
+			i++
+
+			continue
+		}
+
+		var (
+			name  = text[nameStart:i]
+			value string
+			j     = i
+		)
+		for j < len(text) && isSpace(text[j]) {
+			j++
+		}
+
+		if j < len(text) && text[j] == '=' {
+			j++
+			for j < len(text) && isSpace(text[j]) {
+				j++
+			}
+
+			if j >= len(text) {
+				return nil, 0, false, fmt.Errorf("start tag %q at offset %d is not terminated", el.Name, start)
+			}
+
+			if quote := text[j]; quote == '"' || quote == '\'' {
+				var valueEnd = strings.IndexByte(text[j+1:], quote)
+				if valueEnd == -1 {
+					return nil, 0, false, fmt.Errorf("attribute %q at offset %d is not terminated", name, nameStart)
+				}
+
+				value = text[j+1 : j+1+valueEnd]
+				i = j + valueEnd + 2
+			} else {
+				var valueStart = j
+				for j < len(text) && !isSpace(text[j]) && text[j] != '>' {
+					j++
+				}
+
+				value = text[valueStart:j]
+				i = j
+			}
+		}
+
+		el.Attrs = append(el.Attrs, Attr{Name: name, Value: html.UnescapeString(value), raw: text[attrStart:i]})
+	}
+}
+
+func isSpace(char byte) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
+}
+
+func isNameEnd(char byte) bool {
+	return isSpace(char) || char == '>' || char == '/'
+}
+
+func isNameStart(char byte) bool {
+	return char == '_' || char == ':' || char >= 0x80 || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
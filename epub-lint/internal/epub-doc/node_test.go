@@ -0,0 +1,174 @@
+//go:build unit
+
+package epubdoc_test
+
+import (
+	"testing"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripTestCase struct {
+	input string
+}
+
+var roundTripTestCases = map[string]roundTripTestCase{
+	"When a document has a declaration, doctype, comments, cdata, and namespaces, it is written back out exactly as it was": {
+		input: `<?xml version="1.0" encoding="utf-8"?>
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.1//EN" "http://www.w3.org/TR/xhtml11/DTD/xhtml11.dtd">
+<!-- a comment with a <tag> in it -->
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub='http://www.idpf.org/2007/ops'>
+<head><style type="text/css"><![CDATA[ p > a { color: red; } ]]></style></head>
+<body>
+	<p class = "first"   id=unquoted>Tom &amp; Jerry</p>
+	<br/><br />
+</body>
+</html>
+`,
+	},
+	"When a document has an end tag that does not match an open element, it is kept as text": {
+		input: `<package><metadata></dc:title></metadata></package>`,
+	},
+	"When a document has elements that are never closed, they are written back out without end tags": {
+		input: `<html><body><p>Some text`,
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range roundTripTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := epubdoc.Parse(args.input)
+			require.NoError(t, err)
+
+			assert.Equal(t, args.input, doc.String())
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{`<p>text<!-- comment`, `<p class="value`, `<package`} {
+		_, err := epubdoc.Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+type editTestCase struct {
+	input    string
+	edit     func(doc *epubdoc.Node)
+	expected string
+}
+
+var editTestCases = map[string]editTestCase{
+	"When an attribute is set, its quotes and spacing are kept": {
+		input: `<a  href='old.xhtml' id="link">Link</a>`,
+		edit: func(doc *epubdoc.Node) {
+			doc.FindElement("a").SetAttr("href", "new & improved.xhtml")
+		},
+		expected: `<a  href='new &amp; improved.xhtml' id="link">Link</a>`,
+	},
+	"When an attribute is added and another is removed, the other attributes are untouched": {
+		input: `<dc:identifier  id="pub-id"	opf:scheme="uuid">123</dc:identifier>`,
+		edit: func(doc *epubdoc.Node) {
+			var identifier = doc.FindElement("dc:identifier")
+			identifier.RemoveAttr("id")
+			identifier.SetAttr("id", "other")
+		},
+		expected: `<dc:identifier	opf:scheme="uuid" id="other">123</dc:identifier>`,
+	},
+	"When an element that is alone on its line is removed, its line is removed as well": {
+		input: `<spine>
+  <itemref idref="a"/>
+  <itemref idref="b"/>
+  <itemref idref="c"/>
+</spine>`,
+		edit: func(doc *epubdoc.Node) {
+			doc.FindElement("spine").Elements()[1].Remove()
+		},
+		expected: `<spine>
+  <itemref idref="a"/>
+  <itemref idref="c"/>
+</spine>`,
+	},
+	"When an element that shares its line is removed, only the element is removed": {
+		input: `<spine>
+  <itemref idref="a"/><itemref idref="b"/>
+</spine>`,
+		edit: func(doc *epubdoc.Node) {
+			doc.FindElement("spine").Elements()[1].Remove()
+		},
+		expected: `<spine>
+  <itemref idref="a"/>
+</spine>`,
+	},
+	"When a child is appended to an element with tab indented children, it uses the same indentation": {
+		input: "<ol>\n\t<li>One</li>\n</ol>",
+		edit: func(doc *epubdoc.Node) {
+			var li = epubdoc.NewElement("li")
+			li.SetText("Two")
+			doc.FindElement("ol").AppendIndentedChild(li)
+		},
+		expected: "<ol>\n\t<li>One</li>\n\t<li>Two</li>\n</ol>",
+	},
+	"When a child is appended to an empty self-closing element on its own line, the element gets an end tag": {
+		input: "<package>\n  <spine/>\n</package>",
+		edit: func(doc *epubdoc.Node) {
+			doc.FindElement("spine").AppendIndentedChild(epubdoc.NewElement("itemref", epubdoc.Attr{Name: "idref", Value: "a"}))
+		},
+		expected: "<package>\n  <spine>\n    <itemref idref=\"a\"/>\n  </spine>\n</package>",
+	},
+	"When a child is inserted after the last child which shares a line with the end tag, the end tag is moved to its own line": {
+		input: "<metadata>\n  <dc:title>Title</dc:title></metadata>",
+		edit: func(doc *epubdoc.Node) {
+			var metadata = doc.FindElement("metadata")
+			metadata.InsertIndentedAfter(epubdoc.NewElement("dc:language"), metadata.Elements()[0])
+		},
+		expected: "<metadata>\n  <dc:title>Title</dc:title>\n  <dc:language/>\n</metadata>",
+	},
+}
+
+func TestEdits(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range editTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := epubdoc.Parse(args.input)
+			require.NoError(t, err)
+
+			args.edit(doc)
+
+			assert.Equal(t, args.expected, doc.String())
+		})
+	}
+}
+
+func TestQueries(t *testing.T) {
+	t.Parallel()
+
+	doc, err := epubdoc.Parse(`<package><metadata><dc:title>Tom &amp; <![CDATA[Jerry]]></dc:title><meta property="title-type"/></metadata></package>`)
+	require.NoError(t, err)
+
+	var title = doc.FindElement("title")
+	require.NotNil(t, title)
+	assert.Equal(t, "dc:title", title.Name)
+	assert.Equal(t, "title", title.LocalName())
+	assert.Equal(t, "Tom & Jerry", title.Text())
+	assert.Equal(t, "package", title.Ancestor("package").Name)
+	assert.Nil(t, doc.FindElement("dc:meta"))
+	assert.Len(t, doc.FindElement("metadata").Elements(), 2)
+	assert.False(t, doc.FindElement("metadata").Unclosed())
+
+	doc, err = epubdoc.Parse(`<package><metadata><dc:title>Title</dc:title></package>`)
+	require.NoError(t, err)
+	assert.True(t, doc.FindElement("metadata").Unclosed())
+	assert.False(t, doc.FindElement("package").Unclosed())
+}
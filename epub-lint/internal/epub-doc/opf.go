@@ -0,0 +1,270 @@
+package epubdoc
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrNoPackage  = errors.New("opf has no package element")
+	ErrNoMetadata = errors.New("opf has no metadata element")
+	ErrNoManifest = errors.New("opf has no manifest element")
+	ErrNoSpine    = errors.New("opf has no spine element")
+)
+
+// Opf is an OPF package document
+type Opf struct {
+	Document *Node
+	Package  *Node
+}
+
+// ManifestItem is an item element in the manifest
+type ManifestItem struct {
+	*Node
+}
+
+// SpineItem is an itemref element in the spine
+type SpineItem struct {
+	*Node
+}
+
+// GuideReference is a reference element in the guide
+type GuideReference struct {
+	*Node
+}
+
+// ParseOpf parses the contents of an OPF file
+func ParseOpf(contents string) (*Opf, error) {
+	doc, err := Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkg = doc.FindElement("package")
+	if pkg == nil {
+		return nil, ErrNoPackage
+	}
+
+	return &Opf{
+		Document: doc,
+		Package:  pkg,
+	}, nil
+}
+
+// String writes the OPF back out keeping the formatting of anything that was not changed
+func (o *Opf) String() string {
+	return o.Document.String()
+}
+
+func (o *Opf) Metadata() *Node {
+	return o.Package.FindElement("metadata")
+}
+
+func (o *Opf) Manifest() *Node {
+	return o.Package.FindElement("manifest")
+}
+
+func (o *Opf) Spine() *Node {
+	return o.Package.FindElement("spine")
+}
+
+func (o *Opf) Guide() *Node {
+	return o.Package.FindElement("guide")
+}
+
+// Collections gets the top-level collection elements of the package
+func (o *Opf) Collections() []*Node {
+	return o.Package.ElementsNamed("collection")
+}
+
+// MetadataElements gets the metadata elements with the provided name (i.e. "dc:identifier" or "meta")
+func (o *Opf) MetadataElements(name string) []*Node {
+	var metadata = o.Metadata()
+	if metadata == nil {
+		return nil
+	}
+
+	return metadata.ElementsNamed(name)
+}
+
+// UniqueIdentifier gets the identifier element that the unique-identifier attribute of the package refers to
+func (o *Opf) UniqueIdentifier() *Node {
+	var id = o.Package.AttrValue("unique-identifier")
+	if id == "" {
+		return nil
+	}
+
+	for _, identifier := range o.MetadataElements("dc:identifier") {
+		if identifier.AttrValue("id") == id {
+			return identifier
+		}
+	}
+
+	return nil
+}
+
+// AddMetadata adds a metadata element with the provided text and attributes at the end of the metadata
+func (o *Opf) AddMetadata(name, text string, attrs ...Attr) (*Node, error) {
+	var metadata = o.Metadata()
+	if metadata == nil {
+		return nil, ErrNoMetadata
+	}
+
+	var el = NewElement(name, attrs...)
+	el.SetText(text)
+	metadata.AppendIndentedChild(el)
+
+	return el, nil
+}
+
+func (o *Opf) ManifestItems() []ManifestItem {
+	var manifest = o.Manifest()
+	if manifest == nil {
+		return nil
+	}
+
+	var items []ManifestItem
+	for _, el := range manifest.ElementsNamed("item") {
+		items = append(items, ManifestItem{el})
+	}
+
+	return items
+}
+
+// ManifestItemById gets the manifest item with the provided id
+func (o *Opf) ManifestItemById(id string) (ManifestItem, bool) {
+	for _, item := range o.ManifestItems() {
+		if item.Id() == id {
+			return item, true
+		}
+	}
+
+	return ManifestItem{}, false
+}
+
+// ManifestItemsByHref gets the manifest items whose href is the provided path or ends with it as a path segment
+// so "chapter.xhtml" matches "Text/chapter.xhtml" but not "other-chapter.xhtml"
+func (o *Opf) ManifestItemsByHref(href string) []ManifestItem {
+	var items []ManifestItem
+	for _, item := range o.ManifestItems() {
+		var itemHref = item.Href()
+		if itemHref == href || strings.HasSuffix(itemHref, "/"+href) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// AddManifestItem adds an item at the end of the manifest
+func (o *Opf) AddManifestItem(id, href, mediaType string) (ManifestItem, error) {
+	var manifest = o.Manifest()
+	if manifest == nil {
+		return ManifestItem{}, ErrNoManifest
+	}
+
+	var el = NewElement("item", Attr{Name: "id", Value: id}, Attr{Name: "href", Value: href}, Attr{Name: "media-type", Value: mediaType})
+	manifest.AppendIndentedChild(el)
+
+	return ManifestItem{el}, nil
+}
+
+// RemoveManifestItem removes the item from the manifest along with any references to it in the spine
+func (o *Opf) RemoveManifestItem(item ManifestItem) {
+	item.Remove()
+
+	if id := item.Id(); id != "" {
+		o.RemoveSpineItems(id)
+	}
+}
+
+func (o *Opf) SpineItems() []SpineItem {
+	var spine = o.Spine()
+	if spine == nil {
+		return nil
+	}
+
+	var items []SpineItem
+	for _, el := range spine.ElementsNamed("itemref") {
+		items = append(items, SpineItem{el})
+	}
+
+	return items
+}
+
+// AddSpineItem adds an itemref at the end of the spine
+func (o *Opf) AddSpineItem(idref string) (SpineItem, error) {
+	var spine = o.Spine()
+	if spine == nil {
+		return SpineItem{}, ErrNoSpine
+	}
+
+	var el = NewElement("itemref", Attr{Name: "idref", Value: idref})
+	spine.AppendIndentedChild(el)
+
+	return SpineItem{el}, nil
+}
+
+// RemoveSpineItems removes the itemrefs that refer to the id returning how many were removed
+func (o *Opf) RemoveSpineItems(idref string) int {
+	var removed int
+	for _, item := range o.SpineItems() {
+		if item.Idref() == idref {
+			item.Remove()
+			removed++
+		}
+	}
+
+	return removed
+}
+
+func (o *Opf) GuideReferences() []GuideReference {
+	var guide = o.Guide()
+	if guide == nil {
+		return nil
+	}
+
+	var references []GuideReference
+	for _, el := range guide.ElementsNamed("reference") {
+		references = append(references, GuideReference{el})
+	}
+
+	return references
+}
+
+func (i ManifestItem) Id() string {
+	return i.AttrValue("id")
+}
+
+func (i ManifestItem) Href() string {
+	return i.AttrValue("href")
+}
+
+func (i ManifestItem) MediaType() string {
+	return i.AttrValue("media-type")
+}
+
+func (i ManifestItem) Properties() []string {
+	return strings.Fields(i.AttrValue("properties"))
+}
+
+func (i SpineItem) Idref() string {
+	return i.AttrValue("idref")
+}
+
+// Linear is whether the item is part of the default reading order which is the case unless linear is set to "no"
+func (i SpineItem) Linear() bool {
+	return strings.TrimSpace(i.AttrValue("linear")) != "no"
+}
+
+func (r GuideReference) Type() string {
+	return r.AttrValue("type")
+}
+
+func (r GuideReference) Title() string {
+	return r.AttrValue("title")
+}
+
+func (r GuideReference) Href() string {
+	return r.AttrValue("href")
+}
@@ -0,0 +1,117 @@
+//go:build unit
+
+package epubdoc_test
+
+import (
+	"testing"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const opfContents = `<?xml version="1.0" encoding="utf-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="pub-id">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="isbn">9781234567890</dc:identifier>
+    <dc:identifier id="pub-id">urn:uuid:1234</dc:identifier>
+    <dc:title>Title</dc:title>
+  </metadata>
+  <manifest>
+    <!-- content -->
+    <item id="chapter1" href="Text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="nav" href="Text/nav.xhtml" media-type="application/xhtml+xml" properties="nav scripted"/>
+  </manifest>
+  <spine toc="ncx">
+    <itemref idref="nav" linear="no"/>
+    <itemref idref="chapter1"/>
+  </spine>
+  <guide>
+    <reference type="toc" title="Contents" href="Text/nav.xhtml"/>
+  </guide>
+  <collection role="index"/>
+</package>`
+
+func TestParseOpf(t *testing.T) {
+	t.Parallel()
+
+	opf, err := epubdoc.ParseOpf(opfContents)
+	require.NoError(t, err)
+
+	assert.Equal(t, opfContents, opf.String())
+	assert.Equal(t, "urn:uuid:1234", opf.UniqueIdentifier().Text())
+	assert.Len(t, opf.MetadataElements("dc:identifier"), 2)
+	assert.Len(t, opf.Collections(), 1)
+
+	var items = opf.ManifestItems()
+	require.Len(t, items, 2)
+	assert.Equal(t, []string{"nav", "scripted"}, items[1].Properties())
+
+	var spineItems = opf.SpineItems()
+	require.Len(t, spineItems, 2)
+	assert.False(t, spineItems[0].Linear())
+	assert.True(t, spineItems[1].Linear())
+
+	var references = opf.GuideReferences()
+	require.Len(t, references, 1)
+	assert.Equal(t, "toc", references[0].Type())
+	assert.Equal(t, "Text/nav.xhtml", references[0].Href())
+
+	assert.Len(t, opf.ManifestItemsByHref("chapter1.xhtml"), 1)
+	assert.Empty(t, opf.ManifestItemsByHref("pter1.xhtml"))
+
+	_, err = epubdoc.ParseOpf(`<ncx></ncx>`)
+	assert.ErrorIs(t, err, epubdoc.ErrNoPackage)
+}
+
+func TestOpfMutations(t *testing.T) {
+	t.Parallel()
+
+	opf, err := epubdoc.ParseOpf(opfContents)
+	require.NoError(t, err)
+
+	_, err = opf.AddManifestItem("chapter2", "Text/chapter 2.xhtml", "application/xhtml+xml")
+	require.NoError(t, err)
+
+	_, err = opf.AddSpineItem("chapter2")
+	require.NoError(t, err)
+
+	_, err = opf.AddMetadata("dc:language", "en")
+	require.NoError(t, err)
+
+	item, ok := opf.ManifestItemById("chapter1")
+	require.True(t, ok)
+	opf.RemoveManifestItem(item)
+
+	assert.Equal(t, `<?xml version="1.0" encoding="utf-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="pub-id">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="isbn">9781234567890</dc:identifier>
+    <dc:identifier id="pub-id">urn:uuid:1234</dc:identifier>
+    <dc:title>Title</dc:title>
+    <dc:language>en</dc:language>
+  </metadata>
+  <manifest>
+    <!-- content -->
+    <item id="nav" href="Text/nav.xhtml" media-type="application/xhtml+xml" properties="nav scripted"/>
+    <item id="chapter2" href="Text/chapter 2.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine toc="ncx">
+    <itemref idref="nav" linear="no"/>
+    <itemref idref="chapter2"/>
+  </spine>
+  <guide>
+    <reference type="toc" title="Contents" href="Text/nav.xhtml"/>
+  </guide>
+  <collection role="index"/>
+</package>`, opf.String())
+
+	opf, err = epubdoc.ParseOpf(`<package><metadata/></package>`)
+	require.NoError(t, err)
+
+	_, err = opf.AddManifestItem("id", "file.xhtml", "application/xhtml+xml")
+	assert.ErrorIs(t, err, epubdoc.ErrNoManifest)
+
+	_, err = opf.AddSpineItem("id")
+	assert.ErrorIs(t, err, epubdoc.ErrNoSpine)
+}
@@ -1,23 +1,22 @@
 package epubhandler
 
 import (
-	"fmt"
-	"strings"
+	"html"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
 )
 
-// AddFileToNav adds the specified file at the end of the nav contents
+// AddFileToNav adds the specified file at the end of the toc list of the nav contents
+// where the file path and title are already escaped
 func AddFileToNav(navContents, filePath, title string) string {
-	endOfEl, endOfElIndex := GetNavTOCContentPositionInfo(navContents)
-	if endOfEl == -1 || endOfElIndex == -1 {
+	nav, err := epubdoc.ParseNav(navContents)
+	if err != nil {
 		return navContents
 	}
 
-	// for now we shall assume that all nav TOCs are made up of an ol element.
-	const endingOlEl = "</ol>"
-	insertIndex := strings.LastIndex(navContents[:endOfElIndex], endingOlEl)
-	if insertIndex == -1 {
+	if err = nav.AddTocEntry(html.UnescapeString(filePath), title); err != nil {
 		return navContents
 	}
 
-	return navContents[:insertIndex] + fmt.Sprintf(`<li><a href=%q>%s</a></li>`, filePath, title) + "\n" + navContents[insertIndex:]
+	return nav.String()
 }
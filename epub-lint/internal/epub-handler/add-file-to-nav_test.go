@@ -129,8 +129,8 @@ var addFileToNavTestCases = map[string]addFileToNavTestCase{
 	<nav epub:type="toc" id="toc">
   <h1>Table of Contents</h1>
 	<ol>
-	<li><a href="Text/tl_notes.xhtml">Translator's Notes</a></li>
-</ol>
+		<li><a href="Text/tl_notes.xhtml">Translator's Notes</a></li>
+	</ol>
 	</nav>
   <nav epub:type="landmarks" id="landmarks" hidden="">
   <h2>Guide</h2>
@@ -278,8 +278,8 @@ var addFileToNavTestCases = map[string]addFileToNavTestCase{
   <li>
   <a href="../Text/section-0029.html">Newsletter</a>
   </li>
-	<li><a href="Text/tl_notes.xhtml">Translator's Notes</a></li>
-</ol>
+  <li><a href="Text/tl_notes.xhtml">Translator's Notes</a></li>
+	</ol>
 	</nav>
   <nav epub:type="landmarks" id="landmarks" hidden="">
   <h2>Guide</h2>
@@ -1,28 +1,21 @@
 package epubhandler
 
 import (
-	"fmt"
-	"strings"
+	"html"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
 )
 
+// AddFileToNcx adds a nav point for the file at the end of the nav map where the file path and title are already escaped
 func AddFileToNcx(text, filePath, title, id string) string {
-	navMapClose := "</navMap>"
-	navMapIdx := strings.Index(text, navMapClose)
-	if navMapIdx == -1 {
+	ncx, err := epubdoc.ParseNcx(text)
+	if err != nil {
 		return text
 	}
 
-	var playOrder = strings.Count(text, "<navPoint") + 1
-
-	navPoint := fmt.Sprintf(
-		`  <navPoint id="%s" playOrder="%d">
-    <navLabel>
-      <text>%s</text>
-    </navLabel>
-    <content src="%s"/>
-  </navPoint>
-`, id, playOrder, title, filePath)
+	if _, err = ncx.AddNavPoint(id, html.UnescapeString(title), html.UnescapeString(filePath)); err != nil {
+		return text
+	}
 
-	result := text[:navMapIdx] + navPoint + text[navMapIdx:]
-	return result
+	return ncx.String()
 }
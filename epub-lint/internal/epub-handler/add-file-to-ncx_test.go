@@ -50,12 +50,12 @@ var addFileToNcxTestCases = map[string]addFileToNcxTestCase{
 		expected: `<ncx>
   <navMap>
     <navPoint id="ch1" playOrder="1">
-    <navLabel>
-      <text>Chapter 1</text>
-    </navLabel>
-    <content src="chapter1.xhtml"/>
-  </navPoint>
-</navMap>
+      <navLabel>
+        <text>Chapter 1</text>
+      </navLabel>
+      <content src="chapter1.xhtml"/>
+    </navPoint>
+  </navMap>
 </ncx>`,
 	},
 	"When there are 3 navPoints, the added file's playOrder should be 4": {
@@ -93,12 +93,12 @@ var addFileToNcxTestCases = map[string]addFileToNcxTestCase{
       <content src="ch3.xhtml"/>
     </navPoint>
     <navPoint id="ch4" playOrder="4">
-    <navLabel>
-      <text>Chapter 4</text>
-    </navLabel>
-    <content src="chapter4.xhtml"/>
-  </navPoint>
-</navMap>
+      <navLabel>
+        <text>Chapter 4</text>
+      </navLabel>
+      <content src="chapter4.xhtml"/>
+    </navPoint>
+  </navMap>
 </ncx>`,
 	},
 }
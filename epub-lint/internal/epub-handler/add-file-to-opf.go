@@ -1,25 +1,26 @@
 package epubhandler
 
 import (
-	"fmt"
-	"strings"
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
 )
 
+const xhtmlMediaType = "application/xhtml+xml"
+
+// AddFileToOpf adds the file to the manifest and, when it is a content document, to the end of the spine
 func AddFileToOpf(text, filename, id, mediaType string) string {
-	itemEntry := fmt.Sprintf(`<item id=%q href=%q media-type=%q/>`, id, filename, mediaType)
-	itemrefEntry := fmt.Sprintf(`<itemref idref=%q/>`, id)
+	opf, err := epubdoc.ParseOpf(text)
+	if err != nil {
+		return text
+	}
 
-	manifestClose := "</manifest>"
-	manifestIndex := strings.Index(text, manifestClose)
-	if manifestIndex != -1 {
-		text = text[:manifestIndex] + "  " + itemEntry + "\n" + text[manifestIndex:]
+	if _, err = opf.AddManifestItem(id, filename, mediaType); err != nil {
+		return text
 	}
 
-	spineClose := "</spine>"
-	spineIndex := strings.Index(text, spineClose)
-	if spineIndex != -1 {
-		text = text[:spineIndex] + "  " + itemrefEntry + "\n" + text[spineIndex:]
+	if mediaType == xhtmlMediaType {
+		// a missing spine only means the file cannot be added to the reading order, so the manifest change is still kept
+		_, _ = opf.AddSpineItem(id)
 	}
 
-	return text
+	return opf.String()
 }
@@ -31,22 +31,20 @@ var addFileToOpfTestCases = map[string]addFileToOpfTestCase{
 		expected: `<package>
   <manifest>
     <item id="test-id" href="test.xhtml" media-type="application/xhtml+xml"/>
-</manifest>
+  </manifest>
   <spine>
     <itemref idref="test-id"/>
-</spine>
+  </spine>
 </package>`,
 	},
-	"When the manifest is on a single line, the file should be added and the ending manifest tag should now be on its own line": {
+	"When the manifest is on a single line, the file should be added without adding any line breaks": {
 		inputText: `<package><manifest></manifest><spine></spine></package>`,
 		filename:  "test.xhtml",
 		id:        "test-id",
 		mediaType: "application/xhtml+xml",
-		expected: `<package><manifest>  <item id="test-id" href="test.xhtml" media-type="application/xhtml+xml"/>
-</manifest><spine>  <itemref idref="test-id"/>
-</spine></package>`,
+		expected:  `<package><manifest><item id="test-id" href="test.xhtml" media-type="application/xhtml+xml"/></manifest><spine><itemref idref="test-id"/></spine></package>`,
 	},
-	"When the spine is on a single line, the file should be added and the ending spine tag should now be on its own line": {
+	"When the spine is empty and on a single line, the file should be added on its own line and the ending spine tag should now be on its own line": {
 		inputText: `<package>
   <manifest>
     <item id="item1" href="chapter1.xhtml" media-type="application/xhtml+xml"/>
@@ -60,9 +58,10 @@ var addFileToOpfTestCases = map[string]addFileToOpfTestCase{
   <manifest>
     <item id="item1" href="chapter1.xhtml" media-type="application/xhtml+xml"/>
     <item id="test-id" href="test.xhtml" media-type="application/xhtml+xml"/>
-</manifest>
-  <spine>  <itemref idref="test-id"/>
-</spine>
+  </manifest>
+  <spine>
+    <itemref idref="test-id"/>
+  </spine>
 </package>`,
 	},
 	"When the manifest and spine each have entries on their own line, the file should be added correctly": {
@@ -84,12 +83,30 @@ var addFileToOpfTestCases = map[string]addFileToOpfTestCase{
     <item id="item1" href="chapter1.xhtml" media-type="application/xhtml+xml"/>
     <item id="item2" href="chapter2.xhtml" media-type="application/xhtml+xml"/>
     <item id="test-id" href="test.xhtml" media-type="application/xhtml+xml"/>
-</manifest>
+  </manifest>
   <spine>
     <itemref idref="item1"/>
     <itemref idref="item2"/>
     <itemref idref="test-id"/>
-</spine>
+  </spine>
+</package>`,
+	},
+	"When the file is not a content document, it should only be added to the manifest": {
+		inputText: `<package>
+  <manifest>
+  </manifest>
+  <spine>
+  </spine>
+</package>`,
+		filename:  "stylesheet.css",
+		id:        "css",
+		mediaType: "text/css",
+		expected: `<package>
+  <manifest>
+    <item id="css" href="stylesheet.css" media-type="text/css"/>
+  </manifest>
+  <spine>
+  </spine>
 </package>`,
 	},
 }
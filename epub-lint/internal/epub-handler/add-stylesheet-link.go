@@ -0,0 +1,25 @@
+package epubhandler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var headEndRegex = regexp.MustCompile(`(?i)[ \t]*</head>`)
+
+// AddStylesheetLink adds a link to the stylesheet to the end of the head of the content file
+// when the file has a head and does not already link to the stylesheet
+func AddStylesheetLink(text, href string) string {
+	var indices = headEndRegex.FindStringIndex(text)
+	if indices == nil || strings.Contains(text, fmt.Sprintf(`href=%q`, href)) {
+		return text
+	}
+
+	var (
+		closingTag = text[indices[0]:indices[1]]
+		indent     = closingTag[:len(closingTag)-len(strings.TrimLeft(closingTag, " \t"))]
+	)
+
+	return text[:indices[0]] + fmt.Sprintf(`%s  <link href=%q rel="stylesheet" type="text/css"/>`, indent, href) + "\n" + text[indices[0]:]
+}
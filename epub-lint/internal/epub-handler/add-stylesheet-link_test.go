@@ -0,0 +1,57 @@
+//go:build unit
+
+package epubhandler_test
+
+import (
+	"testing"
+
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/stretchr/testify/assert"
+)
+
+type addStylesheetLinkTestCase struct {
+	inputText string
+	href      string
+	expected  string
+}
+
+var addStylesheetLinkTestCases = map[string]addStylesheetLinkTestCase{
+	"When the head is present, the link is added to the end of it with the indentation of the head": {
+		inputText: `<html>
+  <head>
+    <title>Chapter 1</title>
+  </head>
+  <body></body>
+</html>`,
+		href: "../Styles/stylesheet.css",
+		expected: `<html>
+  <head>
+    <title>Chapter 1</title>
+    <link href="../Styles/stylesheet.css" rel="stylesheet" type="text/css"/>
+  </head>
+  <body></body>
+</html>`,
+	},
+	"When the head is missing, no change is made": {
+		inputText: `<html><body></body></html>`,
+		href:      "stylesheet.css",
+		expected:  `<html><body></body></html>`,
+	},
+	"When the stylesheet is already linked, no change is made": {
+		inputText: `<html><head><link href="stylesheet.css" rel="stylesheet" type="text/css"/></head><body></body></html>`,
+		href:      "stylesheet.css",
+		expected:  `<html><head><link href="stylesheet.css" rel="stylesheet" type="text/css"/></head><body></body></html>`,
+	},
+}
+
+func TestAddStylesheetLink(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range addStylesheetLinkTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			result := epubhandler.AddStylesheetLink(tc.inputText, tc.href)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package epubhandler
 
 import (
 	"fmt"
+	"html"
+	"regexp"
 	"strings"
 )
 
@@ -10,6 +12,9 @@ const (
 	ManifestEndTag   = "</manifest>"
 )
 
+// IdAttributeRegex matches an id attribute with a group for a double quoted value followed by a group for a single quoted value
+var IdAttributeRegex = regexp.MustCompile(`\sid\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
 func GetManifestContents(opfContents string) (int, int, string, error) {
 	startIndex := strings.Index(opfContents, ManifestStartTag)
 	endIndex := strings.Index(opfContents, ManifestEndTag)
@@ -108,3 +113,36 @@ func GetAttributeValue(text, attribute string) (string, int, int, error) {
 
 	return text[startOfAttribute:endOfAttribute], startOfAttribute, endOfAttribute, nil
 }
+
+// AddTagAttribute adds the attribute to the end of the tag keeping any whitespace before the end of the tag
+func AddTagAttribute(tag, attribute string) string {
+	var insertIndex = len(tag) - 1
+	if strings.HasSuffix(tag, "/>") {
+		insertIndex--
+	}
+
+	var beforeEnd = strings.TrimRight(tag[:insertIndex], " \t\n")
+
+	return beforeEnd + " " + attribute + tag[len(beforeEnd):]
+}
+
+// GetTagAttributeValue gets the unescaped value of the attribute in the tag where the attribute regex has a group
+// for a double quoted value followed by a group for a single quoted value
+func GetTagAttributeValue(attributeRegex *regexp.Regexp, tag string) string {
+	var groups = attributeRegex.FindStringSubmatch(tag)
+	if groups == nil {
+		return ""
+	}
+
+	return html.UnescapeString(groups[1] + groups[2])
+}
+
+// GetIds gets the values of the id attributes in the contents
+func GetIds(contents string) map[string]struct{} {
+	var ids = make(map[string]struct{})
+	for _, groups := range IdAttributeRegex.FindAllStringSubmatch(contents, -1) {
+		ids[groups[1]+groups[2]] = struct{}{}
+	}
+
+	return ids
+}
@@ -0,0 +1,43 @@
+package epubhandler
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+// ValidateFilesExist makes sure that all of the manifest files exist in the existing files, which are by their path relative
+// to the root of the epub, returning an error for each one that is missing
+func ValidateFilesExist[T *zip.File | struct{}](opfFolder string, existingFiles map[string]T, manifestFiles ...map[string]struct{}) error {
+	var errs []error
+	for _, files := range manifestFiles {
+		for _, file := range slices.Sorted(maps.Keys(files)) {
+			var filePath = GetFilePath(opfFolder, file)
+
+			if _, ok := existingFiles[filePath]; !ok {
+				errs = append(errs, fmt.Errorf(`file from manifest not found: %q must exist`, filePath))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// GetFilePaths gets the paths of the manifest files relative to the root of the epub
+func GetFilePaths(opfFolder string, files map[string]struct{}) map[string]struct{} {
+	var filePaths = make(map[string]struct{}, len(files))
+	for file := range files {
+		filePaths[GetFilePath(opfFolder, file)] = struct{}{}
+	}
+
+	return filePaths
+}
+
+// GetFilePath gets the path of the manifest file relative to the root of the epub
+func GetFilePath(opfFolder, file string) string {
+	return filehandler.JoinPath(opfFolder, file)
+}
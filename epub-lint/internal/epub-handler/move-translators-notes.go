@@ -2,46 +2,160 @@ package epubhandler
 
 import (
 	"fmt"
+	"html"
+	"maps"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
 )
 
-const tlNoteFileName = "tl_notes.xhtml"
+const (
+	DefaultTLNoteFileName = "tl_notes.xhtml"
+	DefaultTLNoteHeading  = "Translator's Notes"
+)
 
-var defaultTLNoteContents = `<?xml version='1.0' encoding='utf-8'?>
+var (
+	noteFileBodyRegex     = regexp.MustCompile(`(?is)<body\b[^>]*>(.*)</body>`)
+	noteFileHeadingRegex  = regexp.MustCompile(`(?is)<h[1-6]\b[^>]*>.*?</h[1-6]>`)
+	noteFileMediaRegex    = regexp.MustCompile(`(?i)<(?:img|image|svg|video|audio|object|iframe)\b`)
+	defaultTLNoteContents = `<?xml version='1.0' encoding='utf-8'?>
 <html xmlns="http://www.w3.org/1999/xhtml">
 <head>
-    <title>Translator's Notes</title>
+    <title>%[1]s</title>
 </head>
 <body>
-    <h3>Translator's Notes</h3>
+    <h3>%[1]s</h3>
     <ol>
-				%s</ol>
+				%[2]s</ol>
+</body>
+</html>
+`
+	popupTLNoteContents = `<?xml version='1.0' encoding='utf-8'?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head>
+    <title>%[1]s</title>
+</head>
+<body>
+    <section epub:type="footnotes">
+    <h3>%[1]s</h3>
+				%[2]s</section>
 </body>
 </html>
 `
+)
+
+// NoteSettings determines which translator's notes get moved and how they get written out.
+// Empty values fall back to the defaults.
+type NoteSettings struct {
+	Style                 linter.NoteStyle `json:"-"`
+	ConvertPublisherNotes bool             `json:"-"`
+	Indicators            []string         `json:"indicators"`
+	FileName              string           `json:"fileName"`
+	Heading               string           `json:"heading"`
+	BacklinkText          string           `json:"backlinkText"`
+	// ExcludedNotes is the indexes of the translator's notes to leave alone by the full path of the spine file they are in
+	ExcludedNotes map[string]map[int]struct{} `json:"-"`
+}
+
+// NotePreview is a translator's note that was found along with where it was found
+type NotePreview struct {
+	File    string
+	Index   int
+	Content string
+}
+
+// PreviewTranslatorsNotes gets the translator's notes in the spine files without moving them
+// so that false positives can be excluded before moving them. Publisher notes are removed from the files they are in
+// first when they are going to be converted so that the indexes of the notes match the ones used when moving them.
+func PreviewTranslatorsNotes(spineOrder []string, opfFolder string, settings NoteSettings, getContentByFileName func(string) (string, error)) ([]NotePreview, error) {
+	var getContents = getContentByFileName
+	if settings.ConvertPublisherNotes {
+		var updatedContents = make(map[string]string)
+		getContents = func(fileName string) (string, error) {
+			if contents, ok := updatedContents[fileName]; ok {
+				return contents, nil
+			}
+
+			return getContentByFileName(fileName)
+		}
+
+		_, _, err := getPublisherNotes(spineOrder, opfFolder, getValueOrDefault(settings.FileName, DefaultTLNoteFileName), updatedContents, getContents)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var notePreviews []NotePreview
+	for _, file := range spineOrder {
+		var fullFilePath = filepath.Join(opfFolder, file)
+
+		contents, err := getContents(fullFilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		notes, err := linter.FindTranslatorsNotes(contents, fullFilePath, settings.Indicators)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, note := range notes {
+			notePreviews = append(notePreviews, NotePreview{
+				File:    fullFilePath,
+				Index:   i,
+				Content: note,
+			})
+		}
+	}
+
+	return notePreviews, nil
+}
 
-func MoveTranslatorsNotes(spineOrder []string, opfFolder, ncxFilename, opfFilename, navFilename string, nameToUpdatedContents map[string]string, getContentByFileName func(string) (string, error)) (int, error) {
+// MoveTranslatorsNotes replaces the translator's notes in the spine files with references to them and writes out the notes
+// in the specified style. When converting publisher notes, superscript number links to notes in other spine files are
+// also converted, removing the original notes from the files they were in. Files that only have headings left once their
+// notes are removed get removed from the epub, so the returned files need to be left out when writing the epub.
+func MoveTranslatorsNotes(spineOrder []string, opfFolder, ncxFilename, opfFilename, navFilename string, settings NoteSettings, nameToUpdatedContents map[string]string, getContentByFileName func(string) (string, error)) (int, []string, error) {
 	var (
 		translatorNoteListItems []string
 		fileTranslatorNotes     []string
 		startingNumber          int
 		fullFilePath            string
+		publisherNotes          map[string]map[string]string
+		publisherNoteFiles      map[string]struct{}
+		removedFiles            []string
+		noteFileName            = getValueOrDefault(settings.FileName, DefaultTLNoteFileName)
+		heading                 = linter.EscapeText(getValueOrDefault(settings.Heading, DefaultTLNoteHeading))
+		err                     error
 	)
+	if settings.ConvertPublisherNotes {
+		publisherNotes, publisherNoteFiles, err = getPublisherNotes(spineOrder, opfFolder, noteFileName, nameToUpdatedContents, getContentByFileName)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
 	for _, file := range spineOrder {
 		fullFilePath = filepath.Join(opfFolder, file)
 
 		contents, err := getContentByFileName(fullFilePath)
 		if err != nil {
-			return 0, err
+			return 0, nil, err
 		}
 
 		var nameParts = strings.Split(file, "/")
-		contents, fileTranslatorNotes, startingNumber, err = linter.GetTranslatorsNotes(contents, nameParts[len(nameParts)-1], tlNoteFileName, startingNumber)
+		contents, fileTranslatorNotes, startingNumber, err = linter.GetTranslatorsNotes(contents, nameParts[len(nameParts)-1], noteFileName, startingNumber, linter.NoteOptions{
+			Style:          settings.Style,
+			PublisherNotes: publisherNotes[fullFilePath],
+			Indicators:     settings.Indicators,
+			BacklinkText:   settings.BacklinkText,
+			ExcludedNotes:  settings.ExcludedNotes[fullFilePath],
+		})
 		if err != nil {
-			return 0, err
+			return 0, nil, err
 		}
 
 		translatorNoteListItems = append(translatorNoteListItems, fileTranslatorNotes...)
@@ -49,6 +163,19 @@ func MoveTranslatorsNotes(spineOrder []string, opfFolder, ncxFilename, opfFilena
 		nameToUpdatedContents[fullFilePath] = contents
 	}
 
+	for _, publisherNoteFile := range slices.Sorted(maps.Keys(publisherNoteFiles)) {
+		if !hasOnlyHeadings(nameToUpdatedContents[publisherNoteFile]) {
+			continue
+		}
+
+		err = removeEmptiedNoteFile(publisherNoteFile, ncxFilename, opfFilename, navFilename, nameToUpdatedContents, getContentByFileName)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		removedFiles = append(removedFiles, publisherNoteFile)
+	}
+
 	if len(translatorNoteListItems) > 0 {
 		if opfFolder == "." {
 			opfFolder = ""
@@ -57,26 +184,31 @@ func MoveTranslatorsNotes(spineOrder []string, opfFolder, ncxFilename, opfFilena
 		var (
 			pathParts      = strings.Split(fullFilePath, "/")
 			htmlFolderPath = opfFolder
-			relativePath   = tlNoteFileName
+			relativePath   = noteFileName
 		)
 		if len(pathParts) > 1 {
 			htmlFolderPath = strings.Join(pathParts[0:len(pathParts)-1], "/")
 		}
 
 		if len(pathParts) > 2 {
-			relativePath = filepath.Join(strings.Join(pathParts[1:len(pathParts)-1], "/"), tlNoteFileName)
+			relativePath = filepath.Join(strings.Join(pathParts[1:len(pathParts)-1], "/"), noteFileName)
 		}
 
-		var tlNotesFilePath = tlNoteFileName
+		var tlNotesFilePath = noteFileName
 		if htmlFolderPath != "" {
-			tlNotesFilePath = filepath.Join(htmlFolderPath, tlNoteFileName)
+			tlNotesFilePath = filepath.Join(htmlFolderPath, noteFileName)
 		}
 
-		nameToUpdatedContents[tlNotesFilePath] = fmt.Sprintf(defaultTLNoteContents, strings.Join(translatorNoteListItems, "				"))
+		var tlNoteContents = defaultTLNoteContents
+		if settings.Style == linter.PopupStyle {
+			tlNoteContents = popupTLNoteContents
+		}
+
+		nameToUpdatedContents[tlNotesFilePath] = fmt.Sprintf(tlNoteContents, heading, strings.Join(translatorNoteListItems, "				"))
 
 		opfFileContents, err := getContentByFileName(opfFilename)
 		if err != nil {
-			return 0, err
+			return 0, nil, err
 		}
 
 		if opfFolder == "" {
@@ -88,10 +220,10 @@ func MoveTranslatorsNotes(spineOrder []string, opfFolder, ncxFilename, opfFilena
 		if ncxFilename != "" {
 			ncxFileContents, err := getContentByFileName(ncxFilename)
 			if err != nil {
-				return 0, err
+				return 0, nil, err
 			}
 
-			ncxFileContents = AddFileToNcx(ncxFileContents, relativePath, "Translator's Notes", "tl_notes")
+			ncxFileContents = AddFileToNcx(ncxFileContents, relativePath, heading, "tl_notes")
 			nameToUpdatedContents[ncxFilename] = ncxFileContents
 		}
 
@@ -101,18 +233,162 @@ func MoveTranslatorsNotes(spineOrder []string, opfFolder, ncxFilename, opfFilena
 			)
 			navFileContents, err := getContentByFileName(navFilename)
 			if err != nil {
-				return 0, err
+				return 0, nil, err
 			}
 
 			var relativeTlNotesPath string
 			relativeTlNotesPath, err = filepath.Rel(navFolderPath, tlNotesFilePath)
 			if err != nil {
-				return 0, fmt.Errorf("Failed to determine relative path between nav file %q and file %q: %w", navFilename, tlNotesFilePath, err)
+				return 0, nil, fmt.Errorf("Failed to determine relative path between nav file %q and file %q: %w", navFilename, tlNotesFilePath, err)
 			}
 
-			nameToUpdatedContents[navFilename] = AddFileToNav(navFileContents, relativeTlNotesPath, "Translator's Notes")
+			nameToUpdatedContents[navFilename] = AddFileToNav(navFileContents, relativeTlNotesPath, heading)
 		}
 	}
 
-	return len(translatorNoteListItems), nil
+	return startingNumber, removedFiles, nil
+}
+
+// removeEmptiedNoteFile removes the file that had its notes moved out of it from the spine and manifest along with
+// any entries for it in the ncx and nav files
+func removeEmptiedNoteFile(filePath, ncxFilename, opfFilename, navFilename string, nameToUpdatedContents map[string]string, getContentByFileName func(string) (string, error)) error {
+	delete(nameToUpdatedContents, filePath)
+
+	opfFileContents, err := getContentByFileName(opfFilename)
+	if err != nil {
+		return err
+	}
+
+	relativeOpfPath, err := filepath.Rel(filepath.Dir(opfFilename), filePath)
+	if err != nil {
+		return fmt.Errorf("failed to determine relative path between opf file %q and file %q: %w", opfFilename, filePath, err)
+	}
+
+	updatedOpfContents, err := RemoveFileFromOpf(opfFileContents, filepath.ToSlash(relativeOpfPath))
+	if err != nil {
+		return fmt.Errorf("failed to remove file %q from opf: %w", filePath, err)
+	}
+
+	if updatedOpfContents != opfFileContents {
+		nameToUpdatedContents[opfFilename] = updatedOpfContents
+	}
+
+	if ncxFilename != "" {
+		ncxFileContents, err := getContentByFileName(ncxFilename)
+		if err != nil {
+			return err
+		}
+
+		relativeNcxPath, err := filepath.Rel(filepath.Dir(ncxFilename), filePath)
+		if err != nil {
+			return fmt.Errorf("failed to determine relative path between ncx file %q and file %q: %w", ncxFilename, filePath, err)
+		}
+
+		if updatedNcxContents := RemoveFileFromNcx(ncxFileContents, filepath.ToSlash(relativeNcxPath)); updatedNcxContents != ncxFileContents {
+			nameToUpdatedContents[ncxFilename] = updatedNcxContents
+		}
+	}
+
+	if navFilename != "" {
+		navFileContents, err := getContentByFileName(navFilename)
+		if err != nil {
+			return err
+		}
+
+		relativeNavPath, err := filepath.Rel(filepath.Dir(navFilename), filePath)
+		if err != nil {
+			return fmt.Errorf("failed to determine relative path between nav file %q and file %q: %w", navFilename, filePath, err)
+		}
+
+		if updatedNavContents := RemoveFileFromNav(navFileContents, filepath.ToSlash(relativeNavPath)); updatedNavContents != navFileContents {
+			nameToUpdatedContents[navFilename] = updatedNavContents
+		}
+	}
+
+	return nil
+}
+
+// hasOnlyHeadings returns whether the body of the file has no text or media left in it other than its headings
+func hasOnlyHeadings(contents string) bool {
+	var groups = noteFileBodyRegex.FindStringSubmatch(contents)
+	if groups == nil {
+		return false
+	}
+
+	var body = noteFileHeadingRegex.ReplaceAllString(groups[1], "")
+	if noteFileMediaRegex.MatchString(body) {
+		return false
+	}
+
+	return strings.TrimSpace(html.UnescapeString(linter.HtmlTagRegex.ReplaceAllString(body, ""))) == ""
+}
+
+// getPublisherNotes finds the publisher footnotes referenced in the spine files, removes them from the files they are in,
+// and returns the contents of the notes by the href used to reference them for each spine file along with the files the notes were in.
+// Notes that are already in a translator's notes file are left alone since they have already been organized.
+func getPublisherNotes(spineOrder []string, opfFolder, noteFileName string, nameToUpdatedContents map[string]string, getContentByFileName func(string) (string, error)) (map[string]map[string]string, map[string]struct{}, error) {
+	var (
+		spineFiles     = make(map[string]struct{}, len(spineOrder))
+		publisherNotes = make(map[string]map[string]string)
+		noteFiles      = make(map[string]struct{})
+	)
+	for _, file := range spineOrder {
+		spineFiles[filepath.Join(opfFolder, file)] = struct{}{}
+	}
+
+	for _, file := range spineOrder {
+		var fullFilePath = filepath.Join(opfFolder, file)
+		contents, err := getContentByFileName(fullFilePath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, href := range linter.GetPublisherNoteHrefs(contents) {
+			if _, alreadyFound := publisherNotes[fullFilePath][href]; alreadyFound {
+				continue
+			}
+
+			var noteFile, id, _ = strings.Cut(href, "#")
+			if filepath.Base(noteFile) == noteFileName {
+				continue
+			}
+
+			var noteFilePath = fullFilePath
+			if noteFile != "" {
+				noteFilePath = filepath.Join(filepath.Dir(fullFilePath), noteFile)
+			}
+
+			if _, isSpineFile := spineFiles[noteFilePath]; !isSpineFile {
+				continue
+			}
+
+			noteFileContents, err := getContentByFileName(noteFilePath)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			noteFileContents, noteContents, found := linter.RemovePublisherNote(noteFileContents, id)
+			if !found {
+				continue
+			}
+
+			nameToUpdatedContents[noteFilePath] = noteFileContents
+			noteFiles[noteFilePath] = struct{}{}
+			if _, ok := publisherNotes[fullFilePath]; !ok {
+				publisherNotes[fullFilePath] = make(map[string]string)
+			}
+
+			publisherNotes[fullFilePath][href] = noteContents
+		}
+	}
+
+	return publisherNotes, noteFiles, nil
+}
+
+func getValueOrDefault(value, defaultValue string) string {
+	if strings.TrimSpace(value) == "" {
+		return defaultValue
+	}
+
+	return value
 }
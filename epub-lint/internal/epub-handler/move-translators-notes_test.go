@@ -4,11 +4,13 @@ package epubhandler_test
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	_ "embed"
 
 	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -17,7 +19,8 @@ type moveTranslatorsNotesTestCase struct {
 	opfFolder, ncxFilename, opfFilename, navFilename string
 	expectedTranslatorNoteCount                      int
 	expectedFileState, validFilesToInitialContent    map[string]string // filename to content
-	spineOrder                                       []string
+	spineOrder, expectedRemovedFiles                 []string
+	settings                                         epubhandler.NoteSettings
 }
 
 var (
@@ -59,6 +62,38 @@ var (
 	xhtmlNoOpfFolderNavOriginal string
 	//go:embed testdata/move-translators-notes/no-opf-folder-nav_updated.xhtml
 	xhtmlNoOpfFolderNavExpected string
+	//go:embed testdata/move-translators-notes/publisher-footnotes.html
+	htmlPublisherFootnotesOriginal string
+	//go:embed testdata/move-translators-notes/publisher-footnotes-popup_updated.html
+	htmlPublisherFootnotesPopupExpected string
+	//go:embed testdata/move-translators-notes/publisher-footnotes-inline-aside_updated.html
+	htmlPublisherFootnotesInlineAsideExpected string
+	//go:embed testdata/move-translators-notes/publisher-footnotes-notes.html
+	htmlPublisherFootnotesNotesOriginal string
+	//go:embed testdata/move-translators-notes/publisher-footnotes-notes-with-translators-notes.html
+	htmlPublisherFootnotesNotesWithTranslatorsNotes string
+	//go:embed testdata/move-translators-notes/publisher-footnotes.opf
+	opfPublisherFootnotesOriginal string
+	//go:embed testdata/move-translators-notes/publisher-footnotes-popup_updated.opf
+	opfPublisherFootnotesPopupExpected string
+	//go:embed testdata/move-translators-notes/publisher-footnotes-inline-aside_updated.opf
+	opfPublisherFootnotesInlineAsideExpected string
+	//go:embed testdata/move-translators-notes/publisher-footnotes.ncx
+	ncxPublisherFootnotesOriginal string
+	//go:embed testdata/move-translators-notes/publisher-footnotes-popup_updated.ncx
+	ncxPublisherFootnotesPopupExpected string
+	//go:embed testdata/move-translators-notes/publisher-footnotes-inline-aside_updated.ncx
+	ncxPublisherFootnotesInlineAsideExpected string
+	//go:embed testdata/move-translators-notes/translators-notes-popup.xhtml
+	xhtmlPopupTranslatorsNotes string
+	//go:embed testdata/move-translators-notes/html-file-with-single-translators-note-custom-settings_updated.html
+	htmlSingleTranslatorNoteCustomSettingsExpected string
+	//go:embed testdata/move-translators-notes/custom-settings_updated.ncx
+	ncxCustomSettingsExpected string
+	//go:embed testdata/move-translators-notes/custom-settings_updated.opf
+	opfCustomSettingsExpected string
+	//go:embed testdata/move-translators-notes/translators-notes-custom-settings.xhtml
+	xhtmlCustomSettingsTranslatorsNotes string
 )
 
 var moveTranslatorsNotesTestCases = map[string]moveTranslatorsNotesTestCase{
@@ -152,6 +187,86 @@ var moveTranslatorsNotesTestCases = map[string]moveTranslatorsNotesTestCase{
 			"OPS/Text/nav.xhtml":         xhtmlNoOpfFolderNavOriginal,
 		},
 	},
+	"When the style is popup and publisher footnotes are converted, the footnotes and translator's notes become footnote asides in the notes file and the emptied original notes file is removed": {
+		opfFolder:   "OPS",
+		ncxFilename: "OPS/toc.ncx",
+		opfFilename: "OPS/content.opf",
+		settings: epubhandler.NoteSettings{
+			Style:                 linter.PopupStyle,
+			ConvertPublisherNotes: true,
+		},
+		spineOrder: []string{
+			"Text/section-0001.html",
+			"Text/notes.html",
+		},
+		expectedTranslatorNoteCount: 3,
+		expectedRemovedFiles:        []string{"OPS/Text/notes.html"},
+		expectedFileState: map[string]string{
+			"OPS/Text/section-0001.html": htmlPublisherFootnotesPopupExpected,
+			"OPS/toc.ncx":                ncxPublisherFootnotesPopupExpected,
+			"OPS/content.opf":            opfPublisherFootnotesPopupExpected,
+			"OPS/Text/tl_notes.xhtml":    xhtmlPopupTranslatorsNotes,
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/Text/section-0001.html": htmlPublisherFootnotesOriginal,
+			"OPS/Text/notes.html":        htmlPublisherFootnotesNotesOriginal,
+			"OPS/toc.ncx":                ncxPublisherFootnotesOriginal,
+			"OPS/content.opf":            opfPublisherFootnotesOriginal,
+		},
+	},
+	"When the style is inline-aside, the notes are kept in the file they are referenced in, no notes file is created, and the emptied original notes file is removed": {
+		opfFolder:   "OPS",
+		ncxFilename: "OPS/toc.ncx",
+		opfFilename: "OPS/content.opf",
+		settings: epubhandler.NoteSettings{
+			Style:                 linter.InlineAsideStyle,
+			ConvertPublisherNotes: true,
+		},
+		spineOrder: []string{
+			"Text/section-0001.html",
+			"Text/notes.html",
+		},
+		expectedTranslatorNoteCount: 3,
+		expectedRemovedFiles:        []string{"OPS/Text/notes.html"},
+		expectedFileState: map[string]string{
+			"OPS/Text/section-0001.html": htmlPublisherFootnotesInlineAsideExpected,
+			"OPS/toc.ncx":                ncxPublisherFootnotesInlineAsideExpected,
+			"OPS/content.opf":            opfPublisherFootnotesInlineAsideExpected,
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/Text/section-0001.html": htmlPublisherFootnotesOriginal,
+			"OPS/Text/notes.html":        htmlPublisherFootnotesNotesOriginal,
+			"OPS/toc.ncx":                ncxPublisherFootnotesOriginal,
+			"OPS/content.opf":            opfPublisherFootnotesOriginal,
+		},
+	},
+	"When the notes file name, heading, and backlink text are provided, they are used for the notes file and its TOC entry": {
+		opfFolder:   "OPS",
+		ncxFilename: "OPS/toc.ncx",
+		opfFilename: "OPS/content.opf",
+		settings: epubhandler.NoteSettings{
+			FileName:     "notas.xhtml",
+			Heading:      "Notas del Traductor",
+			BacklinkText: "Volver",
+		},
+		spineOrder: []string{
+			"Text/section-0001.html",
+			"Text/section-0002.html",
+		},
+		expectedTranslatorNoteCount: 1,
+		expectedFileState: map[string]string{
+			"OPS/Text/section-0002.html": htmlSingleTranslatorNoteCustomSettingsExpected,
+			"OPS/toc.ncx":                ncxCustomSettingsExpected,
+			"OPS/content.opf":            opfCustomSettingsExpected,
+			"OPS/Text/notas.xhtml":       xhtmlCustomSettingsTranslatorsNotes,
+		},
+		validFilesToInitialContent: map[string]string{
+			"OPS/Text/section-0001.html": noTranslatorNotesXhtml,
+			"OPS/Text/section-0002.html": htmlSingleTranslatorNoteOriginal,
+			"OPS/toc.ncx":                ncxSimpleOriginal,
+			"OPS/content.opf":            opfSimpleOriginal,
+		},
+	},
 }
 
 func createTestCaseFileHandlerFunction(validFilesToContent map[string]string, currentContents map[string]string) func(string) (string, error) {
@@ -174,15 +289,81 @@ func TestMoveTranslatorsNotes(t *testing.T) {
 			t.Parallel()
 			var nameToUpdatedFileContents = map[string]string{}
 
-			actualTranslatorNoteCount, err := epubhandler.MoveTranslatorsNotes(tc.spineOrder, tc.opfFolder, tc.ncxFilename, tc.opfFilename, tc.navFilename, nameToUpdatedFileContents, createTestCaseFileHandlerFunction(tc.validFilesToInitialContent, nameToUpdatedFileContents))
+			actualTranslatorNoteCount, actualRemovedFiles, err := epubhandler.MoveTranslatorsNotes(tc.spineOrder, tc.opfFolder, tc.ncxFilename, tc.opfFilename, tc.navFilename, tc.settings, nameToUpdatedFileContents, createTestCaseFileHandlerFunction(tc.validFilesToInitialContent, nameToUpdatedFileContents))
 			require.NoError(t, err)
 			assert.Equal(t, tc.expectedTranslatorNoteCount, actualTranslatorNoteCount)
+			assert.Equal(t, tc.expectedRemovedFiles, actualRemovedFiles)
+
+			for _, name := range tc.expectedRemovedFiles {
+				assert.NotContains(t, nameToUpdatedFileContents, name, "expected removed file %q to not be written", name)
+			}
 
 			for name, expectedContents := range tc.expectedFileState {
 				actualContents, ok := nameToUpdatedFileContents[name]
 				assert.True(t, ok, "expected file %q to be updated, but it was not", name)
 				assert.Equal(t, expectedContents, actualContents, "expected file contents for %q did not match actual contents", name)
 			}
+
+			if tc.settings.Style == linter.InlineAsideStyle {
+				assert.NotContains(t, nameToUpdatedFileContents, filepath.Join(tc.opfFolder, "Text/tl_notes.xhtml"), "expected no notes file to be created")
+			}
 		})
 	}
 }
+
+func TestPreviewTranslatorsNotes(t *testing.T) {
+	t.Parallel()
+
+	var validFilesToInitialContent = map[string]string{
+		"OPS/Text/section-0001.html": htmlFirstMultipleTranslatorsNotesOriginal,
+		"OPS/Text/section-0002.html": htmlSingleTranslatorNoteOriginal,
+	}
+
+	actual, err := epubhandler.PreviewTranslatorsNotes([]string{"Text/section-0001.html", "Text/section-0002.html"}, "OPS", epubhandler.NoteSettings{}, createTestCaseFileHandlerFunction(validFilesToInitialContent, map[string]string{}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []epubhandler.NotePreview{
+		{
+			File:    "OPS/Text/section-0001.html",
+			Index:   0,
+			Content: "This file is meant to be simple so it can easily find the translator's note right here.",
+		},
+		{
+			File:    "OPS/Text/section-0001.html",
+			Index:   1,
+			Content: "This is a reference to a meme.",
+		},
+		{
+			File:    "OPS/Text/section-0002.html",
+			Index:   0,
+			Content: "This file is meant to be simple so it can easily find the translator's note right here.",
+		},
+	}, actual)
+}
+
+func TestPreviewTranslatorsNotesWhenConvertingPublisherNotes(t *testing.T) {
+	t.Parallel()
+
+	var validFilesToInitialContent = map[string]string{
+		"OPS/Text/section-0001.html": htmlPublisherFootnotesOriginal,
+		"OPS/Text/notes.html":        htmlPublisherFootnotesNotesWithTranslatorsNotes,
+	}
+
+	actual, err := epubhandler.PreviewTranslatorsNotes([]string{"Text/section-0001.html", "Text/notes.html"}, "OPS", epubhandler.NoteSettings{
+		ConvertPublisherNotes: true,
+	}, createTestCaseFileHandlerFunction(validFilesToInitialContent, map[string]string{}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []epubhandler.NotePreview{
+		{
+			File:    "OPS/Text/section-0001.html",
+			Index:   0,
+			Content: "This is a translator's note.",
+		},
+		{
+			File:    "OPS/Text/notes.html",
+			Index:   0,
+			Content: "A translator's note about the notes.",
+		},
+	}, actual)
+}
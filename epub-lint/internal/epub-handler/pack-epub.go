@@ -0,0 +1,227 @@
+package epubhandler
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+// PackInfoFile is the file that unpacking an epub creates to keep track of the order and settings of the files
+// in the original epub so that packing it back up can recreate the original archive as closely as possible
+const PackInfoFile = ".epub-lint-pack.json"
+
+var ErrUnpackFolderNotEmpty = errors.New("the folder to unpack the epub into must be empty or not exist")
+
+type packInfo struct {
+	Entries []packEntry `json:"entries"`
+}
+
+type packEntry struct {
+	Name         string `json:"name"`
+	Method       uint16 `json:"method"`
+	ModifiedTime uint16 `json:"modifiedTime"`
+	ModifiedDate uint16 `json:"modifiedDate"`
+	Extra        []byte `json:"extra,omitempty"`
+}
+
+// UnpackEpub extracts the epub into the destination folder along with a pack info file
+// that is used to keep the file order and compression settings when packing the epub back up
+func UnpackEpub(src, destDir string) error {
+	entries, err := os.ReadDir(destDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read folder %q: %w", destDir, err)
+	}
+
+	if len(entries) != 0 {
+		return ErrUnpackFolderNotEmpty
+	}
+
+	epubFile, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", src, err)
+	}
+	defer filehandler.TryClose(src, epubFile)
+
+	err = filehandler.UnzipFile(epubFile, destDir)
+	if err != nil {
+		return fmt.Errorf("failed to unzip %q: %w", src, err)
+	}
+
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return fmt.Errorf("failed to get zip contents for %q: %w", src, err)
+	}
+	defer filehandler.TryClose(src, r)
+
+	var info packInfo
+	for _, file := range r.File {
+		info.Entries = append(info.Entries, packEntry{
+			Name:         file.Name,
+			Method:       file.Method,
+			ModifiedTime: file.ModifiedTime,
+			ModifiedDate: file.ModifiedDate,
+			Extra:        file.Extra,
+		})
+	}
+
+	infoContents, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to create pack info for %q: %w", src, err)
+	}
+
+	return filehandler.WriteFileContents(filepath.Join(destDir, PackInfoFile), string(infoContents))
+}
+
+// PackEpub zips up the folder as an epub with the mimetype first and uncompressed.
+// Files from the original epub are written in their original order with their original compression method and timestamps
+// and any new files are written afterwards in alphabetical order which means that repacking an unmodified epub
+// gives a byte-identical archive when the original was created with the same zip implementation.
+func PackEpub(srcDir string, w *zip.Writer) error {
+	info, err := readPackInfo(srcDir)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	err = filepath.WalkDir(srcDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if filePath == srcDir {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(srcDir, filePath)
+		if err != nil {
+			return err
+		}
+
+		relativePath = filepath.ToSlash(relativePath)
+		if d.IsDir() {
+			relativePath += "/"
+		}
+
+		if relativePath == PackInfoFile {
+			return nil
+		}
+
+		files = append(files, relativePath)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get the files in %q: %w", srcDir, err)
+	}
+
+	var entries = make([]packEntry, 0, len(files)+1)
+	for _, entry := range info.Entries {
+		if slices.Contains(files, entry.Name) {
+			entries = append(entries, entry)
+		}
+	}
+
+	var newFiles []string
+	for _, file := range files {
+		if strings.HasSuffix(file, "/") || slices.ContainsFunc(entries, func(entry packEntry) bool { return entry.Name == file }) {
+			continue
+		}
+
+		newFiles = append(newFiles, file)
+	}
+
+	slices.Sort(newFiles)
+
+	for _, file := range newFiles {
+		entries = append(entries, packEntry{
+			Name:   file,
+			Method: zip.Deflate,
+		})
+	}
+
+	var mimetypeIndex = slices.IndexFunc(entries, func(entry packEntry) bool { return entry.Name == "mimetype" })
+	if mimetypeIndex == -1 {
+		err = filehandler.WriteZipUncompressedString(w, "mimetype", defaultMimetypeContents)
+		if err != nil {
+			return fmt.Errorf("failed to add default mimetype to zip file: %w", err)
+		}
+	} else {
+		var mimetype = entries[mimetypeIndex]
+		// the mimetype has to be stored without compression or an extra field for readers to be able to check it
+		mimetype.Method = zip.Store
+		mimetype.Extra = nil
+		entries = append([]packEntry{mimetype}, slices.Delete(entries, mimetypeIndex, mimetypeIndex+1)...)
+	}
+
+	for _, entry := range entries {
+		err = writePackEntry(w, srcDir, entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readPackInfo(srcDir string) (packInfo, error) {
+	var (
+		info         packInfo
+		packInfoPath = filepath.Join(srcDir, PackInfoFile)
+	)
+	exists, err := filehandler.FileExists(packInfoPath)
+	if err != nil || !exists {
+		return info, err
+	}
+
+	contents, err := filehandler.ReadInFileContents(packInfoPath)
+	if err != nil {
+		return info, err
+	}
+
+	err = json.Unmarshal([]byte(contents), &info)
+	if err != nil {
+		return info, fmt.Errorf("failed to parse pack info file %q: %w", packInfoPath, err)
+	}
+
+	return info, nil
+}
+
+func writePackEntry(w *zip.Writer, srcDir string, entry packEntry) error {
+	f, err := w.CreateHeader(&zip.FileHeader{
+		Name:         entry.Name,
+		Method:       entry.Method,
+		ModifiedTime: entry.ModifiedTime,
+		ModifiedDate: entry.ModifiedDate,
+		Extra:        entry.Extra,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add %q to zip file: %w", entry.Name, err)
+	}
+
+	if strings.HasSuffix(entry.Name, "/") {
+		return nil
+	}
+
+	var filePath = filepath.Join(srcDir, filepath.FromSlash(entry.Name))
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", filePath, err)
+	}
+	defer filehandler.TryClose(filePath, file)
+
+	_, err = io.Copy(f, file)
+	if err != nil {
+		return fmt.Errorf("failed to write %q to zip file: %w", entry.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+//go:build unit
+
+package epubhandler_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// packTestModifiedDate and packTestModifiedTime are 2024-01-02 03:04:06 in MS-DOS format
+const (
+	packTestModifiedDate = (2024-1980)<<9 | 1<<5 | 2
+	packTestModifiedTime = 3<<11 | 4<<5 | 6/2
+)
+
+type testZipEntry struct {
+	name     string
+	contents string
+	method   uint16
+	extra    []byte
+}
+
+var packTestEntries = []testZipEntry{
+	{name: "mimetype", contents: "application/epub+zip", method: zip.Store},
+	{name: "META-INF/", method: zip.Store},
+	{name: "META-INF/container.xml", contents: "<container/>", method: zip.Deflate},
+	{name: "OEBPS/content.opf", contents: "<package/>", method: zip.Deflate},
+	{name: "OEBPS/Text/b.xhtml", contents: "<html>b</html>", method: zip.Deflate},
+	{name: "OEBPS/Text/a.xhtml", contents: "<html>a</html>", method: zip.Store},
+}
+
+func createTestEpub(t *testing.T, entries []testZipEntry) []byte {
+	t.Helper()
+
+	var (
+		epub bytes.Buffer
+		w    = zip.NewWriter(&epub)
+	)
+	for _, entry := range entries {
+		// the MS-DOS date and time are used instead of the modified time since setting it adds an extra field to the entry
+		f, err := w.CreateHeader(&zip.FileHeader{
+			Name:         entry.name,
+			Method:       entry.method,
+			ModifiedTime: packTestModifiedTime,
+			ModifiedDate: packTestModifiedDate,
+			Extra:        entry.extra,
+		})
+		require.NoError(t, err)
+
+		_, err = f.Write([]byte(entry.contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+
+	return epub.Bytes()
+}
+
+func packTestDir(t *testing.T, dir string) []byte {
+	t.Helper()
+
+	var (
+		epub bytes.Buffer
+		w    = zip.NewWriter(&epub)
+	)
+	require.NoError(t, epubhandler.PackEpub(dir, w))
+	require.NoError(t, w.Close())
+
+	return epub.Bytes()
+}
+
+func TestUnpackAndPackEpubRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tempDir  = t.TempDir()
+		src      = filepath.Join(tempDir, "book.epub")
+		destDir  = filepath.Join(tempDir, "book")
+		original = createTestEpub(t, packTestEntries)
+	)
+	require.NoError(t, os.WriteFile(src, original, 0644))
+	require.NoError(t, epubhandler.UnpackEpub(src, destDir))
+
+	for _, entry := range packTestEntries {
+		if entry.contents == "" {
+			continue
+		}
+
+		contents, err := filehandler.ReadInFileContents(filepath.Join(destDir, entry.name))
+		require.NoError(t, err)
+		assert.Equal(t, entry.contents, contents)
+	}
+
+	assert.Equal(t, original, packTestDir(t, destDir))
+
+	assert.ErrorIs(t, epubhandler.UnpackEpub(src, destDir), epubhandler.ErrUnpackFolderNotEmpty)
+}
+
+func TestPackEpubAddsNewFilesAfterOriginalFiles(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tempDir = t.TempDir()
+		src     = filepath.Join(tempDir, "book.epub")
+		destDir = filepath.Join(tempDir, "book")
+	)
+	require.NoError(t, os.WriteFile(src, createTestEpub(t, packTestEntries), 0644))
+	require.NoError(t, epubhandler.UnpackEpub(src, destDir))
+	require.NoError(t, os.Remove(filepath.Join(destDir, "OEBPS/Text/b.xhtml")))
+	require.NoError(t, os.WriteFile(filepath.Join(destDir, "OEBPS/Text/d.xhtml"), []byte("<html>d</html>"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(destDir, "OEBPS/Text/c.xhtml"), []byte("<html>c</html>"), 0644))
+
+	packed := packTestDir(t, destDir)
+
+	r, err := zip.NewReader(bytes.NewReader(packed), int64(len(packed)))
+	require.NoError(t, err)
+
+	var names []string
+	for _, file := range r.File {
+		names = append(names, file.Name)
+	}
+
+	assert.Equal(t, []string{"mimetype", "META-INF/", "META-INF/container.xml", "OEBPS/content.opf", "OEBPS/Text/a.xhtml", "OEBPS/Text/c.xhtml", "OEBPS/Text/d.xhtml"}, names)
+	assert.Equal(t, zip.Store, r.File[0].Method)
+	assert.Equal(t, zip.Deflate, r.File[5].Method)
+}
+
+func TestPackEpubWithoutPackInfo(t *testing.T) {
+	t.Parallel()
+
+	var dir = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "OEBPS"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OEBPS/content.opf"), []byte("<package/>"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mimetype"), []byte("application/epub+zip"), 0644))
+
+	packed := packTestDir(t, dir)
+
+	r, err := zip.NewReader(bytes.NewReader(packed), int64(len(packed)))
+	require.NoError(t, err)
+	require.Len(t, r.File, 2)
+	assert.Equal(t, "mimetype", r.File[0].Name)
+	assert.Equal(t, zip.Store, r.File[0].Method)
+	assert.Equal(t, "OEBPS/content.opf", r.File[1].Name)
+
+	// packing the same folder again gives the same result
+	assert.Equal(t, packed, packTestDir(t, dir))
+}
+
+func TestPackEpubStoresMimetypeWithoutExtraField(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tempDir = t.TempDir()
+		src     = filepath.Join(tempDir, "book.epub")
+		destDir = filepath.Join(tempDir, "book")
+		entries = []testZipEntry{
+			// extended timestamp extra field
+			{name: "mimetype", contents: "application/epub+zip", method: zip.Deflate, extra: []byte{0x55, 0x54, 0x05, 0x00, 0x01, 0x46, 0x7d, 0x93, 0x65}},
+			{name: "OEBPS/content.opf", contents: "<package/>", method: zip.Deflate, extra: []byte{0x55, 0x54, 0x05, 0x00, 0x01, 0x46, 0x7d, 0x93, 0x65}},
+		}
+	)
+	require.NoError(t, os.WriteFile(src, createTestEpub(t, entries), 0644))
+	require.NoError(t, epubhandler.UnpackEpub(src, destDir))
+
+	packed := packTestDir(t, destDir)
+
+	r, err := zip.NewReader(bytes.NewReader(packed), int64(len(packed)))
+	require.NoError(t, err)
+	require.Len(t, r.File, 2)
+	assert.Equal(t, "mimetype", r.File[0].Name)
+	assert.Equal(t, zip.Store, r.File[0].Method)
+	assert.Empty(t, r.File[0].Extra)
+	assert.Equal(t, entries[1].extra, r.File[1].Extra)
+}
@@ -89,7 +89,7 @@ func ParseOpfFile(text, opfFilename string) (EpubInfo, error) {
 		return epubInfo, fmt.Errorf(ErrorParsingXmlMessageStart+"%v", err)
 	}
 
-	epubInfo.Version, err = versionTextToInt(opfInfo.Version)
+	epubInfo.Version, err = VersionTextToInt(opfInfo.Version)
 	if err != nil {
 		return epubInfo, err
 	}
@@ -103,7 +103,7 @@ func ParseOpfFile(text, opfFilename string) (EpubInfo, error) {
 
 	var filePath string
 	for _, manifestItem := range opfInfo.Manifest.Items {
-		filePath, err = hrefToFile(manifestItem.Href)
+		filePath, err = HrefToFile(manifestItem.Href)
 		if err != nil {
 			return epubInfo, fmt.Errorf("failed to convert manifest href %q to file path: %w", manifestItem.Href, err)
 		}
@@ -146,12 +146,12 @@ func ParseOpfFile(text, opfFilename string) (EpubInfo, error) {
 		for _, guideReference := range opfInfo.Guide.References {
 			switch guideReference.Type {
 			case "toc":
-				epubInfo.TocFile, err = hrefToFile(guideReference.Href)
+				epubInfo.TocFile, err = HrefToFile(guideReference.Href)
 				if err != nil {
 					return epubInfo, fmt.Errorf("failed to convert toc href %q to file path: %w", guideReference.Href, err)
 				}
 			case "cover":
-				epubInfo.CoverFile, err = hrefToFile(guideReference.Href)
+				epubInfo.CoverFile, err = HrefToFile(guideReference.Href)
 				if err != nil {
 					return epubInfo, fmt.Errorf("failed to convert cover href %q to file path: %w", guideReference.Href, err)
 				}
@@ -162,7 +162,8 @@ func ParseOpfFile(text, opfFilename string) (EpubInfo, error) {
 	return epubInfo, nil
 }
 
-func hrefToFile(href string) (string, error) {
+// HrefToFile gets the file path an href refers to without any fragment or escaping
+func HrefToFile(href string) (string, error) {
 	var before, _, ok = strings.Cut(href, "#")
 	if !ok {
 		return url.QueryUnescape(href)
@@ -171,7 +172,8 @@ func hrefToFile(href string) (string, error) {
 	return url.QueryUnescape(before)
 }
 
-func versionTextToInt(versionText string) (int, error) {
+// VersionTextToInt gets the major version from the version of an opf (i.e. 3 for "3.0")
+func VersionTextToInt(versionText string) (int, error) {
 	versionText = strings.TrimSpace(versionText)
 	if versionText == "" {
 		return 0, ErrNoPackageInfo
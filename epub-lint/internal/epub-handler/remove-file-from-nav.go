@@ -1,6 +1,10 @@
 package epubhandler
 
-import "strings"
+import (
+	"strings"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+)
 
 // RemoveFileFromNav determines if the specified file is referenced in an href attribute in an anchor tag in a list item.
 // If it is, then it removes the list item and the anchor tag.
@@ -30,75 +34,17 @@ import "strings"
 //		 </li>
 //		</ol>
 func RemoveFileFromNav(text, file string) string {
-	hrefAttributeEnd := strings.Index(text, file+`"`)
-	if hrefAttributeEnd == -1 {
-		return text
-	}
-
-	// check if it truly is in an href...
-	startOfAttribute := strings.LastIndex(text[:hrefAttributeEnd], `"`)
-	if startOfAttribute == -1 { // something is wrong, so we are going to skip this...
-		return text
-	}
-
-	if !strings.HasSuffix(text[:startOfAttribute], "href=") {
-		return text
-	}
-
-	tagName, tagStart := getPreviousHtmlTagIfNotClosingTag(text, startOfAttribute)
-	if tagStart == -1 {
-		return text
-	}
-
-	if tagName != "a" { // this is not a scenario we are handling at this time...
-		return text
-	}
-
-	tagName, tagStart = getPreviousHtmlTagIfNotClosingTag(text, tagStart)
-	if tagStart == -1 {
+	nav, err := epubdoc.ParseNav(text)
+	if err != nil {
 		return text
 	}
 
-	if tagName != "li" { // this is not a scenario we are handling at this time...
+	var removed = nav.RemoveListItems(func(href string) bool {
+		return strings.HasSuffix(href, file)
+	})
+	if removed == 0 {
 		return text
 	}
 
-	// it should be safe to just excise the content until the next `</li>` at this point
-	const endingListItem = "</li>"
-	tagEnd := strings.Index(text[startOfAttribute:], endingListItem)
-	if tagEnd == -1 {
-		return text
-	}
-
-	tagEnd += len(endingListItem) + startOfAttribute
-
-	startOfContentToRemove, endOfContentToRemove := GetLineBoundsIfEmpty(text, tagStart, tagEnd)
-
-	return text[:startOfContentToRemove] + text[endOfContentToRemove:]
-}
-
-// getPreviousHtmlTagIfNotClosingTag gets the nearest prior opening html tag and the start of its position
-// if there is no closing tag between it and the start index
-func getPreviousHtmlTagIfNotClosingTag(text string, start int) (string, int) {
-	elStart := strings.LastIndex(text[:start], "<")
-	if elStart == -1 { // if this happens there is no prior HTML tag
-		return "", -1
-	}
-
-	if text[elStart+1] == '/' { // if the element is a closing tag/is self closing return an empty string
-		return "", -1
-	}
-
-	elementEndEnd := strings.Index(text[elStart:], ">")
-	if elementEndEnd == -1 {
-		return "", -1
-	}
-
-	var elementEndIndex = elStart + elementEndEnd
-	elementEndEnd = strings.Index(text[elStart:elStart+elementEndEnd], " ")
-	if elementEndEnd != -1 {
-		elementEndIndex = elStart + elementEndEnd
-	}
-
-	return text[elStart+1 : elementEndIndex], elStart
+	return nav.String()
 }
@@ -1,43 +1,19 @@
 package epubhandler
 
 import (
-	"fmt"
-	"strings"
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
 )
 
+// RemoveFileFromNcx removes the nav points whose src is the relative file path
 func RemoveFileFromNcx(contents, relativeFilePath string) string {
-	var (
-		remainingNcx           = contents
-		nextRelativePathIndex  int
-		currentActualIndex     int
-		pathToFindSrc          = fmt.Sprintf(`src=%q`, relativeFilePath) // we may want to account for references to ids in the file as well, but for now this should work
-		navPointStartIndicator = "<navPoint"
-		navPointEndIndicator   = "</navPoint>"
-	)
-	for nextRelativePathIndex != -1 {
-		nextRelativePathIndex = strings.Index(remainingNcx, pathToFindSrc)
-		if nextRelativePathIndex == -1 {
-			break
-		}
-
-		var (
-			absoluteIndex = currentActualIndex + nextRelativePathIndex
-			navPointStart = strings.LastIndex(contents[:absoluteIndex], navPointStartIndicator)
-			navPointEnd   = strings.Index(contents[absoluteIndex+len(pathToFindSrc):], navPointEndIndicator)
-		)
-		if navPointStart == -1 || navPointEnd == -1 {
-			currentActualIndex += nextRelativePathIndex + len(pathToFindSrc)
-			remainingNcx = remainingNcx[nextRelativePathIndex+len(pathToFindSrc):]
-			continue
-		}
-
-		navPointEnd += absoluteIndex + len(pathToFindSrc) + len(navPointEndIndicator)
-
-		startOfContentToRemove, endOfContentToRemove := GetLineBoundsIfEmpty(contents, navPointStart, navPointEnd)
+	ncx, err := epubdoc.ParseNcx(contents)
+	if err != nil {
+		return contents
+	}
 
-		contents = contents[:startOfContentToRemove] + contents[endOfContentToRemove:]
-		remainingNcx = contents[startOfContentToRemove:]
+	if ncx.RemoveNavPoints(relativeFilePath) == 0 {
+		return contents
 	}
 
-	return contents
+	return ncx.String()
 }
@@ -10,9 +10,88 @@ import (
 	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
 )
 
-const defaultMimetypeContents = "application/epub+zip"
+const (
+	defaultMimetypeContents = "application/epub+zip"
+	mimetypeFilename        = "mimetype"
+)
+
+// StreamOperation rewrites an epub one file at a time so that only the file currently being rewritten needs to be held in memory
+type StreamOperation struct {
+	// RewriteFile is run for each file in the epub in the order the files are stored in. It either writes the new version of the file
+	// and returns true or returns false to have the file copied over as is without it being decompressed and recompressed.
+	// Returning true without writing the file removes it from the epub.
+	RewriteFile func(zipFile *zip.File, w *zip.Writer) (bool, error)
+	// AddFiles is run once all of the existing files are handled to add any new files to the epub
+	AddFiles func(w *zip.Writer) error
+}
 
+// UpdateEpub runs the operation against the epub where any file the operation does not say it handled
+// is copied over as is without it being decompressed and recompressed
 func UpdateEpub(src string, operation func(map[string]*zip.File, *zip.Writer, EpubInfo, string) ([]string, error)) error {
+	return rewriteEpub(src, func(files []*zip.File, zipFiles map[string]*zip.File, w *zip.Writer, epubInfo EpubInfo, opfFolder string) error {
+		filesHandled, err := operation(zipFiles, w, epubInfo, opfFolder)
+		if err != nil {
+			return err
+		}
+
+		for _, zipFile := range files {
+			if zipFile.Name == mimetypeFilename || slices.Contains(filesHandled, zipFile.Name) {
+				continue
+			}
+
+			err = filehandler.CopyZipFile(w, zipFile)
+			if err != nil {
+				return fmt.Errorf("failed to write file %q to zip for %q: %w", zipFile.Name, src, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// StreamUpdateEpub rewrites the epub one file at a time which keeps the memory used proportional to the largest file in the epub
+// rather than to the size of the whole epub. Prepare gets the operation to run and can read in any files needed up front.
+func StreamUpdateEpub(src string, prepare func(map[string]*zip.File, EpubInfo, string) (StreamOperation, error)) error {
+	return rewriteEpub(src, func(files []*zip.File, zipFiles map[string]*zip.File, w *zip.Writer, epubInfo EpubInfo, opfFolder string) error {
+		operation, err := prepare(zipFiles, epubInfo, opfFolder)
+		if err != nil {
+			return err
+		}
+
+		for _, zipFile := range files {
+			if zipFile.Name == mimetypeFilename {
+				continue
+			}
+
+			var handled bool
+			if operation.RewriteFile != nil {
+				handled, err = operation.RewriteFile(zipFile, w)
+				if err != nil {
+					return err
+				}
+			}
+
+			if handled {
+				continue
+			}
+
+			err = filehandler.CopyZipFile(w, zipFile)
+			if err != nil {
+				return fmt.Errorf("failed to write file %q to zip for %q: %w", zipFile.Name, src, err)
+			}
+		}
+
+		if operation.AddFiles == nil {
+			return nil
+		}
+
+		return operation.AddFiles(w)
+	})
+}
+
+// rewriteEpub writes a new version of the epub using the operation to fill in everything but the mimetype
+// and then swaps it in for the original which is kept with an ".original" suffix
+func rewriteEpub(src string, operation func([]*zip.File, map[string]*zip.File, *zip.Writer, EpubInfo, string) error) error {
 	r, zipFiles, err := filehandler.GetFilesFromZip(src)
 	if err != nil {
 		return fmt.Errorf("failed to get zip contents for %q: %w", src, err)
@@ -25,33 +104,18 @@ func UpdateEpub(src string, operation func(map[string]*zip.File, *zip.Writer, Ep
 		}
 	}()
 
-	var (
-		opfFilename string
-		opfFile     *zip.File
-	)
-	for filename, file := range zipFiles {
-		if strings.HasSuffix(filename, "opf") {
-			opfFilename = filename
-			opfFile = file
-			break
-		}
-	}
-
-	if opfFile == nil {
-		return fmt.Errorf("failed to find the opf file for %q", src)
-	}
-
-	fileContents, err := filehandler.ReadInZipFileContents(opfFile)
+	epubInfo, opfFolder, err := GetEpubInfo(src, zipFiles)
 	if err != nil {
 		return err
 	}
 
-	epubInfo, err := ParseOpfFile(fileContents, opfFilename)
-	if err != nil {
-		return fmt.Errorf("failed to parse %q for %q: %w", opfFilename, src, err)
+	var files = make([]*zip.File, 0, len(zipFiles))
+	for _, zipFile := range r.File {
+		if zipFiles[zipFile.Name] == zipFile {
+			files = append(files, zipFile)
+		}
 	}
 
-	var opfFolder = filehandler.GetFileFolder(opfFilename)
 	var tempEpub = src + ".temp"
 	var runOperation = func() error {
 		tempEpubFile, err := os.Create(tempEpub)
@@ -64,7 +128,7 @@ func UpdateEpub(src string, operation func(map[string]*zip.File, *zip.Writer, Ep
 		w := zip.NewWriter(tempEpubFile)
 		defer filehandler.TryClose(tempEpub+" zip writer", w)
 
-		if mimetypeFile, ok := zipFiles["mimetype"]; ok {
+		if mimetypeFile, ok := zipFiles[mimetypeFilename]; ok {
 			if mimetypeFile.UncompressedSize64 == uint64(len([]byte(defaultMimetypeContents))) {
 				err = filehandler.WriteZipUncompressedFile(w, mimetypeFile)
 
@@ -72,39 +136,21 @@ func UpdateEpub(src string, operation func(map[string]*zip.File, *zip.Writer, Ep
 					return fmt.Errorf("failed to copy mimetype to zip file: %w", err)
 				}
 			} else {
-				err = filehandler.WriteZipUncompressedString(w, "mimetype", defaultMimetypeContents)
+				err = filehandler.WriteZipUncompressedString(w, mimetypeFilename, defaultMimetypeContents)
 
 				if err != nil {
 					return fmt.Errorf("failed to update mimetype to match the default one in zip file: %w", err)
 				}
 			}
 		} else {
-			err = filehandler.WriteZipUncompressedString(w, "mimetype", defaultMimetypeContents)
+			err = filehandler.WriteZipUncompressedString(w, mimetypeFilename, defaultMimetypeContents)
 
 			if err != nil {
 				return fmt.Errorf("failed to add default mimetype to zip file: %w", err)
 			}
 		}
 
-		filesHandled, err := operation(zipFiles, w, epubInfo, opfFolder)
-		if err != nil {
-			return err
-		}
-
-		filesHandled = append(filesHandled, "mimetype")
-
-		for filename, zipFile := range zipFiles {
-			if slices.Contains(filesHandled, filename) {
-				continue
-			}
-
-			err = filehandler.WriteZipCompressedFile(w, zipFile)
-			if err != nil {
-				return fmt.Errorf("failed to write file %q to zip for %q", zipFile.Name, src)
-			}
-		}
-
-		return nil
+		return operation(files, zipFiles, w, epubInfo, opfFolder)
 	}
 
 	err = runOperation()
@@ -131,3 +177,50 @@ func UpdateEpub(src string, operation func(map[string]*zip.File, *zip.Writer, Ep
 
 	return nil
 }
+
+// ReadEpub runs the provided operation against the contents of the epub without modifying it
+func ReadEpub(src string, operation func(map[string]*zip.File, EpubInfo, string) error) error {
+	r, zipFiles, err := filehandler.GetFilesFromZip(src)
+	if err != nil {
+		return fmt.Errorf("failed to get zip contents for %q: %w", src, err)
+	}
+	defer filehandler.TryClose(src, r)
+
+	epubInfo, opfFolder, err := GetEpubInfo(src, zipFiles)
+	if err != nil {
+		return err
+	}
+
+	return operation(zipFiles, epubInfo, opfFolder)
+}
+
+// GetEpubInfo finds the opf file in the epub and parses it returning the epub info and the folder the opf file is in
+func GetEpubInfo(src string, zipFiles map[string]*zip.File) (EpubInfo, string, error) {
+	var (
+		opfFilename string
+		opfFile     *zip.File
+	)
+	for filename, file := range zipFiles {
+		if strings.HasSuffix(filename, "opf") {
+			opfFilename = filename
+			opfFile = file
+			break
+		}
+	}
+
+	if opfFile == nil {
+		return EpubInfo{}, "", fmt.Errorf("failed to find the opf file for %q", src)
+	}
+
+	fileContents, err := filehandler.ReadInZipFileContents(opfFile)
+	if err != nil {
+		return EpubInfo{}, "", err
+	}
+
+	epubInfo, err := ParseOpfFile(fileContents, opfFilename)
+	if err != nil {
+		return EpubInfo{}, "", fmt.Errorf("failed to parse %q for %q: %w", opfFilename, src, err)
+	}
+
+	return epubInfo, filehandler.GetFileFolder(opfFilename), nil
+}
@@ -0,0 +1,90 @@
+//go:build unit
+
+package epubhandler_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateTestEntries = []testZipEntry{
+	{name: "mimetype", contents: "application/epub+zip", method: zip.Store},
+	{name: "META-INF/container.xml", contents: "<container/>", method: zip.Deflate},
+	{name: "OEBPS/content.opf", contents: `<package version="3.0"><manifest>
+<item id="a" href="Text/a.xhtml" media-type="application/xhtml+xml"/>
+<item id="b" href="Text/b.xhtml" media-type="application/xhtml+xml"/>
+<item id="cover" href="Images/cover.jpg" media-type="image/jpeg"/>
+</manifest><spine><itemref idref="a"/><itemref idref="b"/></spine></package>`, method: zip.Deflate},
+	{name: "OEBPS/Text/b.xhtml", contents: "<html>b</html>", method: zip.Deflate},
+	{name: "OEBPS/Images/cover.jpg", contents: "not really a jpeg", method: zip.Store},
+	{name: "OEBPS/Text/a.xhtml", contents: "<html>a</html>", method: zip.Deflate},
+	{name: "OEBPS/unused.txt", contents: "unused", method: zip.Deflate},
+}
+
+func TestStreamUpdateEpub(t *testing.T) {
+	t.Parallel()
+
+	var src = filepath.Join(t.TempDir(), "book.epub")
+	require.NoError(t, os.WriteFile(src, createTestEpub(t, updateTestEntries), 0644))
+
+	var rewritten []string
+	err := epubhandler.StreamUpdateEpub(src, func(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string) (epubhandler.StreamOperation, error) {
+		assert.Equal(t, "OEBPS", opfFolder)
+		assert.Len(t, epubInfo.HtmlFiles, 2)
+
+		return epubhandler.StreamOperation{
+			RewriteFile: func(zipFile *zip.File, w *zip.Writer) (bool, error) {
+				rewritten = append(rewritten, zipFile.Name)
+
+				switch zipFile.Name {
+				case "OEBPS/Text/a.xhtml":
+					return true, filehandler.WriteZipCompressedString(w, zipFile.Name, "<html>updated a</html>")
+				case "OEBPS/unused.txt":
+					return true, nil
+				}
+
+				return false, nil
+			},
+			AddFiles: func(w *zip.Writer) error {
+				return filehandler.WriteZipCompressedString(w, "OEBPS/Text/c.xhtml", "<html>c</html>")
+			},
+		}, nil
+	})
+	require.NoError(t, err)
+
+	// the mimetype is always written first and is never passed along to be rewritten
+	assert.Equal(t, []string{"META-INF/container.xml", "OEBPS/content.opf", "OEBPS/Text/b.xhtml", "OEBPS/Images/cover.jpg", "OEBPS/Text/a.xhtml", "OEBPS/unused.txt"}, rewritten)
+
+	r, err := zip.OpenReader(src)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var (
+		names    []string
+		contents = make(map[string]string, len(r.File))
+	)
+	for _, file := range r.File {
+		names = append(names, file.Name)
+
+		contents[file.Name], err = filehandler.ReadInZipFileContents(file)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"mimetype", "META-INF/container.xml", "OEBPS/content.opf", "OEBPS/Text/b.xhtml", "OEBPS/Images/cover.jpg", "OEBPS/Text/a.xhtml", "OEBPS/Text/c.xhtml"}, names)
+	assert.Equal(t, "<html>updated a</html>", contents["OEBPS/Text/a.xhtml"])
+	assert.Equal(t, "<html>b</html>", contents["OEBPS/Text/b.xhtml"])
+	assert.Equal(t, "<html>c</html>", contents["OEBPS/Text/c.xhtml"])
+
+	// files that are copied over keep how they were originally stored
+	assert.Equal(t, zip.Store, r.File[4].Method)
+
+	_, err = os.Stat(src + ".original")
+	assert.NoError(t, err)
+}
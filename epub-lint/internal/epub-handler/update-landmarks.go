@@ -1,99 +1,42 @@
 package epubhandler
 
 import (
-	"fmt"
-	"strings"
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
 )
 
+// UpdateLandmarks points the cover and toc landmarks that link to the relative file path at the new cover and toc files
+// leaving a landmark alone when there is no file to point it at
 func UpdateLandmarks(contents, relativeFilePath, relativeCoverPath, relativeTocPath string) string {
-	landmarksIndicator := strings.Index(contents, `epub:type="landmarks"`)
-	if landmarksIndicator == -1 {
+	nav, err := epubdoc.ParseNav(contents)
+	if err != nil {
 		return contents
 	}
 
-	startOfEl := strings.LastIndex(contents[:landmarksIndicator], "<")
-	if startOfEl == -1 {
-		return contents
-	}
-
-	var (
-		startingElContent = contents[startOfEl+1 : landmarksIndicator]
-		before, _, ok     = strings.Cut(startingElContent, " ")
-	)
-	if !ok { // this should not happen, but just in case, we will not make any changes
-		return contents
-	}
-
-	endOfLandMarkEl := strings.Index(contents[landmarksIndicator:], fmt.Sprintf("</%s>", before))
-	if endOfLandMarkEl == -1 { // another scenario that should not happen
-		return contents
-	}
-
-	var (
-		remainingLandmarkContents = contents[landmarksIndicator : landmarksIndicator+endOfLandMarkEl]
-		nextRelativePathIndex     int
-		currentActualIndex        = landmarksIndicator
-		pathToFindHref            = fmt.Sprintf(`href=%q`, relativeFilePath)
-	)
-	for nextRelativePathIndex != -1 {
-		nextRelativePathIndex = strings.Index(remainingLandmarkContents, pathToFindHref)
-		if nextRelativePathIndex == -1 {
-			break
-		}
-
-		var (
-			absoluteIndex = currentActualIndex + nextRelativePathIndex
-			tagStart      = strings.LastIndex(contents[:absoluteIndex], "<")
-			tagEnd        = strings.Index(contents[absoluteIndex+len(pathToFindHref):], ">")
-		)
-		if tagStart == -1 || tagEnd == -1 {
-			currentActualIndex += nextRelativePathIndex + len(pathToFindHref)
-			remainingLandmarkContents = remainingLandmarkContents[nextRelativePathIndex+len(pathToFindHref):]
-			continue
-		}
-
-		tagEnd += absoluteIndex + len(pathToFindHref)
-
-		var (
-			tag                 = contents[tagStart : tagEnd+1]
-			epubType, _, _, err = GetAttributeValue(tag, "epub:type")
-		)
-		if err != nil {
-			currentActualIndex += nextRelativePathIndex + len(pathToFindHref)
-			remainingLandmarkContents = remainingLandmarkContents[nextRelativePathIndex+len(pathToFindHref):]
+	var updated bool
+	for _, link := range nav.LandmarkLinks() {
+		if link.Href() != relativeFilePath {
 			continue
 		}
 
-		var replacement string
-		switch epubType {
+		var newHref string
+		switch link.EpubType() {
 		case "cover":
-			if relativeCoverPath == "" {
-				currentActualIndex += nextRelativePathIndex + len(pathToFindHref)
-				remainingLandmarkContents = remainingLandmarkContents[nextRelativePathIndex+len(pathToFindHref):]
-				continue
-			}
-
-			replacement = fmt.Sprintf(`href=%q`, relativeCoverPath)
+			newHref = relativeCoverPath
 		case "toc":
-			if relativeTocPath == "" {
-				currentActualIndex += nextRelativePathIndex + len(pathToFindHref)
-				remainingLandmarkContents = remainingLandmarkContents[nextRelativePathIndex+len(pathToFindHref):]
-				continue
-			}
+			newHref = relativeTocPath
+		}
 
-			replacement = fmt.Sprintf(`href=%q`, relativeTocPath)
-		default:
-			currentActualIndex += nextRelativePathIndex + len(pathToFindHref)
-			remainingLandmarkContents = remainingLandmarkContents[nextRelativePathIndex+len(pathToFindHref):]
+		if newHref == "" {
 			continue
 		}
 
-		contents = contents[:absoluteIndex] + replacement + contents[absoluteIndex+len(pathToFindHref):]
+		link.SetHref(newHref)
+		updated = true
+	}
 
-		delta := len(replacement) - len(pathToFindHref)
-		currentActualIndex += nextRelativePathIndex + len(replacement)
-		remainingLandmarkContents = contents[currentActualIndex : landmarksIndicator+endOfLandMarkEl+delta]
+	if !updated {
+		return contents
 	}
 
-	return contents
+	return nav.String()
 }
@@ -41,3 +41,7 @@ func kbSizeToString(size float64) string {
 
 	return fmt.Sprintf("%.2f KB", size)
 }
+
+func BytesToString(bytes uint64) string {
+	return kbSizeToString(float64(bytes) / 1024)
+}
@@ -57,6 +57,11 @@ func cleanupJNovelsXhtml(ctx JNovelsCleanupContext) ([]string, error) {
 			return handledFiles, err
 		}
 
+		// epub 3 files do not need an ncx file
+		if ctx.NcxFileName == "" {
+			continue
+		}
+
 		ncxFolderPath := filepath.Dir(ctx.NcxFileName) // used instead of the file path as that results in an additional "../" being added
 		var relativeFilePath string
 		relativeFilePath, err = filepath.Rel(ncxFolderPath, filename)
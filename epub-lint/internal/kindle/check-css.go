@@ -0,0 +1,157 @@
+package kindle
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+)
+
+const (
+	PositionFixed    = "position-fixed"
+	CssAnimation     = "css-animation"
+	UnsupportedCss   = "unsupported-css"
+	OversizedImage   = "oversized-image"
+	UnsupportedImage = "unsupported-image"
+	MissingNcx       = "missing-ncx"
+	FontLicense      = "font-license"
+)
+
+var (
+	// FixableRules are the rules whose issues can be fixed without changing how the epub looks on Kindle
+	FixableRules = []string{PositionFixed, CssAnimation, OversizedImage, MissingNcx}
+
+	cssRuleBodyRegex      = regexp.MustCompile(`\{[^{}]*\}`)
+	styleElementRegex     = regexp.MustCompile(`(?is)<style\b[^>]*>(.*?)</style>`)
+	styleAttributeRegex   = regexp.MustCompile(`(?i)\sstyle\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	cssPropertyRegex      = regexp.MustCompile(`^-?[a-zA-Z][\w-]*$`)
+	vendorPrefixRegex     = regexp.MustCompile(`^-(?:webkit|moz|ms|o|epub)-`)
+	unsupportedProperties = []string{"transform", "columns", "column-count", "column-width"}
+	unsupportedDisplays   = []string{"flex", "inline-flex", "grid", "inline-grid"}
+)
+
+type declaration struct {
+	start, end int
+	property   string
+	value      string
+}
+
+// IsFixable returns whether the issue the finding is for can be fixed without changing how the epub looks on Kindle
+func IsFixable(finding structurecheck.Finding) bool {
+	return slices.Contains(FixableRules, finding.Rule)
+}
+
+// CheckStyles checks the css of a css file or the style elements and attributes of an html file
+// for properties that Kindle does not support or that render badly on it
+func CheckStyles(file, contents string) []structurecheck.Finding {
+	var findings []structurecheck.Finding
+	for _, declaration := range getDeclarations(file, contents) {
+		rule, message := getCssIssue(declaration)
+		if rule != "" {
+			findings = append(findings, structurecheck.NewFinding(file, contents, declaration.start, rule, message))
+		}
+	}
+
+	return findings
+}
+
+// FixStyles removes the css declarations that can be safely removed for Kindle from a css file
+// or the style elements and attributes of an html file returning the new contents and the number of declarations removed
+func FixStyles(file, contents string) (string, int) {
+	var (
+		declarations = getDeclarations(file, contents)
+		removed      int
+	)
+	for i := len(declarations) - 1; i >= 0; i-- {
+		rule, _ := getCssIssue(declarations[i])
+		if rule != PositionFixed && rule != CssAnimation {
+			continue
+		}
+
+		var start, end = epubhandler.GetLineBoundsIfEmpty(contents, declarations[i].start, declarations[i].end)
+		contents = contents[:start] + contents[end:]
+		removed++
+	}
+
+	return contents, removed
+}
+
+func getCssIssue(declaration declaration) (string, string) {
+	var property = vendorPrefixRegex.ReplaceAllString(declaration.property, "")
+	switch {
+	case property == "position" && declaration.value == "fixed":
+		return PositionFixed, "position: fixed is not supported on Kindle and can hide content or repeat it on every page"
+	case property == "animation" || strings.HasPrefix(property, "animation-") || property == "transition" || strings.HasPrefix(property, "transition-"):
+		return CssAnimation, fmt.Sprintf("%s is not supported on Kindle", declaration.property)
+	case property == "display" && slices.Contains(unsupportedDisplays, declaration.value):
+		return UnsupportedCss, fmt.Sprintf("display: %s is not supported on Kindle, so the content will be laid out as blocks instead", declaration.value)
+	case slices.Contains(unsupportedProperties, property):
+		return UnsupportedCss, fmt.Sprintf("%s is not supported on Kindle", declaration.property)
+	}
+
+	return "", ""
+}
+
+// getDeclarations gets the css declarations of a css file or the style elements and attributes of an html file
+func getDeclarations(file, contents string) []declaration {
+	var declarations []declaration
+	if strings.EqualFold(path.Ext(file), ".css") {
+		for _, indices := range cssRuleBodyRegex.FindAllStringIndex(contents, -1) {
+			declarations = appendDeclarations(declarations, contents, indices[0]+1, indices[1]-1)
+		}
+
+		return declarations
+	}
+
+	for _, indices := range styleElementRegex.FindAllStringSubmatchIndex(contents, -1) {
+		for _, bodyIndices := range cssRuleBodyRegex.FindAllStringIndex(contents[indices[2]:indices[3]], -1) {
+			declarations = appendDeclarations(declarations, contents, indices[2]+bodyIndices[0]+1, indices[2]+bodyIndices[1]-1)
+		}
+	}
+
+	for _, indices := range styleAttributeRegex.FindAllStringSubmatchIndex(contents, -1) {
+		var start, end = indices[2], indices[3]
+		if start == -1 {
+			start, end = indices[4], indices[5]
+		}
+
+		declarations = appendDeclarations(declarations, contents, start, end)
+	}
+
+	slices.SortFunc(declarations, func(a, b declaration) int {
+		return a.start - b.start
+	})
+
+	return declarations
+}
+
+// appendDeclarations adds the declarations between the start and end of a rule body or style attribute
+// where each declaration includes its ending semicolon when it has one
+func appendDeclarations(declarations []declaration, contents string, start, end int) []declaration {
+	for start < end {
+		var declarationEnd = end
+		if semicolonIndex := strings.IndexByte(contents[start:end], ';'); semicolonIndex != -1 {
+			declarationEnd = start + semicolonIndex + 1
+		}
+
+		var text = contents[start:declarationEnd]
+		if property, value, ok := strings.Cut(strings.TrimSuffix(text, ";"), ":"); ok && cssPropertyRegex.MatchString(strings.TrimSpace(property)) {
+			value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(strings.ToLower(value)), "!important"))
+
+			declarations = append(declarations, declaration{
+				start:    start + len(text) - len(strings.TrimLeft(text, " \t\r\n")),
+				end:      declarationEnd,
+				property: strings.ToLower(strings.TrimSpace(property)),
+				value:    value,
+			})
+		}
+
+		start = declarationEnd
+	}
+
+	return declarations
+}
@@ -0,0 +1,101 @@
+//go:build unit
+
+package kindle_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/kindle"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/stretchr/testify/assert"
+)
+
+type stylesTestCase struct {
+	file             string
+	contents         string
+	expectedFindings []structurecheck.Finding
+	expectedFixed    string
+	expectedRemoved  int
+}
+
+const kindleCss = `.header {
+  position: fixed;
+  top: 0;
+}
+
+.fade { -webkit-animation: fade 1s; transition: opacity 1s !important; color: red }
+
+.row {
+  display: flex;
+  background: url(data:image/png;base64,AAAA);
+  column-count: 2;
+}`
+
+var stylesTestCases = map[string]stylesTestCase{
+	"css files have unsupported declarations found and the safe ones removed": {
+		file:     "OEBPS/style.css",
+		contents: kindleCss,
+		expectedFindings: []structurecheck.Finding{
+			{File: "OEBPS/style.css", Line: 2, Column: 3, Rule: kindle.PositionFixed, Message: "position: fixed is not supported on Kindle and can hide content or repeat it on every page"},
+			{File: "OEBPS/style.css", Line: 6, Column: 9, Rule: kindle.CssAnimation, Message: "-webkit-animation is not supported on Kindle"},
+			{File: "OEBPS/style.css", Line: 6, Column: 37, Rule: kindle.CssAnimation, Message: "transition is not supported on Kindle"},
+			{File: "OEBPS/style.css", Line: 9, Column: 3, Rule: kindle.UnsupportedCss, Message: "display: flex is not supported on Kindle, so the content will be laid out as blocks instead"},
+			{File: "OEBPS/style.css", Line: 11, Column: 3, Rule: kindle.UnsupportedCss, Message: "column-count is not supported on Kindle"},
+		},
+		expectedFixed: `.header {
+  top: 0;
+}
+
+.fade {   color: red }
+
+.row {
+  display: flex;
+  background: url(data:image/png;base64,AAAA);
+  column-count: 2;
+}`,
+		expectedRemoved: 3,
+	},
+	"html files have their style elements and attributes checked": {
+		file:     "OEBPS/a.xhtml",
+		contents: `<html><head><style>p { position: fixed; }</style></head><body><p style="position:fixed">position: fixed;</p><div style="display: grid">text</div></body></html>`,
+		expectedFindings: []structurecheck.Finding{
+			{File: "OEBPS/a.xhtml", Line: 1, Column: 24, Rule: kindle.PositionFixed, Message: "position: fixed is not supported on Kindle and can hide content or repeat it on every page"},
+			{File: "OEBPS/a.xhtml", Line: 1, Column: 73, Rule: kindle.PositionFixed, Message: "position: fixed is not supported on Kindle and can hide content or repeat it on every page"},
+			{File: "OEBPS/a.xhtml", Line: 1, Column: 121, Rule: kindle.UnsupportedCss, Message: "display: grid is not supported on Kindle, so the content will be laid out as blocks instead"},
+		},
+		expectedFixed:   `<html><head><style>p {  }</style></head><body><p style="">position: fixed;</p><div style="display: grid">text</div></body></html>`,
+		expectedRemoved: 2,
+	},
+	"supported css has no findings": {
+		file:          "OEBPS/style.css",
+		contents:      `p { position: relative; display: block; margin: 0 }`,
+		expectedFixed: `p { position: relative; display: block; margin: 0 }`,
+	},
+}
+
+func TestCheckStyles(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range stylesTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, args.expectedFindings, kindle.CheckStyles(args.file, args.contents))
+		})
+	}
+}
+
+func TestFixStyles(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range stylesTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			fixed, removed := kindle.FixStyles(args.file, args.contents)
+
+			assert.Equal(t, args.expectedFixed, fixed)
+			assert.Equal(t, args.expectedRemoved, removed)
+		})
+	}
+}
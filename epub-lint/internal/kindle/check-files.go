@@ -0,0 +1,191 @@
+package kindle
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/pjkaufman/go-go-gadgets/pkg/image"
+)
+
+const (
+	// MaxImageSize is the largest an image can be in bytes before Kindle rejects or downscales it
+	MaxImageSize = 5 * 1024 * 1024
+
+	licenseNameId    = 13
+	licenseUrlNameId = 14
+)
+
+var (
+	unsupportedImageExts = []string{".webp", ".avif", ".tif", ".tiff", ".heic"}
+	// FontExts are the file extensions of fonts that can be embedded in an epub
+	FontExts           = []string{".ttf", ".otf", ".woff", ".woff2"}
+	licenseFileMarkers = []string{"license", "licence", "ofl"}
+)
+
+// CheckImage checks that the image is in a format Kindle supports and that it is not too large
+func CheckImage(file string, data []byte) []structurecheck.Finding {
+	var ext = strings.ToLower(path.Ext(file))
+	if slices.Contains(unsupportedImageExts, ext) {
+		return []structurecheck.Finding{{File: file, Rule: UnsupportedImage, Message: fmt.Sprintf("%s images are not supported on Kindle", strings.TrimPrefix(ext, "."))}}
+	}
+
+	if len(data) <= MaxImageSize {
+		return nil
+	}
+
+	var message = fmt.Sprintf("image is %.1f MB which is more than the %d MB Kindle allows", float64(len(data))/1024/1024, MaxImageSize/1024/1024)
+	if !slices.Contains(image.CompressableImageExts, strings.TrimPrefix(ext, ".")) {
+		message += ", so it will need to be compressed by hand"
+	}
+
+	return []structurecheck.Finding{{File: file, Rule: OversizedImage, Message: message}}
+}
+
+// HasLicenseFile returns whether one of the files looks like a license file for the fonts in the epub
+func HasLicenseFile(files []string) bool {
+	for _, file := range files {
+		var name = strings.ToLower(path.Base(file))
+		for _, marker := range licenseFileMarkers {
+			if strings.Contains(name, marker) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// CheckFont checks that the font has a license in its name table when there is no license file in the epub
+// since Send-to-Kindle can drop or reject fonts without one
+func CheckFont(file string, data []byte, hasLicenseFile bool) []structurecheck.Finding {
+	if hasLicenseFile {
+		return nil
+	}
+
+	hasLicense, err := fontHasLicense(data)
+	if err != nil {
+		return []structurecheck.Finding{{File: file, Rule: FontLicense, Message: fmt.Sprintf("font license could not be checked (%s) and there is no font license file in the epub", err)}}
+	}
+
+	if !hasLicense {
+		return []structurecheck.Finding{{File: file, Rule: FontLicense, Message: "font has no license in its name table and there is no font license file in the epub"}}
+	}
+
+	return nil
+}
+
+// fontHasLicense returns whether the name table of a TrueType, OpenType, or WOFF font has a license description or URL
+func fontHasLicense(data []byte) (bool, error) {
+	nameTable, err := getNameTable(data)
+	if err != nil {
+		return false, err
+	}
+
+	if len(nameTable) < 6 {
+		return false, fmt.Errorf("name table is too short")
+	}
+
+	var (
+		count         = int(binary.BigEndian.Uint16(nameTable[2:4]))
+		stringsOffset = int(binary.BigEndian.Uint16(nameTable[4:6]))
+	)
+	for i := 0; i < count; i++ {
+		var recordStart = 6 + i*12
+		if recordStart+12 > len(nameTable) {
+			break
+		}
+
+		var (
+			nameId = binary.BigEndian.Uint16(nameTable[recordStart+6 : recordStart+8])
+			length = int(binary.BigEndian.Uint16(nameTable[recordStart+8 : recordStart+10]))
+			offset = stringsOffset + int(binary.BigEndian.Uint16(nameTable[recordStart+10:recordStart+12]))
+		)
+		if (nameId == licenseNameId || nameId == licenseUrlNameId) && length != 0 && offset+length <= len(nameTable) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// getNameTable gets the uncompressed name table of a TrueType, OpenType, or WOFF font
+func getNameTable(data []byte) ([]byte, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("font is too short")
+	}
+
+	switch string(data[:4]) {
+	case "\x00\x01\x00\x00", "OTTO", "true":
+		var numTables = int(binary.BigEndian.Uint16(data[4:6]))
+		for i := 0; i < numTables; i++ {
+			var recordStart = 12 + i*16
+			if recordStart+16 > len(data) {
+				break
+			}
+
+			if string(data[recordStart:recordStart+4]) != "name" {
+				continue
+			}
+
+			var (
+				offset = int(binary.BigEndian.Uint32(data[recordStart+8 : recordStart+12]))
+				length = int(binary.BigEndian.Uint32(data[recordStart+12 : recordStart+16]))
+			)
+			if offset+length > len(data) {
+				return nil, fmt.Errorf("name table is out of bounds")
+			}
+
+			return data[offset : offset+length], nil
+		}
+	case "wOFF":
+		if len(data) < 44 {
+			return nil, fmt.Errorf("font is too short")
+		}
+
+		var numTables = int(binary.BigEndian.Uint16(data[12:14]))
+		for i := 0; i < numTables; i++ {
+			var entryStart = 44 + i*20
+			if entryStart+20 > len(data) {
+				break
+			}
+
+			if string(data[entryStart:entryStart+4]) != "name" {
+				continue
+			}
+
+			var (
+				offset           = int(binary.BigEndian.Uint32(data[entryStart+4 : entryStart+8]))
+				compressedLength = int(binary.BigEndian.Uint32(data[entryStart+8 : entryStart+12]))
+				length           = int(binary.BigEndian.Uint32(data[entryStart+12 : entryStart+16]))
+			)
+			if offset+compressedLength > len(data) {
+				return nil, fmt.Errorf("name table is out of bounds")
+			}
+
+			if compressedLength == length {
+				return data[offset : offset+length], nil
+			}
+
+			reader, err := zlib.NewReader(bytes.NewReader(data[offset : offset+compressedLength]))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decompress the name table: %w", err)
+			}
+			defer reader.Close()
+
+			return io.ReadAll(reader)
+		}
+	case "wOF2":
+		return nil, fmt.Errorf("woff2 fonts are not supported")
+	default:
+		return nil, fmt.Errorf("unknown font format")
+	}
+
+	return nil, fmt.Errorf("font has no name table")
+}
@@ -0,0 +1,138 @@
+//go:build unit
+
+package kindle_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/kindle"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/stretchr/testify/assert"
+)
+
+type checkFontTestCase struct {
+	data             []byte
+	hasLicenseFile   bool
+	expectedFindings []structurecheck.Finding
+}
+
+var checkFontTestCases = map[string]checkFontTestCase{
+	"a font with a license description has no findings": {
+		data: newTrueTypeFont(13),
+	},
+	"a font with a license url has no findings": {
+		data: newTrueTypeFont(14),
+	},
+	"a font without a license is a finding": {
+		data: newTrueTypeFont(1),
+		expectedFindings: []structurecheck.Finding{
+			{File: "OEBPS/font.ttf", Rule: kindle.FontLicense, Message: "font has no license in its name table and there is no font license file in the epub"},
+		},
+	},
+	"a font without a license has no findings when there is a license file": {
+		data:           newTrueTypeFont(1),
+		hasLicenseFile: true,
+	},
+	"a woff font with a compressed license has no findings": {
+		data: newWoffFont(13),
+	},
+	"a woff2 font without a license file is a finding": {
+		data: []byte("wOF2\x00\x01\x00\x00\x00\x00\x00\x00"),
+		expectedFindings: []structurecheck.Finding{
+			{File: "OEBPS/font.ttf", Rule: kindle.FontLicense, Message: "font license could not be checked (woff2 fonts are not supported) and there is no font license file in the epub"},
+		},
+	},
+}
+
+func TestCheckFont(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range checkFontTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, args.expectedFindings, kindle.CheckFont("OEBPS/font.ttf", args.data, args.hasLicenseFile))
+		})
+	}
+}
+
+func TestCheckImage(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, kindle.CheckImage("OEBPS/a.jpg", make([]byte, 1024)))
+	assert.Equal(t, []structurecheck.Finding{
+		{File: "OEBPS/a.webp", Rule: kindle.UnsupportedImage, Message: "webp images are not supported on Kindle"},
+	}, kindle.CheckImage("OEBPS/a.webp", make([]byte, 1024)))
+	assert.Equal(t, []structurecheck.Finding{
+		{File: "OEBPS/a.jpg", Rule: kindle.OversizedImage, Message: "image is 6.0 MB which is more than the 5 MB Kindle allows"},
+	}, kindle.CheckImage("OEBPS/a.jpg", make([]byte, 6*1024*1024)))
+	assert.Equal(t, []structurecheck.Finding{
+		{File: "OEBPS/a.gif", Rule: kindle.OversizedImage, Message: "image is 6.0 MB which is more than the 5 MB Kindle allows, so it will need to be compressed by hand"},
+	}, kindle.CheckImage("OEBPS/a.gif", make([]byte, 6*1024*1024)))
+}
+
+func TestHasLicenseFile(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, kindle.HasLicenseFile([]string{"OEBPS/Fonts/font.ttf", "OEBPS/Fonts/OFL.txt"}))
+	assert.True(t, kindle.HasLicenseFile([]string{"OEBPS/Fonts/LICENSE"}))
+	assert.False(t, kindle.HasLicenseFile([]string{"OEBPS/Fonts/font.ttf", "OEBPS/Text/chapter1.xhtml"}))
+}
+
+// newNameTable creates a name table with a single record with the provided name id
+func newNameTable(nameId uint16) []byte {
+	var (
+		value = []byte("text")
+		table = binary.BigEndian.AppendUint16(nil, 0)
+	)
+	table = binary.BigEndian.AppendUint16(table, 1)
+	table = binary.BigEndian.AppendUint16(table, 18)
+	for _, field := range []uint16{3, 1, 0x409, nameId, uint16(len(value)), 0} {
+		table = binary.BigEndian.AppendUint16(table, field)
+	}
+
+	return append(table, value...)
+}
+
+func newTrueTypeFont(nameId uint16) []byte {
+	var (
+		nameTable = newNameTable(nameId)
+		font      = []byte{0, 1, 0, 0}
+	)
+	font = binary.BigEndian.AppendUint16(font, 1)
+	font = append(font, make([]byte, 6)...)
+	font = append(font, "name"...)
+	font = binary.BigEndian.AppendUint32(font, 0)
+	font = binary.BigEndian.AppendUint32(font, 28)
+	font = binary.BigEndian.AppendUint32(font, uint32(len(nameTable)))
+
+	return append(font, nameTable...)
+}
+
+func newWoffFont(nameId uint16) []byte {
+	var (
+		nameTable  = newNameTable(nameId)
+		compressed bytes.Buffer
+		writer     = zlib.NewWriter(&compressed)
+	)
+	_, err := writer.Write(nameTable)
+	if err != nil {
+		panic(err)
+	}
+
+	writer.Close()
+
+	var font = []byte("wOFF\x00\x01\x00\x00\x00\x00\x00\x00")
+	font = binary.BigEndian.AppendUint16(font, 1)
+	font = append(font, make([]byte, 30)...)
+	font = append(font, "name"...)
+	font = binary.BigEndian.AppendUint32(font, 64)
+	font = binary.BigEndian.AppendUint32(font, uint32(compressed.Len()))
+	font = binary.BigEndian.AppendUint32(font, uint32(len(nameTable)))
+	font = binary.BigEndian.AppendUint32(font, 0)
+
+	return append(font, compressed.Bytes()...)
+}
@@ -0,0 +1,130 @@
+package kindle
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/links"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+)
+
+const (
+	ncxId        = "ncx"
+	ncxMediaType = "application/x-dtbncx+xml"
+	ncxContents  = `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+<head>
+  <meta name="dtb:uid" content="%[1]s"/>
+  <meta name="dtb:depth" content="1"/>
+  <meta name="dtb:totalPageCount" content="0"/>
+  <meta name="dtb:maxPageNumber" content="0"/>
+</head>
+<docTitle>
+  <text>%[2]s</text>
+</docTitle>
+<navMap>
+</navMap>
+</ncx>
+`
+)
+
+var (
+	ErrNoNavToc     = errors.New("nav file has no toc to create the NCX file from")
+	ErrNoSpine      = errors.New("opf file has no spine element")
+	spineTagRegex   = regexp.MustCompile(`(?i)<spine\b[^>]*>`)
+	whitespaceRegex = regexp.MustCompile(`\s+`)
+)
+
+// CheckNcx checks that the epub has an NCX file since older Kindles use it for the table of contents
+func CheckNcx(opfFile, opfContents, ncxFile string) []structurecheck.Finding {
+	if ncxFile != "" {
+		return nil
+	}
+
+	var index int
+	if indices := spineTagRegex.FindStringIndex(opfContents); indices != nil {
+		index = indices[0]
+	}
+
+	return []structurecheck.Finding{structurecheck.NewFinding(opfFile, opfContents, index, MissingNcx, "epub has no NCX file which older Kindles use for the table of contents")}
+}
+
+// CreateNcx creates an NCX file with a nav point for each link in the toc of the nav file.
+// The file paths are relative to the root of the epub.
+func CreateNcx(navContents, navFile, ncxFile, opfContents string) (string, error) {
+	nav, err := epubdoc.ParseNav(navContents)
+	if err != nil {
+		return "", err
+	}
+
+	if nav.Toc() == nil {
+		return "", ErrNoNavToc
+	}
+
+	var identifier, title string
+	if opf, err := epubdoc.ParseOpf(opfContents); err == nil {
+		if uniqueIdentifier := opf.UniqueIdentifier(); uniqueIdentifier != nil {
+			identifier = strings.TrimSpace(uniqueIdentifier.Text())
+		} else if identifiers := opf.MetadataElements("dc:identifier"); len(identifiers) != 0 {
+			identifier = strings.TrimSpace(identifiers[0].Text())
+		}
+
+		if titles := opf.MetadataElements("dc:title"); len(titles) != 0 {
+			title = strings.TrimSpace(titles[0].Text())
+		}
+	}
+
+	ncx, err := epubdoc.ParseNcx(fmt.Sprintf(ncxContents, html.EscapeString(identifier), html.EscapeString(title)))
+	if err != nil {
+		return "", err
+	}
+
+	for i, link := range nav.TocLinks() {
+		var (
+			target, fragment = links.ResolveLink(navFile, link.Href())
+			src              = (&url.URL{Path: links.GetRelativeLink(ncxFile, target)}).EscapedPath()
+			label            = strings.TrimSpace(whitespaceRegex.ReplaceAllString(link.Title(), " "))
+		)
+		if fragment != "" {
+			src += "#" + fragment
+		}
+
+		_, err = ncx.AddNavPoint(fmt.Sprintf("navPoint-%d", i+1), label, src)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return ncx.String(), nil
+}
+
+// AddNcxToOpf adds the NCX file to the manifest and sets it as the toc of the spine where the href is relative to the opf file
+func AddNcxToOpf(opfContents, href string) (string, error) {
+	opf, err := epubdoc.ParseOpf(opfContents)
+	if err != nil {
+		return opfContents, err
+	}
+
+	var spine = opf.Spine()
+	if spine == nil {
+		return opfContents, ErrNoSpine
+	}
+
+	var id = ncxId
+	for _, ok := opf.ManifestItemById(id); ok; _, ok = opf.ManifestItemById(id) {
+		id = "kindle-" + id
+	}
+
+	if _, err = opf.AddManifestItem(id, href, ncxMediaType); err != nil {
+		return opfContents, err
+	}
+
+	spine.SetAttr("toc", id)
+
+	return opf.String(), nil
+}
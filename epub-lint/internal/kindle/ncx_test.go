@@ -0,0 +1,105 @@
+//go:build unit
+
+package kindle_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/kindle"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	kindleOpf = `<package version="3.0" unique-identifier="id">
+  <metadata>
+    <dc:identifier id="id">urn:uuid:1234</dc:identifier>
+    <dc:title>Title &amp; More</dc:title>
+  </metadata>
+  <manifest>
+    <item id="nav" href="Text/nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+  </manifest>
+  <spine>
+  </spine>
+</package>`
+	kindleNav = `<html><body>
+<nav epub:type="toc" id="toc">
+<ol>
+  <li><a href="chapter1.xhtml">Chapter <b>1</b></a></li>
+  <li><a href="chapter%202.xhtml#part">Chapter 2 &amp; 3</a></li>
+</ol>
+</nav>
+<nav epub:type="landmarks"><ol><li><a href="cover.xhtml">Cover</a></li></ol></nav>
+</body></html>`
+)
+
+func TestCheckNcx(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, kindle.CheckNcx("OEBPS/content.opf", kindleOpf, "toc.ncx"))
+	assert.Equal(t, []structurecheck.Finding{
+		{File: "OEBPS/content.opf", Line: 9, Column: 3, Rule: kindle.MissingNcx, Message: "epub has no NCX file which older Kindles use for the table of contents"},
+	}, kindle.CheckNcx("OEBPS/content.opf", kindleOpf, ""))
+}
+
+func TestCreateNcx(t *testing.T) {
+	t.Parallel()
+
+	ncx, err := kindle.CreateNcx(kindleNav, "OEBPS/Text/nav.xhtml", "OEBPS/toc.ncx", kindleOpf)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+<head>
+  <meta name="dtb:uid" content="urn:uuid:1234"/>
+  <meta name="dtb:depth" content="1"/>
+  <meta name="dtb:totalPageCount" content="0"/>
+  <meta name="dtb:maxPageNumber" content="0"/>
+</head>
+<docTitle>
+  <text>Title &amp; More</text>
+</docTitle>
+<navMap>
+  <navPoint id="navPoint-1" playOrder="1">
+    <navLabel>
+      <text>Chapter 1</text>
+    </navLabel>
+    <content src="Text/chapter1.xhtml"/>
+  </navPoint>
+  <navPoint id="navPoint-2" playOrder="2">
+    <navLabel>
+      <text>Chapter 2 &amp; 3</text>
+    </navLabel>
+    <content src="Text/chapter%202.xhtml#part"/>
+  </navPoint>
+</navMap>
+</ncx>
+`, ncx)
+
+	_, err = kindle.CreateNcx(`<html><body></body></html>`, "OEBPS/Text/nav.xhtml", "OEBPS/toc.ncx", kindleOpf)
+	assert.ErrorIs(t, err, kindle.ErrNoNavToc)
+}
+
+func TestAddNcxToOpf(t *testing.T) {
+	t.Parallel()
+
+	opf, err := kindle.AddNcxToOpf(kindleOpf, "toc.ncx")
+	require.NoError(t, err)
+
+	assert.Equal(t, `<package version="3.0" unique-identifier="id">
+  <metadata>
+    <dc:identifier id="id">urn:uuid:1234</dc:identifier>
+    <dc:title>Title &amp; More</dc:title>
+  </metadata>
+  <manifest>
+    <item id="nav" href="Text/nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+  </manifest>
+  <spine toc="ncx">
+  </spine>
+</package>`, opf)
+
+	_, err = kindle.AddNcxToOpf(`<package><manifest></manifest></package>`, "toc.ncx")
+	assert.ErrorIs(t, err, kindle.ErrNoSpine)
+}
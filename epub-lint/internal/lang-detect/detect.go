@@ -0,0 +1,249 @@
+package langdetect
+
+import (
+	"embed"
+	"html"
+	"math"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+)
+
+const (
+	// profileSize is the number of the most common trigrams kept for a language profile and for the text being detected
+	profileSize = 300
+	// MinLetters is the least number of letters text needs for its language to be detected
+	MinLetters = 40
+	// MaxLetters is the most letters of text that are used for detection which keeps detection fast for large books
+	MaxLetters = 20000
+)
+
+type scriptLanguage struct {
+	lang    string
+	scripts []*unicode.RangeTable
+}
+
+var (
+	// profileFiles are the most common trigrams of each language written in the latin script in rank order one per line
+	// with spaces written as underscores. They are generated by the lang-profiles command from a corpus of text for each language
+	// which is currently the translations of the Vim tutor along with a sample of narrative text.
+	//go:embed profiles/*.txt
+	profileFiles embed.FS
+	// profiles are the trigram profiles of the languages written in the latin script
+	profiles = loadProfiles()
+	// scriptLanguages are the languages that can be detected by their script alone where kana is checked before han
+	// since Japanese mixes kana with han
+	scriptLanguages = []scriptLanguage{
+		{lang: "ja", scripts: []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana}},
+		{lang: "ko", scripts: []*unicode.RangeTable{unicode.Hangul}},
+		{lang: "zh", scripts: []*unicode.RangeTable{unicode.Han}},
+		{lang: "el", scripts: []*unicode.RangeTable{unicode.Greek}},
+		{lang: "he", scripts: []*unicode.RangeTable{unicode.Hebrew}},
+		{lang: "th", scripts: []*unicode.RangeTable{unicode.Thai}},
+	}
+	headRegex       = regexp.MustCompile(`(?is)<head\b.*?</head>`)
+	nonTextRegex    = regexp.MustCompile(`(?is)<(script|style)\b.*?</(script|style)>`)
+	whitespaceRegex = regexp.MustCompile(`\s+`)
+)
+
+// Languages returns the languages that can be detected
+func Languages() []string {
+	var languages = make([]string, 0, len(profiles)+len(scriptLanguages))
+	for lang := range profiles {
+		languages = append(languages, lang)
+	}
+
+	for _, scriptLanguage := range scriptLanguages {
+		languages = append(languages, scriptLanguage.lang)
+	}
+
+	slices.Sort(languages)
+
+	return languages
+}
+
+// Detect detects the language of the text returning an empty string when the text is too short
+// or is not in one of the languages that can be detected
+func Detect(text string) string {
+	var (
+		letters, latinLetters int
+		scriptCounts          = make([]int, len(scriptLanguages))
+	)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		letters++
+		if letters > MaxLetters {
+			break
+		}
+
+		if unicode.Is(unicode.Latin, r) {
+			latinLetters++
+
+			continue
+		}
+
+		for i, scriptLanguage := range scriptLanguages {
+			if unicode.In(r, scriptLanguage.scripts...) {
+				scriptCounts[i]++
+
+				break
+			}
+		}
+	}
+
+	if letters < MinLetters {
+		return ""
+	}
+
+	// Japanese text can have more han than kana, so any meaningful amount of kana alongside han is Japanese
+	var cjkCount = scriptCounts[0] + scriptCounts[2]
+	if scriptCounts[0] != 0 && cjkCount*2 > letters && scriptCounts[0]*10 >= cjkCount {
+		return scriptLanguages[0].lang
+	}
+
+	for i, count := range scriptCounts {
+		if count*2 > letters {
+			return scriptLanguages[i].lang
+		}
+	}
+
+	if latinLetters*2 <= letters {
+		return ""
+	}
+
+	var (
+		textProfile  = getTrigramRanks(getMostCommonTrigrams(text, MaxLetters))
+		bestLang     string
+		bestDistance = math.MaxInt
+	)
+	for lang, profile := range profiles {
+		var distance = getDistance(textProfile, profile)
+		if distance < bestDistance || (distance == bestDistance && lang < bestLang) {
+			bestLang, bestDistance = lang, distance
+		}
+	}
+
+	return bestLang
+}
+
+// DetectHtml detects the language of the text in the body of an html file
+func DetectHtml(contents string) string {
+	return Detect(GetHtmlText(contents))
+}
+
+// GetHtmlText gets the text of an html file without its head, scripts, styles, and tags
+func GetHtmlText(contents string) string {
+	contents = headRegex.ReplaceAllString(contents, " ")
+	contents = nonTextRegex.ReplaceAllString(contents, " ")
+	contents = linter.HtmlTagRegex.ReplaceAllString(contents, " ")
+
+	return strings.TrimSpace(whitespaceRegex.ReplaceAllString(html.UnescapeString(contents), " "))
+}
+
+func loadProfiles() map[string]map[string]int {
+	files, err := profileFiles.ReadDir("profiles")
+	if err != nil {
+		panic(err)
+	}
+
+	var languageProfiles = make(map[string]map[string]int, len(files))
+	for _, file := range files {
+		data, err := profileFiles.ReadFile(path.Join("profiles", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		var trigrams []string
+		for line := range strings.Lines(string(data)) {
+			if trigram := strings.TrimRight(line, "\r\n"); trigram != "" {
+				trigrams = append(trigrams, strings.ReplaceAll(trigram, "_", " "))
+			}
+		}
+
+		languageProfiles[strings.TrimSuffix(file.Name(), path.Ext(file.Name()))] = getTrigramRanks(trigrams)
+	}
+
+	return languageProfiles
+}
+
+// BuildProfile gets the most common trigrams of the words in all of the text in rank order with spaces written as underscores
+// which is what the language profiles are made up of
+func BuildProfile(text string) []string {
+	var trigrams = getMostCommonTrigrams(text, -1)
+	for i, trigram := range trigrams {
+		trigrams[i] = strings.ReplaceAll(trigram, " ", "_")
+	}
+
+	return trigrams
+}
+
+func getTrigramRanks(trigrams []string) map[string]int {
+	var ranks = make(map[string]int, len(trigrams))
+	for rank, trigram := range trigrams {
+		ranks[trigram] = rank
+	}
+
+	return ranks
+}
+
+// getMostCommonTrigrams gets the most common trigrams of the words in the text in rank order where each word is padded
+// with a space on either side so the start and end of words are included. Only up to the max letters are used unless it is negative.
+func getMostCommonTrigrams(text string, maxLetters int) []string {
+	var (
+		counts  = make(map[string]int)
+		letters int
+	)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		var runes = []rune(" " + word + " ")
+		letters += len(runes) - 2
+		for i := 0; i+3 <= len(runes); i++ {
+			counts[string(runes[i:i+3])]++
+		}
+
+		if maxLetters >= 0 && letters > maxLetters {
+			break
+		}
+	}
+
+	var trigrams = make([]string, 0, len(counts))
+	for trigram := range counts {
+		trigrams = append(trigrams, trigram)
+	}
+
+	slices.SortFunc(trigrams, func(a, b string) int {
+		if counts[a] != counts[b] {
+			return counts[b] - counts[a]
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	if len(trigrams) > profileSize {
+		trigrams = trigrams[:profileSize]
+	}
+
+	return trigrams
+}
+
+// getDistance gets the out-of-place distance between the profile of the text and a language profile
+func getDistance(textProfile, languageProfile map[string]int) int {
+	var distance int
+	for trigram, rank := range textProfile {
+		if languageRank, ok := languageProfile[trigram]; ok {
+			distance += max(rank-languageRank, languageRank-rank)
+		} else {
+			distance += profileSize
+		}
+	}
+
+	return distance
+}
@@ -0,0 +1,178 @@
+//go:build unit
+
+package langdetect_test
+
+import (
+	"testing"
+
+	langdetect "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/lang-detect"
+	"github.com/stretchr/testify/assert"
+)
+
+type detectTestCase struct {
+	inputText    string
+	expectedLang string
+}
+
+var detectTestCases = map[string]detectTestCase{
+	"english text is detected as english": {
+		inputText:    "The captain stood on the deck of the ship and watched the storm coming in from the west. He knew that they would not reach the harbor before dark.",
+		expectedLang: "en",
+	},
+	"spanish text is detected as spanish": {
+		inputText:    "El capitán estaba en la cubierta del barco y miraba la tormenta que llegaba desde el oeste. Sabía que no llegarían al puerto antes de que anocheciera.",
+		expectedLang: "es",
+	},
+	"french text is detected as french": {
+		inputText:    "Le capitaine se tenait sur le pont du navire et regardait la tempête qui arrivait de l'ouest. Il savait qu'ils n'atteindraient pas le port avant la nuit.",
+		expectedLang: "fr",
+	},
+	"german text is detected as german": {
+		inputText:    "Der Kapitän stand auf dem Deck des Schiffes und beobachtete den Sturm, der aus dem Westen kam. Er wusste, dass sie den Hafen nicht vor der Dunkelheit erreichen würden.",
+		expectedLang: "de",
+	},
+	"italian text is detected as italian": {
+		inputText:    "Il capitano stava sul ponte della nave e guardava la tempesta che arrivava da ovest. Sapeva che non avrebbero raggiunto il porto prima del buio.",
+		expectedLang: "it",
+	},
+	"portuguese text is detected as portuguese": {
+		inputText:    "O capitão estava no convés do navio e observava a tempestade que vinha do oeste. Ele sabia que não chegariam ao porto antes de escurecer.",
+		expectedLang: "pt",
+	},
+	"dutch text is detected as dutch": {
+		inputText:    "De kapitein stond op het dek van het schip en keek naar de storm die uit het westen kwam. Hij wist dat ze de haven niet voor het donker zouden bereiken.",
+		expectedLang: "nl",
+	},
+	"spanish dialogue from a novel is detected as spanish": {
+		inputText: `—¿Estás seguro de que quieres hacerlo? —preguntó ella mientras cerraba la puerta detrás de sí—. Nadie ha vuelto de ese bosque desde hace años.
+Él se encogió de hombros y siguió preparando su mochila. No tenía otra opción; si no encontraba la medicina antes del invierno, su hermana no sobreviviría.`,
+		expectedLang: "es",
+	},
+	"portuguese dialogue from a novel is detected as portuguese": {
+		inputText: `— Tem certeza de que quer fazer isso? — perguntou ela enquanto fechava a porta atrás de si. — Ninguém voltou daquela floresta há anos.
+Ele deu de ombros e continuou arrumando a mochila. Não tinha outra escolha; se não encontrasse o remédio antes do inverno, sua irmã não sobreviveria.`,
+		expectedLang: "pt",
+	},
+	"dutch dialogue from a novel is detected as dutch": {
+		inputText: `'Weet je zeker dat je dit wilt doen?' vroeg ze terwijl ze de deur achter zich dichtdeed. 'Niemand is al jaren uit dat bos teruggekomen.'
+Hij haalde zijn schouders op en ging verder met het inpakken van zijn rugzak. Hij had geen keus; als hij het medicijn niet voor de winter vond, zou zijn zus het niet overleven.`,
+		expectedLang: "nl",
+	},
+	"german dialogue from a novel is detected as german": {
+		inputText: `„Bist du sicher, dass du das tun willst?“, fragte sie, während sie die Tür hinter sich schloss. „Seit Jahren ist niemand aus diesem Wald zurückgekehrt.“
+Er zuckte mit den Schultern und packte weiter seinen Rucksack. Er hatte keine Wahl; wenn er die Medizin nicht vor dem Winter fand, würde seine Schwester nicht überleben.`,
+		expectedLang: "de",
+	},
+	"english dialogue from a novel is detected as english": {
+		inputText: `"Are you sure you want to do this?" she asked as she closed the door behind her. "Nobody has come back from that forest in years."
+He shrugged and kept packing his bag. He had no choice; if he did not find the medicine before winter, his sister would not survive.`,
+		expectedLang: "en",
+	},
+	"french dialogue from a novel is detected as french": {
+		inputText: `« Tu es sûr de vouloir faire ça ? » demanda-t-elle en refermant la porte derrière elle. « Personne n'est revenu de cette forêt depuis des années. »
+Il haussa les épaules et continua à préparer son sac. Il n'avait pas le choix ; s'il ne trouvait pas le remède avant l'hiver, sa sœur ne survivrait pas.`,
+		expectedLang: "fr",
+	},
+	"italian dialogue from a novel is detected as italian": {
+		inputText: `«Sei sicuro di volerlo fare?» chiese lei mentre chiudeva la porta dietro di sé. «Nessuno è tornato da quella foresta da anni.»
+Lui alzò le spalle e continuò a preparare lo zaino. Non aveva scelta; se non avesse trovato la medicina prima dell'inverno, sua sorella non sarebbe sopravvissuta.`,
+		expectedLang: "it",
+	},
+	"a short spanish sentence is not mistaken for portuguese": {
+		inputText:    "No sé qué decirte, pero la verdad es que todavía no estoy lista para volver a casa.",
+		expectedLang: "es",
+	},
+	"a short portuguese sentence is not mistaken for spanish": {
+		inputText:    "Não sei o que te dizer, mas a verdade é que ainda não estou pronta para voltar para casa.",
+		expectedLang: "pt",
+	},
+	"spanish without accents is not mistaken for portuguese": {
+		inputText:    "Ella miro por la ventana y penso en la casa donde habia crecido con su madre y sus hermanos.",
+		expectedLang: "es",
+	},
+	"portuguese without accents is not mistaken for spanish": {
+		inputText:    "Ela olhou pela janela e pensou na casa onde tinha crescido com a mae e os irmaos.",
+		expectedLang: "pt",
+	},
+	"a short dutch sentence is not mistaken for german": {
+		inputText:    "Ik weet niet wat ik moet zeggen, maar ik ben nog niet klaar om terug naar huis te gaan.",
+		expectedLang: "nl",
+	},
+	"a short german sentence is not mistaken for dutch": {
+		inputText:    "Ich weiß nicht, was ich sagen soll, aber ich bin noch nicht bereit, nach Hause zu gehen.",
+		expectedLang: "de",
+	},
+	"dutch about the weather is not mistaken for german": {
+		inputText:    "De wind was koud en het regende de hele dag, dus bleven de kinderen binnen en speelden met hun vader.",
+		expectedLang: "nl",
+	},
+	"german about the weather is not mistaken for dutch": {
+		inputText:    "Der Wind war kalt und es regnete den ganzen Tag, also blieben die Kinder drinnen und spielten mit ihrem Vater.",
+		expectedLang: "de",
+	},
+	"japanese text with kana and kanji is detected as japanese": {
+		inputText:    "船長は船の甲板に立ち、西から近づいてくる嵐を見ていた。暗くなる前に港に着くことはできないと彼は分かっていた。",
+		expectedLang: "ja",
+	},
+	"chinese text is detected as chinese": {
+		inputText:    "船长站在船的甲板上，看着从西边来的暴风雨。他知道他们在天黑之前无法到达港口，所以他命令所有的水手做好准备。",
+		expectedLang: "zh",
+	},
+	"korean text is detected as korean": {
+		inputText:    "선장은 배의 갑판에 서서 서쪽에서 다가오는 폭풍을 바라보았다. 그는 어두워지기 전에 항구에 도착할 수 없다는 것을 알고 있었다.",
+		expectedLang: "ko",
+	},
+	"greek text is detected as greek": {
+		inputText:    "Ο καπετάνιος στεκόταν στο κατάστρωμα του πλοίου και κοίταζε την καταιγίδα που ερχόταν από τη δύση.",
+		expectedLang: "el",
+	},
+	"text that is too short is not detected": {
+		inputText:    "Chapter One",
+		expectedLang: "",
+	},
+	"text in a script that cannot be detected is not detected": {
+		inputText:    "Капитан стоял на палубе корабля и смотрел на бурю, которая приближалась с запада.",
+		expectedLang: "",
+	},
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range detectTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, args.expectedLang, langdetect.Detect(args.inputText))
+		})
+	}
+}
+
+func TestDetectHtml(t *testing.T) {
+	t.Parallel()
+
+	var contents = `<?xml version="1.0" encoding="utf-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head><title>The title of the chapter in english words</title><style>p { margin: 0; }</style></head>
+<body>
+<p>El capitán estaba en la cubierta del barco y miraba la tormenta que llegaba desde el oeste.</p>
+<p>Sabía que no llegarían al puerto antes de que anocheciera &amp; no dijo nada.</p>
+</body>
+</html>`
+
+	assert.Equal(t, "es", langdetect.DetectHtml(contents))
+	assert.Equal(t, "El capitán estaba en la cubierta del barco y miraba la tormenta que llegaba desde el oeste. Sabía que no llegarían al puerto antes de que anocheciera & no dijo nada.", langdetect.GetHtmlText(contents))
+}
+
+func TestBuildProfile(t *testing.T) {
+	t.Parallel()
+
+	// trigrams with the same count are sorted alphabetically
+	assert.Equal(t, []string{"_th", "he_", "the", "_ca", "at_", "cat"}, langdetect.BuildProfile("The cat, the... the!"))
+}
+
+func TestLanguages(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"de", "el", "en", "es", "fr", "he", "it", "ja", "ko", "nl", "pt", "th", "zh"}, langdetect.Languages())
+}
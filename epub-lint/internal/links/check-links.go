@@ -0,0 +1,219 @@
+package links
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+)
+
+var (
+	linkAttributeRegex = regexp.MustCompile(`(?i)\s(?:href|src|xlink:href)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	cssUrlRegex        = regexp.MustCompile(`(?i)url\(\s*(?:"([^"]*)"|'([^']*)'|([^'")\s]*))\s*\)`)
+	htmlExts           = []string{".xhtml", ".html", ".htm", ".ncx"}
+)
+
+// Link is a reference to another file or id in a file
+type Link struct {
+	// File is the path of the file the link is in relative to the root of the epub
+	File  string
+	Value string
+	// Start is the index of the start of the link value in the file
+	Start  int
+	Line   int
+	Column int
+}
+
+// BrokenLink is a link to a file or id that does not exist along with its repair if one could be found
+type BrokenLink struct {
+	Link
+	// Target is the path of the file the link points to relative to the root of the epub
+	Target      string
+	Fragment    string
+	MissingFile bool
+	// Repair is the value to replace the link with which is empty when no repair could be found
+	Repair string
+}
+
+// Message describes why the link is broken
+func (b BrokenLink) Message() string {
+	if b.MissingFile {
+		return fmt.Sprintf("%q points to %q which does not exist", b.Value, b.Target)
+	}
+
+	return fmt.Sprintf("%q points to id %q which does not exist in %q", b.Value, b.Fragment, b.Target)
+}
+
+// Edit gets the text edit that replaces the link value with the repair
+func (b BrokenLink) Edit(contents string) positions.TextEdit {
+	if b.Repair == "" {
+		return positions.TextEdit{}
+	}
+
+	return positions.TextEdit{
+		Range: positions.Range{
+			Start: positions.IndexToPosition(contents, b.Start),
+			End:   positions.IndexToPosition(contents, b.Start+len(b.Value)),
+		},
+		NewText: b.Repair,
+	}
+}
+
+// GetLinks gets the href, src, xlink:href, and css url() references in the file
+// ignoring external links and empty links
+func GetLinks(file, contents string) []Link {
+	var links []Link
+	for _, linkRegex := range []*regexp.Regexp{linkAttributeRegex, cssUrlRegex} {
+		for _, indices := range linkRegex.FindAllStringSubmatchIndex(contents, -1) {
+			var start, end = -1, -1
+			for group := 1; group < len(indices)/2; group++ {
+				if indices[group*2] != -1 {
+					start, end = indices[group*2], indices[group*2+1]
+					break
+				}
+			}
+
+			if start == -1 || strings.TrimSpace(contents[start:end]) == "" || isExternalLink(contents[start:end]) {
+				continue
+			}
+
+			var position = positions.IndexToPosition(contents, start)
+			links = append(links, Link{
+				File:   file,
+				Value:  contents[start:end],
+				Start:  start,
+				Line:   position.Line,
+				Column: position.Column,
+			})
+		}
+	}
+
+	slices.SortFunc(links, func(a, b Link) int {
+		return a.Start - b.Start
+	})
+
+	return links
+}
+
+// CheckLinks resolves the links in the files against the existing files in the epub and the ids in the html files
+// returning the broken links in file and then position order. When a broken link's file can be found by its basename
+// or its id is close to an id in the target file, the broken link includes a repair.
+// files is the contents of the files to check by their path relative to the root of the epub which needs to include
+// any html files that are linked to so that their ids can be checked. existingFiles is all files in the epub.
+func CheckLinks(files map[string]string, existingFiles map[string]struct{}) []BrokenLink {
+	var (
+		idsByFile           = make(map[string][]string)
+		basenameToFilePaths = make(map[string][]string)
+		brokenLinks         []BrokenLink
+	)
+	for file, contents := range files {
+		if slices.Contains(htmlExts, strings.ToLower(path.Ext(file))) {
+			idsByFile[file] = slices.Sorted(maps.Keys(epubhandler.GetIds(contents)))
+		}
+	}
+
+	for _, file := range slices.Sorted(maps.Keys(existingFiles)) {
+		var basename = path.Base(file)
+		basenameToFilePaths[basename] = append(basenameToFilePaths[basename], file)
+	}
+
+	for _, file := range slices.Sorted(maps.Keys(files)) {
+		for _, link := range GetLinks(file, files[file]) {
+			var (
+				target, fragment = ResolveLink(file, link.Value)
+				targetPart, _, _ = strings.Cut(link.Value, "#")
+			)
+			if _, exists := existingFiles[target]; !exists {
+				var brokenLink = BrokenLink{
+					Link:        link,
+					Target:      target,
+					Fragment:    fragment,
+					MissingFile: true,
+				}
+
+				if possibleFiles := basenameToFilePaths[path.Base(target)]; len(possibleFiles) != 0 {
+					brokenLink.Repair = GetRelativeLink(file, possibleFiles[0])
+					if fragment != "" {
+						if ids, isHtml := idsByFile[possibleFiles[0]]; !isHtml || slices.Contains(ids, fragment) {
+							brokenLink.Repair += "#" + fragment
+						} else if closestId := findClosestId(fragment, ids); closestId != "" {
+							brokenLink.Repair += "#" + closestId
+						}
+					}
+				}
+
+				brokenLinks = append(brokenLinks, brokenLink)
+
+				continue
+			}
+
+			ids, isHtml := idsByFile[target]
+			if fragment == "" || !isHtml || slices.Contains(ids, fragment) {
+				continue
+			}
+
+			var brokenLink = BrokenLink{
+				Link:     link,
+				Target:   target,
+				Fragment: fragment,
+			}
+
+			if closestId := findClosestId(fragment, ids); closestId != "" {
+				brokenLink.Repair = targetPart + "#" + closestId
+			}
+
+			brokenLinks = append(brokenLinks, brokenLink)
+		}
+	}
+
+	return brokenLinks
+}
+
+// ResolveLink gets the path relative to the root of the epub and the fragment of the link
+func ResolveLink(file, link string) (string, string) {
+	target, fragment, _ := strings.Cut(link, "#")
+	if unescapedTarget, err := url.PathUnescape(target); err == nil {
+		target = unescapedTarget
+	}
+
+	if unescapedFragment, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescapedFragment
+	}
+
+	if target == "" {
+		return file, fragment
+	}
+
+	if strings.HasPrefix(target, "/") {
+		return path.Clean(strings.TrimPrefix(target, "/")), fragment
+	}
+
+	return path.Join(path.Dir(file), target), fragment
+}
+
+// GetRelativeLink gets the link to the target from the file where both paths are relative to the root of the epub
+func GetRelativeLink(file, target string) string {
+	relativePath, err := filepath.Rel(filepath.Dir(file), target)
+	if err != nil {
+		return target
+	}
+
+	return filepath.ToSlash(relativePath)
+}
+
+func isExternalLink(link string) bool {
+	if strings.HasPrefix(link, "//") {
+		return true
+	}
+
+	parsedUrl, err := url.Parse(link)
+
+	return err == nil && parsedUrl.Scheme != ""
+}
@@ -0,0 +1,105 @@
+//go:build unit
+
+package links_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/links"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chapterOne = `<html>
+<head><link href="../Styles/style.css" rel="stylesheet"/><style>p { background: url('../Images/bg.png'); }</style></head>
+<body>
+<h1 id="start">Chapter 1</h1>
+<p><a href="chapter2.xhtml#Chapter_Two">next</a> <a href="https://example.com">site</a> <a href="#Start">top</a></p>
+<p><img src="../Images/cover.jpg" alt=""/> <a href="chapter2.xhtml#completely-different">far</a></p>
+</body>
+</html>`
+	chapterTwo = `<html><body><h1 id="chapter-two">Chapter 2</h1><svg><image xlink:href="../cover.jpg"/></svg><a href="chapter%201.xhtml">spaced</a></body></html>`
+	styles     = `body { background: url(../Images/missing.png); }
+@font-face { src: url("../Fonts/font.ttf"); }`
+	ncx = `<ncx><navMap><navPoint><content src="Text/chapter1.xhtml#start"/></navPoint><navPoint><content src="Text/chapter3.xhtml"/></navPoint></navMap></ncx>`
+)
+
+func TestGetLinks(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []links.Link{
+		{File: "OEBPS/Styles/style.css", Value: "../Images/missing.png", Start: 23, Line: 1, Column: 24},
+		{File: "OEBPS/Styles/style.css", Value: "../Fonts/font.ttf", Start: 72, Line: 2, Column: 24},
+	}, links.GetLinks("OEBPS/Styles/style.css", styles))
+}
+
+func TestCheckLinks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		files = map[string]string{
+			"OEBPS/Text/chapter1.xhtml": chapterOne,
+			"OEBPS/Text/chapter2.xhtml": chapterTwo,
+			"OEBPS/Styles/style.css":    styles,
+			"OEBPS/toc.ncx":             ncx,
+		}
+		existingFiles = map[string]struct{}{
+			"OEBPS/Text/chapter1.xhtml":  {},
+			"OEBPS/Text/chapter2.xhtml":  {},
+			"OEBPS/Text/chapter 1.xhtml": {},
+			"OEBPS/Styles/style.css":     {},
+			"OEBPS/Images/bg.png":        {},
+			"OEBPS/Images/cover.jpg":     {},
+			"OEBPS/Fonts/font.ttf":       {},
+			"OEBPS/toc.ncx":              {},
+		}
+	)
+
+	brokenLinks := links.CheckLinks(files, existingFiles)
+	assert.Equal(t, []links.BrokenLink{
+		{
+			Link:        links.Link{File: "OEBPS/Styles/style.css", Value: "../Images/missing.png", Start: 23, Line: 1, Column: 24},
+			Target:      "OEBPS/Images/missing.png",
+			MissingFile: true,
+		},
+		{
+			Link:     links.Link{File: "OEBPS/Text/chapter1.xhtml", Value: "chapter2.xhtml#Chapter_Two", Start: 178, Line: 5, Column: 13},
+			Target:   "OEBPS/Text/chapter2.xhtml",
+			Fragment: "Chapter_Two",
+			Repair:   "chapter2.xhtml#chapter-two",
+		},
+		{
+			Link:     links.Link{File: "OEBPS/Text/chapter1.xhtml", Value: "#Start", Start: 263, Line: 5, Column: 98},
+			Target:   "OEBPS/Text/chapter1.xhtml",
+			Fragment: "Start",
+			Repair:   "#start",
+		},
+		{
+			Link:     links.Link{File: "OEBPS/Text/chapter1.xhtml", Value: "chapter2.xhtml#completely-different", Start: 335, Line: 6, Column: 53},
+			Target:   "OEBPS/Text/chapter2.xhtml",
+			Fragment: "completely-different",
+		},
+		{
+			Link:        links.Link{File: "OEBPS/Text/chapter2.xhtml", Value: "../cover.jpg", Start: 71, Line: 1, Column: 72},
+			Target:      "OEBPS/cover.jpg",
+			MissingFile: true,
+			Repair:      "../Images/cover.jpg",
+		},
+		{
+			Link:        links.Link{File: "OEBPS/toc.ncx", Value: "Text/chapter3.xhtml", Start: 100, Line: 1, Column: 101},
+			Target:      "OEBPS/Text/chapter3.xhtml",
+			MissingFile: true,
+		},
+	}, brokenLinks)
+
+	assert.Equal(t, `"chapter2.xhtml#Chapter_Two" points to id "Chapter_Two" which does not exist in "OEBPS/Text/chapter2.xhtml"`, brokenLinks[1].Message())
+	assert.Equal(t, `"../cover.jpg" points to "OEBPS/cover.jpg" which does not exist`, brokenLinks[4].Message())
+
+	updatedChapter, err := positions.ApplyEdits("OEBPS/Text/chapter1.xhtml", chapterOne, []positions.TextEdit{brokenLinks[1].Edit(chapterOne), brokenLinks[2].Edit(chapterOne), brokenLinks[3].Edit(chapterOne)})
+	require.NoError(t, err)
+	assert.Contains(t, updatedChapter, `<a href="chapter2.xhtml#chapter-two">next</a>`)
+	assert.Contains(t, updatedChapter, `<a href="#start">top</a>`)
+	assert.Contains(t, updatedChapter, `<a href="chapter2.xhtml#completely-different">far</a>`)
+}
@@ -0,0 +1,72 @@
+package links
+
+import (
+	"strings"
+	"unicode"
+)
+
+// findClosestId finds the id that the fragment was most likely meant to be.
+// It first looks for an id that only differs by case or punctuation and then for the id with the smallest
+// edit distance as long as the distance is small enough that it is unlikely to be a different id.
+// An empty string is returned when no id is close enough.
+func findClosestId(fragment string, ids []string) string {
+	var normalizedFragment = normalizeId(fragment)
+	for _, id := range ids {
+		if normalizeId(id) == normalizedFragment {
+			return id
+		}
+	}
+
+	var (
+		closestId       string
+		closestDistance = max(1, len(fragment)/3) + 1
+	)
+	for _, id := range ids {
+		var distance = getEditDistance(strings.ToLower(fragment), strings.ToLower(id))
+		if distance < closestDistance {
+			closestId = id
+			closestDistance = distance
+		}
+	}
+
+	return closestId
+}
+
+func normalizeId(id string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, id)
+}
+
+// getEditDistance gets the levenshtein distance between the two strings
+func getEditDistance(a, b string) int {
+	var (
+		aRunes   = []rune(a)
+		bRunes   = []rune(b)
+		previous = make([]int, len(bRunes)+1)
+		current  = make([]int, len(bRunes)+1)
+	)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(aRunes); i++ {
+		current[0] = i
+		for j := 1; j <= len(bRunes); j++ {
+			var substitutionCost = 1
+			if aRunes[i-1] == bRunes[j-1] {
+				substitutionCost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+substitutionCost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(bRunes)]
+}
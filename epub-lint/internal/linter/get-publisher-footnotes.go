@@ -0,0 +1,212 @@
+package linter
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// publisherNoteRefRegex matches superscript number links like <a href="notes.xhtml#n1"><sup>1</sup></a> or <sup><a href="#fn1">[1]</a></sup>
+	publisherNoteRefRegex = regexp.MustCompile(`(?i)<sup[^>]*>\s*<a\s[^>]*?href="([^"]*#[^"]+)"[^>]*>\s*\[?\d+\]?\s*</a>\s*</sup>|<a\s[^>]*?href="([^"]*#[^"]+)"[^>]*>\s*<sup[^>]*>\s*\[?\d+\]?\s*</sup>\s*</a>`)
+	anchorRegex           = regexp.MustCompile(`(?is)<a(\s[^>]*)?>(.*?)</a>`)
+	leadingNoteNumber     = regexp.MustCompile(`^\s*(?:\[\d+\]|\d+[.)]?)(?:\s|$)`)
+	leadingNoteSeparator  = regexp.MustCompile(`^\s*[.):]\s+`)
+	trailingLineBreaks    = regexp.MustCompile(`(?i)(\s*<br\s*/?>)+\s*$`)
+	backlinkTexts         = []string{"↩", "↑", "^", "back", "return"}
+	noteBlockElements     = []string{"p", "li", "div", "aside", "dd"}
+)
+
+type publisherNoteRef struct {
+	Start, End int
+	Href       string
+}
+
+// GetPublisherNoteHrefs gets the hrefs of the publisher footnote references in the text
+// which are superscript numbers that link to a note elsewhere in the epub
+func GetPublisherNoteHrefs(text string) []string {
+	var hrefs []string
+	for _, ref := range findPublisherNoteRefs(text) {
+		hrefs = append(hrefs, ref.Href)
+	}
+
+	return hrefs
+}
+
+// RemovePublisherNote removes the note element with the specified id from the text returning the updated text,
+// the contents of the note without its backlinks or leading number, and whether or not the note was found
+func RemovePublisherNote(text, id string) (string, string, bool) {
+	var idIndex = strings.Index(text, `id="`+id+`"`)
+	if idIndex == -1 {
+		idIndex = strings.Index(text, `id='`+id+`'`)
+		if idIndex == -1 {
+			return text, "", false
+		}
+	}
+
+	var (
+		elStart = strings.LastIndex(text[:idIndex], "<")
+		tagName = getTagName(text[elStart:])
+	)
+	if !slices.Contains(noteBlockElements, tagName) {
+		elStart, tagName = getEnclosingNoteBlock(text, elStart)
+		if elStart == -1 {
+			return text, "", false
+		}
+	}
+
+	var openingTagEnd = strings.Index(text[elStart:], ">")
+	if openingTagEnd == -1 {
+		return text, "", false
+	}
+	openingTagEnd += elStart + 1
+
+	var elEnd, closingTagStart = getElementEnd(text, tagName, openingTagEnd)
+	if elEnd == -1 {
+		return text, "", false
+	}
+
+	var content = cleanupPublisherNoteContent(text[openingTagEnd:closingTagStart])
+
+	return removeAndCleanupLine(text, elStart, elEnd), content, true
+}
+
+func findPublisherNoteRefs(text string) []publisherNoteRef {
+	var refs []publisherNoteRef
+	for _, indices := range publisherNoteRefRegex.FindAllStringSubmatchIndex(text, -1) {
+		var ref = publisherNoteRef{
+			Start: indices[0],
+			End:   indices[1],
+		}
+
+		if indices[2] != -1 {
+			ref.Href = text[indices[2]:indices[3]]
+		} else {
+			ref.Href = text[indices[4]:indices[5]]
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+func cleanupPublisherNoteContent(content string) string {
+	content = anchorRegex.ReplaceAllStringFunc(content, func(anchor string) string {
+		var (
+			parts     = anchorRegex.FindStringSubmatch(anchor)
+			attrs     = parts[1]
+			innerText = strings.TrimSpace(HtmlTagRegex.ReplaceAllString(parts[2], ""))
+		)
+		if innerText == "" || (strings.Contains(attrs, "#") && isBacklinkText(innerText)) {
+			return ""
+		}
+
+		return anchor
+	})
+
+	content = leadingNoteNumber.ReplaceAllString(content, "")
+	content = leadingNoteSeparator.ReplaceAllString(content, "")
+	content = trailingLineBreaks.ReplaceAllString(content, "")
+
+	return strings.TrimSpace(content)
+}
+
+func isBacklinkText(text string) bool {
+	if leadingNoteNumber.MatchString(text + " ") {
+		return true
+	}
+
+	var lowerText = strings.ToLower(text)
+	for _, backlinkText := range backlinkTexts {
+		if strings.HasPrefix(lowerText, backlinkText) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getTagName(tag string) string {
+	var end = strings.IndexAny(tag, " \t\r\n/>")
+	if end == -1 {
+		return ""
+	}
+
+	return strings.ToLower(tag[1:end])
+}
+
+// getEnclosingNoteBlock gets the start and tag name of the closest block element that starts before the provided index
+func getEnclosingNoteBlock(text string, index int) (int, string) {
+	var (
+		start   = -1
+		tagName string
+	)
+	for _, blockEl := range noteBlockElements {
+		for _, openingTag := range []string{"<" + blockEl + " ", "<" + blockEl + ">"} {
+			var elStart = strings.LastIndex(text[:index], openingTag)
+			if elStart > start {
+				start = elStart
+				tagName = blockEl
+			}
+		}
+	}
+
+	return start, tagName
+}
+
+// getElementEnd gets the end of the closing tag and the start of the closing tag for the element
+// with the provided tag name whose contents start at the provided index
+func getElementEnd(text, tagName string, contentStart int) (int, int) {
+	var (
+		depth      = 1
+		closingTag = "</" + tagName + ">"
+		i          = contentStart
+	)
+	for i < len(text) {
+		var nextTag = strings.Index(text[i:], "<")
+		if nextTag == -1 {
+			return -1, -1
+		}
+		i += nextTag
+
+		if strings.HasPrefix(text[i:], closingTag) {
+			depth--
+			if depth == 0 {
+				return i + len(closingTag), i
+			}
+		} else if getTagName(text[i:]) == tagName {
+			var tagEnd = strings.Index(text[i:], ">")
+			if tagEnd != -1 && text[i+tagEnd-1] != '/' {
+				depth++
+			}
+		}
+
+		i++
+	}
+
+	return -1, -1
+}
+
+// removeAndCleanupLine removes the text between start and end and removes the line it was on if it is now empty
+func removeAndCleanupLine(text string, start, end int) string {
+	var (
+		lineStart = strings.LastIndex(text[:start], "\n") + 1
+		lineEnd   = strings.Index(text[end:], "\n")
+	)
+	if lineEnd == -1 {
+		lineEnd = len(text)
+	} else {
+		lineEnd += end
+	}
+
+	if strings.TrimSpace(text[lineStart:start]) == "" && strings.TrimSpace(text[end:lineEnd]) == "" {
+		if lineEnd < len(text) {
+			lineEnd++
+		}
+
+		return text[:lineStart] + text[lineEnd:]
+	}
+
+	return text[:start] + text[end:]
+}
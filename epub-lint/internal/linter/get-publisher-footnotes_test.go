@@ -0,0 +1,78 @@
+//go:build unit
+
+package linter_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+	"github.com/stretchr/testify/assert"
+)
+
+type removePublisherNoteTestCase struct {
+	inputText       string
+	id              string
+	expectedText    string
+	expectedContent string
+	expectedFound   bool
+}
+
+var removePublisherNoteTestCases = map[string]removePublisherNoteTestCase{
+	"a note id that is not present in the text is not found": {
+		inputText:     `<p id="fn2">2. Some note.</p>`,
+		id:            "fn1",
+		expectedText:  `<p id="fn2">2. Some note.</p>`,
+		expectedFound: false,
+	},
+	"a list item note has its backlink and trailing line break removed along with the line it was on": {
+		inputText: `<ol>
+  <li id="fn1">Some <i>note</i>.<br/><a href="chapter1.xhtml#ref1">↩</a></li>
+  <li id="fn2">Another note.</li>
+</ol>`,
+		id: "fn1",
+		expectedText: `<ol>
+  <li id="fn2">Another note.</li>
+</ol>`,
+		expectedContent: "Some <i>note</i>.",
+		expectedFound:   true,
+	},
+	"an anchor id inside of a paragraph removes the whole paragraph and the number link at the start of the note": {
+		inputText: `<p class="note"><a id="n1"></a><a href="chapter1.xhtml#r1">1</a>. A note with <a href="https://example.com">a link</a>.</p>
+<p>Other content</p>`,
+		id:              "n1",
+		expectedText:    `<p>Other content</p>`,
+		expectedContent: `A note with <a href="https://example.com">a link</a>.`,
+		expectedFound:   true,
+	},
+	"a note with nested divs and a leading number removes the full note": {
+		inputText:       `<div id="fn3"><div>[3] A nested note.</div></div><p>After</p>`,
+		id:              "fn3",
+		expectedText:    `<p>After</p>`,
+		expectedContent: `<div>[3] A nested note.</div>`,
+		expectedFound:   true,
+	},
+}
+
+func TestRemovePublisherNote(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range removePublisherNoteTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actualText, actualContent, actualFound := linter.RemovePublisherNote(args.inputText, args.id)
+
+			assert.Equal(t, args.expectedText, actualText)
+			assert.Equal(t, args.expectedContent, actualContent)
+			assert.Equal(t, args.expectedFound, actualFound)
+		})
+	}
+}
+
+func TestGetPublisherNoteHrefs(t *testing.T) {
+	t.Parallel()
+
+	actual := linter.GetPublisherNoteHrefs(`<p>Text<a href="notes.xhtml#fn1"><sup>1</sup></a> and<sup class="ref"><a id="r2" href="#fn2">[2]</a></sup> but not <a href="chapter2.xhtml#start">a link</a> or <sup>3</sup>.</p>`)
+
+	assert.Equal(t, []string{"notes.xhtml#fn1", "#fn2"}, actual)
+}
@@ -11,49 +11,257 @@ import (
 	"unicode/utf8"
 )
 
-// these values are lowercased because that makes the checks later on more performant since we don't need
-// to lowercase them
-var noteIndicators = []string{"tl note:", "translator's note:", "t/n:", "tn:", "tln:", "tl:", "author's note:", "note:", "ed:"}
+var (
+	// these values are lowercased because that makes the checks later on more performant since we don't need
+	// to lowercase them
+	noteIndicators = []string{"tl note:", "translator's note:", "t/n:", "tn:", "tln:", "tl:", "author's note:", "note:", "ed:"}
+	textEscaper    = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+const (
+	epubNamespace       = `xmlns:epub="http://www.idpf.org/2007/ops"`
+	DefaultBacklinkText = "Back to Reference"
+)
+
+type NoteStyle string
+
+const (
+	// EndnotesStyle links to notes in a list in the notes file with plain links
+	EndnotesStyle NoteStyle = "endnotes"
+	// PopupStyle links to notes in the notes file using epub:type="noteref" and epub:type="footnote" asides
+	// so that readers that support it can show the note in a popup
+	PopupStyle NoteStyle = "popup"
+	// InlineAsideStyle keeps notes in the file they are referenced in as epub:type="footnote" asides
+	// right after the element they are referenced in
+	InlineAsideStyle NoteStyle = "inline-aside"
+)
+
+var NoteStyles = []string{string(EndnotesStyle), string(PopupStyle), string(InlineAsideStyle)}
+
+type NoteOptions struct {
+	// Style is how the notes get written out which defaults to endnotes
+	Style NoteStyle
+	// PublisherNotes is the contents of publisher footnotes by the href used to reference them
+	// which lets existing footnotes get converted to the same structure as translator's notes
+	PublisherNotes map[string]string
+	// Indicators are the case insensitive text that starts a translator's note which defaults to the English indicators
+	Indicators []string
+	// BacklinkText is the text of the link back to the note reference which defaults to "Back to Reference"
+	BacklinkText string
+	// ExcludedNotes is the indexes of the translator's notes in the text that should be left alone
+	ExcludedNotes map[int]struct{}
+}
+
+// EscapeText escapes the characters that are not allowed as is in the text of an element
+func EscapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// GetNoteIndicators gets the lowercased indicators to use for finding translator's notes
+// falling back to the default indicators when none are provided
+func GetNoteIndicators(indicators []string) []string {
+	var lowercaseIndicators = make([]string, 0, len(indicators))
+	for _, indicator := range indicators {
+		indicator = strings.ToLower(strings.TrimSpace(indicator))
+		if indicator != "" {
+			lowercaseIndicators = append(lowercaseIndicators, indicator)
+		}
+	}
+
+	if len(lowercaseIndicators) == 0 {
+		return noteIndicators
+	}
+
+	return lowercaseIndicators
+}
+
+// FindTranslatorsNotes gets the contents of the translator's notes in the text in the order they appear
+// which is the order used for the indexes of excluded notes
+func FindTranslatorsNotes(text, fileName string, indicators []string) ([]string, error) {
+	matches, err := findNotesWithXML(text, GetNoteIndicators(indicators))
+	if err != nil {
+		return nil, fmt.Errorf("file %q had issues determining translator's notes: %w", fileName, err)
+	}
 
-func GetTranslatorsNotes(text, fileName, noteFileName string, startingNoteNumber int) (string, []string, int, error) {
-	matches, err := findNotesWithXML(text)
+	var notes = make([]string, len(matches))
+	for i, match := range matches {
+		notes[i] = match.Content
+	}
+
+	return notes, nil
+}
+
+// GetTranslatorsNotes replaces translator's notes and referenced publisher footnotes in the text with references to the notes
+// returning the updated text, the notes to add to the notes file, and the number of the last note in the text.
+// Notes written out in the inline-aside style are left in the text, so no notes are returned for the notes file.
+func GetTranslatorsNotes(text, fileName, noteFileName string, startingNoteNumber int, options NoteOptions) (string, []string, int, error) {
+	matches, err := findNotesWithXML(text, GetNoteIndicators(options.Indicators))
 	if err != nil {
 		return "", []string{}, 0, fmt.Errorf("file %q had issues determining translator's notes: %w", fileName, err)
 	}
 
+	if len(options.ExcludedNotes) != 0 {
+		var includedMatches = make([]noteMatch, 0, len(matches))
+		for i, match := range matches {
+			if _, excluded := options.ExcludedNotes[i]; !excluded {
+				includedMatches = append(includedMatches, match)
+			}
+		}
+
+		matches = includedMatches
+	}
+
+	matches = addPublisherNotes(text, matches, options.PublisherNotes)
 	if len(matches) == 0 {
 		return text, []string{}, startingNoteNumber, nil
 	}
 
-	var tlNotes = make([]string, len(matches))
+	var backlinkText = DefaultBacklinkText
+	if options.BacklinkText != "" {
+		backlinkText = EscapeText(options.BacklinkText)
+	}
+
+	var tlNotes = make([]string, 0, len(matches))
 	slices.Reverse(matches)
 
 	startingNoteNumber += len(matches)
 	noteNum := startingNoteNumber
 
-	for i, match := range matches {
+	for _, match := range matches {
 		refId := fmt.Sprintf("note_ref_%d", noteNum)
 		noteId := fmt.Sprintf("tl_note_%d", noteNum)
-		noteAnchor := fmt.Sprintf(`<a id=%q href="%s#%s"><sup>%d</sup></a>`, refId, noteFileName, noteId, noteNum)
 
-		tlNotes[i] = fmt.Sprintf(`<li id=%q>%s<br/><a href="%s#%s">Back to Reference</a></li>`+"\n",
-			noteId, match.Content, fileName, refId)
+		switch options.Style {
+		case PopupStyle:
+			noteAnchor := fmt.Sprintf(`<a id=%q epub:type="noteref" href="%s#%s"><sup>%d</sup></a>`, refId, noteFileName, noteId, noteNum)
+			tlNotes = append(tlNotes, fmt.Sprintf(`<aside id=%q epub:type="footnote">%s<p><a href="%s#%s">%s</a></p></aside>`+"\n",
+				noteId, wrapInParagraph(match.Content), fileName, refId, backlinkText))
+
+			text = text[:match.Start] + noteAnchor + text[match.End:]
+		case InlineAsideStyle:
+			noteAnchor := fmt.Sprintf(`<a id=%q epub:type="noteref" href="#%s"><sup>%d</sup></a>`, refId, noteId, noteNum)
+			aside := fmt.Sprintf(`<aside id=%q epub:type="footnote">%s</aside>`, noteId, wrapInParagraph(match.Content))
+
+			insertPos := getInlineAsidePos(text, match.End)
+			text = text[:match.Start] + noteAnchor + text[match.End:insertPos] + "\n" + aside + text[insertPos:]
+		default:
+			noteAnchor := fmt.Sprintf(`<a id=%q href="%s#%s"><sup>%d</sup></a>`, refId, noteFileName, noteId, noteNum)
+			tlNotes = append(tlNotes, fmt.Sprintf(`<li id=%q>%s<br/><a href="%s#%s">%s</a></li>`+"\n",
+				noteId, match.Content, fileName, refId, backlinkText))
+
+			text = text[:match.Start] + noteAnchor + text[match.End:]
+		}
 
-		text = text[:match.Start] + noteAnchor + text[match.End:]
 		noteNum--
 	}
 
+	if options.Style == PopupStyle || options.Style == InlineAsideStyle {
+		text = AddEpubNamespace(text)
+	}
+
 	slices.Reverse(tlNotes)
 	return text, tlNotes, startingNoteNumber, nil
 }
 
+// addPublisherNotes adds the publisher footnote references that have a note to the matches
+// keeping the matches in the order they appear in the text
+func addPublisherNotes(text string, matches []noteMatch, publisherNotes map[string]string) []noteMatch {
+	if len(publisherNotes) == 0 {
+		return matches
+	}
+
+	var addedNote bool
+	for _, ref := range findPublisherNoteRefs(text) {
+		content, ok := publisherNotes[ref.Href]
+		if !ok {
+			continue
+		}
+
+		var overlapsNote bool
+		for _, match := range matches {
+			if ref.Start < match.End && match.Start < ref.End {
+				overlapsNote = true
+				break
+			}
+		}
+
+		if !overlapsNote {
+			matches = append(matches, noteMatch{
+				Start:   ref.Start,
+				End:     ref.End,
+				Content: content,
+			})
+			addedNote = true
+		}
+	}
+
+	if addedNote {
+		slices.SortFunc(matches, func(a, b noteMatch) int {
+			return a.Start - b.Start
+		})
+	}
+
+	return matches
+}
+
+// getInlineAsidePos gets the position right after the element the note was in.
+// Notes are handled in reverse order, so inserting right after the element keeps the asides in the order of their references.
+func getInlineAsidePos(text string, noteEnd int) int {
+	var insertPos = -1
+	for _, closingTag := range []string{"</p>", "</div>", "</li>", "</blockquote>"} {
+		var closingTagPos = strings.Index(text[noteEnd:], closingTag)
+		if closingTagPos != -1 && (insertPos == -1 || noteEnd+closingTagPos+len(closingTag) < insertPos) {
+			insertPos = noteEnd + closingTagPos + len(closingTag)
+		}
+	}
+
+	if insertPos == -1 {
+		insertPos = strings.Index(text[noteEnd:], "</body>")
+		if insertPos == -1 {
+			return len(text)
+		}
+
+		return noteEnd + insertPos
+	}
+
+	return insertPos
+}
+
+func wrapInParagraph(content string) string {
+	if strings.Contains(content, "<p") {
+		return content
+	}
+
+	return "<p>" + content + "</p>"
+}
+
+// AddEpubNamespace adds the epub namespace to the html element if it is not already present
+func AddEpubNamespace(text string) string {
+	if strings.Contains(text, "xmlns:epub=") {
+		return text
+	}
+
+	var htmlTagStart = strings.Index(text, "<html")
+	if htmlTagStart == -1 {
+		return text
+	}
+
+	var htmlTagEnd = strings.Index(text[htmlTagStart:], ">")
+	if htmlTagEnd == -1 {
+		return text
+	}
+	htmlTagEnd += htmlTagStart
+
+	return text[:htmlTagEnd] + " " + epubNamespace + text[htmlTagEnd:]
+}
+
 type noteMatch struct {
 	Start   int
 	End     int
 	Content string
 }
 
-func findNotesWithXML(text string) ([]noteMatch, error) {
+func findNotesWithXML(text string, indicators []string) ([]noteMatch, error) {
 	var matches []noteMatch
 	decoder := xml.NewDecoder(strings.NewReader(text))
 	decoder.Strict = false
@@ -81,7 +289,7 @@ func findNotesWithXML(text string) ([]noteMatch, error) {
 				innerContent, textOnlyContent, _ = getInnerContent(decoder)
 			}
 
-			indicator, tlNotePos := translatorNoteIndicatorPosInfo(innerContent)
+			indicator, tlNotePos := translatorNoteIndicatorPosInfo(innerContent, indicators)
 			if tlNotePos == -1 {
 				continue
 			}
@@ -157,12 +365,12 @@ func getInnerContent(decoder *xml.Decoder) (string, string, bool) {
 	return content.String(), textOnly.String(), false
 }
 
-func translatorNoteIndicatorPosInfo(text string) (string, int) {
+func translatorNoteIndicatorPosInfo(text string, indicators []string) (string, int) {
 	var (
 		lowerText = strings.ToLower(text)
 		pos       int
 	)
-	for _, indicator := range noteIndicators {
+	for _, indicator := range indicators {
 		pos = strings.Index(lowerText, indicator)
 		if pos != -1 {
 			return indicator, pos
@@ -183,7 +391,7 @@ func extractNoteContent(indicator, innerElContent, textOnlyContent string, indic
 
 	// If indicator at start, return all
 	if startOfTextNote == 0 {
-		if innerElContent[startOfNote] == ' ' {
+		if startOfNote < len(innerElContent) && innerElContent[startOfNote] == ' ' {
 			startOfNote++
 		}
 
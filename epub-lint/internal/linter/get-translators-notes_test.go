@@ -16,6 +16,7 @@ type getTranslatorsNotesTestCase struct {
 	fileName       string
 	noteFileName   string
 	startingNumber int
+	options        linter.NoteOptions
 	expectedText   string
 	expectedNotes  []string
 	expectedNext   int
@@ -112,6 +113,99 @@ var testCases = map[string]getTranslatorsNotesTestCase{
 		expectedText:   `<p>　Audrey is the legitimate wife of the Prince, in other words, the Princess. <a id="note_ref_1" href="notes.xhtml#tl_note_1"><sup>1</sup></a></p>`,
 		expectedNotes: []string{
 			`<li id="tl_note_1">it says queen (王妃 'ohi'), but I didn't feel like using it, so changed it to Princess, suggestions are welcomed though.<br/><a href="main.xhtml#note_ref_1">Back to Reference</a></li>
+`,
+		},
+		expectedNext: 1,
+	},
+	"the popup style uses noterefs and footnote asides and adds the epub namespace to the file": {
+		inputText: `<html xmlns="http://www.w3.org/1999/xhtml">
+<body>
+<p>Some content before.</p>
+<p>TL Note: First author note.</p>
+</body>
+</html>`,
+		fileName:       "currentfile.xhtml",
+		noteFileName:   "notes.xhtml",
+		startingNumber: 0,
+		options: linter.NoteOptions{
+			Style: linter.PopupStyle,
+		},
+		expectedText: `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<body>
+<p>Some content before.</p>
+<p><a id="note_ref_1" epub:type="noteref" href="notes.xhtml#tl_note_1"><sup>1</sup></a></p>
+</body>
+</html>`,
+		expectedNotes: []string{
+			`<aside id="tl_note_1" epub:type="footnote"><p>First author note.</p><p><a href="currentfile.xhtml#note_ref_1">Back to Reference</a></p></aside>
+`,
+		},
+		expectedNext: 1,
+	},
+	"the inline-aside style keeps the notes in the file after the element they were in and in the order they were found": {
+		inputText: `<p>Some content before.<a href="#fn1"><sup>1</sup></a> More content. (TL Note: Second author note.)</p>
+<p>Some content after.</p>`,
+		fileName:       "currentfile.xhtml",
+		noteFileName:   "notes.xhtml",
+		startingNumber: 2,
+		options: linter.NoteOptions{
+			Style: linter.InlineAsideStyle,
+			PublisherNotes: map[string]string{
+				"#fn1": "First publisher note.",
+			},
+		},
+		expectedText: `<p>Some content before.<a id="note_ref_3" epub:type="noteref" href="#tl_note_3"><sup>3</sup></a> More content. <a id="note_ref_4" epub:type="noteref" href="#tl_note_4"><sup>4</sup></a></p>
+<aside id="tl_note_3" epub:type="footnote"><p>First publisher note.</p></aside>
+<aside id="tl_note_4" epub:type="footnote"><p>Second author note.</p></aside>
+<p>Some content after.</p>`,
+		expectedNotes: []string{},
+		expectedNext:  4,
+	},
+	"publisher footnotes with a note are converted in the order they appear with translator's notes": {
+		inputText: `<p>Some content<a href="notes.html#fn1"><sup>1</sup></a> before.</p>
+<p>TL Note: First author note.</p>
+<p>Some content<sup><a href="notes.html#fn2">[2]</a></sup> after.</p>
+<p>A link to somewhere else<a href="other.html#fn3"><sup>3</sup></a>.</p>`,
+		fileName:       "currentfile.xhtml",
+		noteFileName:   "notes.xhtml",
+		startingNumber: 0,
+		options: linter.NoteOptions{
+			PublisherNotes: map[string]string{
+				"notes.html#fn1": "First publisher note.",
+				"notes.html#fn2": "Second publisher note.",
+			},
+		},
+		expectedText: `<p>Some content<a id="note_ref_1" href="notes.xhtml#tl_note_1"><sup>1</sup></a> before.</p>
+<p><a id="note_ref_2" href="notes.xhtml#tl_note_2"><sup>2</sup></a></p>
+<p>Some content<a id="note_ref_3" href="notes.xhtml#tl_note_3"><sup>3</sup></a> after.</p>
+<p>A link to somewhere else<a href="other.html#fn3"><sup>3</sup></a>.</p>`,
+		expectedNotes: []string{
+			`<li id="tl_note_1">First publisher note.<br/><a href="currentfile.xhtml#note_ref_1">Back to Reference</a></li>
+`,
+			`<li id="tl_note_2">First author note.<br/><a href="currentfile.xhtml#note_ref_2">Back to Reference</a></li>
+`,
+			`<li id="tl_note_3">Second publisher note.<br/><a href="currentfile.xhtml#note_ref_3">Back to Reference</a></li>
+`,
+		},
+		expectedNext: 3,
+	},
+	"custom indicators and backlink text are used and excluded notes are left alone": {
+		inputText: `<p>Nota: this is a normal sentence.</p>
+<p>TL Note: This is not an indicator for this run.</p>
+<p>Texto (N. del T.: Una nota del traductor.) más texto.</p>`,
+		fileName:       "capitulo.xhtml",
+		noteFileName:   "notas.xhtml",
+		startingNumber: 0,
+		options: linter.NoteOptions{
+			Indicators:    []string{"N. del T.:", " Nota: "},
+			BacklinkText:  "Volver & continuar",
+			ExcludedNotes: map[int]struct{}{0: {}},
+		},
+		expectedText: `<p>Nota: this is a normal sentence.</p>
+<p>TL Note: This is not an indicator for this run.</p>
+<p>Texto <a id="note_ref_1" href="notas.xhtml#tl_note_1"><sup>1</sup></a> más texto.</p>`,
+		expectedNotes: []string{
+			`<li id="tl_note_1">Una nota del traductor.<br/><a href="capitulo.xhtml#note_ref_1">Volver &amp; continuar</a></li>
 `,
 		},
 		expectedNext: 1,
@@ -134,7 +228,7 @@ func TestGetTranslatorsNotes(t *testing.T) {
 	for name, args := range testCases {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			updatedText, notes, next, err := linter.GetTranslatorsNotes(args.inputText, args.fileName, args.noteFileName, args.startingNumber)
+			updatedText, notes, next, err := linter.GetTranslatorsNotes(args.inputText, args.fileName, args.noteFileName, args.startingNumber, args.options)
 
 			if args.expectedError != nil {
 				require.Error(t, err)
@@ -149,3 +243,14 @@ func TestGetTranslatorsNotes(t *testing.T) {
 		})
 	}
 }
+
+func TestFindTranslatorsNotes(t *testing.T) {
+	t.Parallel()
+
+	actual, err := linter.FindTranslatorsNotes(`<p>Texto (N. del T.: Una nota.) más texto.</p>
+<p>Note: not an indicator for this run.</p>
+<p>Nota: otra nota.</p>`, "capitulo.xhtml", []string{"n. del t.:", "nota:"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Una nota.", "otra nota."}, actual)
+}
@@ -0,0 +1,6 @@
+package linter
+
+import "regexp"
+
+// HtmlTagRegex matches any html tag which is useful for stripping the tags from contents to get their text
+var HtmlTagRegex = regexp.MustCompile(`<[^>]*>`)
@@ -0,0 +1,135 @@
+package linter
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// EnglishLanguages are the languages that rules depending on English words or grammar apply to
+	EnglishLanguages = []string{"en"}
+	// LatinScriptLanguages are the common languages written in the Latin script that rules depending on Latin letters,
+	// spacing, or punctuation apply to
+	LatinScriptLanguages = []string{"en", "es", "fr", "de", "it", "pt", "nl", "pl", "cs", "ro", "sv", "da", "no", "nb", "nn", "fi", "tr", "id", "ms", "tl", "vi"}
+	// CjkLanguages are the Chinese, Japanese, and Korean languages which can use ruby annotations and vertical writing
+	CjkLanguages = []string{"ja", "zh", "ko"}
+
+	htmlElRegex      = regexp.MustCompile(`(?i)<html\b[^>]*>`)
+	xmlLangRegex     = regexp.MustCompile(`\sxml:lang\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	langRegex        = regexp.MustCompile(`\slang\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	elementTagRegex  = regexp.MustCompile(`<(/?)([a-zA-Z][\w:-]*)\b([^>]*?)(/?)>`)
+	voidElementNames = []string{"area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr"}
+)
+
+// GetLanguage gets the language of the file from the xml:lang or lang attribute of its html element
+// returning an empty string when neither is set
+func GetLanguage(text string) string {
+	var htmlEl = htmlElRegex.FindString(text)
+	if htmlEl == "" {
+		return ""
+	}
+
+	return getLangAttribute(htmlEl)
+}
+
+// LanguageMatches returns whether the primary subtag of the language is one of the languages
+// which is always the case when the language is unknown or there are no languages to match
+func LanguageMatches(lang string, languages []string) bool {
+	if lang == "" || len(languages) == 0 {
+		return true
+	}
+
+	var primarySubtag, _, _ = strings.Cut(strings.ToLower(strings.ReplaceAll(lang, "_", "-")), "-")
+
+	return slices.Contains(languages, primarySubtag)
+}
+
+// ApplyToLanguage runs replace on the parts of the text that are in one of the languages.
+// When the file's language, or the default language when the file has none, is not one of the languages the text is left as is.
+// Otherwise, ruby annotations and elements with a lang or xml:lang that is not one of the languages are left as is.
+func ApplyToLanguage(text, defaultLang string, languages []string, replace func(string) string) string {
+	var lang = GetLanguage(text)
+	if lang == "" {
+		lang = defaultLang
+	}
+
+	if !LanguageMatches(lang, languages) {
+		return text
+	}
+
+	var (
+		newText        strings.Builder
+		openElements   []string
+		protectedDepth = -1
+		protectedStart int
+		lastEnd        int
+	)
+	for _, indices := range elementTagRegex.FindAllStringSubmatchIndex(text, -1) {
+		var (
+			isClosing     = indices[3] != indices[2]
+			name          = strings.ToLower(text[indices[4]:indices[5]])
+			isSelfClosing = indices[9] != indices[8] || slices.Contains(voidElementNames, name)
+		)
+		if isClosing {
+			var openIndex = -1
+			for i := len(openElements) - 1; i >= 0; i-- {
+				if openElements[i] == name {
+					openIndex = i
+					break
+				}
+			}
+
+			if openIndex == -1 {
+				continue
+			}
+
+			openElements = openElements[:openIndex]
+			if protectedDepth != -1 && len(openElements) <= protectedDepth {
+				newText.WriteString(replace(text[lastEnd:protectedStart]))
+				newText.WriteString(text[protectedStart:indices[1]])
+				lastEnd = indices[1]
+				protectedDepth = -1
+			}
+
+			continue
+		}
+
+		if isSelfClosing {
+			continue
+		}
+
+		if protectedDepth == -1 && name != "html" {
+			var elementLang = getLangAttribute(text[indices[0]:indices[1]])
+			if name == "ruby" || (elementLang != "" && !LanguageMatches(elementLang, languages)) {
+				protectedDepth = len(openElements)
+				protectedStart = indices[0]
+			}
+		}
+
+		openElements = append(openElements, name)
+	}
+
+	if protectedDepth != -1 {
+		newText.WriteString(replace(text[lastEnd:protectedStart]))
+		newText.WriteString(text[protectedStart:])
+
+		return newText.String()
+	}
+
+	newText.WriteString(replace(text[lastEnd:]))
+
+	return newText.String()
+}
+
+func getLangAttribute(tag string) string {
+	for _, attributeRegex := range []*regexp.Regexp{xmlLangRegex, langRegex} {
+		if groups := attributeRegex.FindStringSubmatch(tag); groups != nil {
+			if lang := strings.TrimSpace(groups[1] + groups[2]); lang != "" {
+				return lang
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,73 @@
+//go:build unit
+
+package linter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+	"github.com/stretchr/testify/assert"
+)
+
+type applyToLanguageTestCase struct {
+	inputText    string
+	defaultLang  string
+	expectedText string
+}
+
+var applyToLanguageTestCases = map[string]applyToLanguageTestCase{
+	"a file in a matching language has all of its text replaced": {
+		inputText:    `<html lang="en-US"><body><p>text</p></body></html>`,
+		expectedText: `<html lang="en-US"><body><p>TEXT</p></body></html>`,
+	},
+	"a file in a language that does not match is left as is": {
+		inputText:    `<html xml:lang="ja" lang="ja"><body><p>text</p></body></html>`,
+		expectedText: `<html xml:lang="ja" lang="ja"><body><p>text</p></body></html>`,
+	},
+	"a file without a language uses the default language": {
+		inputText:    `<html><body><p>text</p></body></html>`,
+		defaultLang:  "ja",
+		expectedText: `<html><body><p>text</p></body></html>`,
+	},
+	"ruby annotations and elements in other languages are left as is": {
+		inputText:    `<html lang="en"><body><p>text <ruby>kan<rt>ji</rt></ruby> <span lang="ja">text <b>bold</b></span> <span>text</span><br/></p></body></html>`,
+		expectedText: `<html lang="en"><body><p>TEXT <ruby>kan<rt>ji</rt></ruby> <span lang="ja">text <b>bold</b></span> <span>TEXT</span><br/></p></body></html>`,
+	},
+	"an unclosed element in another language leaves the rest of the text as is": {
+		inputText:    `<html><body><p>text</p><div lang="ja">text</body></html>`,
+		expectedText: `<html><body><p>TEXT</p><div lang="ja">text</body></html>`,
+	},
+}
+
+func TestApplyToLanguage(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range applyToLanguageTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, args.expectedText, linter.ApplyToLanguage(args.inputText, args.defaultLang, linter.LatinScriptLanguages, func(text string) string {
+				return strings.ReplaceAll(text, "text", "TEXT")
+			}))
+		})
+	}
+}
+
+func TestGetLanguage(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "ja", linter.GetLanguage(`<html lang="en" xml:lang="ja">`))
+	assert.Equal(t, "en", linter.GetLanguage(`<html xmlns="http://www.w3.org/1999/xhtml" lang="en">`))
+	assert.Equal(t, "", linter.GetLanguage(`<html><body lang="en">`))
+}
+
+func TestLanguageMatches(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, linter.LanguageMatches("en-GB", linter.EnglishLanguages))
+	assert.True(t, linter.LanguageMatches("EN_us", linter.EnglishLanguages))
+	assert.True(t, linter.LanguageMatches("", linter.EnglishLanguages))
+	assert.True(t, linter.LanguageMatches("ja", nil))
+	assert.False(t, linter.LanguageMatches("ja-JP", linter.LatinScriptLanguages))
+}
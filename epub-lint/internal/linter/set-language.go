@@ -1,7 +1,10 @@
 package linter
 
 import (
+	"regexp"
 	"strings"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
 )
 
 const (
@@ -10,6 +13,42 @@ const (
 	openingHtmlTag   = "<html"
 )
 
+var dcLanguageRegex = regexp.MustCompile(`(?i)<dc:language\b[^>]*>([^<]*)</dc:language>`)
+
+// GetOpfLanguage gets the first dc:language of the opf file returning an empty string when there is none
+func GetOpfLanguage(opfContents string) string {
+	if groups := dcLanguageRegex.FindStringSubmatch(opfContents); groups != nil {
+		return strings.TrimSpace(groups[1])
+	}
+
+	return ""
+}
+
+// EnsureOpfLanguageIsSet sets the language of the opf file when its dc:language is empty or adds
+// a dc:language to its metadata when it does not have one
+func EnsureOpfLanguageIsSet(opfContents, lang string) string {
+	opf, err := epubdoc.ParseOpf(opfContents)
+	if err != nil {
+		return opfContents
+	}
+
+	if languages := opf.MetadataElements("dc:language"); len(languages) != 0 {
+		if strings.TrimSpace(languages[0].Text()) != "" {
+			return opfContents
+		}
+
+		languages[0].SetText(lang)
+
+		return opf.String()
+	}
+
+	if _, err = opf.AddMetadata("dc:language", lang); err != nil {
+		return opfContents
+	}
+
+	return opf.String()
+}
+
 func EnsureLanguageIsSet(text, lang string) string {
 	var htmlOpenStart = strings.Index(text, openingHtmlTag)
 	if htmlOpenStart == -1 {
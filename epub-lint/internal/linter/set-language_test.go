@@ -111,3 +111,84 @@ func TestSetLanguage(t *testing.T) {
 		})
 	}
 }
+
+var setOpfLanguageTestCases = map[string]setLanguageTestCase{
+	"when the opf has a dc:language, no change is made": {
+		inputText: `<package>
+  <metadata>
+    <dc:language>en</dc:language>
+  </metadata>
+</package>`,
+		inputLang: "es",
+		expectedText: `<package>
+  <metadata>
+    <dc:language>en</dc:language>
+  </metadata>
+</package>`,
+	},
+	"when the opf has an empty dc:language, the language is set": {
+		inputText: `<package>
+  <metadata>
+    <dc:language> </dc:language>
+  </metadata>
+</package>`,
+		inputLang: "es",
+		expectedText: `<package>
+  <metadata>
+    <dc:language>es</dc:language>
+  </metadata>
+</package>`,
+	},
+	"when the opf has no dc:language, one is added to the end of the metadata": {
+		inputText: `<package>
+  <metadata>
+    <dc:title>Title</dc:title>
+  </metadata>
+</package>`,
+		inputLang: "es",
+		expectedText: `<package>
+  <metadata>
+    <dc:title>Title</dc:title>
+    <dc:language>es</dc:language>
+  </metadata>
+</package>`,
+	},
+	"when the opf metadata has a prefix, the escaped language is added to it": {
+		inputText: `<package>
+  <opf:metadata>
+    <dc:title>Title</dc:title>
+  </opf:metadata>
+</package>`,
+		inputLang: "en&",
+		expectedText: `<package>
+  <opf:metadata>
+    <dc:title>Title</dc:title>
+    <dc:language>en&amp;</dc:language>
+  </opf:metadata>
+</package>`,
+	},
+	"when the opf has no metadata, no change is made": {
+		inputText:    `<package></package>`,
+		inputLang:    "es",
+		expectedText: `<package></package>`,
+	},
+}
+
+func TestSetOpfLanguage(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range setOpfLanguageTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			actual := linter.EnsureOpfLanguageIsSet(args.inputText, args.inputLang)
+			assert.Equal(t, args.expectedText, actual)
+		})
+	}
+}
+
+func TestGetOpfLanguage(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "en-US", linter.GetOpfLanguage(`<metadata><dc:language id="lang"> en-US </dc:language><dc:language>ja</dc:language></metadata>`))
+	assert.Equal(t, "", linter.GetOpfLanguage(`<metadata><dc:title>Title</dc:title></metadata>`))
+}
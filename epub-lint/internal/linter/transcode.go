@@ -0,0 +1,221 @@
+package linter
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	textunicode "golang.org/x/text/encoding/unicode"
+)
+
+const (
+	Utf8Encoding = "utf-8"
+	// minMojibakeMarkers is how many mojibake markers need to be in text for it to be considered to have mojibake
+	// since a single one could be legitimate text
+	minMojibakeMarkers = 2
+)
+
+type encodingCandidate struct {
+	name     string
+	encoding encoding.Encoding
+	scripts  []*unicode.RangeTable
+}
+
+var (
+	utf8Bom    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LeBom = []byte{0xFF, 0xFE}
+	utf16BeBom = []byte{0xFE, 0xFF}
+	// rightDoubleQuoteStart is the start of ” in utf-8
+	rightDoubleQuoteStart = []byte{0xE2, 0x80}
+	// heuristicEncodings are the legacy multibyte encodings to try when the file has no byte order mark or declaration
+	// where the decoded text has to contain one of the scripts of the language the encoding is for
+	heuristicEncodings = []encodingCandidate{
+		{name: "shift_jis", encoding: japanese.ShiftJIS, scripts: []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana}},
+		{name: "euc-jp", encoding: japanese.EUCJP, scripts: []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana}},
+		{name: "euc-kr", encoding: korean.EUCKR, scripts: []*unicode.RangeTable{unicode.Hangul}},
+	}
+	xmlEncodingRegex  = regexp.MustCompile(`(?i)(<\?xml\b[^>]*?\sencoding\s*=\s*["'])([^"']*)(["'])`)
+	metaCharsetRegex  = regexp.MustCompile(`(?i)(<meta\b[^>]*?\bcharset\s*=\s*["']?)([\w.:-]+)`)
+	cssCharsetRegex   = regexp.MustCompile(`(?i)(@charset\s*["'])([^"']*)(["'])`)
+	charsetDeclRegexs = []*regexp.Regexp{xmlEncodingRegex, metaCharsetRegex, cssCharsetRegex}
+)
+
+// DecodeToUtf8 detects the encoding of the file contents from its byte order mark, its xml, meta, or css encoding declaration,
+// or heuristics for common legacy encodings and returns the contents as utf-8 along with the name of the detected encoding
+func DecodeToUtf8(data []byte) (string, string, error) {
+	switch {
+	case bytes.HasPrefix(data, utf8Bom):
+		return string(data[len(utf8Bom):]), Utf8Encoding, nil
+	case bytes.HasPrefix(data, utf16LeBom):
+		return decode(data, "utf-16le", textunicode.UTF16(textunicode.LittleEndian, textunicode.ExpectBOM))
+	case bytes.HasPrefix(data, utf16BeBom):
+		return decode(data, "utf-16be", textunicode.UTF16(textunicode.BigEndian, textunicode.ExpectBOM))
+	case utf8.Valid(data):
+		return string(data), Utf8Encoding, nil
+	}
+
+	if declaredEncoding := getDeclaredEncoding(data); declaredEncoding != "" {
+		if enc, err := htmlindex.Get(declaredEncoding); err == nil {
+			if name, err := htmlindex.Name(enc); err == nil && name != Utf8Encoding {
+				return decode(data, name, enc)
+			}
+		}
+	}
+
+	var (
+		bestCandidate encodingCandidate
+		bestText      string
+		bestCount     int
+	)
+	for _, candidate := range heuristicEncodings {
+		decoded, err := candidate.encoding.NewDecoder().Bytes(data)
+		if err != nil || bytes.ContainsRune(decoded, utf8.RuneError) {
+			continue
+		}
+
+		var count int
+		for _, r := range string(decoded) {
+			if unicode.In(r, candidate.scripts...) {
+				count++
+			}
+		}
+
+		if count > bestCount {
+			bestCandidate, bestText, bestCount = candidate, string(decoded), count
+		}
+	}
+
+	if bestCount != 0 {
+		return bestText, bestCandidate.name, nil
+	}
+
+	return decode(data, "windows-1252", charmap.Windows1252)
+}
+
+// SetUtf8EncodingDeclarations sets the xml encoding, meta charset, and css charset declarations to utf-8.
+// It should only be used once the contents are utf-8.
+func SetUtf8EncodingDeclarations(text string) string {
+	for _, declarationRegex := range charsetDeclRegexs {
+		text = declarationRegex.ReplaceAllStringFunc(text, func(match string) string {
+			var groups = declarationRegex.FindStringSubmatch(match)
+			if strings.EqualFold(groups[2], Utf8Encoding) {
+				return match
+			}
+
+			return groups[1] + Utf8Encoding + strings.Join(groups[3:], "")
+		})
+	}
+
+	return text
+}
+
+// HasMojibake returns whether the text has enough clear signs of utf-8 text that was decoded as windows-1252 to
+// be worth repairing which are "â€" (the start of quotes and dashes) and "Ã" followed by the rest of an accented letter
+func HasMojibake(text string) bool {
+	var (
+		markers  = strings.Count(text, "â€")
+		previous rune
+	)
+	for _, r := range text {
+		if previous == 'Ã' && isContinuationByte(r) {
+			markers++
+		}
+
+		if markers >= minMojibakeMarkers {
+			return true
+		}
+
+		previous = r
+	}
+
+	return false
+}
+
+// RepairMojibake replaces sequences of characters that are utf-8 text which was decoded as windows-1252
+// (i.e. "â€™" instead of "’") with the characters they were meant to be returning the new text and the number of repairs made
+func RepairMojibake(text string) (string, int) {
+	var (
+		newText strings.Builder
+		repairs int
+		run     []byte
+		flush   = func() {
+			for len(run) != 0 {
+				r, size := utf8.DecodeRune(run)
+				if size > 1 && isMojibakeTarget(r) {
+					newText.WriteRune(r)
+					repairs++
+				} else if bytes.HasPrefix(run, rightDoubleQuoteStart) && (len(run) == 2 || run[2] < 0x80 || run[2] > 0xBF) {
+					// the last byte of ” is not a character in windows-1252, so it is usually dropped leaving just "â€"
+					newText.WriteRune('”')
+					repairs++
+					size = len(rightDoubleQuoteStart)
+				} else {
+					newText.WriteRune(charmap.Windows1252.DecodeByte(run[0]))
+					size = 1
+				}
+
+				run = run[size:]
+			}
+		}
+	)
+	for _, r := range text {
+		if r >= utf8.RuneSelf {
+			if b, ok := charmap.Windows1252.EncodeRune(r); ok {
+				run = append(run, b)
+
+				continue
+			}
+		}
+
+		flush()
+		newText.WriteRune(r)
+	}
+
+	flush()
+
+	if repairs == 0 {
+		return text, 0
+	}
+
+	return newText.String(), repairs
+}
+
+// isMojibakeTarget returns whether the character is one that commonly ends up as mojibake which keeps
+// legitimate text like "Fuß“" from being treated as mojibake
+func isMojibakeTarget(r rune) bool {
+	return (r >= '\u00a0' && r <= '\u017f') || (r >= '\u2000' && r <= '\u206f') || r == '€' || r == '™'
+}
+
+// isContinuationByte returns whether the character is what a utf-8 continuation byte becomes when decoded as windows-1252
+func isContinuationByte(r rune) bool {
+	b, ok := charmap.Windows1252.EncodeRune(r)
+
+	return ok && b >= 0x80 && b <= 0xBF
+}
+
+func decode(data []byte, name string, enc encoding.Encoding) (string, string, error) {
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode the contents as %s: %w", name, err)
+	}
+
+	return string(decoded), name, nil
+}
+
+func getDeclaredEncoding(data []byte) string {
+	for _, declarationRegex := range charsetDeclRegexs {
+		if groups := declarationRegex.FindSubmatch(data); groups != nil {
+			return strings.TrimSpace(string(groups[2]))
+		}
+	}
+
+	return ""
+}
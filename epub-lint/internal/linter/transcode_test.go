@@ -0,0 +1,126 @@
+//go:build unit
+
+package linter_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeToUtf8TestCase struct {
+	input            []byte
+	expectedText     string
+	expectedEncoding string
+}
+
+var decodeToUtf8TestCases = map[string]decodeToUtf8TestCase{
+	"utf-8 text is left as is": {
+		input:            []byte(`<p>Café “quoted”</p>`),
+		expectedText:     `<p>Café “quoted”</p>`,
+		expectedEncoding: linter.Utf8Encoding,
+	},
+	"a utf-8 byte order mark is removed": {
+		input:            []byte("\xEF\xBB\xBF<p>text</p>"),
+		expectedText:     `<p>text</p>`,
+		expectedEncoding: linter.Utf8Encoding,
+	},
+	"utf-16 with a byte order mark is converted": {
+		input:            []byte("\xFF\xFE<\x00p\x00>\x00\xE9\x00"),
+		expectedText:     `<p>é`,
+		expectedEncoding: "utf-16le",
+	},
+	"the declared encoding is used when the text is not utf-8": {
+		input:            []byte("<?xml version=\"1.0\" encoding=\"iso-8859-2\"?>\n<p>\xB1</p>"),
+		expectedText:     "<?xml version=\"1.0\" encoding=\"iso-8859-2\"?>\n<p>ą</p>",
+		expectedEncoding: "iso-8859-2",
+	},
+	"a meta charset declaration is used": {
+		input:            []byte("<meta http-equiv=\"Content-Type\" content=\"text/html; charset=Shift_JIS\"/><p>\x82\xA0</p>"),
+		expectedText:     `<meta http-equiv="Content-Type" content="text/html; charset=Shift_JIS"/><p>あ</p>`,
+		expectedEncoding: "shift_jis",
+	},
+	"shift-jis without a declaration is detected from the text": {
+		input:            []byte("<p>\x82\xB1\x82\xF1\x82\xC9\x82\xBF\x82\xCD</p>"),
+		expectedText:     `<p>こんにちは</p>`,
+		expectedEncoding: "shift_jis",
+	},
+	"windows-1252 without a declaration is the fallback": {
+		input:            []byte("<p>Caf\xE9 \x93quoted\x94 \x85</p>"),
+		expectedText:     `<p>Café “quoted” …</p>`,
+		expectedEncoding: "windows-1252",
+	},
+}
+
+func TestDecodeToUtf8(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range decodeToUtf8TestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			text, encoding, err := linter.DecodeToUtf8(args.input)
+			require.NoError(t, err)
+
+			assert.Equal(t, args.expectedText, text)
+			assert.Equal(t, args.expectedEncoding, encoding)
+		})
+	}
+}
+
+func TestRepairMojibake(t *testing.T) {
+	t.Parallel()
+
+	text, repairs := linter.RepairMojibake(`It’s a cafÃ© â€œquoteâ€ â€” done`)
+	assert.Equal(t, `It’s a café “quote” — done`, text)
+	assert.Equal(t, 4, repairs)
+
+	text, repairs = linter.RepairMojibake(`Die Straße „Fuß“ ist café … 日本語`)
+	assert.Equal(t, `Die Straße „Fuß“ ist café … 日本語`, text)
+	assert.Equal(t, 0, repairs)
+}
+
+var hasMojibakeTestCases = map[string]struct {
+	input    string
+	expected bool
+}{
+	"text without mojibake markers does not have mojibake": {
+		input:    `Die Straße „Fuß“ ist café … 日本語`,
+		expected: false,
+	},
+	"text with a single mojibake marker does not have enough to be considered mojibake": {
+		input:    `A name like TÃ­a could be legitimate`,
+		expected: false,
+	},
+	"text with a capital A with a tilde that is not followed by the rest of a character does not have mojibake": {
+		input:    `SÃO PAULO Ã É`,
+		expected: false,
+	},
+	"text with multiple mojibake markers has mojibake": {
+		input:    `It’s a cafÃ© â€œquoteâ€ â€” done`,
+		expected: true,
+	},
+}
+
+func TestHasMojibake(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range hasMojibakeTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, args.expected, linter.HasMojibake(args.input))
+		})
+	}
+}
+
+func TestSetUtf8EncodingDeclarations(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `<?xml version="1.0" encoding="utf-8"?><meta charset="utf-8"/><meta content="text/html; charset=utf-8"/>`,
+		linter.SetUtf8EncodingDeclarations(`<?xml version="1.0" encoding="Shift_JIS"?><meta charset="windows-1252"/><meta content="text/html; charset=Shift_JIS"/>`))
+	assert.Equal(t, `@charset "utf-8";`, linter.SetUtf8EncodingDeclarations(`@charset "iso-8859-1";`))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`, linter.SetUtf8EncodingDeclarations(`<?xml version="1.0" encoding="UTF-8"?>`))
+}
@@ -0,0 +1,137 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	rulefixes "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/rule-fixes"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+)
+
+// getCodeActions gets the quick fixes for the diagnostics of the server that the client sent with the request
+// using the existing rule fixes for structural issues and the suggestions for content issues
+func (s *Server) getCodeActions(params codeActionParams) []CodeAction {
+	var actions = []CodeAction{}
+
+	file, ok := s.getFilePath(params.TextDocument.Uri)
+	if !ok {
+		return actions
+	}
+
+	contents, err := s.getContents(file)
+	if err != nil {
+		return actions
+	}
+
+	for _, diagnostic := range params.Context.Diagnostics {
+		if diagnostic.Source != diagnosticSource {
+			continue
+		}
+
+		var (
+			start = positionToIndex(contents, diagnostic.Range.Start)
+			end   = positionToIndex(contents, diagnostic.Range.End)
+		)
+		if end < start {
+			continue
+		}
+
+		switch diagnostic.Code {
+		case structurecheck.ManifestFile:
+			continue
+		case structurecheck.MissingAlt:
+			var tagEnd = strings.LastIndexByte(contents[start:end], '>')
+			if tagEnd == -1 {
+				continue
+			}
+
+			tagEnd += start
+
+			var edit positions.TextEdit
+			if tagEnd > 0 && contents[tagEnd-1] == '/' {
+				var pos = positions.IndexToPosition(contents, tagEnd+1)
+				edit = rulefixes.FixMissingImageAlt(pos.Line, pos.Column, contents)
+			} else {
+				var pos = positions.IndexToPosition(contents, tagEnd)
+				edit = positions.TextEdit{
+					Range: positions.Range{
+						Start: pos,
+						End:   pos,
+					},
+					NewText: ` alt=""`,
+				}
+			}
+
+			actions = addCodeAction(actions, params, diagnostic, "Add an empty alt attribute", true, contents, edit)
+		case structurecheck.DuplicateId:
+			var id = contents[start:end]
+
+			actions = addCodeAction(actions, params, diagnostic, fmt.Sprintf("Rename the duplicates of id %q", id), true, contents, rulefixes.UpdateDuplicateIds(contents, id)...)
+		case structurecheck.BrokenLink:
+			if diagnostic.Data != nil {
+				actions = addCodeAction(actions, params, diagnostic, fmt.Sprintf("Replace the link with %q", diagnostic.Data.Replacement), true, contents, positions.TextEdit{
+					Range: positions.Range{
+						Start: positions.IndexToPosition(contents, start),
+						End:   positions.IndexToPosition(contents, end),
+					},
+					NewText: diagnostic.Data.Replacement,
+				})
+			}
+
+			if strings.Contains(contents[start:end], "#") {
+				var pos = positions.IndexToPosition(contents, start)
+
+				actions = addCodeAction(actions, params, diagnostic, "Remove the id from the link", false, contents, rulefixes.RemoveLinkId(contents, pos.Line, pos.Column))
+			}
+		default:
+			if diagnostic.Data == nil {
+				continue
+			}
+
+			actions = append(actions, newCodeAction(params, diagnostic, fmt.Sprintf("Replace with %q", diagnostic.Data.Replacement), true, []TextEdit{
+				{
+					Range:   diagnostic.Range,
+					NewText: diagnostic.Data.Replacement,
+				},
+			}))
+		}
+	}
+
+	return actions
+}
+
+// addCodeAction adds a code action for the rule fix edits skipping edits that are empty since that is how the rule fixes say there is no fix
+func addCodeAction(actions []CodeAction, params codeActionParams, diagnostic Diagnostic, title string, isPreferred bool, contents string, edits ...positions.TextEdit) []CodeAction {
+	var textEdits []TextEdit
+	for _, edit := range edits {
+		if edit.IsEmpty() {
+			continue
+		}
+
+		textEdits = append(textEdits, TextEdit{
+			Range:   toLspRange(contents, edit.Range),
+			NewText: edit.NewText,
+		})
+	}
+
+	if len(textEdits) == 0 {
+		return actions
+	}
+
+	return append(actions, newCodeAction(params, diagnostic, title, isPreferred, textEdits))
+}
+
+func newCodeAction(params codeActionParams, diagnostic Diagnostic, title string, isPreferred bool, edits []TextEdit) CodeAction {
+	return CodeAction{
+		Title:       title,
+		Kind:        quickFixKind,
+		Diagnostics: []Diagnostic{diagnostic},
+		Edit: &WorkspaceEdit{
+			Changes: map[string][]TextEdit{
+				params.TextDocument.Uri: edits,
+			},
+		},
+		IsPreferred: isPreferred,
+	}
+}
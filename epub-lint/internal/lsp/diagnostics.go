@@ -0,0 +1,233 @@
+package lsp
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/watch"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/pkg/epub"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+// getDiagnostics validates the opf and manifest of the epub, runs the structural checks on its content files,
+// and runs the content checks on the files in the spine returning the diagnostics by the path of the file they are for.
+// The files on disk are only gotten again when refreshing and otherwise the ones from the last refresh are used.
+func (s *Server) getDiagnostics(refresh bool) (map[string][]Diagnostic, error) {
+	if refresh || s.files == nil {
+		snapshot, err := watch.TakeSnapshot(s.root)
+		if err != nil {
+			return nil, err
+		}
+
+		s.files = snapshot.Files()
+		clear(s.lintedFiles)
+	}
+
+	var (
+		files       = s.files
+		opfFilename string
+	)
+	for file := range files {
+		if strings.HasSuffix(file, ".opf") && (opfFilename == "" || file < opfFilename) {
+			opfFilename = file
+		}
+	}
+
+	if opfFilename == "" {
+		return nil, fmt.Errorf("failed to find the opf file in %q", s.root)
+	}
+
+	opfContents, err := s.getContents(opfFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics = make(map[string][]Diagnostic)
+	epubInfo, err := epubhandler.ParseOpfFile(opfContents, opfFilename)
+	if err != nil {
+		diagnostics[opfFilename] = []Diagnostic{newDiagnostic(Range{}, SeverityError, structurecheck.ManifestFile, err.Error(), "")}
+
+		return diagnostics, nil
+	}
+
+	var opfFolder = filehandler.GetFileFolder(opfFilename)
+	for _, manifestFiles := range []map[string]struct{}{epubInfo.HtmlFiles, epubInfo.ImagesFiles, epubInfo.CssFiles, epubInfo.OtherFiles} {
+		for _, file := range slices.Sorted(maps.Keys(manifestFiles)) {
+			var filePath = filehandler.JoinPath(opfFolder, file)
+			if _, exists := files[filePath]; exists {
+				continue
+			}
+
+			diagnostics[opfFilename] = append(diagnostics[opfFilename], newDiagnostic(getValueRange(opfContents, file), SeverityError, structurecheck.ManifestFile, fmt.Sprintf("file from manifest not found: %q must exist", filePath), ""))
+		}
+	}
+
+	var htmlFiles = make(map[string]string, len(epubInfo.HtmlFiles))
+	for file := range epubInfo.HtmlFiles {
+		var filePath = filehandler.JoinPath(opfFolder, file)
+		if _, exists := files[filePath]; !exists {
+			continue
+		}
+
+		contents, err := s.getContents(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		htmlFiles[filePath] = contents
+	}
+
+	for _, finding := range structurecheck.CheckFiles(htmlFiles, files) {
+		var contents = htmlFiles[finding.File]
+		diagnostics[finding.File] = append(diagnostics[finding.File], newDiagnostic(getFindingRange(contents, finding), SeverityWarning, finding.Rule, finding.Message, finding.Repair))
+	}
+
+	var contentFiles []epub.ContentFile
+	for _, file := range epubInfo.FilePathsInSpineOrder {
+		var filePath = filehandler.JoinPath(opfFolder, file)
+		if contents, ok := htmlFiles[filePath]; ok {
+			contentFiles = append(contentFiles, epub.ContentFile{
+				Path: filePath,
+				Text: contents,
+			})
+		}
+	}
+
+	issues, err := s.lintContentFiles(contentFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		var contents = htmlFiles[issue.File]
+		for start := strings.Index(contents, issue.Original); start != -1; {
+			var end = start + len(issue.Original)
+			diagnostics[issue.File] = append(diagnostics[issue.File], newDiagnostic(Range{
+				Start: indexToPosition(contents, start),
+				End:   indexToPosition(contents, end),
+			}, SeverityInformation, string(issue.Check), issue.Check.Description(), issue.Suggested))
+
+			var next = strings.Index(contents[end:], issue.Original)
+			if next == -1 {
+				break
+			}
+
+			start = end + next
+		}
+	}
+
+	return diagnostics, nil
+}
+
+// lintContentFiles runs the content checks on the content files that changed since they were last linted reusing the issues
+// of the rest. The names for name consistency come from all of the content files, so the name consistency issues of files
+// that did not change are only updated once the epub gets refreshed.
+func (s *Server) lintContentFiles(contentFiles []epub.ContentFile) ([]epub.Issue, error) {
+	var changedFiles []epub.ContentFile
+	for _, file := range contentFiles {
+		if linted, ok := s.lintedFiles[file.Path]; !ok || linted.text != file.Text {
+			changedFiles = append(changedFiles, file)
+		}
+	}
+
+	if len(changedFiles) != 0 {
+		var options = s.options.LintOptions
+		options.NameSourceFiles = contentFiles
+
+		changedIssues, err := epub.LintFiles(changedFiles, options)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range changedFiles {
+			s.lintedFiles[file.Path] = lintedFile{
+				text: file.Text,
+			}
+		}
+
+		for _, issue := range changedIssues {
+			var linted = s.lintedFiles[issue.File]
+			linted.issues = append(linted.issues, issue)
+			s.lintedFiles[issue.File] = linted
+		}
+	}
+
+	var (
+		issues      []epub.Issue
+		lintedFiles = make(map[string]lintedFile, len(contentFiles))
+	)
+	for _, file := range contentFiles {
+		lintedFiles[file.Path] = s.lintedFiles[file.Path]
+		issues = append(issues, s.lintedFiles[file.Path].issues...)
+	}
+
+	// files that are no longer content files are dropped so they get linted again if they become content files again
+	s.lintedFiles = lintedFiles
+
+	return issues, nil
+}
+
+func newDiagnostic(diagnosticRange Range, severity DiagnosticSeverity, code, message, replacement string) Diagnostic {
+	var diagnostic = Diagnostic{
+		Range:    diagnosticRange,
+		Severity: severity,
+		Code:     code,
+		Source:   diagnosticSource,
+		Message:  message,
+	}
+
+	if replacement != "" {
+		diagnostic.Data = &DiagnosticData{
+			Replacement: replacement,
+		}
+	}
+
+	return diagnostic
+}
+
+// getFindingRange gets the range of the value the finding is for which is the whole tag for a missing alt
+// and the attribute value for duplicate ids and broken links
+func getFindingRange(contents string, finding structurecheck.Finding) Range {
+	var start = positions.GetPositionOffset(contents, finding.Line, finding.Column)
+	if start == -1 {
+		return Range{}
+	}
+
+	var end int
+	if finding.Rule == structurecheck.MissingAlt {
+		end = strings.IndexByte(contents[start:], '>') + 1
+	} else {
+		end = strings.IndexAny(contents[start:], "\"')\n")
+	}
+
+	if end <= 0 {
+		end = len(contents)
+	} else {
+		end += start
+	}
+
+	return Range{
+		Start: indexToPosition(contents, start),
+		End:   indexToPosition(contents, end),
+	}
+}
+
+// getValueRange gets the range of the first quoted attribute value that matches the value or an empty range at the start of the file when there is none
+func getValueRange(contents, value string) Range {
+	for _, quote := range []string{`"`, `'`} {
+		var start = strings.Index(contents, quote+value+quote)
+		if start != -1 {
+			return Range{
+				Start: indexToPosition(contents, start+1),
+				End:   indexToPosition(contents, start+1+len(value)),
+			}
+		}
+	}
+
+	return Range{}
+}
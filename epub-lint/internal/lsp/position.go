@@ -0,0 +1,66 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+)
+
+// indexToPosition converts the byte index in the contents to an LSP position
+func indexToPosition(contents string, index int) Position {
+	index = max(0, min(index, len(contents)))
+
+	var lineStart = strings.LastIndex(contents[:index], "\n") + 1
+
+	return Position{
+		Line:      strings.Count(contents[:index], "\n"),
+		Character: getUtf16Length(contents[lineStart:index]),
+	}
+}
+
+// positionToIndex converts the LSP position to a byte index in the contents clamping it to the end of its line
+func positionToIndex(contents string, position Position) int {
+	var index int
+	for range position.Line {
+		var lineEnd = strings.IndexByte(contents[index:], '\n')
+		if lineEnd == -1 {
+			return len(contents)
+		}
+
+		index += lineEnd + 1
+	}
+
+	for character := 0; character < position.Character && index < len(contents) && contents[index] != '\n'; {
+		char, size := utf8.DecodeRuneInString(contents[index:])
+		character += utf16RuneLength(char)
+		index += size
+	}
+
+	return index
+}
+
+// toLspRange converts a range with 1-based lines and 1-based rune columns like the rule fixes use to an LSP range
+func toLspRange(contents string, editRange positions.Range) Range {
+	return Range{
+		Start: indexToPosition(contents, positions.GetPositionOffset(contents, editRange.Start.Line, editRange.Start.Column)),
+		End:   indexToPosition(contents, positions.GetPositionOffset(contents, editRange.End.Line, editRange.End.Column)),
+	}
+}
+
+func getUtf16Length(text string) int {
+	var length int
+	for _, char := range text {
+		length += utf16RuneLength(char)
+	}
+
+	return length
+}
+
+func utf16RuneLength(char rune) int {
+	if char > 0xFFFF {
+		return 2
+	}
+
+	return 1
+}
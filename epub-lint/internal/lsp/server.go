@@ -0,0 +1,326 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/pkg/epub"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+type Options struct {
+	// Root is the folder of the unpacked epub which defaults to the root of the workspace the client opens
+	Root string
+	// LintOptions are the options for the content checks that get run on the content files
+	LintOptions epub.LintOptions
+}
+
+// Server is a language server for an unpacked epub that publishes diagnostics for its structural issues and content checks
+// and offers the rule fixes and content suggestions as code actions
+type Server struct {
+	options Options
+	root    string
+	out     io.Writer
+	// documents are the contents of the documents open in the client by their path relative to the root of the epub
+	documents map[string]string
+	// published are the uris with diagnostics currently published so they can be cleared once they are fixed
+	published map[string]struct{}
+	// files are the files on disk by their path relative to the root of the epub as of the last time the epub was saved
+	files map[string]struct{}
+	// lintedFiles are the content files that have been linted by their path relative to the root of the epub so that only
+	// the files that change get linted again
+	lintedFiles map[string]lintedFile
+}
+
+type lintedFile struct {
+	text   string
+	issues []epub.Issue
+}
+
+func NewServer(options Options) *Server {
+	return &Server{
+		options:     options,
+		documents:   make(map[string]string),
+		published:   make(map[string]struct{}),
+		lintedFiles: make(map[string]lintedFile),
+	}
+}
+
+// Run handles the messages from in writing the responses and notifications to out until the client says to exit or in is closed
+func (s *Server) Run(in io.Reader, out io.Writer) error {
+	s.out = out
+
+	var reader = bufio.NewReader(in)
+	for {
+		body, err := readMessage(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		var req request
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			// the id is unknown when the message cannot be parsed which is sent as null
+			err = s.respondWithError(nil, parseErrorCode, fmt.Sprintf("failed to parse message: %s", err))
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if req.Method == "exit" {
+			return nil
+		}
+
+		err = s.handle(req)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(req request) error {
+	switch req.Method {
+	case "initialize":
+		var params initializeParams
+		err := json.Unmarshal(req.Params, &params)
+		if err != nil {
+			return s.respondWithError(req.Id, invalidParamsCode, err.Error())
+		}
+
+		err = s.setRoot(params)
+		if err != nil {
+			return s.respondWithError(req.Id, invalidParamsCode, err.Error())
+		}
+
+		return s.respond(req.Id, initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncFull,
+				CodeActionProvider: codeActionProvider{
+					CodeActionKinds: []string{quickFixKind},
+				},
+			},
+			ServerInfo: serverInfo{
+				Name: diagnosticSource,
+			},
+		})
+	case "initialized", "textDocument/didSave":
+		return s.publishDiagnostics(true)
+	case "shutdown":
+		return s.respond(req.Id, nil)
+	case "textDocument/didOpen":
+		var params didOpenTextDocumentParams
+		if json.Unmarshal(req.Params, &params) != nil {
+			return nil
+		}
+
+		if file, ok := s.getFilePath(params.TextDocument.Uri); ok {
+			s.documents[file] = params.TextDocument.Text
+		}
+
+		return s.publishDiagnostics(false)
+	case "textDocument/didChange":
+		var params didChangeTextDocumentParams
+		if json.Unmarshal(req.Params, &params) != nil || len(params.ContentChanges) == 0 {
+			return nil
+		}
+
+		if file, ok := s.getFilePath(params.TextDocument.Uri); ok {
+			s.documents[file] = params.ContentChanges[len(params.ContentChanges)-1].Text
+		}
+
+		return s.publishDiagnostics(false)
+	case "textDocument/didClose":
+		var params didCloseTextDocumentParams
+		if json.Unmarshal(req.Params, &params) != nil {
+			return nil
+		}
+
+		if file, ok := s.getFilePath(params.TextDocument.Uri); ok {
+			delete(s.documents, file)
+		}
+
+		return s.publishDiagnostics(false)
+	case "textDocument/codeAction":
+		var params codeActionParams
+		err := json.Unmarshal(req.Params, &params)
+		if err != nil {
+			return s.respondWithError(req.Id, invalidParamsCode, err.Error())
+		}
+
+		return s.respond(req.Id, s.getCodeActions(params))
+	}
+
+	if req.Id != nil {
+		return s.respondWithError(req.Id, methodNotFoundCode, fmt.Sprintf("method %q is not supported", req.Method))
+	}
+
+	// notifications that are not supported are ignored
+	return nil
+}
+
+func (s *Server) setRoot(params initializeParams) error {
+	var root = s.options.Root
+	if root == "" {
+		if params.RootUri != "" {
+			root = uriToPath(params.RootUri)
+		} else if len(params.WorkspaceFolders) != 0 {
+			root = uriToPath(params.WorkspaceFolders[0].Uri)
+		} else if params.RootPath != "" {
+			root = params.RootPath
+		} else {
+			root = "."
+		}
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("failed to get the absolute path of %q: %w", root, err)
+	}
+
+	s.root = absRoot
+
+	return nil
+}
+
+// publishDiagnostics checks the epub and publishes its diagnostics clearing the diagnostics of files that no longer have any.
+// Refreshing gets the files on disk again and lints all of the content files instead of just the ones that changed.
+func (s *Server) publishDiagnostics(refresh bool) error {
+	diagnostics, err := s.getDiagnostics(refresh)
+	if err != nil {
+		return s.notify("window/logMessage", logMessageParams{
+			Type:    messageTypeError,
+			Message: err.Error(),
+		})
+	}
+
+	var published = make(map[string]struct{}, len(diagnostics))
+	for _, file := range slices.Sorted(maps.Keys(diagnostics)) {
+		var uri = s.getUri(file)
+		published[uri] = struct{}{}
+
+		err = s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			Uri:         uri,
+			Diagnostics: diagnostics[file],
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, uri := range slices.Sorted(maps.Keys(s.published)) {
+		if _, ok := published[uri]; ok {
+			continue
+		}
+
+		err = s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			Uri:         uri,
+			Diagnostics: []Diagnostic{},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	s.published = published
+
+	return nil
+}
+
+// getContents gets the contents of the file from the client when it is open and from disk when it is not
+func (s *Server) getContents(file string) (string, error) {
+	if contents, ok := s.documents[file]; ok {
+		return contents, nil
+	}
+
+	return filehandler.ReadInFileContents(filepath.Join(s.root, filepath.FromSlash(file)))
+}
+
+// getFilePath gets the path relative to the root of the epub for the uri returning false when it is not in the epub
+func (s *Server) getFilePath(uri string) (string, bool) {
+	file, err := filepath.Rel(s.root, uriToPath(uri))
+	if err != nil {
+		return "", false
+	}
+
+	file = filepath.ToSlash(file)
+	if file == ".." || strings.HasPrefix(file, "../") {
+		return "", false
+	}
+
+	return file, true
+}
+
+func (s *Server) getUri(file string) string {
+	var fileUrl = url.URL{
+		Scheme: "file",
+		Path:   filepath.ToSlash(filepath.Join(s.root, filepath.FromSlash(file))),
+	}
+
+	if !strings.HasPrefix(fileUrl.Path, "/") {
+		fileUrl.Path = "/" + fileUrl.Path
+	}
+
+	return fileUrl.String()
+}
+
+func (s *Server) respond(id json.RawMessage, result any) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to serialize result: %w", err)
+	}
+
+	return writeMessage(s.out, response{
+		JsonRpc: jsonRpcVersion,
+		Id:      id,
+		Result:  body,
+	})
+}
+
+func (s *Server) respondWithError(id json.RawMessage, code int, message string) error {
+	return writeMessage(s.out, response{
+		JsonRpc: jsonRpcVersion,
+		Id:      id,
+		Error: &responseError{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
+func (s *Server) notify(method string, params any) error {
+	return writeMessage(s.out, notification{
+		JsonRpc: jsonRpcVersion,
+		Method:  method,
+		Params:  params,
+	})
+}
+
+func uriToPath(uri string) string {
+	fileUrl, err := url.Parse(uri)
+	if err != nil || fileUrl.Scheme != "file" {
+		return uri
+	}
+
+	var path = fileUrl.Path
+	// windows paths come through as /C:/path
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' && os.PathSeparator == '\\' {
+		path = path[1:]
+	}
+
+	return filepath.FromSlash(path)
+}
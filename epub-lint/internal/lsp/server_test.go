@@ -0,0 +1,285 @@
+//go:build unit
+
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testOpf = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="bookid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:language>en</dc:language>
+  </metadata>
+  <manifest>
+    <item id="chapter1" href="Text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="missing" href="Images/missing.jpg" media-type="image/jpeg"/>
+  </manifest>
+  <spine>
+    <itemref idref="chapter1"/>
+  </spine>
+</package>`
+	testChapter = `<html xmlns="http://www.w3.org/1999/xhtml" xml:lang="en">
+<body>
+<p id="start">I packed apples, pears and plums.</p>
+<p id="start"><img src="cover.jpg"/></p>
+</body>
+</html>`
+)
+
+type testMessage struct {
+	Id     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+type testPublishDiagnosticsParams struct {
+	Uri         string           `json:"uri"`
+	Diagnostics []lsp.Diagnostic `json:"diagnostics"`
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	var dir = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "OEBPS", "Text"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OEBPS", "content.opf"), []byte(testOpf), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OEBPS", "Text", "chapter1.xhtml"), []byte(testChapter), 0644))
+
+	var (
+		chapterUri = fileUri(filepath.Join(dir, "OEBPS", "Text", "chapter1.xhtml"))
+		opfUri     = fileUri(filepath.Join(dir, "OEBPS", "content.opf"))
+		in         bytes.Buffer
+	)
+	writeTestMessage(t, &in, 1, "initialize", map[string]any{"rootUri": fileUri(dir)})
+	writeTestMessage(t, &in, 0, "initialized", map[string]any{})
+
+	var out bytes.Buffer
+	require.NoError(t, lsp.NewServer(lsp.Options{}).Run(&in, &out))
+
+	var messages = readTestMessages(t, &out)
+	require.Len(t, messages, 3)
+	assert.Contains(t, string(messages[0].Result), `"codeActionProvider"`)
+
+	var diagnostics = make(map[string][]lsp.Diagnostic)
+	for _, message := range messages[1:] {
+		require.Equal(t, "textDocument/publishDiagnostics", message.Method)
+
+		var params testPublishDiagnosticsParams
+		require.NoError(t, json.Unmarshal(message.Params, &params))
+		diagnostics[params.Uri] = params.Diagnostics
+	}
+
+	require.Len(t, diagnostics[opfUri], 1)
+	assert.Equal(t, "manifest", diagnostics[opfUri][0].Code)
+	assert.Equal(t, lsp.SeverityError, diagnostics[opfUri][0].Severity)
+	assert.Equal(t, lsp.Range{Start: lsp.Position{Line: 7, Character: 29}, End: lsp.Position{Line: 7, Character: 47}}, diagnostics[opfUri][0].Range)
+
+	var chapterDiagnostics = make(map[string]lsp.Diagnostic)
+	for _, diagnostic := range diagnostics[chapterUri] {
+		assert.Equal(t, "epub-lint", diagnostic.Source)
+		chapterDiagnostics[diagnostic.Code] = diagnostic
+	}
+
+	require.Contains(t, chapterDiagnostics, "duplicate-id")
+	assert.Equal(t, lsp.Range{Start: lsp.Position{Line: 3, Character: 7}, End: lsp.Position{Line: 3, Character: 12}}, chapterDiagnostics["duplicate-id"].Range)
+	require.Contains(t, chapterDiagnostics, "missing-alt")
+	assert.Equal(t, lsp.Range{Start: lsp.Position{Line: 3, Character: 14}, End: lsp.Position{Line: 3, Character: 36}}, chapterDiagnostics["missing-alt"].Range)
+	require.Contains(t, chapterDiagnostics, "broken-link")
+	require.Contains(t, chapterDiagnostics, "oxford-commas")
+	assert.Equal(t, lsp.SeverityInformation, chapterDiagnostics["oxford-commas"].Severity)
+	require.NotNil(t, chapterDiagnostics["oxford-commas"].Data)
+
+	in.Reset()
+	writeTestMessage(t, &in, 1, "initialize", map[string]any{"rootUri": fileUri(dir)})
+	writeTestMessage(t, &in, 2, "textDocument/codeAction", map[string]any{
+		"textDocument": map[string]any{"uri": chapterUri},
+		"range":        chapterDiagnostics["missing-alt"].Range,
+		"context": map[string]any{
+			"diagnostics": []lsp.Diagnostic{chapterDiagnostics["missing-alt"], chapterDiagnostics["duplicate-id"], chapterDiagnostics["oxford-commas"]},
+		},
+	})
+	writeTestMessage(t, &in, 3, "shutdown", nil)
+	writeTestMessage(t, &in, 0, "exit", nil)
+
+	out.Reset()
+	require.NoError(t, lsp.NewServer(lsp.Options{}).Run(&in, &out))
+
+	messages = readTestMessages(t, &out)
+	require.Len(t, messages, 3)
+	assert.Equal(t, "null", string(messages[2].Result))
+
+	var actions []lsp.CodeAction
+	require.NoError(t, json.Unmarshal(messages[1].Result, &actions))
+	require.Len(t, actions, 3)
+
+	var expectedEdits = []lsp.TextEdit{
+		{Range: lsp.Range{Start: lsp.Position{Line: 3, Character: 34}, End: lsp.Position{Line: 3, Character: 34}}, NewText: ` alt=""`},
+		{Range: lsp.Range{Start: lsp.Position{Line: 3, Character: 12}, End: lsp.Position{Line: 3, Character: 12}}, NewText: "_2"},
+		{Range: chapterDiagnostics["oxford-commas"].Range, NewText: chapterDiagnostics["oxford-commas"].Data.Replacement},
+	}
+	for i, action := range actions {
+		assert.Equal(t, "quickfix", action.Kind)
+		require.NotNil(t, action.Edit)
+		assert.Equal(t, []lsp.TextEdit{expectedEdits[i]}, action.Edit.Changes[chapterUri], action.Title)
+	}
+}
+
+func TestServerOnlyGetsFilesOnDiskOnSave(t *testing.T) {
+	t.Parallel()
+
+	var dir = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "OEBPS", "Text"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OEBPS", "content.opf"), []byte(testOpf), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OEBPS", "Text", "chapter1.xhtml"), []byte(testChapter), 0644))
+
+	var (
+		chapterUri = fileUri(filepath.Join(dir, "OEBPS", "Text", "chapter1.xhtml"))
+		opfUri     = fileUri(filepath.Join(dir, "OEBPS", "content.opf"))
+		server     = lsp.NewServer(lsp.Options{})
+		in, out    bytes.Buffer
+	)
+	writeTestMessage(t, &in, 1, "initialize", map[string]any{"rootUri": fileUri(dir)})
+	writeTestMessage(t, &in, 0, "initialized", map[string]any{})
+	require.NoError(t, server.Run(&in, &out))
+
+	var diagnostics = getPublishedDiagnostics(t, readTestMessages(t, &out)[1:])
+	require.Len(t, diagnostics[opfUri], 1)
+	assert.Contains(t, getDiagnosticCodes(diagnostics[chapterUri]), "oxford-commas")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "OEBPS", "Images"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OEBPS", "Images", "missing.jpg"), []byte("image"), 0644))
+
+	// changes only lint the changed text against the files on disk from the last time the epub was saved
+	writeTestMessage(t, &in, 0, "textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": chapterUri, "version": 2},
+		"contentChanges": []map[string]any{{"text": strings.Replace(testChapter, "pears and", "pears, and", 1)}},
+	})
+	require.NoError(t, server.Run(&in, &out))
+
+	diagnostics = getPublishedDiagnostics(t, readTestMessages(t, &out))
+	require.Len(t, diagnostics[opfUri], 1)
+	assert.NotContains(t, getDiagnosticCodes(diagnostics[chapterUri]), "oxford-commas")
+	assert.Contains(t, getDiagnosticCodes(diagnostics[chapterUri]), "duplicate-id")
+
+	writeTestMessage(t, &in, 0, "textDocument/didSave", map[string]any{
+		"textDocument": map[string]any{"uri": chapterUri},
+	})
+	require.NoError(t, server.Run(&in, &out))
+
+	diagnostics = getPublishedDiagnostics(t, readTestMessages(t, &out))
+	require.Contains(t, diagnostics, opfUri)
+	assert.Empty(t, diagnostics[opfUri])
+	assert.NotContains(t, getDiagnosticCodes(diagnostics[chapterUri]), "oxford-commas")
+}
+
+func TestServerKeepsRunningAfterAMessageFailsToParse(t *testing.T) {
+	t.Parallel()
+
+	var (
+		in, out bytes.Buffer
+		body    = `{"jsonrpc": "2.0", "id": 1, "method": `
+	)
+	fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	writeTestMessage(t, &in, 2, "shutdown", nil)
+	writeTestMessage(t, &in, 0, "exit", nil)
+
+	require.NoError(t, lsp.NewServer(lsp.Options{}).Run(&in, &out))
+
+	var messages = readTestMessages(t, &out)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "null", string(messages[0].Id))
+	require.NotNil(t, messages[0].Error)
+	assert.Equal(t, -32700, messages[0].Error.Code)
+	assert.Equal(t, "2", string(messages[1].Id))
+	assert.Equal(t, "null", string(messages[1].Result))
+}
+
+func getPublishedDiagnostics(t *testing.T, messages []testMessage) map[string][]lsp.Diagnostic {
+	var diagnostics = make(map[string][]lsp.Diagnostic)
+	for _, message := range messages {
+		require.Equal(t, "textDocument/publishDiagnostics", message.Method)
+
+		var params testPublishDiagnosticsParams
+		require.NoError(t, json.Unmarshal(message.Params, &params))
+		diagnostics[params.Uri] = params.Diagnostics
+	}
+
+	return diagnostics
+}
+
+func getDiagnosticCodes(diagnostics []lsp.Diagnostic) []string {
+	var codes []string
+	for _, diagnostic := range diagnostics {
+		codes = append(codes, diagnostic.Code)
+	}
+
+	return codes
+}
+
+func writeTestMessage(t *testing.T, w *bytes.Buffer, id int, method string, params any) {
+	var message = map[string]any{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+	}
+
+	if id != 0 {
+		message["id"] = id
+	}
+
+	body, err := json.Marshal(message)
+	require.NoError(t, err)
+
+	fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func readTestMessages(t *testing.T, r *bytes.Buffer) []testMessage {
+	var (
+		messages []testMessage
+		reader   = bufio.NewReader(r)
+	)
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return messages
+		}
+
+		contentLength, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "Content-Length:")))
+		require.NoError(t, err)
+
+		_, err = reader.ReadString('\n')
+		require.NoError(t, err)
+
+		var body = make([]byte, contentLength)
+		_, err = io.ReadFull(reader, body)
+		require.NoError(t, err)
+
+		var message testMessage
+		require.NoError(t, json.Unmarshal(body, &message))
+		messages = append(messages, message)
+	}
+}
+
+func fileUri(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
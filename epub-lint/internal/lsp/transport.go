@@ -0,0 +1,65 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var ErrMissingContentLength = errors.New("message is missing its Content-Length header")
+
+// readMessage reads the body of the next message which is preceded by headers that include its Content-Length
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	var contentLength = -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			continue
+		}
+
+		contentLength, err = strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the Content-Length header %q: %w", line, err)
+		}
+	}
+
+	if contentLength < 0 {
+		return nil, ErrMissingContentLength
+	}
+
+	var body = make([]byte, contentLength)
+	_, err := io.ReadFull(r, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the message body: %w", err)
+	}
+
+	return body, nil
+}
+
+func writeMessage(w io.Writer, message any) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to serialize message: %w", err)
+	}
+
+	_, err = fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package pagelist
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/links"
+)
+
+var (
+	ErrNoBodyEnd      = errors.New("nav file is incorrectly formatted since it has no closing body element")
+	ErrNoNavMapEnd    = errors.New("ncx file is incorrectly formatted since it has no closing navMap element")
+	arabicNumberRegex = regexp.MustCompile(`^\d+$`)
+	romanNumeralRegex = regexp.MustCompile(`(?i)^[ivxlcdm]+$`)
+)
+
+// ReadingOrder gets where a file and id are in the reading order of the epub
+type ReadingOrder struct {
+	spineIndexes map[string]int
+	files        map[string]string
+	// idOffsets are the offsets of the first id attribute with each id in the files which are found the first time a file is used
+	idOffsets map[string]map[string]int
+}
+
+type readingPosition struct {
+	spineIndex, offset int
+}
+
+// NewReadingOrder creates a reading order from the spine order and the contents of the files in it
+// where the file paths are relative to the root of the epub
+func NewReadingOrder(spineOrder []string, files map[string]string) ReadingOrder {
+	var spineIndexes = make(map[string]int, len(spineOrder))
+	for i, file := range spineOrder {
+		if _, exists := spineIndexes[file]; !exists {
+			spineIndexes[file] = i
+		}
+	}
+
+	return ReadingOrder{
+		spineIndexes: spineIndexes,
+		files:        files,
+		idOffsets:    make(map[string]map[string]int),
+	}
+}
+
+func (r ReadingOrder) position(file, id string) readingPosition {
+	spineIndex, ok := r.spineIndexes[file]
+	if !ok {
+		spineIndex = len(r.spineIndexes)
+	}
+
+	var position = readingPosition{spineIndex: spineIndex}
+	if id == "" {
+		return position
+	}
+
+	idOffsets, ok := r.idOffsets[file]
+	if !ok {
+		idOffsets = make(map[string]int)
+		for _, indices := range epubhandler.IdAttributeRegex.FindAllStringSubmatchIndex(r.files[file], -1) {
+			// the id is in the second group when it is in single quotes
+			var valueIndices = indices[2:4]
+			if valueIndices[0] == -1 {
+				valueIndices = indices[4:6]
+			}
+
+			var fileId = r.files[file][valueIndices[0]:valueIndices[1]]
+
+			if _, exists := idOffsets[fileId]; !exists {
+				idOffsets[fileId] = indices[0]
+			}
+		}
+
+		r.idOffsets[file] = idOffsets
+	}
+
+	position.offset = idOffsets[id]
+
+	return position
+}
+
+// SetNavPageList replaces any existing page list in the nav file with one for the page markers
+// which is added to the end of the body of the nav file
+func SetNavPageList(navContents, navFile string, markers []PageMarker) (string, error) {
+	nav, err := epubdoc.ParseNav(navContents)
+	if err != nil {
+		return navContents, err
+	}
+
+	var body = nav.Document.FindElement("body")
+	if body == nil || body.Unclosed() {
+		return navContents, ErrNoBodyEnd
+	}
+
+	for pageList := nav.PageList(); pageList != nil; pageList = nav.PageList() {
+		pageList.Remove()
+	}
+
+	var (
+		pageList = epubdoc.NewElement("nav", epubdoc.Attr{Name: "epub:type", Value: "page-list"}, epubdoc.Attr{Name: "hidden"})
+		list     = epubdoc.NewElement("ol")
+	)
+	body.AppendIndentedChild(pageList)
+	pageList.AppendIndentedChild(list)
+	for _, marker := range markers {
+		var (
+			li   = epubdoc.NewElement("li")
+			link = epubdoc.NewElement("a", epubdoc.Attr{Name: "href", Value: getMarkerLink(navFile, marker)})
+		)
+		link.SetText(marker.Label)
+		li.AppendChild(link)
+		list.AppendIndentedChild(li)
+	}
+
+	return nav.String(), nil
+}
+
+// SetNcxPageList replaces any existing page list in the ncx file with one for the page markers which is added after the nav map.
+// Since page targets share the play order with nav points, the play order of the nav points is updated to follow the reading order as well.
+func SetNcxPageList(ncxContents, ncxFile string, markers []PageMarker, readingOrder ReadingOrder) (string, error) {
+	ncx, err := epubdoc.ParseNcx(ncxContents)
+	if err != nil {
+		return ncxContents, err
+	}
+
+	var navMap = ncx.NavMap()
+	if navMap == nil || navMap.Unclosed() {
+		return ncxContents, ErrNoNavMapEnd
+	}
+
+	for _, pageList := range ncx.Root.FindElements("pageList") {
+		pageList.Remove()
+	}
+
+	var (
+		navPoints         = ncx.NavPoints()
+		navPointPositions = make([]readingPosition, len(navPoints))
+		markerPositions   = make([]readingPosition, len(markers))
+		allPositions      = make([]readingPosition, 0, len(navPoints)+len(markers))
+	)
+	for i, navPoint := range navPoints {
+		if src := navPoint.Src(); src != "" {
+			navPointPositions[i] = readingOrder.position(links.ResolveLink(ncxFile, src))
+		}
+	}
+
+	for i, marker := range markers {
+		markerPositions[i] = readingOrder.position(marker.File, marker.Id)
+	}
+
+	allPositions = append(append(allPositions, navPointPositions...), markerPositions...)
+	slices.SortFunc(allPositions, compareReadingPositions)
+	allPositions = slices.Compact(allPositions)
+
+	var getPlayOrder = func(position readingPosition) string {
+		index, _ := slices.BinarySearchFunc(allPositions, position, compareReadingPositions)
+
+		return strconv.Itoa(index + 1)
+	}
+
+	for i, navPoint := range navPoints {
+		navPoint.SetAttr("playOrder", getPlayOrder(navPointPositions[i]))
+	}
+
+	var pageList = epubdoc.NewElement("pageList")
+	navMap.Parent.InsertIndentedAfter(pageList, navMap)
+	addNavLabel(pageList, "Pages")
+	for i, marker := range markers {
+		var attrs = []epubdoc.Attr{{Name: "id", Value: fmt.Sprintf("page-target-%d", i+1)}, {Name: "type", Value: "special"}}
+		if arabicNumberRegex.MatchString(marker.Label) {
+			attrs[1].Value = "normal"
+			attrs = append(attrs, epubdoc.Attr{Name: "value", Value: marker.Label})
+		} else if romanNumeralRegex.MatchString(marker.Label) {
+			attrs[1].Value = "front"
+		}
+
+		var pageTarget = epubdoc.NewElement("pageTarget", append(attrs, epubdoc.Attr{Name: "playOrder", Value: getPlayOrder(markerPositions[i])})...)
+		pageList.AppendIndentedChild(pageTarget)
+		addNavLabel(pageTarget, marker.Label)
+		pageTarget.AppendIndentedChild(epubdoc.NewElement("content", epubdoc.Attr{Name: "src", Value: getMarkerLink(ncxFile, marker)}))
+	}
+
+	var maxPageNumber int
+	for _, marker := range markers {
+		if page, err := strconv.Atoi(marker.Label); err == nil {
+			maxPageNumber = max(maxPageNumber, page)
+		}
+	}
+
+	setNcxMetaContent(ncx, "dtb:totalPageCount", strconv.Itoa(len(markers)))
+	setNcxMetaContent(ncx, "dtb:maxPageNumber", strconv.Itoa(maxPageNumber))
+
+	return ncx.String(), nil
+}
+
+// addNavLabel adds a navLabel with the label as its text to the end of the element
+func addNavLabel(el *epubdoc.Node, label string) {
+	var (
+		navLabel = epubdoc.NewElement("navLabel")
+		text     = epubdoc.NewElement("text")
+	)
+	el.AppendIndentedChild(navLabel)
+	text.SetText(label)
+	navLabel.AppendIndentedChild(text)
+}
+
+// setNcxMetaContent updates the content of the meta element with the provided name if it exists
+func setNcxMetaContent(ncx *epubdoc.Ncx, name, content string) {
+	if meta := ncx.HeadMeta(name); meta != nil {
+		meta.SetAttr("content", content)
+	}
+}
+
+func compareReadingPositions(a, b readingPosition) int {
+	if a.spineIndex != b.spineIndex {
+		return a.spineIndex - b.spineIndex
+	}
+
+	return a.offset - b.offset
+}
+
+func getMarkerLink(file string, marker PageMarker) string {
+	return links.GetRelativeLink(file, marker.File) + "#" + marker.Id
+}
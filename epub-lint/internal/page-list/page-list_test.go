@@ -0,0 +1,101 @@
+//go:build unit
+
+package pagelist_test
+
+import (
+	"testing"
+
+	pagelist "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/page-list"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMarkers = []pagelist.PageMarker{
+	{File: "OEBPS/Text/ch1.xhtml", Id: "page_ii", Label: "ii"},
+	{File: "OEBPS/Text/ch1.xhtml", Id: "page_1", Label: "1"},
+	{File: "OEBPS/Text/ch2.xhtml", Id: "page_2", Label: "2"},
+}
+
+func TestSetNavPageList(t *testing.T) {
+	t.Parallel()
+
+	actual, err := pagelist.SetNavPageList(`<html xmlns:epub="http://www.idpf.org/2007/ops">
+  <body>
+    <nav epub:type="toc"><ol><li><a href="Text/ch1.xhtml">One</a></li></ol></nav>
+    <nav epub:type="page-list" hidden=""><ol><li><a href="Text/ch1.xhtml#old">1</a></li></ol></nav>
+  </body>
+</html>`, "OEBPS/nav.xhtml", testMarkers)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<html xmlns:epub="http://www.idpf.org/2007/ops">
+  <body>
+    <nav epub:type="toc"><ol><li><a href="Text/ch1.xhtml">One</a></li></ol></nav>
+    <nav epub:type="page-list" hidden="">
+      <ol>
+        <li><a href="Text/ch1.xhtml#page_ii">ii</a></li>
+        <li><a href="Text/ch1.xhtml#page_1">1</a></li>
+        <li><a href="Text/ch2.xhtml#page_2">2</a></li>
+      </ol>
+    </nav>
+  </body>
+</html>`, actual)
+
+	_, err = pagelist.SetNavPageList(`<html><body>`, "OEBPS/nav.xhtml", testMarkers)
+	assert.ErrorIs(t, err, pagelist.ErrNoBodyEnd)
+}
+
+func TestSetNcxPageList(t *testing.T) {
+	t.Parallel()
+
+	var readingOrder = pagelist.NewReadingOrder([]string{"OEBPS/Text/ch1.xhtml", "OEBPS/Text/ch2.xhtml"}, map[string]string{
+		"OEBPS/Text/ch1.xhtml": `<html><body><span id="page_ii"/><h1 id='one'>One</h1><span id="page_1"/></body></html>`,
+		"OEBPS/Text/ch2.xhtml": `<html><body><span id="page_2"/><h1>Two</h1></body></html>`,
+	})
+
+	actual, err := pagelist.SetNcxPageList(`<ncx>
+  <head>
+    <meta name="dtb:totalPageCount" content="0"/>
+    <meta name="dtb:maxPageNumber" content="0"/>
+  </head>
+  <navMap>
+    <navPoint id="n1" playOrder="1"><navLabel><text>One</text></navLabel><content src="Text/ch1.xhtml#one"/></navPoint>
+    <navPoint id="n2"><navLabel><text>Two</text></navLabel><content src="Text/ch2.xhtml"/></navPoint>
+  </navMap>
+  <pageList><pageTarget id="old" type="normal" value="1" playOrder="3"><navLabel><text>1</text></navLabel><content src="Text/ch1.xhtml#old"/></pageTarget></pageList>
+</ncx>`, "OEBPS/toc.ncx", testMarkers, readingOrder)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<ncx>
+  <head>
+    <meta name="dtb:totalPageCount" content="3"/>
+    <meta name="dtb:maxPageNumber" content="2"/>
+  </head>
+  <navMap>
+    <navPoint id="n1" playOrder="2"><navLabel><text>One</text></navLabel><content src="Text/ch1.xhtml#one"/></navPoint>
+    <navPoint id="n2" playOrder="4"><navLabel><text>Two</text></navLabel><content src="Text/ch2.xhtml"/></navPoint>
+  </navMap>
+  <pageList>
+    <navLabel>
+      <text>Pages</text>
+    </navLabel>
+    <pageTarget id="page-target-1" type="front" playOrder="1">
+      <navLabel>
+        <text>ii</text>
+      </navLabel>
+      <content src="Text/ch1.xhtml#page_ii"/>
+    </pageTarget>
+    <pageTarget id="page-target-2" type="normal" value="1" playOrder="3">
+      <navLabel>
+        <text>1</text>
+      </navLabel>
+      <content src="Text/ch1.xhtml#page_1"/>
+    </pageTarget>
+    <pageTarget id="page-target-3" type="normal" value="2" playOrder="5">
+      <navLabel>
+        <text>2</text>
+      </navLabel>
+      <content src="Text/ch2.xhtml#page_2"/>
+    </pageTarget>
+  </pageList>
+</ncx>`, actual)
+}
@@ -0,0 +1,351 @@
+package pagelist
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+)
+
+const idPrefix = "page_"
+
+var (
+	pageBreakTagRegex  = regexp.MustCompile(`(?i)<([a-z][\w:-]*)\b[^>]*?(?:epub:type\s*=\s*["'][^"']*\bpagebreak\b[^"']*["']|role\s*=\s*["']doc-pagebreak["'])[^>]*>`)
+	pageIdTagRegex     = regexp.MustCompile(`(?i)<([a-z][\w:-]*)\b[^>]*?\sid\s*=\s*["']((?:page|pg)[_-]?(?:\d+|[ivxlcdm]+))["'][^>]*>`)
+	pageIdRegex        = regexp.MustCompile(`(?i)^(?:page|pg|p)[_-]?(\d+|[ivxlcdm]+)$`)
+	ariaLabelAttrRegex = regexp.MustCompile(`\saria-label\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	titleAttrRegex     = regexp.MustCompile(`\stitle\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	epubTypeAttrRegex  = regexp.MustCompile(`\sepub:type\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	roleAttrRegex      = regexp.MustCompile(`\srole\s*=\s*["']`)
+	bodyStartRegex     = regexp.MustCompile(`(?i)<body\b[^>]*>`)
+	skippedTagRegex    = regexp.MustCompile(`(?i)^<(svg|math|script|style)\b`)
+	nonIdCharRegex     = regexp.MustCompile(`[^\w.-]`)
+)
+
+// PageMarker is a print page number location in a content file
+type PageMarker struct {
+	// File is the path of the file the page marker is in relative to the root of the epub
+	File  string
+	Id    string
+	Label string
+}
+
+// Normalizer finds page markers in content files and normalizes them so that they are pagebreak elements with an id and label.
+// It is meant to be used on the content files in spine order since page markers without a label are numbered based on the previous page.
+type Normalizer struct {
+	// Pattern is an optional pattern that matches page markers in the text where the first capture group is the page number.
+	// Each match is replaced with a pagebreak element.
+	Pattern      *regexp.Regexp
+	Version      int
+	previousPage int
+}
+
+type pageMarkerMatch struct {
+	start, end int
+	tagName    string
+	label      string
+	isTag      bool
+}
+
+// Normalize finds the pagebreak elements, empty elements with a page id like "page_12" or "p12",
+// and matches of the pattern in the file and normalizes them returning the updated file contents and its page markers
+func (n *Normalizer) Normalize(file, contents string) (string, []PageMarker) {
+	var matches []pageMarkerMatch
+	for _, indices := range pageBreakTagRegex.FindAllStringSubmatchIndex(contents, -1) {
+		var (
+			tag     = contents[indices[0]:indices[1]]
+			tagName = contents[indices[2]:indices[3]]
+			label   = epubhandler.GetTagAttributeValue(ariaLabelAttrRegex, tag)
+		)
+		if label == "" {
+			label = epubhandler.GetTagAttributeValue(titleAttrRegex, tag)
+		}
+
+		if label == "" && !strings.HasSuffix(tag, "/>") {
+			if closingIndex := strings.Index(contents[indices[1]:], "</"+tagName); closingIndex != -1 {
+				label = html.UnescapeString(strings.TrimSpace(linter.HtmlTagRegex.ReplaceAllString(contents[indices[1]:indices[1]+closingIndex], "")))
+			}
+		}
+
+		if label == "" {
+			label = getLabelFromId(epubhandler.GetTagAttributeValue(epubhandler.IdAttributeRegex, tag))
+		}
+
+		matches = append(matches, pageMarkerMatch{start: indices[0], end: indices[1], tagName: tagName, label: label, isTag: true})
+	}
+
+	for _, indices := range pageIdTagRegex.FindAllStringSubmatchIndex(contents, -1) {
+		var (
+			tag     = contents[indices[0]:indices[1]]
+			tagName = contents[indices[2]:indices[3]]
+		)
+		if !strings.HasSuffix(tag, "/>") && !strings.HasPrefix(strings.TrimLeft(contents[indices[1]:], " \t\r\n"), "</"+tagName+">") {
+			continue
+		}
+
+		matches = append(matches, pageMarkerMatch{start: indices[0], end: indices[1], tagName: tagName, label: getLabelFromId(contents[indices[4]:indices[5]]), isTag: true})
+	}
+
+	if n.Pattern != nil {
+		for _, indices := range n.Pattern.FindAllStringSubmatchIndex(contents, -1) {
+			if isInsideTag(contents, indices[0]) {
+				continue
+			}
+
+			var label string
+			for group := 1; group < len(indices)/2 && label == ""; group++ {
+				if indices[group*2] != -1 {
+					label = strings.TrimSpace(contents[indices[group*2]:indices[group*2+1]])
+				}
+			}
+
+			if label == "" {
+				label = strings.TrimSpace(linter.HtmlTagRegex.ReplaceAllString(contents[indices[0]:indices[1]], ""))
+			}
+
+			matches = append(matches, pageMarkerMatch{start: indices[0], end: indices[1], label: label})
+		}
+	}
+
+	if len(matches) == 0 {
+		return contents, nil
+	}
+
+	slices.SortFunc(matches, func(a, b pageMarkerMatch) int {
+		return a.start - b.start
+	})
+
+	var (
+		ids         = epubhandler.GetIds(contents)
+		markers     []PageMarker
+		newContents strings.Builder
+		lastEnd     int
+	)
+	for _, match := range matches {
+		if match.start < lastEnd {
+			continue
+		}
+
+		var label = match.label
+		if label == "" {
+			label = strconv.Itoa(n.previousPage + 1)
+		}
+
+		if page, err := strconv.Atoi(label); err == nil {
+			n.previousPage = page
+		}
+
+		var marker = PageMarker{
+			File:  file,
+			Label: label,
+		}
+
+		newContents.WriteString(contents[lastEnd:match.start])
+		if match.isTag {
+			var tag = contents[match.start:match.end]
+			marker.Id = epubhandler.GetTagAttributeValue(epubhandler.IdAttributeRegex, tag)
+			if marker.Id == "" {
+				marker.Id = getUniqueId(ids, label)
+				tag = epubhandler.AddTagAttribute(tag, fmt.Sprintf("id=%q", marker.Id))
+			}
+
+			newContents.WriteString(n.normalizeTag(tag, label))
+		} else {
+			marker.Id = getUniqueId(ids, label)
+			newContents.WriteString(getPageMarkerElement(marker.Id, label, n.Version))
+		}
+
+		markers = append(markers, marker)
+		lastEnd = match.end
+	}
+
+	newContents.WriteString(contents[lastEnd:])
+
+	if n.Version != 2 {
+		return linter.AddEpubNamespace(newContents.String()), markers
+	}
+
+	return newContents.String(), markers
+}
+
+// normalizeTag makes sure the tag is marked as a pagebreak and has a label
+func (n *Normalizer) normalizeTag(tag, label string) string {
+	var escapedLabel = html.EscapeString(label)
+	if n.Version == 2 {
+		if !titleAttrRegex.MatchString(tag) {
+			tag = epubhandler.AddTagAttribute(tag, `title="`+escapedLabel+`"`)
+		}
+
+		return tag
+	}
+
+	if indices := epubTypeAttrRegex.FindStringSubmatchIndex(tag); indices == nil {
+		tag = epubhandler.AddTagAttribute(tag, `epub:type="pagebreak"`)
+	} else if !strings.Contains(tag[indices[0]:indices[1]], "pagebreak") {
+		var valueEnd = max(indices[3], indices[5])
+		tag = tag[:valueEnd] + " pagebreak" + tag[valueEnd:]
+	}
+
+	if !roleAttrRegex.MatchString(tag) {
+		tag = epubhandler.AddTagAttribute(tag, `role="doc-pagebreak"`)
+	}
+
+	if !ariaLabelAttrRegex.MatchString(tag) {
+		tag = epubhandler.AddTagAttribute(tag, `aria-label="`+escapedLabel+`"`)
+	}
+
+	return tag
+}
+
+// Synthesizer adds page markers to content files every set number of characters for epubs that do not have print page numbers.
+// It is meant to be used on the content files in spine order since the character count and page number carry over between files.
+type Synthesizer struct {
+	// Every is the number of characters per page
+	Every      int
+	Version    int
+	page       int
+	count      int
+	nextPageAt int
+}
+
+// Synthesize adds a page marker before the word that starts each page in the body of the file
+// returning the updated file contents and its page markers
+func (s *Synthesizer) Synthesize(file, contents string) (string, []PageMarker) {
+	var bodyIndices = bodyStartRegex.FindStringIndex(contents)
+	if bodyIndices == nil || s.Every <= 0 {
+		return contents, nil
+	}
+
+	var (
+		ids           = epubhandler.GetIds(contents)
+		markers       []PageMarker
+		newContents   strings.Builder
+		lastWrite     int
+		previousSpace = true
+	)
+	for i := bodyIndices[1]; i < len(contents); {
+		switch contents[i] {
+		case '<':
+			var tagEnd = strings.Index(contents[i:], ">")
+			if tagEnd == -1 {
+				i = len(contents)
+
+				continue
+			}
+
+			if groups := skippedTagRegex.FindStringSubmatch(contents[i:]); groups != nil && !strings.HasSuffix(contents[i:i+tagEnd+1], "/>") {
+				if closingIndex := strings.Index(strings.ToLower(contents[i:]), "</"+strings.ToLower(groups[1])); closingIndex != -1 {
+					tagEnd = closingIndex + strings.Index(contents[i+closingIndex:], ">")
+				}
+			}
+
+			i += tagEnd + 1
+			previousSpace = true
+
+			continue
+		case '&':
+			if entityEnd := strings.Index(contents[i:], ";"); entityEnd != -1 && entityEnd < 10 {
+				s.count++
+				previousSpace = false
+				i += entityEnd + 1
+
+				continue
+			}
+		}
+
+		var r, size = utf8.DecodeRuneInString(contents[i:])
+		if unicode.IsSpace(r) {
+			if !previousSpace {
+				s.count++
+			}
+
+			previousSpace = true
+			i += size
+
+			continue
+		}
+
+		if previousSpace && s.count >= s.nextPageAt {
+			s.page++
+			s.nextPageAt += s.Every
+
+			var (
+				label = strconv.Itoa(s.page)
+				id    = getUniqueId(ids, label)
+			)
+			newContents.WriteString(contents[lastWrite:i])
+			newContents.WriteString(getPageMarkerElement(id, label, s.Version))
+			lastWrite = i
+
+			markers = append(markers, PageMarker{
+				File:  file,
+				Id:    id,
+				Label: label,
+			})
+		}
+
+		s.count++
+		previousSpace = false
+		i += size
+	}
+
+	if len(markers) == 0 {
+		return contents, nil
+	}
+
+	newContents.WriteString(contents[lastWrite:])
+
+	if s.Version != 2 {
+		return linter.AddEpubNamespace(newContents.String()), markers
+	}
+
+	return newContents.String(), markers
+}
+
+func getPageMarkerElement(id, label string, version int) string {
+	var escapedLabel = html.EscapeString(label)
+	if version == 2 {
+		return fmt.Sprintf(`<span id=%q title="%s"></span>`, id, escapedLabel)
+	}
+
+	return fmt.Sprintf(`<span epub:type="pagebreak" role="doc-pagebreak" id=%q aria-label="%s"></span>`, id, escapedLabel)
+}
+
+// getUniqueId gets an id for the page label that is not already in use in the file and marks it as in use
+func getUniqueId(ids map[string]struct{}, label string) string {
+	var (
+		baseId = idPrefix + nonIdCharRegex.ReplaceAllString(label, "_")
+		id     = baseId
+	)
+	for i := 2; ; i++ {
+		if _, exists := ids[id]; !exists {
+			break
+		}
+
+		id = fmt.Sprintf("%s_%d", baseId, i)
+	}
+
+	ids[id] = struct{}{}
+
+	return id
+}
+
+func getLabelFromId(id string) string {
+	var groups = pageIdRegex.FindStringSubmatch(id)
+	if groups == nil {
+		return ""
+	}
+
+	return groups[1]
+}
+
+func isInsideTag(contents string, index int) bool {
+	return strings.LastIndex(contents[:index], "<") > strings.LastIndex(contents[:index], ">")
+}
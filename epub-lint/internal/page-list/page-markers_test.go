@@ -0,0 +1,99 @@
+//go:build unit
+
+package pagelist_test
+
+import (
+	"regexp"
+	"testing"
+
+	pagelist "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/page-list"
+	"github.com/stretchr/testify/assert"
+)
+
+type normalizeTestCase struct {
+	contents         string
+	pattern          *regexp.Regexp
+	version          int
+	expectedContents string
+	expectedMarkers  []pagelist.PageMarker
+}
+
+var normalizeTestCases = map[string]normalizeTestCase{
+	"no page markers results in no changes": {
+		contents:         `<html xmlns:epub="http://www.idpf.org/2007/ops"><body><p id="p">Text</p></body></html>`,
+		version:          3,
+		expectedContents: `<html xmlns:epub="http://www.idpf.org/2007/ops"><body><p id="p">Text</p></body></html>`,
+	},
+	"existing pagebreak elements get their missing id, role, and label from the text": {
+		contents:         `<html xmlns:epub="http://www.idpf.org/2007/ops"><body><span epub:type="pagebreak">iv</span><p>Text</p><span epub:type="pagebreak" id="pg5" title="5"/></body></html>`,
+		version:          3,
+		expectedContents: `<html xmlns:epub="http://www.idpf.org/2007/ops"><body><span epub:type="pagebreak" id="page_iv" role="doc-pagebreak" aria-label="iv">iv</span><p>Text</p><span epub:type="pagebreak" id="pg5" title="5" role="doc-pagebreak" aria-label="5"/></body></html>`,
+		expectedMarkers: []pagelist.PageMarker{
+			{File: "OEBPS/ch.xhtml", Id: "page_iv", Label: "iv"},
+			{File: "OEBPS/ch.xhtml", Id: "pg5", Label: "5"},
+		},
+	},
+	"empty elements with page ids become pagebreak elements and the epub namespace is added": {
+		contents:         `<html><body><p>Text<a id="pg12"></a> more</p><p id="page13">Not a page marker</p><span id="page_13" /></body></html>`,
+		version:          3,
+		expectedContents: `<html xmlns:epub="http://www.idpf.org/2007/ops"><body><p>Text<a id="pg12" epub:type="pagebreak" role="doc-pagebreak" aria-label="12"></a> more</p><p id="page13">Not a page marker</p><span id="page_13" epub:type="pagebreak" role="doc-pagebreak" aria-label="13" /></body></html>`,
+		expectedMarkers: []pagelist.PageMarker{
+			{File: "OEBPS/ch.xhtml", Id: "pg12", Label: "12"},
+			{File: "OEBPS/ch.xhtml", Id: "page_13", Label: "13"},
+		},
+	},
+	"empty elements with paragraph ids are not page markers": {
+		contents:         `<html><body><a id="p12"></a><p id="p13">Text</p><a id="p_14"/></body></html>`,
+		version:          3,
+		expectedContents: `<html><body><a id="p12"></a><p id="p13">Text</p><a id="p_14"/></body></html>`,
+	},
+	"pattern matches are replaced with pagebreak elements with unique ids": {
+		contents:         `<html><body><p id="page_1">One [Page 1] two</p><p title="[Page 9]">Three {Page 2}</p></body></html>`,
+		pattern:          regexp.MustCompile(`[\[{]Page (\d+)[\]}]`),
+		version:          2,
+		expectedContents: `<html><body><p id="page_1">One <span id="page_1_2" title="1"></span> two</p><p title="[Page 9]">Three <span id="page_2" title="2"></span></p></body></html>`,
+		expectedMarkers: []pagelist.PageMarker{
+			{File: "OEBPS/ch.xhtml", Id: "page_1_2", Label: "1"},
+			{File: "OEBPS/ch.xhtml", Id: "page_2", Label: "2"},
+		},
+	},
+}
+
+func TestNormalizerNormalize(t *testing.T) {
+	for name, args := range normalizeTestCases {
+		t.Run(name, func(t *testing.T) {
+			var normalizer = pagelist.Normalizer{Pattern: args.pattern, Version: args.version}
+
+			actualContents, actualMarkers := normalizer.Normalize("OEBPS/ch.xhtml", args.contents)
+
+			assert.Equal(t, args.expectedContents, actualContents)
+			assert.Equal(t, args.expectedMarkers, actualMarkers)
+		})
+	}
+}
+
+func TestNormalizerNormalizeNumbersUnlabeledMarkersFromThePreviousPage(t *testing.T) {
+	var normalizer = pagelist.Normalizer{Version: 2}
+
+	_, firstMarkers := normalizer.Normalize("OEBPS/ch1.xhtml", `<html><body><span id="page_7"/></body></html>`)
+	_, secondMarkers := normalizer.Normalize("OEBPS/ch2.xhtml", `<html><body><span role="doc-pagebreak"/></body></html>`)
+
+	assert.Equal(t, []pagelist.PageMarker{{File: "OEBPS/ch1.xhtml", Id: "page_7", Label: "7"}}, firstMarkers)
+	assert.Equal(t, []pagelist.PageMarker{{File: "OEBPS/ch2.xhtml", Id: "page_8", Label: "8"}}, secondMarkers)
+}
+
+func TestSynthesizerSynthesize(t *testing.T) {
+	var synthesizer = pagelist.Synthesizer{Every: 10, Version: 2}
+
+	firstContents, firstMarkers := synthesizer.Synthesize("OEBPS/ch1.xhtml", `<html><head><title>Title text</title></head><body><p>Short &amp; sweet</p><svg><text>Not counted at all</text></svg><p>Another line</p></body></html>`)
+	secondContents, secondMarkers := synthesizer.Synthesize("OEBPS/ch2.xhtml", `<html><body><p>More words</p></body></html>`)
+
+	assert.Equal(t, `<html><head><title>Title text</title></head><body><p><span id="page_1" title="1"></span>Short &amp; sweet</p><svg><text>Not counted at all</text></svg><p><span id="page_2" title="2"></span>Another <span id="page_3" title="3"></span>line</p></body></html>`, firstContents)
+	assert.Equal(t, []pagelist.PageMarker{
+		{File: "OEBPS/ch1.xhtml", Id: "page_1", Label: "1"},
+		{File: "OEBPS/ch1.xhtml", Id: "page_2", Label: "2"},
+		{File: "OEBPS/ch1.xhtml", Id: "page_3", Label: "3"},
+	}, firstMarkers)
+	assert.Equal(t, `<html><body><p>More <span id="page_4" title="4"></span>words</p></body></html>`, secondContents)
+	assert.Equal(t, []pagelist.PageMarker{{File: "OEBPS/ch2.xhtml", Id: "page_4", Label: "4"}}, secondMarkers)
+}
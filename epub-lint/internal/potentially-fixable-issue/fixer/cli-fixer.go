@@ -21,7 +21,7 @@ type CliFixer struct {
 	getFile                                     FileGetter
 	writeFile                                   FileWriter
 	cssFiles, handledFiles                      []string
-	opfFolder                                   string
+	opfFolder, newCssFile                       string
 	contextBreak                                *string
 	runAll, skipCss, runSectionBreak            bool
 	addCssSectionIfMissing, addCssPageIfMissing bool
@@ -79,7 +79,7 @@ func (c *CliFixer) Setup() error {
 	var filePathToText = make(map[string]string, len(c.epubInfo.HtmlFiles))
 	// Collect file contents
 	for file := range c.epubInfo.HtmlFiles {
-		var filePath = getFilePath(c.opfFolder, file)
+		var filePath = epubhandler.GetFilePath(c.opfFolder, file)
 		fileText, err := c.getFile(filePath)
 		if err != nil {
 			return err
@@ -123,6 +123,11 @@ func (c *CliFixer) Run() error {
 		}
 	}
 
+	if len(c.cssFiles) == 0 && (c.addCssSectionIfMissing || c.addCssPageIfMissing) {
+		c.newCssFile = getNewCssFile(c.epubInfo)
+		addStylesheetLinks(c.suggestionManager.FileSuggestionData, epubhandler.GetFilePath(c.opfFolder, c.newCssFile))
+	}
+
 	c.handledFiles = make([]string, len(c.suggestionManager.FileSuggestionData))
 	for _, fileData := range c.suggestionManager.FileSuggestionData {
 		err = c.writeFile(fileData.Name, fileData.Text)
@@ -141,6 +146,12 @@ func (c *CliFixer) HandleCss() ([]string, error) {
 		return c.handledFiles, nil
 	}
 
+	if c.newCssFile != "" {
+		logger.WriteInfof("Creating %q since the epub does not have a css file\n", c.newCssFile)
+
+		return createCssFile(c.addCssSectionIfMissing, c.addCssPageIfMissing, c.epubInfo.OpfFile, c.opfFolder, c.newCssFile, *c.contextBreak, c.handledFiles, c.getFile, c.writeFile)
+	}
+
 	var cssSelectionPrompt strings.Builder
 	cssSelectionPrompt.WriteString("Please enter the number of the css file to append the css to:\n")
 
@@ -179,11 +190,13 @@ func promptAboutSuggestions(suggestionManager *suggestionmanager.SuggestionManag
 		switch strings.ToLower(resp) {
 		case "y":
 			err = suggestionManager.AcceptSuggestion()
-			if err != nil {
+			if errors.Is(err, suggestionmanager.ErrSuggestionIsStale) {
+				logger.WriteWarn("Skipping the update since an earlier update changed the text it applies to.")
+			} else if err != nil {
 				logger.WriteFatal(err.Error())
+			} else {
+				valueReplaced = true
 			}
-
-			valueReplaced = true
 		case "q":
 			return valueReplaced, true
 		}
@@ -1,13 +1,22 @@
 package fixer
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/links"
 	potentiallyfixableissue "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/potentially-fixable-issue"
-	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	suggestionmanager "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/suggestion-manager"
 )
 
-func getFilePath(opfFolder, file string) string {
-	return filehandler.JoinPath(opfFolder, file)
-}
+const (
+	newCssFileName   = "stylesheet"
+	newCssId         = "stylesheet"
+	cssMediaType     = "text/css"
+	cssFileExtension = ".css"
+)
 
 func updateCssFile(addCssSectionIfMissing, addCssPageIfMissing bool, selectedCssFile, contextBreak string, handledFiles []string, getFile FileGetter, writeFile FileWriter) ([]string, error) {
 	css, err := getFile(selectedCssFile)
@@ -15,15 +24,7 @@ func updateCssFile(addCssSectionIfMissing, addCssPageIfMissing bool, selectedCss
 		return nil, err
 	}
 
-	var newCssText = css
-	if addCssSectionIfMissing {
-		newCssText = potentiallyfixableissue.AddCssSectionBreakIfMissing(newCssText, contextBreak)
-	}
-
-	if addCssPageIfMissing {
-		newCssText = potentiallyfixableissue.AddCssPageBreakIfMissing(newCssText)
-	}
-
+	var newCssText = addCss(addCssSectionIfMissing, addCssPageIfMissing, css, contextBreak)
 	if newCssText == css {
 		return handledFiles, nil
 	}
@@ -35,3 +36,75 @@ func updateCssFile(addCssSectionIfMissing, addCssPageIfMissing bool, selectedCss
 
 	return append(handledFiles, selectedCssFile), nil
 }
+
+// getNewCssFile gets a name for a new css file in the opf folder that is not already used by a file in the manifest
+func getNewCssFile(epubInfo *epubhandler.EpubInfo) string {
+	var (
+		cssFile = newCssFileName + cssFileExtension
+		i       = 1
+	)
+	for isInManifest(epubInfo, cssFile) {
+		cssFile = fmt.Sprintf("%s-%d%s", newCssFileName, i, cssFileExtension)
+		i++
+	}
+
+	return cssFile
+}
+
+func isInManifest(epubInfo *epubhandler.EpubInfo, file string) bool {
+	for _, files := range []map[string]struct{}{epubInfo.HtmlFiles, epubInfo.CssFiles, epubInfo.ImagesFiles, epubInfo.OtherFiles} {
+		if _, ok := files[file]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addStylesheetLinks links each of the content files to the css file which is relative to the root of the epub
+func addStylesheetLinks(fileSuggestionData []suggestionmanager.FileSuggestionInfo, cssFilePath string) {
+	for i, fileData := range fileSuggestionData {
+		var href = (&url.URL{Path: links.GetRelativeLink(fileData.Name, cssFilePath)}).EscapedPath()
+
+		fileSuggestionData[i].Text = epubhandler.AddStylesheetLink(fileData.Text, href)
+	}
+}
+
+// createCssFile creates the css file with the section and page break css and adds it to the manifest
+// where the css file is relative to the opf folder
+func createCssFile(addCssSectionIfMissing, addCssPageIfMissing bool, opfFile, opfFolder, cssFile, contextBreak string, handledFiles []string, getFile FileGetter, writeFile FileWriter) ([]string, error) {
+	var cssFilePath = epubhandler.GetFilePath(opfFolder, cssFile)
+	err := writeFile(cssFilePath, addCss(addCssSectionIfMissing, addCssPageIfMissing, "", contextBreak))
+	if err != nil {
+		return nil, err
+	}
+
+	opfContents, err := getFile(opfFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var id = newCssId
+	for strings.Contains(opfContents, fmt.Sprintf(`id=%q`, id)) {
+		id = "epub-lint-" + id
+	}
+
+	err = writeFile(opfFile, epubhandler.AddFileToOpf(opfContents, (&url.URL{Path: cssFile}).EscapedPath(), id, cssMediaType))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(handledFiles, cssFilePath, opfFile), nil
+}
+
+func addCss(addCssSectionIfMissing, addCssPageIfMissing bool, css, contextBreak string) string {
+	if addCssSectionIfMissing {
+		css = potentiallyfixableissue.AddCssSectionBreakIfMissing(css, contextBreak)
+	}
+
+	if addCssPageIfMissing {
+		css = potentiallyfixableissue.AddCssPageBreakIfMissing(css)
+	}
+
+	return css
+}
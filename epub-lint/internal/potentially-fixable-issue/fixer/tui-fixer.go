@@ -25,7 +25,7 @@ type TuiFixer struct {
 	logFile                                     string
 	file                                        *os.File
 	opfFolder                                   string
-	selectedCssFile                             string
+	selectedCssFile, newCssFile                 string
 	contextBreak                                *string
 	runAll, skipCss, runSectionBreak            bool
 	addCssSectionIfMissing, addCssPageIfMissing bool
@@ -68,7 +68,7 @@ func (t *TuiFixer) Setup() error {
 	var filePathToText = make(map[string]string, len(t.epubInfo.HtmlFiles))
 	// Collect file contents
 	for file := range t.epubInfo.HtmlFiles {
-		var filePath = getFilePath(t.opfFolder, file)
+		var filePath = epubhandler.GetFilePath(t.opfFolder, file)
 		fileText, err := t.getFile(filePath)
 		if err != nil {
 			return err
@@ -99,6 +99,15 @@ func (t *TuiFixer) Run() error {
 		return model.Err
 	}
 
+	t.addCssPageIfMissing = model.PotentiallyFixableIssuesInfo.AddCssPageBreakIfMissing
+	t.addCssSectionIfMissing = model.PotentiallyFixableIssuesInfo.AddCssSectionBreakIfMissing
+	t.selectedCssFile = model.CssSelectionInfo.SelectedCssFile
+
+	if len(t.cssFiles) == 0 && (t.addCssSectionIfMissing || t.addCssPageIfMissing) {
+		t.newCssFile = getNewCssFile(t.epubInfo)
+		addStylesheetLinks(model.PotentiallyFixableIssuesInfo.SuggestionManager.FileSuggestionData, epubhandler.GetFilePath(t.opfFolder, t.newCssFile))
+	}
+
 	t.handledFiles = make([]string, len(model.PotentiallyFixableIssuesInfo.SuggestionManager.FileSuggestionData))
 	for _, fileData := range model.PotentiallyFixableIssuesInfo.SuggestionManager.FileSuggestionData {
 		err = t.writeFile(fileData.Name, fileData.Text)
@@ -109,10 +118,6 @@ func (t *TuiFixer) Run() error {
 		t.handledFiles = append(t.handledFiles, fileData.Name)
 	}
 
-	t.addCssPageIfMissing = model.PotentiallyFixableIssuesInfo.AddCssPageBreakIfMissing
-	t.addCssSectionIfMissing = model.PotentiallyFixableIssuesInfo.AddCssSectionBreakIfMissing
-	t.selectedCssFile = model.CssSelectionInfo.SelectedCssFile
-
 	return nil
 }
 
@@ -121,6 +126,10 @@ func (t *TuiFixer) HandleCss() ([]string, error) {
 		return t.handledFiles, nil
 	}
 
+	if t.newCssFile != "" {
+		return createCssFile(t.addCssSectionIfMissing, t.addCssPageIfMissing, t.epubInfo.OpfFile, t.opfFolder, t.newCssFile, *t.contextBreak, t.handledFiles, t.getFile, t.writeFile)
+	}
+
 	if strings.TrimSpace(t.selectedCssFile) == "" {
 		return nil, fmt.Errorf("please select a valid css file instead of %q.\n", t.selectedCssFile)
 	}
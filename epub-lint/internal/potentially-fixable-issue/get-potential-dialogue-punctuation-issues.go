@@ -0,0 +1,273 @@
+package potentiallyfixableissue
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	straightDoubleQuote = '"'
+	openingDoubleQuote  = '“'
+	closingDoubleQuote  = '”'
+	openingSingleQuote  = '‘'
+	closingSingleQuote  = '’'
+)
+
+type quoteToken struct {
+	index     int
+	quote     rune
+	isOpening bool
+}
+
+type openQuote struct {
+	quoteToken
+	isNested bool
+}
+
+type paragraphEdit struct {
+	index   int
+	length  int
+	newText string
+}
+
+type dialogueParagraph struct {
+	original, start, end string
+	content              string
+	startIndex, endIndex int
+	edits                []paragraphEdit
+}
+
+// GetPotentialDialoguePunctuationIssues tracks the state of double quotes across consecutive paragraphs
+// and suggests fixes for unbalanced quotes, nested double quotes that should be single quotes,
+// and commas or periods that are outside of the closing quote of dialogue.
+func GetPotentialDialoguePunctuationIssues(fileContent string) (map[string]string, error) {
+	var (
+		subMatches          = paragraphContents.FindAllStringSubmatchIndex(fileContent, -1)
+		originalToSuggested = make(map[string]string)
+	)
+	if len(subMatches) == 0 {
+		return originalToSuggested, nil
+	}
+
+	var (
+		paragraphs   = make([]dialogueParagraph, len(subMatches))
+		previousOpen []openQuote
+	)
+	for i, groups := range subMatches {
+		var paragraph = &paragraphs[i]
+		paragraph.original = fileContent[groups[0]:groups[1]]
+		paragraph.start = fileContent[groups[2]:groups[3]]
+		paragraph.end = fileContent[groups[6]:groups[7]]
+		paragraph.content = fileContent[groups[4]:groups[5]]
+		paragraph.startIndex = groups[0]
+		paragraph.endIndex = groups[1]
+
+		var (
+			content     = paragraph.content
+			tokens      = getDoubleQuoteTokens(content)
+			isContinued = i > 0 && strings.TrimSpace(fileContent[paragraphs[i-1].endIndex:paragraph.startIndex]) == ""
+			open        []openQuote
+		)
+
+		if len(previousOpen) != 0 {
+			var (
+				startsWithOpeningQuote = len(tokens) != 0 && tokens[0].isOpening && tokens[0].index == firstTextIndex(content)
+				startsWithClosingQuote = len(tokens) != 0 && !tokens[0].isOpening
+			)
+			if isContinued && startsWithClosingQuote {
+				// the dialogue continues into this paragraph, but is missing the opening quote that should start the paragraph
+				paragraph.edits = append(paragraph.edits, paragraphEdit{
+					index:   firstTextIndex(content),
+					newText: string(getOpeningQuote(previousOpen[0].quote)),
+				})
+				open = append(open, previousOpen[0])
+			} else if !isContinued || !startsWithOpeningQuote {
+				addMissingClosingQuotes(&paragraphs[i-1], previousOpen)
+			}
+		}
+
+		for tokenIndex, token := range tokens {
+			if token.isOpening {
+				open = append(open, openQuote{
+					quoteToken: token,
+					isNested:   len(open) != 0 && token.quote == openingDoubleQuote,
+				})
+
+				continue
+			}
+
+			if len(open) == 0 {
+				if tokenIndex == 0 {
+					// there is a closing quote without an opening quote, so the paragraph is likely missing its opening quote
+					paragraph.edits = append(paragraph.edits, paragraphEdit{
+						index:   firstTextIndex(content),
+						newText: string(getOpeningQuote(token.quote)),
+					})
+				}
+
+				continue
+			}
+
+			var opening = open[len(open)-1]
+			open = open[:len(open)-1]
+			if opening.isNested && token.quote == closingDoubleQuote {
+				paragraph.edits = append(paragraph.edits, paragraphEdit{
+					index:   opening.index,
+					length:  utf8.RuneLen(opening.quote),
+					newText: string(openingSingleQuote),
+				}, paragraphEdit{
+					index:   token.index,
+					length:  utf8.RuneLen(token.quote),
+					newText: string(closingSingleQuote),
+				})
+
+				continue
+			}
+
+			if len(open) == 0 {
+				paragraph.edits = append(paragraph.edits, getPunctuationPlacementEdits(content, token)...)
+			}
+		}
+
+		previousOpen = open
+	}
+
+	if len(previousOpen) != 0 {
+		addMissingClosingQuotes(&paragraphs[len(paragraphs)-1], previousOpen)
+	}
+
+	for _, paragraph := range paragraphs {
+		if len(paragraph.edits) == 0 {
+			continue
+		}
+
+		originalToSuggested[paragraph.original] = paragraph.start + applyParagraphEdits(paragraph.content, paragraph.edits) + paragraph.end
+	}
+
+	return originalToSuggested, nil
+}
+
+// getDoubleQuoteTokens gets the double quotes in the text content of a paragraph determining whether straight quotes
+// are opening or closing quotes based on the character before them.
+func getDoubleQuoteTokens(content string) []quoteToken {
+	var (
+		tokens   []quoteToken
+		previous rune
+		inTag    bool
+	)
+	for i, r := range content {
+		if inTag {
+			inTag = r != '>'
+			continue
+		} else if r == '<' {
+			inTag = true
+			continue
+		}
+
+		switch r {
+		case openingDoubleQuote:
+			tokens = append(tokens, quoteToken{index: i, quote: r, isOpening: true})
+		case closingDoubleQuote:
+			tokens = append(tokens, quoteToken{index: i, quote: r})
+		case straightDoubleQuote:
+			tokens = append(tokens, quoteToken{
+				index:     i,
+				quote:     r,
+				isOpening: previous == 0 || unicode.IsSpace(previous) || strings.ContainsRune("([{—–-", previous),
+			})
+		}
+
+		previous = r
+	}
+
+	return tokens
+}
+
+// getPunctuationPlacementEdits moves a comma or period that directly follows the closing quote inside of the quote
+// or removes it when the quoted text already ends with punctuation.
+func getPunctuationPlacementEdits(content string, closing quoteToken) []paragraphEdit {
+	var afterQuote = closing.index + utf8.RuneLen(closing.quote)
+	if afterQuote >= len(content) || (content[afterQuote] != ',' && content[afterQuote] != '.') {
+		return nil
+	}
+
+	// an ellipsis is more likely to be intentional than a misplaced period
+	if afterQuote+1 < len(content) && content[afterQuote+1] == '.' {
+		return nil
+	}
+
+	var lastQuotedRune, _ = utf8.DecodeLastRuneInString(content[:closing.index])
+	if strings.ContainsRune(".,!?…—–-", lastQuotedRune) {
+		return []paragraphEdit{{
+			index:  afterQuote,
+			length: 1,
+		}}
+	}
+
+	return []paragraphEdit{{
+		index:   closing.index,
+		length:  afterQuote + 1 - closing.index,
+		newText: string(content[afterQuote]) + string(closing.quote),
+	}}
+}
+
+func addMissingClosingQuotes(paragraph *dialogueParagraph, open []openQuote) {
+	var (
+		closingQuotes strings.Builder
+		endIndex      = len(strings.TrimRightFunc(paragraph.content, unicode.IsSpace))
+	)
+	for i := len(open) - 1; i >= 0; i-- {
+		closingQuotes.WriteRune(getClosingQuote(open[i].quote))
+	}
+
+	paragraph.edits = append(paragraph.edits, paragraphEdit{
+		index:   endIndex,
+		newText: closingQuotes.String(),
+	})
+}
+
+func applyParagraphEdits(content string, edits []paragraphEdit) string {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].index > edits[j].index
+	})
+
+	for _, edit := range edits {
+		content = content[:edit.index] + edit.newText + content[edit.index+edit.length:]
+	}
+
+	return content
+}
+
+// firstTextIndex gets the index of the first character of the paragraph that is not whitespace or a part of an html tag
+func firstTextIndex(content string) int {
+	var inTag bool
+	for i, r := range content {
+		if inTag {
+			inTag = r != '>'
+		} else if r == '<' {
+			inTag = true
+		} else if !unicode.IsSpace(r) {
+			return i
+		}
+	}
+
+	return len(content)
+}
+
+func getOpeningQuote(quote rune) rune {
+	if quote == straightDoubleQuote {
+		return straightDoubleQuote
+	}
+
+	return openingDoubleQuote
+}
+
+func getClosingQuote(quote rune) rune {
+	if quote == straightDoubleQuote {
+		return straightDoubleQuote
+	}
+
+	return closingDoubleQuote
+}
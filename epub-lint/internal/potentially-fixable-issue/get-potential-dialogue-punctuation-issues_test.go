@@ -0,0 +1,92 @@
+//go:build unit
+
+package potentiallyfixableissue_test
+
+import (
+	"testing"
+
+	potentiallyfixableissue "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/potentially-fixable-issue"
+)
+
+var getPotentialDialoguePunctuationIssuesTestCases = map[string]suggesterTestCase{
+	"make sure that a file with balanced quotes and correct punctuation gets no suggestions": {
+		inputText: `<p>"Hello," he said. "How are you?"</p>
+<p>“I am fine,” she replied.</p>`,
+		expectedSuggestions: map[string]string{},
+	},
+	"make sure that dialogue that spans multiple paragraphs with an opening quote on each paragraph gets no suggestions": {
+		inputText: `<p>"It all started a long time ago.</p>
+<p>"Then the war came."</p>`,
+		expectedSuggestions: map[string]string{},
+	},
+	"make sure that a paragraph with an unclosed quote that is not followed by a paragraph starting with a quote gets a closing quote suggestion": {
+		inputText: `<p>"It all started a long time ago.</p>
+<p>He stopped talking.</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>"It all started a long time ago.</p>`: `<p>"It all started a long time ago."</p>`,
+		},
+	},
+	"make sure that the last paragraph of a file with an unclosed curly quote gets a matching closing quote suggestion": {
+		inputText: `<p>He stopped talking.</p>
+<p>“It all started a long time ago.</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>“It all started a long time ago.</p>`: `<p>“It all started a long time ago.”</p>`,
+		},
+	},
+	"make sure that a paragraph continuing dialogue without an opening quote gets an opening quote suggestion": {
+		inputText: `<p>"It all started a long time ago.</p>
+<p>Then the war came."</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>Then the war came."</p>`: `<p>"Then the war came."</p>`,
+		},
+	},
+	"make sure that a paragraph with a closing quote and no opening quote gets an opening quote suggestion": {
+		inputText: `<p>He stopped talking.</p>
+<p>Then the war came.”</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>Then the war came.”</p>`: `<p>“Then the war came.”</p>`,
+		},
+	},
+	"make sure that a comma or period after the closing quote gets moved inside of the quotes": {
+		inputText: `<p>"Hello", he said.</p>
+<p>She called it “magic”.</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>"Hello", he said.</p>`:      `<p>"Hello," he said.</p>`,
+			`<p>She called it “magic”.</p>`: `<p>She called it “magic.”</p>`,
+		},
+	},
+	"make sure that a comma after a closing quote with the quote already ending in punctuation gets removed": {
+		inputText: `<p>"Really?", he asked.</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>"Really?", he asked.</p>`: `<p>"Really?" he asked.</p>`,
+		},
+	},
+	"make sure that an ellipsis after a closing quote is left alone": {
+		inputText:           `<p>"Well"... he said.</p>`,
+		expectedSuggestions: map[string]string{},
+	},
+	"make sure that nested curly double quotes get a suggestion to use single quotes": {
+		inputText: `<p>“He told me “run” and left,” she said.</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>“He told me “run” and left,” she said.</p>`: `<p>“He told me ‘run’ and left,” she said.</p>`,
+		},
+	},
+	"make sure that quotes inside of html tags are ignored and quotes in formatting are handled": {
+		inputText: `<p class="dialogue"><i>"Hello", he said.</i></p>`,
+		expectedSuggestions: map[string]string{
+			`<p class="dialogue"><i>"Hello", he said.</i></p>`: `<p class="dialogue"><i>"Hello," he said.</i></p>`,
+		},
+	},
+	"make sure that dialogue does not carry over when there is another element between paragraphs": {
+		inputText: `<p>"It all started a long time ago.</p>
+<hr/>
+<p>"Then the war came."</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>"It all started a long time ago.</p>`: `<p>"It all started a long time ago."</p>`,
+		},
+	},
+}
+
+func TestGetPotentialDialoguePunctuationIssues(t *testing.T) {
+	testSuggesterNoError(t, getPotentialDialoguePunctuationIssuesTestCases, potentiallyfixableissue.GetPotentialDialoguePunctuationIssues)
+}
@@ -0,0 +1,313 @@
+package potentiallyfixableissue
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+)
+
+var (
+	paragraphContents = regexp.MustCompile(`(?m)^([\r\t\f\v ]*?<p[^\n>]*?>)([^\n]*?)(</p>)`)
+	// nameMention matches a capitalized word that is optionally followed by a Japanese honorific. Honorifics
+	// separated by a space are limited to the ones that are not also common English words.
+	nameMention = regexp.MustCompile(`(\p{Lu}[\p{Ll}\p{M}]+)(?:([-‐‑–])((?i:san|sama|kun|chan|senpai|sempai|sensei|dono|tan|chin|han|nee|nii|hime|shi))|( )(san|sama|kun|chan|senpai|sempai|sensei|dono))?`)
+	// honorificAliases maps alternate romanizations of honorifics to a common form so they get grouped together
+	honorificAliases = map[string]string{
+		"sempai": "senpai",
+	}
+	romanizationReplacer = strings.NewReplacer(
+		"ā", "a", "ī", "i", "ū", "u", "ē", "e", "ō", "o",
+		"â", "a", "î", "i", "û", "u", "ê", "e", "ô", "o",
+	)
+	sentenceEndingCharacters = ".!?\"“”‘’'(「『—…:"
+)
+
+// NameVariantTable keeps track of how often each spelling of a name and each form of an honorific is used
+// so that the less common variants can be normalized to the dominant one.
+type NameVariantTable struct {
+	// romanization key -> spelling -> count
+	nameCounts map[string]map[string]int
+	// romanization key + "|" + honorific -> separator and honorific as written -> count
+	honorificCounts map[string]map[string]int
+	// spellings that have been used somewhere other than the start of a sentence or with an honorific
+	nameEvidence   map[string]struct{}
+	lowercaseWords map[string]struct{}
+}
+
+type nameMentionInfo struct {
+	start, end      int
+	name            string
+	honorificSuffix string
+	honorificKey    string
+	isSentenceStart bool
+}
+
+func NewNameVariantTable() *NameVariantTable {
+	return &NameVariantTable{
+		nameCounts:      make(map[string]map[string]int),
+		honorificCounts: make(map[string]map[string]int),
+		nameEvidence:    make(map[string]struct{}),
+		lowercaseWords:  make(map[string]struct{}),
+	}
+}
+
+// AddText adds the name and honorific usages in the paragraphs of the provided file contents to the table.
+func (t *NameVariantTable) AddText(fileContent string) {
+	for _, groups := range paragraphContents.FindAllStringSubmatch(fileContent, -1) {
+		var text = linter.HtmlTagRegex.ReplaceAllString(groups[2], "")
+
+		for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsMark(r)
+		}) {
+			if first, _ := utf8.DecodeRuneInString(word); unicode.IsLower(first) {
+				t.lowercaseWords[word] = struct{}{}
+			}
+		}
+
+		for _, mention := range getNameMentions(text) {
+			var key = romanizationKey(mention.name)
+			if _, ok := t.nameCounts[key]; !ok {
+				t.nameCounts[key] = make(map[string]int)
+			}
+			t.nameCounts[key][mention.name]++
+
+			if !mention.isSentenceStart || mention.honorificKey != "" {
+				t.nameEvidence[mention.name] = struct{}{}
+			}
+
+			if mention.honorificKey == "" {
+				continue
+			}
+
+			var honorificKey = key + "|" + mention.honorificKey
+			if _, ok := t.honorificCounts[honorificKey]; !ok {
+				t.honorificCounts[honorificKey] = make(map[string]int)
+			}
+			t.honorificCounts[honorificKey][mention.honorificSuffix]++
+		}
+	}
+}
+
+// GetPotentialNameInconsistencies suggests replacing less common spellings of names and forms of honorifics
+// with the dominant ones found in the table.
+func (t *NameVariantTable) GetPotentialNameInconsistencies(fileContent string) (map[string]string, error) {
+	var (
+		subMatches          = paragraphContents.FindAllStringSubmatch(fileContent, -1)
+		originalToSuggested = make(map[string]string)
+	)
+	if len(subMatches) == 0 {
+		return originalToSuggested, nil
+	}
+
+	var (
+		dominantNames      = t.getDominantNames()
+		dominantHonorifics = getDominantVariants(t.honorificCounts, nil, nil)
+	)
+	for _, groups := range subMatches {
+		var (
+			updatedContent strings.Builder
+			updateMade     bool
+			lastIndex      int
+			tagIndices     = linter.HtmlTagRegex.FindAllStringIndex(groups[2], -1)
+		)
+
+		tagIndices = append(tagIndices, []int{len(groups[2]), len(groups[2])})
+		for _, tagIndex := range tagIndices {
+			var (
+				text      = groups[2][lastIndex:tagIndex[0]]
+				textIndex int
+			)
+			for _, mention := range getNameMentions(text) {
+				var (
+					key        = romanizationKey(mention.name)
+					name       = mention.name
+					suffix     = mention.honorificSuffix
+					dominant   string
+					isDominant bool
+				)
+				if dominant, isDominant = dominantNames[key+"|"+mention.name]; isDominant {
+					name = dominant
+				}
+
+				if mention.honorificKey != "" {
+					if dominant, isDominant = dominantHonorifics[key+"|"+mention.honorificKey+"|"+suffix]; isDominant {
+						suffix = dominant
+					}
+				}
+
+				if name == mention.name && suffix == mention.honorificSuffix {
+					continue
+				}
+
+				updatedContent.WriteString(text[textIndex:mention.start])
+				updatedContent.WriteString(name + suffix)
+				textIndex = mention.end
+				updateMade = true
+			}
+
+			updatedContent.WriteString(text[textIndex:])
+			updatedContent.WriteString(groups[2][tagIndex[0]:tagIndex[1]])
+			lastIndex = tagIndex[1]
+		}
+
+		if updateMade {
+			originalToSuggested[groups[0]] = groups[1] + updatedContent.String() + groups[3]
+		}
+	}
+
+	return originalToSuggested, nil
+}
+
+// getDominantNames returns a map of the spellings of names that should be replaced to the spelling they should be replaced with.
+// Only spellings that look like proper nouns are considered since sentence starting words would otherwise get flagged.
+func (t *NameVariantTable) getDominantNames() map[string]string {
+	return getDominantVariants(t.nameCounts, func(spelling string) bool {
+		_, isUsedLowercase := t.lowercaseWords[strings.ToLower(spelling)]
+
+		return !isUsedLowercase
+	}, func(spelling string) bool {
+		_, isName := t.nameEvidence[spelling]
+
+		return isName
+	})
+}
+
+// getDominantVariants returns a map of each group key and minority variant joined by "|" to the most frequently used variant in its group.
+// Groups with a tie for the most used variant are skipped since there is no clear variant to normalize to.
+func getDominantVariants(groupCounts map[string]map[string]int, isCandidate, isConfirmed func(string) bool) map[string]string {
+	var minorityToDominant = make(map[string]string)
+	for groupKey, variantCounts := range groupCounts {
+		var (
+			candidates   = make(map[string]int, len(variantCounts))
+			hasConfirmed = isConfirmed == nil
+		)
+		for variant, count := range variantCounts {
+			if isCandidate != nil && !isCandidate(variant) {
+				continue
+			}
+
+			candidates[variant] = count
+			if !hasConfirmed {
+				hasConfirmed = isConfirmed(variant)
+			}
+		}
+
+		if len(candidates) < 2 || !hasConfirmed {
+			continue
+		}
+
+		var (
+			dominant     string
+			highestCount int
+			isTied       bool
+		)
+		for variant, count := range candidates {
+			if count > highestCount {
+				dominant, highestCount, isTied = variant, count, false
+			} else if count == highestCount {
+				isTied = true
+			}
+		}
+
+		if isTied {
+			continue
+		}
+
+		for variant := range candidates {
+			if variant != dominant {
+				minorityToDominant[groupKey+"|"+variant] = dominant
+			}
+		}
+	}
+
+	return minorityToDominant
+}
+
+func getNameMentions(text string) []nameMentionInfo {
+	var mentions []nameMentionInfo
+	for _, indices := range nameMention.FindAllStringSubmatchIndex(text, -1) {
+		var (
+			start   = indices[0]
+			nameEnd = indices[3]
+			end     = indices[1]
+		)
+		if start > 0 {
+			if previous, _ := utf8.DecodeLastRuneInString(text[:start]); unicode.IsLetter(previous) || unicode.IsDigit(previous) || previous == '\'' || previous == '’' {
+				continue
+			}
+		}
+
+		if !isWordBoundary(text, nameEnd) {
+			continue
+		}
+
+		var mention = nameMentionInfo{
+			start:           start,
+			end:             nameEnd,
+			name:            text[start:nameEnd],
+			isSentenceStart: isSentenceStart(text, start),
+		}
+		if end != nameEnd && isWordBoundary(text, end) {
+			var honorificStart = indices[6]
+			if honorificStart == -1 {
+				honorificStart = indices[10]
+			}
+
+			var honorific = strings.ToLower(text[honorificStart:end])
+			if alias, ok := honorificAliases[honorific]; ok {
+				honorific = alias
+			}
+
+			mention.end = end
+			mention.honorificSuffix = text[nameEnd:end]
+			mention.honorificKey = honorific
+		}
+
+		mentions = append(mentions, mention)
+	}
+
+	return mentions
+}
+
+func isWordBoundary(text string, index int) bool {
+	if index >= len(text) {
+		return true
+	}
+
+	next, _ := utf8.DecodeRuneInString(text[index:])
+
+	return !unicode.IsLetter(next) && !unicode.IsMark(next) && !unicode.IsDigit(next)
+}
+
+func isSentenceStart(text string, index int) bool {
+	var before = strings.TrimRightFunc(text[:index], unicode.IsSpace)
+	if before == "" {
+		return true
+	}
+
+	previous, _ := utf8.DecodeLastRuneInString(before)
+
+	return strings.ContainsRune(sentenceEndingCharacters, previous)
+}
+
+// romanizationKey normalizes a name so that common romanization differences of long vowels
+// (i.e. "Ryuu", "Ryū", and "Ryu" or "Shouta" and "Shota") result in the same key.
+func romanizationKey(name string) string {
+	var (
+		key      strings.Builder
+		previous rune
+	)
+	for _, r := range romanizationReplacer.Replace(strings.ToLower(name)) {
+		if strings.ContainsRune("aeiou", r) && (r == previous || (previous == 'o' && r == 'u')) {
+			continue
+		}
+
+		key.WriteRune(r)
+		previous = r
+	}
+
+	return key.String()
+}
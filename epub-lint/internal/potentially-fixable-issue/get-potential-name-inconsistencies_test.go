@@ -0,0 +1,117 @@
+//go:build unit
+
+package potentiallyfixableissue_test
+
+import (
+	"testing"
+
+	potentiallyfixableissue "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/potentially-fixable-issue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type getPotentialNameInconsistenciesTestCase struct {
+	inputText           string
+	otherFileTexts      []string
+	expectedSuggestions map[string]string
+}
+
+var getPotentialNameInconsistenciesTestCases = map[string]getPotentialNameInconsistenciesTestCase{
+	"make sure that a file with consistent names gets no suggestions": {
+		inputText: `<p>Ryuu walked into the room.</p>
+<p>"Good morning," said Tanaka-san to Ryuu.</p>`,
+		expectedSuggestions: map[string]string{},
+	},
+	"make sure that a less common romanization of a name gets a suggestion to use the more common one": {
+		inputText: `<p>Ryuu walked into the room.</p>
+<p>"Good morning," said Ryuu.</p>
+<p>Later that day, Ryu went home.</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>Later that day, Ryu went home.</p>`: `<p>Later that day, Ryuu went home.</p>`,
+		},
+	},
+	"make sure that names with macrons are grouped with their long vowel spellings": {
+		inputText: `<p>Then Shouta and Shouta's sister left, leaving Shōta behind.</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>Then Shouta and Shouta's sister left, leaving Shōta behind.</p>`: `<p>Then Shouta and Shouta's sister left, leaving Shouta behind.</p>`,
+		},
+	},
+	"make sure that a less common honorific form gets a suggestion to use the more common one": {
+		inputText: `<p>"Thanks, Tanaka-san."</p>
+<p>"See you, Tanaka-san."</p>
+<p>"Wait, Tanaka san!"</p>
+<p>"Stop, Tanaka-San!"</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>"Wait, Tanaka san!"</p>`: `<p>"Wait, Tanaka-san!"</p>`,
+			`<p>"Stop, Tanaka-San!"</p>`: `<p>"Stop, Tanaka-san!"</p>`,
+		},
+	},
+	"make sure that alternate romanizations of honorifics are grouped together": {
+		inputText: `<p>"Morning, Yuki-senpai."</p>
+<p>"Bye, Yuki-senpai."</p>
+<p>"Hello, Yuki-sempai."</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>"Hello, Yuki-sempai."</p>`: `<p>"Hello, Yuki-senpai."</p>`,
+		},
+	},
+	"make sure that both the name and honorific get updated when they are both minority variants": {
+		inputText: `<p>"Morning, Yuuki-senpai."</p>
+<p>"Bye, Yuuki-senpai."</p>
+<p>"Hello, Yuki sempai."</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>"Hello, Yuki sempai."</p>`: `<p>"Hello, Yuuki-senpai."</p>`,
+		},
+	},
+	"make sure that words that are also used in lowercase are not considered names": {
+		inputText: `<p>Good job. That was a good one.</p>
+<p>God knows what will happen.</p>
+<p>Good grief.</p>`,
+		expectedSuggestions: map[string]string{},
+	},
+	"make sure that words that only show up at the start of sentences are not considered names": {
+		inputText: `<p>Ooh, that looks nice.</p>
+<p>Ooh. Oh no.</p>`,
+		expectedSuggestions: map[string]string{},
+	},
+	"make sure that a tie between variants does not result in a suggestion": {
+		inputText:           `<p>Then Ryuu and Ryu left.</p>`,
+		expectedSuggestions: map[string]string{},
+	},
+	"make sure that names inside of html tags are left alone and only the text is updated": {
+		inputText: `<p>Ryuu and <i>Ryu</i> left with <span class="Ryu">Ryuu</span>.</p>`,
+		expectedSuggestions: map[string]string{
+			`<p>Ryuu and <i>Ryu</i> left with <span class="Ryu">Ryuu</span>.</p>`: `<p>Ryuu and <i>Ryuu</i> left with <span class="Ryu">Ryuu</span>.</p>`,
+		},
+	},
+	"make sure that other files and volumes are used to determine the dominant variant": {
+		inputText: `<p>Then Ryuu said hello.</p>`,
+		otherFileTexts: []string{
+			`<p>Then Ryu said goodbye.</p>`,
+			`<p>After that Ryu went home.</p>`,
+		},
+		expectedSuggestions: map[string]string{
+			`<p>Then Ryuu said hello.</p>`: `<p>Then Ryu said hello.</p>`,
+		},
+	},
+}
+
+func TestGetPotentialNameInconsistencies(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range getPotentialNameInconsistenciesTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var table = potentiallyfixableissue.NewNameVariantTable()
+			table.AddText(args.inputText)
+			for _, text := range args.otherFileTexts {
+				table.AddText(text)
+			}
+
+			actual, err := table.GetPotentialNameInconsistencies(args.inputText)
+
+			require.NoError(t, err)
+			assert.Equal(t, args.expectedSuggestions, actual)
+		})
+	}
+}
@@ -7,4 +7,6 @@ type PotentiallyFixableIssue struct {
 	UpdateAllInstances          bool
 	AddCssSectionBreakIfMissing bool
 	AddCssPageBreakIfMissing    bool
+	// Languages are the languages the issue applies to based on the xml:lang or lang of a file which is all languages when empty
+	Languages []string
 }
@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	filesize "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/file-size"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+var fontExts = map[string]struct{}{
+	".ttf":   {},
+	".otf":   {},
+	".woff":  {},
+	".woff2": {},
+}
+
+type BookStats struct {
+	Name       string         `json:"name"`
+	Chapters   []ChapterStats `json:"chapters"`
+	Total      ChapterStats   `json:"total"`
+	Images     int            `json:"images"`
+	ImageBytes uint64         `json:"imageBytes"`
+	CssBytes   uint64         `json:"cssBytes"`
+	FontBytes  uint64         `json:"fontBytes"`
+}
+
+// GetBookStats gets the stats for each content file in spine order along with the totals for the book
+// and the size of the images, css, and fonts in the epub.
+func GetBookStats(name string, zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string, wordsPerMinute int) (BookStats, error) {
+	var bookStats = BookStats{
+		Name:     name,
+		Chapters: make([]ChapterStats, 0, len(epubInfo.FilePathsInSpineOrder)),
+	}
+
+	for _, file := range epubInfo.FilePathsInSpineOrder {
+		zipFile, ok := zipFiles[filehandler.JoinPath(opfFolder, file)]
+		if !ok {
+			return bookStats, fmt.Errorf("failed to find spine file %q in %q", file, name)
+		}
+
+		contents, err := filehandler.ReadInZipFileContents(zipFile)
+		if err != nil {
+			return bookStats, err
+		}
+
+		bookStats.Chapters = append(bookStats.Chapters, GetChapterStats(file, contents, wordsPerMinute))
+	}
+
+	bookStats.Total = Summarize(bookStats.Chapters, wordsPerMinute)
+
+	for file := range epubInfo.ImagesFiles {
+		bookStats.Images++
+		bookStats.ImageBytes += getUncompressedSize(zipFiles, opfFolder, file)
+	}
+
+	for file := range epubInfo.CssFiles {
+		bookStats.CssBytes += getUncompressedSize(zipFiles, opfFolder, file)
+	}
+
+	for file := range epubInfo.OtherFiles {
+		if _, isFont := fontExts[strings.ToLower(filepath.Ext(file))]; isFont {
+			bookStats.FontBytes += getUncompressedSize(zipFiles, opfFolder, file)
+		}
+	}
+
+	return bookStats, nil
+}
+
+// WriteTable writes the chapter and total stats of each book as a table followed by a table that has the totals
+// for all books when there is more than one book.
+func WriteTable(w io.Writer, books []BookStats) error {
+	var tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, book := range books {
+		fmt.Fprintf(tw, "%s\n", book.Name)
+		fmt.Fprintln(tw, "File\tWords\tCharacters\tParagraphs\tAvg Sentence\tDialogue\tFlesch Ease\tFK Grade\tMinutes\t")
+
+		for _, chapter := range book.Chapters {
+			writeRow(tw, chapter.File, chapter)
+		}
+
+		writeRow(tw, "Total", book.Total)
+		fmt.Fprintf(tw, "Images: %d (%s)  CSS: %s  Fonts: %s\n\n", book.Images, filesize.BytesToString(book.ImageBytes), filesize.BytesToString(book.CssBytes), filesize.BytesToString(book.FontBytes))
+	}
+
+	if len(books) > 1 {
+		fmt.Fprintln(tw, "Book\tWords\tCharacters\tParagraphs\tAvg Sentence\tDialogue\tFlesch Ease\tFK Grade\tMinutes\t")
+
+		for _, book := range books {
+			writeRow(tw, book.Name, book.Total)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func writeRow(w io.Writer, name string, chapterStats ChapterStats) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.2f\t%.0f%%\t%.2f\t%.2f\t%.2f\t\n", name, chapterStats.Words, chapterStats.Characters, chapterStats.Paragraphs, chapterStats.AverageSentenceLength, chapterStats.DialogueRatio*100, chapterStats.FleschReadingEase, chapterStats.FleschKincaidGrade, chapterStats.ReadingTimeMinutes)
+}
+
+func getUncompressedSize(zipFiles map[string]*zip.File, opfFolder, file string) uint64 {
+	zipFile, ok := zipFiles[filehandler.JoinPath(opfFolder, file)]
+	if !ok {
+		return 0
+	}
+
+	return zipFile.UncompressedSize64
+}
@@ -0,0 +1,223 @@
+package stats
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+const DefaultWordsPerMinute = 250
+
+type ChapterStats struct {
+	File                  string  `json:"file,omitempty"`
+	Words                 int     `json:"words"`
+	Characters            int     `json:"characters"`
+	Paragraphs            int     `json:"paragraphs"`
+	Sentences             int     `json:"sentences"`
+	Syllables             int     `json:"syllables"`
+	DialogueWords         int     `json:"dialogueWords"`
+	DialogueRatio         float64 `json:"dialogueRatio"`
+	AverageSentenceLength float64 `json:"averageSentenceLength"`
+	FleschReadingEase     float64 `json:"fleschReadingEase"`
+	FleschKincaidGrade    float64 `json:"fleschKincaidGrade"`
+	ReadingTimeMinutes    float64 `json:"readingTimeMinutes"`
+}
+
+// GetChapterStats gets the word, character, paragraph, sentence, and dialogue counts for the visible text
+// of the provided html along with the readability scores and estimated reading time based on those counts.
+func GetChapterStats(file, contents string, wordsPerMinute int) ChapterStats {
+	var (
+		chapterStats = ChapterStats{
+			File: file,
+		}
+		tokenizer         = html.NewTokenizer(strings.NewReader(contents))
+		skipDepth         int
+		inParagraph       bool
+		paragraphHasText  bool
+		inDialogue        bool
+		endedWithSentence = true
+	)
+
+	for {
+		tt := tokenizer.Next()
+		switch tt {
+		case html.ErrorToken:
+			if !endedWithSentence {
+				chapterStats.Sentences++
+			}
+
+			chapterStats.updateScores(wordsPerMinute)
+
+			return chapterStats
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tagName, _ := tokenizer.TagName()
+			switch string(tagName) {
+			case "head", "script", "style":
+				if tt == html.StartTagToken {
+					skipDepth++
+				}
+			case "p":
+				inParagraph = true
+				paragraphHasText = false
+			}
+		case html.EndTagToken:
+			tagName, _ := tokenizer.TagName()
+			switch string(tagName) {
+			case "head", "script", "style":
+				if skipDepth > 0 {
+					skipDepth--
+				}
+			case "p":
+				if inParagraph && paragraphHasText {
+					chapterStats.Paragraphs++
+				}
+
+				inParagraph = false
+			}
+		case html.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+
+			var text = html.UnescapeString(string(tokenizer.Text()))
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+
+			paragraphHasText = true
+			inDialogue, endedWithSentence = chapterStats.addText(text, inDialogue, endedWithSentence)
+		}
+	}
+}
+
+// Summarize combines the counts of the provided chapters and calculates the scores based on the combined counts
+func Summarize(chapters []ChapterStats, wordsPerMinute int) ChapterStats {
+	var total ChapterStats
+	for _, chapter := range chapters {
+		total.Words += chapter.Words
+		total.Characters += chapter.Characters
+		total.Paragraphs += chapter.Paragraphs
+		total.Sentences += chapter.Sentences
+		total.Syllables += chapter.Syllables
+		total.DialogueWords += chapter.DialogueWords
+	}
+
+	total.updateScores(wordsPerMinute)
+
+	return total
+}
+
+func (c *ChapterStats) addText(text string, inDialogue, endedWithSentence bool) (bool, bool) {
+	var (
+		word          strings.Builder
+		wordHasLetter bool
+		addWord       = func() {
+			if word.Len() != 0 && wordHasLetter {
+				c.Words++
+				c.Syllables += countSyllables(word.String())
+				endedWithSentence = false
+
+				if inDialogue {
+					c.DialogueWords++
+				}
+			}
+
+			word.Reset()
+			wordHasLetter = false
+		}
+	)
+
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			c.Characters++
+		}
+
+		switch {
+		case r == '"':
+			addWord()
+			inDialogue = !inDialogue
+		case r == '“':
+			addWord()
+			inDialogue = true
+		case r == '”':
+			addWord()
+			inDialogue = false
+		case r == '.' || r == '!' || r == '?' || r == '…':
+			addWord()
+
+			if !endedWithSentence {
+				c.Sentences++
+				endedWithSentence = true
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			word.WriteRune(r)
+			wordHasLetter = true
+		case r == '\'' || r == '’' || r == '-':
+			if word.Len() != 0 {
+				word.WriteRune(r)
+			}
+		default:
+			addWord()
+		}
+	}
+
+	addWord()
+
+	return inDialogue, endedWithSentence
+}
+
+func (c *ChapterStats) updateScores(wordsPerMinute int) {
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = DefaultWordsPerMinute
+	}
+
+	c.ReadingTimeMinutes = roundToHundredths(float64(c.Words) / float64(wordsPerMinute))
+	if c.Words == 0 {
+		return
+	}
+
+	var sentences = max(c.Sentences, 1)
+	var (
+		wordsPerSentence  = float64(c.Words) / float64(sentences)
+		syllablesPerWord  = float64(c.Syllables) / float64(c.Words)
+		fleschReadingEase = 206.835 - 1.015*wordsPerSentence - 84.6*syllablesPerWord
+		fleschKincaid     = 0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59
+	)
+
+	c.DialogueRatio = roundToHundredths(float64(c.DialogueWords) / float64(c.Words))
+	c.AverageSentenceLength = roundToHundredths(wordsPerSentence)
+	c.FleschReadingEase = roundToHundredths(fleschReadingEase)
+	c.FleschKincaidGrade = roundToHundredths(fleschKincaid)
+}
+
+// countSyllables estimates the number of syllables in an English word by counting the groups of vowels in it
+func countSyllables(word string) int {
+	word = strings.ToLower(strings.Trim(word, "'’-"))
+
+	var (
+		count         int
+		previousVowel bool
+		runes         = []rune(word)
+	)
+	for i, r := range runes {
+		var isVowel = strings.ContainsRune("aeiouy", r)
+		if isVowel && !previousVowel {
+			// a silent e at the end of a word does not make a syllable
+			if r == 'e' && i == len(runes)-1 && count > 0 {
+				break
+			}
+
+			count++
+		}
+
+		previousVowel = isVowel
+	}
+
+	return max(count, 1)
+}
+
+func roundToHundredths(value float64) float64 {
+	return math.Round(value*100) / 100
+}
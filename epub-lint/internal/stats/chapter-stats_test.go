@@ -0,0 +1,97 @@
+//go:build unit
+
+package stats_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+type getChapterStatsTestCase struct {
+	inputText     string
+	expectedStats stats.ChapterStats
+}
+
+var getChapterStatsTestCases = map[string]getChapterStatsTestCase{
+	"make sure that a file without any body text has no stats other than the file name": {
+		inputText: `<html><head><title>Chapter 1</title><style>p { margin: 0; }</style></head><body></body></html>`,
+		expectedStats: stats.ChapterStats{
+			File: "chapter.xhtml",
+		},
+	},
+	"make sure that words, sentences, and paragraphs are counted and dialogue is tracked": {
+		inputText: `<html><head><title>Chapter 1</title></head><body>
+<p>"Hello there," she said.</p>
+<p>The cat sat on the mat. It was happy!</p>
+<p> </p>
+</body></html>`,
+		expectedStats: stats.ChapterStats{
+			File:                  "chapter.xhtml",
+			Words:                 13,
+			Characters:            50,
+			Paragraphs:            2,
+			Sentences:             3,
+			Syllables:             15,
+			DialogueWords:         2,
+			DialogueRatio:         0.15,
+			AverageSentenceLength: 4.33,
+			FleschReadingEase:     104.82,
+			FleschKincaidGrade:    -0.28,
+			ReadingTimeMinutes:    0.05,
+		},
+	},
+	"make sure that text without an ending punctuation mark still counts as a sentence and entities are unescaped": {
+		inputText: `<p>Tom &amp; Jerry</p>`,
+		expectedStats: stats.ChapterStats{
+			File:                  "chapter.xhtml",
+			Words:                 2,
+			Characters:            9,
+			Paragraphs:            1,
+			Sentences:             1,
+			Syllables:             3,
+			AverageSentenceLength: 2,
+			FleschReadingEase:     77.91,
+			FleschKincaidGrade:    2.89,
+			ReadingTimeMinutes:    0.01,
+		},
+	},
+}
+
+func TestGetChapterStats(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range getChapterStatsTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := stats.GetChapterStats("chapter.xhtml", args.inputText, stats.DefaultWordsPerMinute)
+
+			assert.Equal(t, args.expectedStats, actual)
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	actual := stats.Summarize([]stats.ChapterStats{
+		{File: "chapter1.xhtml", Words: 300, Characters: 1200, Paragraphs: 10, Sentences: 20, Syllables: 400, DialogueWords: 100},
+		{File: "chapter2.xhtml", Words: 200, Characters: 800, Paragraphs: 5, Sentences: 5, Syllables: 300, DialogueWords: 0},
+	}, 250)
+
+	assert.Equal(t, stats.ChapterStats{
+		Words:                 500,
+		Characters:            2000,
+		Paragraphs:            15,
+		Sentences:             25,
+		Syllables:             700,
+		DialogueWords:         100,
+		DialogueRatio:         0.2,
+		AverageSentenceLength: 20,
+		FleschReadingEase:     68.1,
+		FleschKincaidGrade:    8.73,
+		ReadingTimeMinutes:    2,
+	}, actual)
+}
@@ -0,0 +1,129 @@
+package structurecheck
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check/positions"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/links"
+)
+
+const (
+	DuplicateId  = "duplicate-id"
+	BrokenLink   = "broken-link"
+	MissingAlt   = "missing-alt"
+	ManifestFile = "manifest"
+)
+
+var (
+	idAttributeRegex = regexp.MustCompile(`\sid\s*=\s*["']([^"']*)["']`)
+	imgTagRegex      = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	altAttrRegex     = regexp.MustCompile(`(?i)\salt\s*=`)
+)
+
+// Finding is an issue found in a file of an epub
+type Finding struct {
+	// File is the path of the file relative to the root of the epub
+	File    string
+	Line    int
+	Column  int
+	Rule    string
+	Message string
+	// Repair is the value to replace a broken link with which is empty when no repair could be found
+	Repair string
+}
+
+func (f Finding) String() string {
+	if f.Line == 0 {
+		return fmt.Sprintf("%s: %s (%s)", f.File, f.Message, f.Rule)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s (%s)", f.File, f.Line, f.Column, f.Message, f.Rule)
+}
+
+// CheckFiles runs quick structural checks on the provided html files checking for duplicate ids,
+// internal links that point to files or ids that do not exist, and images that are missing alt text.
+// htmlFiles is the contents of the html files by their path relative to the root of the epub
+// and existingFiles is all files in the epub by their path relative to the root of the epub.
+func CheckFiles(htmlFiles map[string]string, existingFiles map[string]struct{}) []Finding {
+	var findings []Finding
+	for file, contents := range htmlFiles {
+		findings = checkIds(file, contents, findings)
+		findings = checkImageAlts(file, contents, findings)
+	}
+
+	for _, brokenLink := range links.CheckLinks(htmlFiles, existingFiles) {
+		findings = append(findings, Finding{
+			File:    brokenLink.File,
+			Line:    brokenLink.Line,
+			Column:  brokenLink.Column,
+			Rule:    BrokenLink,
+			Message: brokenLink.Message(),
+			Repair:  brokenLink.Repair,
+		})
+	}
+
+	SortFindings(findings)
+
+	return findings
+}
+
+// SortFindings sorts the findings by file, then position, and then message
+func SortFindings(findings []Finding) {
+	slices.SortFunc(findings, func(a, b Finding) int {
+		if a.File != b.File {
+			return strings.Compare(a.File, b.File)
+		}
+
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+
+		if a.Column != b.Column {
+			return a.Column - b.Column
+		}
+
+		return strings.Compare(a.Message, b.Message)
+	})
+}
+
+func checkIds(file, contents string, findings []Finding) []Finding {
+	var ids = make(map[string]struct{})
+	for _, indices := range idAttributeRegex.FindAllStringSubmatchIndex(contents, -1) {
+		var id = contents[indices[2]:indices[3]]
+		if _, exists := ids[id]; exists {
+			findings = append(findings, NewFinding(file, contents, indices[2], DuplicateId, fmt.Sprintf("id %q is used more than once", id)))
+
+			continue
+		}
+
+		ids[id] = struct{}{}
+	}
+
+	return findings
+}
+
+func checkImageAlts(file, contents string, findings []Finding) []Finding {
+	for _, indices := range imgTagRegex.FindAllStringIndex(contents, -1) {
+		if !altAttrRegex.MatchString(contents[indices[0]:indices[1]]) {
+			findings = append(findings, NewFinding(file, contents, indices[0], MissingAlt, "img is missing an alt attribute"))
+		}
+	}
+
+	return findings
+}
+
+// NewFinding creates a finding for the rule at the line and column of the index in the contents of the file
+func NewFinding(file, contents string, index int, rule, message string) Finding {
+	var position = positions.IndexToPosition(contents, index)
+
+	return Finding{
+		File:    file,
+		Line:    position.Line,
+		Column:  position.Column,
+		Rule:    rule,
+		Message: message,
+	}
+}
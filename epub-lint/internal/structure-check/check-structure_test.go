@@ -0,0 +1,104 @@
+//go:build unit
+
+package structurecheck_test
+
+import (
+	"testing"
+
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	chapterOne = `<html>
+<head><link rel="stylesheet" href="../Styles/style.css"/></head>
+<body>
+<h1 id="start">Chapter 1</h1>
+<p id="para">Text <a href="chapter2.xhtml#end">next</a> <a href="https://example.com">site</a></p>
+<p id="para"><img src="../Images/1.jpg" alt=""/><img src="../Images/missing.jpg"/></p>
+<p><a href="#start">top</a> <a href="#nowhere">bad</a> <a href="chapter3.xhtml">gone</a> <a href="mailto:a@b.c">mail</a></p>
+</body>
+</html>`
+	chapterTwo = `<html><body><p id="end"><a href="chapter1.xhtml#start">back</a> <a href="chapter%201.xhtml">spaced</a><img src="1.jpg" alt=""/></p></body></html>`
+)
+
+func TestCheckFiles(t *testing.T) {
+	t.Parallel()
+
+	var (
+		htmlFiles = map[string]string{
+			"OEBPS/Text/chapter1.xhtml": chapterOne,
+			"OEBPS/Text/chapter2.xhtml": chapterTwo,
+		}
+		existingFiles = map[string]struct{}{
+			"OEBPS/Text/chapter1.xhtml":  {},
+			"OEBPS/Text/chapter2.xhtml":  {},
+			"OEBPS/Text/chapter 1.xhtml": {},
+			"OEBPS/Styles/style.css":     {},
+			"OEBPS/Images/1.jpg":         {},
+		}
+	)
+
+	assert.Equal(t, []structurecheck.Finding{
+		{
+			File:    "OEBPS/Text/chapter1.xhtml",
+			Line:    6,
+			Column:  8,
+			Rule:    structurecheck.DuplicateId,
+			Message: `id "para" is used more than once`,
+		},
+		{
+			File:    "OEBPS/Text/chapter1.xhtml",
+			Line:    6,
+			Column:  49,
+			Rule:    structurecheck.MissingAlt,
+			Message: "img is missing an alt attribute",
+		},
+		{
+			File:    "OEBPS/Text/chapter1.xhtml",
+			Line:    6,
+			Column:  59,
+			Rule:    structurecheck.BrokenLink,
+			Message: `"../Images/missing.jpg" points to "OEBPS/Images/missing.jpg" which does not exist`,
+		},
+		{
+			File:    "OEBPS/Text/chapter1.xhtml",
+			Line:    7,
+			Column:  38,
+			Rule:    structurecheck.BrokenLink,
+			Message: `"#nowhere" points to id "nowhere" which does not exist in "OEBPS/Text/chapter1.xhtml"`,
+		},
+		{
+			File:    "OEBPS/Text/chapter1.xhtml",
+			Line:    7,
+			Column:  65,
+			Rule:    structurecheck.BrokenLink,
+			Message: `"chapter3.xhtml" points to "OEBPS/Text/chapter3.xhtml" which does not exist`,
+		},
+		{
+			File:    "OEBPS/Text/chapter2.xhtml",
+			Line:    1,
+			Column:  113,
+			Rule:    structurecheck.BrokenLink,
+			Message: `"1.jpg" points to "OEBPS/Text/1.jpg" which does not exist`,
+			Repair:  "../Images/1.jpg",
+		},
+	}, structurecheck.CheckFiles(htmlFiles, existingFiles))
+}
+
+func TestFindingString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `OEBPS/Text/chapter1.xhtml:6:8: id "para" is used more than once (duplicate-id)`, structurecheck.Finding{
+		File:    "OEBPS/Text/chapter1.xhtml",
+		Line:    6,
+		Column:  8,
+		Rule:    structurecheck.DuplicateId,
+		Message: `id "para" is used more than once`,
+	}.String())
+	assert.Equal(t, `OEBPS/content.opf: missing file (manifest)`, structurecheck.Finding{
+		File:    "OEBPS/content.opf",
+		Rule:    structurecheck.ManifestFile,
+		Message: "missing file",
+	}.String())
+}
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
 	potentiallyfixableissue "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/potentially-fixable-issue"
 )
 
@@ -14,6 +15,7 @@ var (
 	ErrNoCurrentSuggestion       = errors.New("no current suggestion available")
 	ErrSuggestionAlreadyAccepted = errors.New("suggestion already accepted")
 	ErrNoCurrentIssueAvailable   = errors.New("no current issue available")
+	ErrSuggestionIsStale         = errors.New("suggestion's original value is no longer in the file")
 )
 
 // SuggestionManager manages the state and navigation of suggestions across files and issue types.
@@ -36,7 +38,13 @@ type SuggestionManager struct {
 type FileSuggestionInfo struct {
 	Name        string
 	Text        string
+	Language    string
 	Suggestions [][]SuggestionState
+	// textVersion gets incremented every time the text gets updated so that suggestions gotten from an older
+	// version of the text can be reloaded
+	textVersion int
+	// suggestionTextVersions is the text version each issue type's suggestions were gotten from
+	suggestionTextVersions []int
 }
 
 // NewSuggestionManager creates a new SuggestionManager instance.
@@ -54,9 +62,11 @@ func NewSuggestionManager(
 
 	for filePath, text := range filePathToText {
 		fileSuggestionData = append(fileSuggestionData, FileSuggestionInfo{
-			Name:        filePath,
-			Text:        text,
-			Suggestions: make([][]SuggestionState, numFixableIssues),
+			Name:                   filePath,
+			Text:                   text,
+			Language:               linter.GetLanguage(text),
+			Suggestions:            make([][]SuggestionState, numFixableIssues),
+			suggestionTextVersions: make([]int, numFixableIssues),
 		})
 	}
 
@@ -91,21 +101,41 @@ func (sm *SuggestionManager) AcceptSuggestion() error {
 		return ErrNoCurrentIssueAvailable
 	}
 
+	if !sm.acceptSuggestion(sm.CurrentFileIndex, *sm.CurrentSuggestion, sm.CurrentSuggestionState) {
+		return ErrSuggestionIsStale
+	}
+
+	return nil
+}
+
+// acceptSuggestion applies the suggestion to the text of the file marking it as accepted. When the original value
+// is no longer in the text, i.e. an earlier accepted suggestion changed it, the suggestion gets marked as stale
+// instead and false is returned.
+func (sm *SuggestionManager) acceptSuggestion(fileIndex int, issue potentiallyfixableissue.PotentiallyFixableIssue, state *SuggestionState) bool {
+	if !strings.Contains(sm.FileSuggestionData[fileIndex].Text, state.Original) {
+		state.IsStale = true
+
+		return false
+	}
+
 	replaceCount := 1
-	if sm.CurrentSuggestion.UpdateAllInstances {
+	if issue.UpdateAllInstances {
 		replaceCount = -1
 	}
 
-	sm.FileSuggestionData[sm.CurrentFileIndex].Text = strings.Replace(
-		sm.FileSuggestionData[sm.CurrentFileIndex].Text,
-		sm.CurrentSuggestionState.Original,
-		sm.CurrentSuggestionState.CurrentSuggestion,
+	sm.FileSuggestionData[fileIndex].Text = strings.Replace(
+		sm.FileSuggestionData[fileIndex].Text,
+		state.Original,
+		state.CurrentSuggestion,
 		replaceCount,
 	)
+	sm.FileSuggestionData[fileIndex].textVersion++
 
-	sm.CurrentSuggestionState.IsAccepted = true
+	state.IsAccepted = true
+	state.IsSkipped = false
+	state.IsStale = false
 
-	return nil
+	return true
 }
 
 // UpdateCurrentSuggestionValue updates the current suggestion's value.
@@ -287,64 +317,38 @@ func (sm *SuggestionManager) SetupForNextSuggestions() (bool, error) {
 			var potentialFixableIssue = sm.Suggestions[sm.CurrentIssueIndex]
 			sm.logf("Possible fixable issue %q is %d of %d issues.", potentialFixableIssue.Name, sm.CurrentIssueIndex+1, len(sm.Suggestions))
 
-			if !sm.runAll && (potentialFixableIssue.IsEnabled == nil || !*potentialFixableIssue.IsEnabled) {
-				sm.logf("Skipping possible fixable issue %q with isEnabled set to %v", potentialFixableIssue.Name, potentialFixableIssue.IsEnabled)
-
-				sm.CurrentIssueIndex++
-				continue
-			} else if sm.skipCss && (potentialFixableIssue.AddCssPageBreakIfMissing || potentialFixableIssue.AddCssSectionBreakIfMissing) {
-				sm.logf("Skipping possible fixable issue %q because css related rules are to be skipped", potentialFixableIssue.Name)
+			if sm.isIssueSkipped(sm.CurrentFileIndex, sm.CurrentIssueIndex) {
 				sm.CurrentIssueIndex++
 
 				continue
 			}
 
+			if len(sm.FileSuggestionData[sm.CurrentFileIndex].Suggestions[sm.CurrentIssueIndex]) != 0 {
+				err := sm.refreshSuggestions(sm.CurrentFileIndex, sm.CurrentIssueIndex)
+				if err != nil {
+					return false, err
+				}
+			}
+
 			if len(sm.FileSuggestionData[sm.CurrentFileIndex].Suggestions[sm.CurrentIssueIndex]) != 0 {
 				sm.logf("Possible fixable issue %q has %d suggestion(s) already\n", potentialFixableIssue.Name, len(sm.FileSuggestionData[sm.CurrentFileIndex].Suggestions[sm.CurrentIssueIndex]))
 
 				sm.CurrentSuggestionIndex = 0
 				sm.CurrentSuggestionState = &sm.FileSuggestionData[sm.CurrentFileIndex].Suggestions[sm.CurrentIssueIndex][0]
+				sm.CurrentSuggestion = &sm.Suggestions[sm.CurrentIssueIndex]
 				sm.CurrentSuggestionName = potentialFixableIssue.Name
 				sm.CurrentFileName = sm.FileSuggestionData[sm.CurrentFileIndex].Name
 
 				return true, nil
 			}
 
-			suggestions, err := potentialFixableIssue.GetSuggestions(sm.FileSuggestionData[sm.CurrentFileIndex].Text)
+			err := sm.loadSuggestions(sm.CurrentFileIndex, sm.CurrentIssueIndex)
 			if err != nil {
 				return false, err
 			}
 
-			sm.logf("Possible fixable issue %q has %d suggestion(s) found\n", potentialFixableIssue.Name, len(suggestions))
-
-			if len(suggestions) != 0 {
-				sm.CurrentSuggestion = &potentialFixableIssue
-				sm.FileSuggestionData[sm.CurrentFileIndex].Suggestions[sm.CurrentIssueIndex] = make([]SuggestionState, len(suggestions))
-
-				var (
-					i             = 0
-					newSuggestion SuggestionState
-				)
-				for original, suggestion := range suggestions {
-					newSuggestion = SuggestionState{
-						Original:           original,
-						OriginalSuggestion: suggestion,
-						CurrentSuggestion:  suggestion,
-					}
-
-					err = newSuggestion.GetStringDiffAsDisplay()
-					if err != nil {
-						return false, err
-					}
-
-					sm.FileSuggestionData[sm.CurrentFileIndex].Suggestions[sm.CurrentIssueIndex][i] = newSuggestion
-					i++
-				}
-
-				sort.Slice(sm.FileSuggestionData[sm.CurrentFileIndex].Suggestions[sm.CurrentIssueIndex], func(i, j int) bool {
-					return sm.FileSuggestionData[sm.CurrentFileIndex].Suggestions[sm.CurrentIssueIndex][i].Original < sm.FileSuggestionData[sm.CurrentFileIndex].Suggestions[sm.CurrentIssueIndex][j].Original
-				})
-
+			if len(sm.FileSuggestionData[sm.CurrentFileIndex].Suggestions[sm.CurrentIssueIndex]) != 0 {
+				sm.CurrentSuggestion = &sm.Suggestions[sm.CurrentIssueIndex]
 				sm.CurrentSuggestionIndex = 0
 				sm.CurrentSuggestionState = &sm.FileSuggestionData[sm.CurrentFileIndex].Suggestions[sm.CurrentIssueIndex][0]
 				sm.CurrentSuggestionName = potentialFixableIssue.Name
@@ -362,6 +366,123 @@ func (sm *SuggestionManager) SetupForNextSuggestions() (bool, error) {
 	return false, nil
 }
 
+// isIssueSkipped returns whether the issue does not get run on the file because it is not enabled, it is css related
+// and css related rules are being skipped, or it does not apply to the language of the file.
+func (sm *SuggestionManager) isIssueSkipped(fileIndex, issueIndex int) bool {
+	var potentialFixableIssue = sm.Suggestions[issueIndex]
+	if !sm.runAll && (potentialFixableIssue.IsEnabled == nil || !*potentialFixableIssue.IsEnabled) {
+		sm.logf("Skipping possible fixable issue %q with isEnabled set to %v", potentialFixableIssue.Name, potentialFixableIssue.IsEnabled)
+
+		return true
+	} else if sm.skipCss && (potentialFixableIssue.AddCssPageBreakIfMissing || potentialFixableIssue.AddCssSectionBreakIfMissing) {
+		sm.logf("Skipping possible fixable issue %q because css related rules are to be skipped", potentialFixableIssue.Name)
+
+		return true
+	} else if !linter.LanguageMatches(sm.FileSuggestionData[fileIndex].Language, potentialFixableIssue.Languages) {
+		sm.logf("Skipping possible fixable issue %q because it does not apply to language %q", potentialFixableIssue.Name, sm.FileSuggestionData[fileIndex].Language)
+
+		return true
+	}
+
+	return false
+}
+
+// loadSuggestions gets the suggestions for the issue based on the current text of the file sorting them by their original value
+func (sm *SuggestionManager) loadSuggestions(fileIndex, issueIndex int) error {
+	var potentialFixableIssue = sm.Suggestions[issueIndex]
+	suggestions, err := potentialFixableIssue.GetSuggestions(sm.FileSuggestionData[fileIndex].Text)
+	if err != nil {
+		return err
+	}
+
+	if issueIndex < len(sm.FileSuggestionData[fileIndex].suggestionTextVersions) {
+		sm.FileSuggestionData[fileIndex].suggestionTextVersions[issueIndex] = sm.FileSuggestionData[fileIndex].textVersion
+	}
+
+	sm.logf("Possible fixable issue %q has %d suggestion(s) found\n", potentialFixableIssue.Name, len(suggestions))
+
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	var (
+		i              = 0
+		newSuggestion  SuggestionState
+		newSuggestions = make([]SuggestionState, len(suggestions))
+	)
+	for original, suggestion := range suggestions {
+		newSuggestion = SuggestionState{
+			Original:           original,
+			OriginalSuggestion: suggestion,
+			CurrentSuggestion:  suggestion,
+		}
+
+		err = newSuggestion.GetStringDiffAsDisplay()
+		if err != nil {
+			return err
+		}
+
+		newSuggestions[i] = newSuggestion
+		i++
+	}
+
+	sort.Slice(newSuggestions, func(i, j int) bool {
+		return newSuggestions[i].Original < newSuggestions[j].Original
+	})
+
+	sm.FileSuggestionData[fileIndex].Suggestions[issueIndex] = newSuggestions
+
+	return nil
+}
+
+// refreshSuggestions reloads the suggestions for the issue in the file when the text of the file has changed since
+// they were gotten. Suggestions that were already accepted are kept as is and any edits and skips are kept for
+// the suggestions that still apply.
+func (sm *SuggestionManager) refreshSuggestions(fileIndex, issueIndex int) error {
+	var fileData = &sm.FileSuggestionData[fileIndex]
+	if issueIndex >= len(fileData.suggestionTextVersions) || fileData.suggestionTextVersions[issueIndex] == fileData.textVersion {
+		return nil
+	}
+
+	sm.logf("Reloading the suggestions for possible fixable issue %q since the file has been updated since they were gotten", sm.Suggestions[issueIndex].Name)
+
+	var oldSuggestions = fileData.Suggestions[issueIndex]
+	fileData.Suggestions[issueIndex] = nil
+
+	err := sm.loadSuggestions(fileIndex, issueIndex)
+	if err != nil {
+		return err
+	}
+
+	var (
+		newSuggestions  = fileData.Suggestions[issueIndex]
+		originalToIndex = make(map[string]int, len(newSuggestions))
+	)
+	for i, suggestion := range newSuggestions {
+		originalToIndex[suggestion.Original] = i
+	}
+
+	for _, oldSuggestion := range oldSuggestions {
+		i, stillApplies := originalToIndex[oldSuggestion.Original]
+		if stillApplies {
+			if !oldSuggestion.IsAccepted {
+				oldSuggestion.IsStale = false
+				newSuggestions[i] = oldSuggestion
+			}
+		} else if oldSuggestion.IsAccepted {
+			newSuggestions = append(newSuggestions, oldSuggestion)
+		}
+	}
+
+	sort.Slice(newSuggestions, func(i, j int) bool {
+		return newSuggestions[i].Original < newSuggestions[j].Original
+	})
+
+	fileData.Suggestions[issueIndex] = newSuggestions
+
+	return nil
+}
+
 // logf logs a message to the configured logf file if one exists.
 func (sm *SuggestionManager) logf(format string, args ...any) {
 	if sm.logFile != nil {
@@ -0,0 +1,78 @@
+package suggestionmanager
+
+import (
+	"regexp"
+	"strings"
+)
+
+var blockStartRegex = regexp.MustCompile(`(?i)<(?:p|h[1-6]|li|blockquote|div|pre|hr|dt|dd|figure|figcaption|table|tr|section|aside|header|footer)\b[^>]*>`)
+
+// GetCurrentSuggestionContext gets the html in the file of the current suggestion that comes before and after it
+// starting and ending at the block elements (i.e. paragraphs and headers) around it with up to the specified number of
+// blocks of extra context on either side. Accepted suggestions are looked for by their current suggestion since that is
+// what is in the file. Found is false when the value is no longer in the file.
+func (sm *SuggestionManager) GetCurrentSuggestionContext(numContextBlocks int) (before, after string, found bool) {
+	if sm.CurrentSuggestionState == nil || sm.CurrentFileIndex < 0 || sm.CurrentFileIndex >= len(sm.FileSuggestionData) {
+		return "", "", false
+	}
+
+	var value = sm.CurrentSuggestionState.Original
+	if sm.CurrentSuggestionState.IsAccepted {
+		value = sm.CurrentSuggestionState.CurrentSuggestion
+	}
+
+	var (
+		text  = sm.FileSuggestionData[sm.CurrentFileIndex].Text
+		start = strings.Index(text, value)
+	)
+	if value == "" || start == -1 {
+		return "", "", false
+	}
+
+	before, after = getSurroundingBlocks(text, start, start+len(value), numContextBlocks)
+
+	return before, after, true
+}
+
+// getSurroundingBlocks gets the text from the start of the block the start index is in and the end of the block
+// the end index is in with the specified number of extra blocks on either side staying inside of the body when there is one
+func getSurroundingBlocks(text string, start, end, numContextBlocks int) (before, after string) {
+	var (
+		bodyStart = 0
+		bodyEnd   = len(text)
+	)
+	if bodyTagStart := strings.Index(text, "<body"); bodyTagStart != -1 && bodyTagStart < start {
+		if bodyTagEnd := strings.Index(text[bodyTagStart:], ">"); bodyTagEnd != -1 {
+			bodyStart = bodyTagStart + bodyTagEnd + 1
+		}
+	}
+
+	if bodyCloseStart := strings.LastIndex(text, "</body"); bodyCloseStart >= end {
+		bodyEnd = bodyCloseStart
+	}
+
+	var blockStarts = []int{bodyStart}
+	for _, indices := range blockStartRegex.FindAllStringIndex(text[bodyStart:bodyEnd], -1) {
+		if indices[0] != 0 {
+			blockStarts = append(blockStarts, bodyStart+indices[0])
+		}
+	}
+
+	var firstBlock, lastBlock int
+	for i, blockStart := range blockStarts {
+		if blockStart <= start {
+			firstBlock = i
+		}
+
+		if blockStart < end {
+			lastBlock = i
+		}
+	}
+
+	var contextEnd = bodyEnd
+	if lastBlock+numContextBlocks+1 < len(blockStarts) {
+		contextEnd = blockStarts[lastBlock+numContextBlocks+1]
+	}
+
+	return text[blockStarts[max(firstBlock-numContextBlocks, 0)]:start], text[end:contextEnd]
+}
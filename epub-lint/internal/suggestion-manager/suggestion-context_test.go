@@ -0,0 +1,123 @@
+//go:build unit
+
+//nolint:testpackage // We check unexported properties here, so we need to be in the same package as the regular one
+package suggestionmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const suggestionContextHtml = `<html>
+<head><title>Chapter 1</title></head>
+<body>
+<h1>Chapter 1</h1>
+<p>First paragraph.</p>
+<p>Second paragraph.</p>
+<p class="text">He bought apples, pears and plums.</p>
+<p>Fourth paragraph.</p>
+<hr/>
+<p>Fifth paragraph.</p>
+</body>
+</html>`
+
+type getCurrentSuggestionContextTestCase struct {
+	state            SuggestionState
+	numContextBlocks int
+	expectedBefore   string
+	expectedAfter    string
+	expectedFound    bool
+}
+
+var getCurrentSuggestionContextTestCases = map[string]getCurrentSuggestionContextTestCase{
+	"When there is no extra context, then only the block the suggestion is in is included": {
+		state: SuggestionState{
+			Original:          "pears and",
+			CurrentSuggestion: "pears, and",
+		},
+		expectedBefore: `<p class="text">He bought apples, `,
+		expectedAfter:  " plums.</p>\n",
+		expectedFound:  true,
+	},
+	"When there is extra context, then the blocks before and after the suggestion are included": {
+		state: SuggestionState{
+			Original:          "pears and",
+			CurrentSuggestion: "pears, and",
+		},
+		numContextBlocks: 2,
+		expectedBefore:   "<p>First paragraph.</p>\n<p>Second paragraph.</p>\n<p class=\"text\">He bought apples, ",
+		expectedAfter:    " plums.</p>\n<p>Fourth paragraph.</p>\n<hr/>\n",
+		expectedFound:    true,
+	},
+	"When the extra context goes past the start or end of the body, then it stops at the body": {
+		state: SuggestionState{
+			Original:          "Fifth",
+			CurrentSuggestion: "5th",
+		},
+		numContextBlocks: 10,
+		expectedBefore:   "\n<h1>Chapter 1</h1>\n<p>First paragraph.</p>\n<p>Second paragraph.</p>\n<p class=\"text\">He bought apples, pears and plums.</p>\n<p>Fourth paragraph.</p>\n<hr/>\n<p>",
+		expectedAfter:    " paragraph.</p>\n",
+		expectedFound:    true,
+	},
+	"When the suggestion spans multiple blocks, then all of those blocks are included": {
+		state: SuggestionState{
+			Original:          "Second paragraph.</p>\n<p class=\"text\">He",
+			CurrentSuggestion: "Second paragraph. He",
+		},
+		numContextBlocks: 1,
+		expectedBefore:   "<p>First paragraph.</p>\n<p>",
+		expectedAfter:    " bought apples, pears and plums.</p>\n<p>Fourth paragraph.</p>\n",
+		expectedFound:    true,
+	},
+	"When the suggestion is accepted, then its current suggestion is what is looked for": {
+		state: SuggestionState{
+			IsAccepted:        true,
+			Original:          "Fourth paragraph",
+			CurrentSuggestion: "Fourth paragraph.",
+		},
+		expectedBefore: "<p>",
+		expectedAfter:  "</p>\n",
+		expectedFound:  true,
+	},
+	"When the suggestion is no longer in the file, then it is not found": {
+		state: SuggestionState{
+			Original:          "Sixth paragraph",
+			CurrentSuggestion: "Sixth paragraph.",
+		},
+	},
+}
+
+func TestGetCurrentSuggestionContext(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range getCurrentSuggestionContextTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			manager := &SuggestionManager{
+				FileSuggestionData: []FileSuggestionInfo{
+					{
+						Name: "chapter1.html",
+						Text: suggestionContextHtml,
+					},
+				},
+				CurrentSuggestionState: &tc.state,
+			}
+
+			before, after, found := manager.GetCurrentSuggestionContext(tc.numContextBlocks)
+
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expectedBefore, before)
+			assert.Equal(t, tc.expectedAfter, after)
+		})
+	}
+
+	t.Run("When there is no current suggestion, then it is not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, found := (&SuggestionManager{}).GetCurrentSuggestionContext(2)
+
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,177 @@
+package suggestionmanager
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrSuggestionNotFound = errors.New("suggestion not found")
+
+// SuggestionListItem is a single suggestion along with where it is in the files and issue types so it can be jumped to.
+type SuggestionListItem struct {
+	FileIndex       int
+	IssueIndex      int
+	SuggestionIndex int
+	FileName        string
+	IssueName       string
+	State           *SuggestionState
+}
+
+// SuggestionFilter limits which suggestions get listed.
+type SuggestionFilter struct {
+	// IssueIndex is the index of the only issue type to list which lists all issue types when it is -1
+	IssueIndex int
+	// Query is text that either the original value or the current suggestion must contain ignoring case
+	Query string
+}
+
+// LoadAllSuggestions gets the suggestions for every file and issue type that have not been gotten yet so
+// they can all be listed. The suggestions are based on the current text of each file, so they get reloaded
+// when they are navigated to after the file has been updated and any that no longer apply when accepted get
+// marked as stale rather than accepted.
+func (sm *SuggestionManager) LoadAllSuggestions() error {
+	for fileIndex := range sm.FileSuggestionData {
+		for issueIndex := range sm.Suggestions {
+			if len(sm.FileSuggestionData[fileIndex].Suggestions[issueIndex]) != 0 || sm.isIssueSkipped(fileIndex, issueIndex) {
+				continue
+			}
+
+			err := sm.loadSuggestions(fileIndex, issueIndex)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListSuggestions gets the suggestions that have been gotten so far that match the filter in the order
+// they are navigated in which is by file, then issue type, and then by the original value.
+func (sm *SuggestionManager) ListSuggestions(filter SuggestionFilter) []SuggestionListItem {
+	var (
+		items []SuggestionListItem
+		query = strings.ToLower(filter.Query)
+	)
+	for fileIndex, fileData := range sm.FileSuggestionData {
+		for issueIndex, suggestions := range fileData.Suggestions {
+			if filter.IssueIndex != -1 && filter.IssueIndex != issueIndex {
+				continue
+			}
+
+			for suggestionIndex := range suggestions {
+				var state = &suggestions[suggestionIndex]
+				if query != "" && !strings.Contains(strings.ToLower(state.Original), query) && !strings.Contains(strings.ToLower(state.CurrentSuggestion), query) {
+					continue
+				}
+
+				items = append(items, SuggestionListItem{
+					FileIndex:       fileIndex,
+					IssueIndex:      issueIndex,
+					SuggestionIndex: suggestionIndex,
+					FileName:        fileData.Name,
+					IssueName:       sm.Suggestions[issueIndex].Name,
+					State:           state,
+				})
+			}
+		}
+	}
+
+	return items
+}
+
+// IssueIndicesWithSuggestions gets the indices of the issue types that have at least one suggestion in any file.
+func (sm *SuggestionManager) IssueIndicesWithSuggestions() []int {
+	var issueIndices []int
+	for issueIndex := range sm.Suggestions {
+		for _, fileData := range sm.FileSuggestionData {
+			if len(fileData.Suggestions[issueIndex]) != 0 {
+				issueIndices = append(issueIndices, issueIndex)
+
+				break
+			}
+		}
+	}
+
+	return issueIndices
+}
+
+// JumpToSuggestion makes the suggestion at the provided indices the current suggestion.
+func (sm *SuggestionManager) JumpToSuggestion(fileIndex, issueIndex, suggestionIndex int) error {
+	if fileIndex < 0 || fileIndex >= len(sm.FileSuggestionData) || issueIndex < 0 || issueIndex >= len(sm.Suggestions) ||
+		suggestionIndex < 0 || suggestionIndex >= len(sm.FileSuggestionData[fileIndex].Suggestions[issueIndex]) {
+		return ErrSuggestionNotFound
+	}
+
+	sm.CurrentFileIndex = fileIndex
+	sm.CurrentIssueIndex = issueIndex
+	sm.CurrentSuggestionIndex = suggestionIndex
+	sm.CurrentFileName = sm.FileSuggestionData[fileIndex].Name
+	sm.CurrentSuggestionName = sm.Suggestions[issueIndex].Name
+	sm.CurrentSuggestion = &sm.Suggestions[issueIndex]
+	sm.CurrentSuggestionState = &sm.FileSuggestionData[fileIndex].Suggestions[issueIndex][suggestionIndex]
+
+	return nil
+}
+
+// SkipSuggestion marks the current suggestion as skipped.
+func (sm *SuggestionManager) SkipSuggestion() error {
+	if sm.CurrentSuggestionState == nil {
+		return ErrNoCurrentSuggestion
+	}
+
+	if sm.CurrentSuggestionState.IsAccepted {
+		return ErrSuggestionAlreadyAccepted
+	}
+
+	sm.CurrentSuggestionState.IsSkipped = true
+
+	return nil
+}
+
+// AcceptRemainingSuggestions accepts all of the suggestions for the issue type in the file that have not been
+// accepted or skipped yet and returns how many were accepted. Suggestions that no longer apply get marked as stale.
+func (sm *SuggestionManager) AcceptRemainingSuggestions(fileIndex, issueIndex int) (int, error) {
+	if fileIndex < 0 || fileIndex >= len(sm.FileSuggestionData) || issueIndex < 0 || issueIndex >= len(sm.Suggestions) {
+		return 0, ErrNoCurrentIssueAvailable
+	}
+
+	var (
+		accepted    int
+		suggestions = sm.FileSuggestionData[fileIndex].Suggestions[issueIndex]
+	)
+	for i := range suggestions {
+		if suggestions[i].IsAccepted || suggestions[i].IsSkipped {
+			continue
+		}
+
+		if sm.acceptSuggestion(fileIndex, sm.Suggestions[issueIndex], &suggestions[i]) {
+			accepted++
+		}
+	}
+
+	return accepted, nil
+}
+
+// SkipRemainingSuggestions skips all of the suggestions for the issue type in the file that have not been
+// accepted or skipped yet and returns how many were skipped.
+func (sm *SuggestionManager) SkipRemainingSuggestions(fileIndex, issueIndex int) (int, error) {
+	if fileIndex < 0 || fileIndex >= len(sm.FileSuggestionData) || issueIndex < 0 || issueIndex >= len(sm.Suggestions) {
+		return 0, ErrNoCurrentIssueAvailable
+	}
+
+	var (
+		skipped     int
+		suggestions = sm.FileSuggestionData[fileIndex].Suggestions[issueIndex]
+	)
+	for i := range suggestions {
+		if suggestions[i].IsAccepted || suggestions[i].IsSkipped {
+			continue
+		}
+
+		suggestions[i].IsSkipped = true
+		skipped++
+	}
+
+	return skipped, nil
+}
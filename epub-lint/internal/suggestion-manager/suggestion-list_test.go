@@ -0,0 +1,300 @@
+//go:build unit
+
+//nolint:testpackage // We check unexported properties here, so we need to be in the same package as the regular one
+package suggestionmanager
+
+import (
+	"strings"
+	"testing"
+
+	potentiallyfixableissue "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/potentially-fixable-issue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type listSuggestionsTestCase struct {
+	filter             SuggestionFilter
+	expectedOriginals  []string
+	expectedIssueNames []string
+}
+
+var listSuggestionsTestCases = map[string]listSuggestionsTestCase{
+	"When there is no filter, then all suggestions are listed in file then issue type order": {
+		filter:             SuggestionFilter{IssueIndex: -1},
+		expectedOriginals:  []string{"apple", "cherry", "banana", "apple", "cherry"},
+		expectedIssueNames: []string{"Fruit", "Fruit", "Yellow", "Fruit", "Fruit"},
+	},
+	"When filtering by issue type, then only suggestions for that issue type are listed": {
+		filter:             SuggestionFilter{IssueIndex: 1},
+		expectedOriginals:  []string{"banana"},
+		expectedIssueNames: []string{"Yellow"},
+	},
+	"When searching, then only suggestions whose original or suggested value contains the query ignoring case are listed": {
+		filter:             SuggestionFilter{IssueIndex: -1, Query: "CHERR"},
+		expectedOriginals:  []string{"cherry", "cherry"},
+		expectedIssueNames: []string{"Fruit", "Fruit"},
+	},
+	"When searching for a suggested value, then the suggestion is listed": {
+		filter:             SuggestionFilter{IssueIndex: -1, Query: "Banana!"},
+		expectedOriginals:  []string{"banana"},
+		expectedIssueNames: []string{"Yellow"},
+	},
+	"When searching and filtering by issue type with no matches, then nothing is listed": {
+		filter: SuggestionFilter{IssueIndex: 1, Query: "apple"},
+	},
+}
+
+func TestSuggestionList(t *testing.T) {
+	t.Parallel()
+
+	t.Run("LoadAllSuggestions", func(t *testing.T) {
+		t.Parallel()
+
+		manager := newManagerForSuggestionListTests()
+
+		require.NoError(t, manager.LoadAllSuggestions())
+
+		assert.Len(t, manager.FileSuggestionData[0].Suggestions[0], 2)
+		assert.Len(t, manager.FileSuggestionData[0].Suggestions[1], 1)
+		assert.Len(t, manager.FileSuggestionData[1].Suggestions[0], 2)
+		assert.Empty(t, manager.FileSuggestionData[1].Suggestions[1])
+		assert.Empty(t, manager.FileSuggestionData[0].Suggestions[2], "disabled issue types should not get suggestions")
+		assert.Equal(t, []int{0, 1}, manager.IssueIndicesWithSuggestions())
+
+		assertManagerIndices(t, manager, 0, -1, 0)
+		assert.Nil(t, manager.CurrentSuggestionState)
+	})
+
+	t.Run("ListSuggestions", func(t *testing.T) {
+		t.Parallel()
+
+		for name, tc := range listSuggestionsTestCases {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				manager := newManagerForSuggestionListTests()
+				require.NoError(t, manager.LoadAllSuggestions())
+
+				var (
+					items      = manager.ListSuggestions(tc.filter)
+					originals  []string
+					issueNames []string
+				)
+				for _, item := range items {
+					originals = append(originals, item.State.Original)
+					issueNames = append(issueNames, item.IssueName)
+					assert.Same(t, &manager.FileSuggestionData[item.FileIndex].Suggestions[item.IssueIndex][item.SuggestionIndex], item.State)
+					assert.Equal(t, manager.FileSuggestionData[item.FileIndex].Name, item.FileName)
+				}
+
+				assert.Equal(t, tc.expectedOriginals, originals)
+				assert.Equal(t, tc.expectedIssueNames, issueNames)
+			})
+		}
+	})
+
+	t.Run("JumpToSuggestion", func(t *testing.T) {
+		t.Parallel()
+
+		manager := newManagerForSuggestionListTests()
+		require.NoError(t, manager.LoadAllSuggestions())
+
+		require.NoError(t, manager.JumpToSuggestion(1, 0, 1))
+
+		assertManagerIndices(t, manager, 1, 0, 1)
+		assertCurrentSuggestion(t, manager, "b.html", "Fruit", &manager.FileSuggestionData[1].Suggestions[0][1])
+		require.NotNil(t, manager.CurrentSuggestion)
+		assert.Equal(t, "Fruit", manager.CurrentSuggestion.Name)
+
+		assert.ErrorIs(t, manager.JumpToSuggestion(1, 1, 0), ErrSuggestionNotFound)
+		assert.ErrorIs(t, manager.JumpToSuggestion(2, 0, 0), ErrSuggestionNotFound)
+		assertManagerIndices(t, manager, 1, 0, 1)
+
+		found, err := manager.SetupForNextSuggestions()
+		require.NoError(t, err)
+		assert.False(t, found, "there should be no suggestions after the last one in the last file")
+	})
+
+	t.Run("SkipSuggestion", func(t *testing.T) {
+		t.Parallel()
+
+		manager := newManagerForSuggestionListTests()
+		assert.ErrorIs(t, manager.SkipSuggestion(), ErrNoCurrentSuggestion)
+
+		found, err := manager.SetupForNextSuggestions()
+		require.NoError(t, err)
+		require.True(t, found)
+
+		require.NoError(t, manager.SkipSuggestion())
+		assert.True(t, manager.CurrentSuggestionState.IsSkipped)
+
+		require.NoError(t, manager.AcceptSuggestion())
+		assert.True(t, manager.CurrentSuggestionState.IsAccepted)
+		assert.False(t, manager.CurrentSuggestionState.IsSkipped, "accepting a skipped suggestion should mean it is no longer skipped")
+		assert.ErrorIs(t, manager.SkipSuggestion(), ErrSuggestionAlreadyAccepted)
+	})
+
+	t.Run("AcceptRemainingSuggestions", func(t *testing.T) {
+		t.Parallel()
+
+		manager := newManagerForSuggestionListTests()
+		require.NoError(t, manager.LoadAllSuggestions())
+
+		manager.FileSuggestionData[0].Suggestions[0][1].IsSkipped = true
+
+		accepted, err := manager.AcceptRemainingSuggestions(0, 0)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, accepted)
+		assert.Equal(t, "Apple! banana cherry", manager.FileSuggestionData[0].Text)
+		assert.True(t, manager.FileSuggestionData[0].Suggestions[0][0].IsAccepted)
+		assert.False(t, manager.FileSuggestionData[0].Suggestions[0][1].IsAccepted, "skipped suggestions should not be accepted")
+		assert.Equal(t, "apple cherry", manager.FileSuggestionData[1].Text, "other files should not be updated")
+
+		accepted, err = manager.AcceptRemainingSuggestions(0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 0, accepted)
+
+		_, err = manager.AcceptRemainingSuggestions(0, 3)
+		assert.ErrorIs(t, err, ErrNoCurrentIssueAvailable)
+	})
+
+	t.Run("SkipRemainingSuggestions", func(t *testing.T) {
+		t.Parallel()
+
+		manager := newManagerForSuggestionListTests()
+		require.NoError(t, manager.LoadAllSuggestions())
+
+		manager.FileSuggestionData[1].Suggestions[0][0].IsAccepted = true
+
+		skipped, err := manager.SkipRemainingSuggestions(1, 0)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, skipped)
+		assert.False(t, manager.FileSuggestionData[1].Suggestions[0][0].IsSkipped, "accepted suggestions should not be skipped")
+		assert.True(t, manager.FileSuggestionData[1].Suggestions[0][1].IsSkipped)
+		assert.False(t, manager.FileSuggestionData[0].Suggestions[0][0].IsSkipped, "other files should not be updated")
+
+		_, err = manager.SkipRemainingSuggestions(-1, 0)
+		assert.ErrorIs(t, err, ErrNoCurrentIssueAvailable)
+	})
+}
+
+func TestOverlappingSuggestions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("When an earlier issue type changes the text of a loaded suggestion, then accepting it marks it as stale", func(t *testing.T) {
+		t.Parallel()
+
+		manager := newManagerForOverlappingSuggestionTests()
+		require.NoError(t, manager.LoadAllSuggestions())
+
+		require.NoError(t, manager.JumpToSuggestion(0, 0, 0))
+		require.NoError(t, manager.AcceptSuggestion())
+		assert.Equal(t, "<p>Apple pie</p>", manager.FileSuggestionData[0].Text)
+
+		require.NoError(t, manager.JumpToSuggestion(0, 1, 0))
+		assert.ErrorIs(t, manager.AcceptSuggestion(), ErrSuggestionIsStale)
+
+		assert.False(t, manager.CurrentSuggestionState.IsAccepted, "a suggestion that could not be applied should not be accepted")
+		assert.True(t, manager.CurrentSuggestionState.IsStale)
+		assert.Equal(t, "<p>Apple pie</p>", manager.FileSuggestionData[0].Text)
+
+		accepted, err := manager.AcceptRemainingSuggestions(0, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 0, accepted)
+	})
+
+	t.Run("When an earlier issue type changes the text of a loaded suggestion, then navigating to it reloads the suggestions", func(t *testing.T) {
+		t.Parallel()
+
+		manager := newManagerForOverlappingSuggestionTests()
+		require.NoError(t, manager.LoadAllSuggestions())
+		require.Len(t, manager.FileSuggestionData[0].Suggestions[1], 1)
+
+		found, err := manager.SetupForNextSuggestions()
+		require.NoError(t, err)
+		require.True(t, found)
+		require.NoError(t, manager.AcceptSuggestion())
+
+		found, err = manager.MoveToNextIssue()
+		require.NoError(t, err)
+		require.True(t, found)
+
+		assertManagerIndices(t, manager, 0, 1, 0)
+		assert.Equal(t, "Apple pie", manager.CurrentSuggestionState.Original)
+		assert.Equal(t, "Apple tart", manager.CurrentSuggestionState.CurrentSuggestion)
+
+		require.NoError(t, manager.AcceptSuggestion())
+		assert.Equal(t, "<p>Apple tart</p>", manager.FileSuggestionData[0].Text)
+	})
+}
+
+// newManagerForOverlappingSuggestionTests creates a manager with 1 file and 2 issue types whose suggestions are for the same text
+func newManagerForOverlappingSuggestionTests() *SuggestionManager {
+	return NewSuggestionManager([]potentiallyfixableissue.PotentiallyFixableIssue{
+		{
+			Name: "Capitalize",
+			GetSuggestions: func(text string) (map[string]string, error) {
+				if strings.Contains(text, "apple pie") {
+					return map[string]string{"apple pie": "Apple pie"}, nil
+				}
+
+				return nil, nil
+			},
+			IsEnabled: pointerToBool(true),
+		},
+		{
+			Name: "Tart",
+			GetSuggestions: func(text string) (map[string]string, error) {
+				for _, original := range []string{"apple pie", "Apple pie"} {
+					if strings.Contains(text, original) {
+						return map[string]string{original: strings.Replace(original, "pie", "tart", 1)}, nil
+					}
+				}
+
+				return nil, nil
+			},
+			IsEnabled: pointerToBool(true),
+		},
+	}, map[string]string{
+		"a.html": "<p>apple pie</p>",
+	}, false, false, nil)
+}
+
+// newManagerForSuggestionListTests creates a manager with 2 files and 3 issue types where the last one is disabled
+func newManagerForSuggestionListTests() *SuggestionManager {
+	var getWordSuggestions = func(words ...string) func(string) (map[string]string, error) {
+		return func(text string) (map[string]string, error) {
+			var suggestions = make(map[string]string)
+			for _, word := range words {
+				if strings.Contains(text, word) {
+					suggestions[word] = strings.ToUpper(word[:1]) + word[1:] + "!"
+				}
+			}
+
+			return suggestions, nil
+		}
+	}
+
+	return NewSuggestionManager([]potentiallyfixableissue.PotentiallyFixableIssue{
+		{
+			Name:           "Fruit",
+			GetSuggestions: getWordSuggestions("cherry", "apple"),
+			IsEnabled:      pointerToBool(true),
+		},
+		{
+			Name:           "Yellow",
+			GetSuggestions: getWordSuggestions("banana"),
+			IsEnabled:      pointerToBool(true),
+		},
+		{
+			Name:           "Disabled",
+			GetSuggestions: getWordSuggestions("apple"),
+			IsEnabled:      pointerToBool(false),
+		},
+	}, map[string]string{
+		"a.html": "apple banana cherry",
+		"b.html": "apple cherry",
+	}, false, false, nil)
+}
@@ -8,7 +8,10 @@ import (
 
 // SuggestionState represents the state of a single suggestion.
 type SuggestionState struct {
-	IsAccepted                           bool
+	IsAccepted bool
+	IsSkipped  bool
+	// IsStale is whether accepting the suggestion failed because its original value is no longer in the file
+	IsStale                              bool
 	OriginallyHadHalfwidthCircleKatakana bool
 	Original                             string
 	OriginalSuggestion                   string
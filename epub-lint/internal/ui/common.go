@@ -14,6 +14,9 @@ var (
 	viewIcon     = string([]byte{0xF0, 0x9F, 0x91, 0x81}) // UTF-8 encoding for "👁"
 	editIcon     = string([]byte{0xE2, 0x9C, 0x8E})       // UTF-8 encoding for "✎"
 	warningIcon  = string([]byte{0xE2, 0x9A, 0xA0})       // UTF-8 encoding for "⚠"
+	acceptedIcon = string([]byte{0xE2, 0x9C, 0x93})       // UTF-8 encoding for "✓"
+	skippedIcon  = string([]byte{0xE2, 0x9C, 0x97})       // UTF-8 encoding for "✗"
+	pendingIcon  = string([]byte{0xE2, 0x80, 0xA2})       // UTF-8 encoding for "•"
 )
 
 func fillLine(currentValue string, width int) string {
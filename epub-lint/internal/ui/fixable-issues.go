@@ -64,6 +64,9 @@ type PotentiallyFixableStageInfo struct {
 	suggestionEdit                                                                      textarea.Model
 	suggestionDisplay                                                                   viewport.Model
 	scrollbar                                                                           tea.Model
+	suggestionList                                                                      suggestionListInfo
+	// showPreview is whether the suggestion is displayed as rendered text with its surrounding paragraphs instead of as html
+	showPreview bool
 }
 
 type CssSelectionStageInfo struct {
@@ -107,6 +110,7 @@ func NewFixableIssuesModel(runAll, skipCss, runSectionBreak bool, potentiallyFix
 			suggestionEdit:    ta,
 			suggestionDisplay: v,
 			scrollbar:         sb,
+			suggestionList:    newSuggestionListInfo(),
 		},
 		CssSelectionInfo: CssSelectionStageInfo{
 			cssFiles: cssFiles,
@@ -157,6 +161,11 @@ func (m FixableIssuesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			return m, tea.Quit
 		case "esc":
+			// esc closes the suggestion list instead of exiting when it is open
+			if m.currentStage == suggestionsProcessing && m.PotentiallyFixableIssuesInfo.suggestionList.isOpen {
+				break
+			}
+
 			cmd = m.exitOrMoveToCssSelection()
 
 			return m, cmd
@@ -229,6 +238,7 @@ func (m *FixableIssuesModel) recalculateElementSizes(resetSuggestionYOffset bool
 	m.body.SetHeight(max(0, m.height-(m.headerHeight()+m.footerHeight())))
 
 	m.sectionBreakInfo.input.SetWidth(m.width)
+	m.PotentiallyFixableIssuesInfo.suggestionList.search.SetWidth(max(m.width-lipgloss.Width(m.PotentiallyFixableIssuesInfo.suggestionList.search.Prompt)-1, 1))
 	m.setSuggestionDisplay(resetSuggestionYOffset)
 }
 
@@ -274,13 +284,12 @@ func (m FixableIssuesModel) bodyView() string {
 	return ""
 }
 
+// exitOrMoveToCssSelection moves to selecting the css file to update when a css update is required and there is a css file to select.
+// Otherwise it exits since a css file will be created when the epub does not have one.
 func (m *FixableIssuesModel) exitOrMoveToCssSelection() tea.Cmd {
-	if m.PotentiallyFixableIssuesInfo.CssUpdateRequired {
+	if m.PotentiallyFixableIssuesInfo.CssUpdateRequired && len(m.CssSelectionInfo.cssFiles) != 0 {
 		m.currentStage = stageCssSelection
-
-		if len(m.CssSelectionInfo.cssFiles) != 0 {
-			m.CssSelectionInfo.SelectedCssFile = m.CssSelectionInfo.cssFiles[m.CssSelectionInfo.currentCssIndex]
-		}
+		m.CssSelectionInfo.SelectedCssFile = m.CssSelectionInfo.cssFiles[m.CssSelectionInfo.currentCssIndex]
 
 		m.recalculateElementSizes(false)
 	} else {
@@ -18,6 +18,15 @@ type helpKeys struct {
 	reset              helpKey
 	original           helpKey
 	cancelEdit         helpKey
+	skip               helpKey
+	acceptAll          helpKey
+	skipAll            helpKey
+	list               helpKey
+	selectItem         helpKey
+	issueTypeFilter    helpKey
+	jumpToItem         helpKey
+	closeList          helpKey
+	preview            helpKey
 }
 
 type helpKey struct {
@@ -88,6 +97,51 @@ var (
 			long:  "Cancel edit",
 			short: "Cancel",
 		},
+		skip: helpKey{
+			keys:  "S",
+			long:  "Skip",
+			short: "Skip",
+		},
+		acceptAll: helpKey{
+			keys:  "Ctrl+A",
+			long:  "Accept remaining of issue type in file",
+			short: "Accept all",
+		},
+		skipAll: helpKey{
+			keys:  "Ctrl+X",
+			long:  "Skip remaining of issue type in file",
+			short: "Skip all",
+		},
+		list: helpKey{
+			keys:  "L",
+			long:  "List all suggestions",
+			short: "List",
+		},
+		selectItem: helpKey{
+			keys:  "↑/↓",
+			long:  "Previous/Next Suggestion",
+			short: "Suggestion",
+		},
+		issueTypeFilter: helpKey{
+			keys:  "Shift+Tab/Tab",
+			long:  "Previous/Next Issue Type Filter",
+			short: "Filter",
+		},
+		jumpToItem: helpKey{
+			keys:  "Enter",
+			long:  "Go to suggestion",
+			short: "Go to",
+		},
+		closeList: helpKey{
+			keys:  "Esc",
+			long:  "Close list",
+			short: "Close",
+		},
+		preview: helpKey{
+			keys:  "P",
+			long:  "Toggle rendered preview",
+			short: "Preview",
+		},
 	}
 )
 
@@ -152,6 +206,19 @@ func (m FixableIssuesModel) currentFooterBindings(keys helpKeys) []helpKey {
 			}
 		}
 
+		if m.PotentiallyFixableIssuesInfo.suggestionList.isOpen {
+			return []helpKey{
+				keys.selectItem,
+				keys.prevNextFile,
+				keys.issueTypeFilter,
+				keys.jumpToItem,
+				keys.acceptAll,
+				keys.skipAll,
+				keys.closeList,
+				keys.exitWithoutSaving,
+			}
+		}
+
 		var currentSuggestion = m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState
 		if currentSuggestion != nil && currentSuggestion.IsAccepted {
 			return []helpKey{
@@ -159,6 +226,10 @@ func (m FixableIssuesModel) currentFooterBindings(keys helpKeys) []helpKey {
 				keys.prevNextIssueType,
 				keys.prevNextFile,
 				keys.copy,
+				keys.preview,
+				keys.acceptAll,
+				keys.skipAll,
+				keys.list,
 				keys.quit,
 				keys.exitWithoutSaving,
 			}
@@ -171,6 +242,11 @@ func (m FixableIssuesModel) currentFooterBindings(keys helpKeys) []helpKey {
 			keys.edit,
 			keys.copy,
 			keys.accept,
+			keys.skip,
+			keys.preview,
+			keys.acceptAll,
+			keys.skipAll,
+			keys.list,
 			keys.quit,
 			keys.exitWithoutSaving,
 		}
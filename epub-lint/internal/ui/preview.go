@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	stringdiff "github.com/pjkaufman/go-go-gadgets/pkg/string-diff"
+	"golang.org/x/net/html"
+)
+
+// previewContextBlocks is how many paragraphs (or other block elements) to show before and after the suggestion in the preview
+const previewContextBlocks = 2
+
+var (
+	previewBlockTags = map[string]struct{}{
+		"p": {}, "div": {}, "h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {}, "li": {}, "ul": {}, "ol": {},
+		"blockquote": {}, "pre": {}, "dt": {}, "dd": {}, "figure": {}, "figcaption": {}, "table": {}, "tr": {},
+		"section": {}, "aside": {}, "header": {}, "footer": {},
+	}
+	previewBoldTags   = map[string]struct{}{"b": {}, "strong": {}, "h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {}}
+	previewItalicTags = map[string]struct{}{"i": {}, "em": {}, "cite": {}, "dfn": {}, "var": {}}
+	previewSkipTags   = map[string]struct{}{"head": {}, "title": {}, "script": {}, "style": {}}
+)
+
+// previewRenderer converts html into formatted terminal text keeping track of the formatting and whitespace
+// so that fragments of html can be rendered one after the other as if they were a single piece of html
+type previewRenderer struct {
+	out          strings.Builder
+	hrWidth      int
+	boldDepth    int
+	italicDepth  int
+	skipDepth    int
+	hasContent   bool
+	pendingBreak bool
+	pendingSpace bool
+	atLineStart  bool
+}
+
+// buildPreview renders the current suggestion and the paragraphs around it as formatted text with the change
+// highlighted falling back to the html diff when the suggestion cannot be found in its file
+func (m *FixableIssuesModel) buildPreview(expectedSuggestionWidth int) string {
+	var (
+		suggestionState      = m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState
+		before, after, found = m.PotentiallyFixableIssuesInfo.SuggestionManager.GetCurrentSuggestionContext(previewContextBlocks)
+	)
+	if !found {
+		return m.buildSuggestion(suggestionState.Display, expectedSuggestionWidth)
+	}
+
+	var beforeRenderer = previewRenderer{hrWidth: expectedSuggestionWidth}
+	beforeRenderer.render(before, true)
+
+	// the change is rendered without formatting so that the diff highlighting is the only styling it has
+	var (
+		originalRenderer   = beforeRenderer.continueRendering()
+		suggestionRenderer = beforeRenderer.continueRendering()
+	)
+	originalRenderer.render(suggestionState.Original, false)
+	suggestionRenderer.render(suggestionState.CurrentSuggestion, false)
+
+	diff, err := stringdiff.GetPrettyDiffString(originalRenderer.out.String(), suggestionRenderer.out.String())
+	if err != nil {
+		return m.buildSuggestion(suggestionState.Display, expectedSuggestionWidth)
+	}
+
+	// what comes after follows the suggestion since that is what will be in the file once it is accepted
+	var afterRenderer = suggestionRenderer.continueRendering()
+	afterRenderer.render(after, true)
+
+	var text = strings.Trim(beforeRenderer.out.String()+diff+afterRenderer.out.String(), "\n")
+
+	return displayStyle.Width(expectedSuggestionWidth).Render(suggestionState.ReplaceBrokenDisplayCharacters(text))
+}
+
+// continueRendering creates a renderer with the same state as the current one, but with no output so
+// the next fragment can be rendered separately
+func (r *previewRenderer) continueRendering() previewRenderer {
+	return previewRenderer{
+		hrWidth:      r.hrWidth,
+		boldDepth:    r.boldDepth,
+		italicDepth:  r.italicDepth,
+		skipDepth:    r.skipDepth,
+		hasContent:   r.hasContent,
+		pendingBreak: r.pendingBreak,
+		pendingSpace: r.pendingSpace,
+		atLineStart:  r.atLineStart,
+	}
+}
+
+func (r *previewRenderer) render(fragment string, useFormatting bool) {
+	var tokenizer = html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		var tokenType = tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return
+		}
+
+		var token = tokenizer.Token()
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			r.handleStartTag(token.Data, tokenType == html.SelfClosingTagToken, useFormatting)
+		case html.EndTagToken:
+			r.handleEndTag(token.Data)
+		case html.TextToken:
+			r.writeText(token.Data, useFormatting)
+		}
+	}
+}
+
+func (r *previewRenderer) handleStartTag(tag string, isSelfClosing, useFormatting bool) {
+	switch tag {
+	case "br":
+		r.pendingSpace = false
+		r.atLineStart = true
+		r.out.WriteString("\n")
+
+		return
+	case "hr":
+		r.pendingBreak = r.hasContent
+		r.flushBreak()
+
+		var line = strings.Repeat("─", max(r.hrWidth, 1))
+		if useFormatting {
+			line = hrStyle.Render(line)
+		}
+
+		r.out.WriteString(line)
+		r.hasContent = true
+		r.atLineStart = false
+		r.pendingBreak = true
+
+		return
+	}
+
+	if _, isBlock := previewBlockTags[tag]; isBlock {
+		r.pendingBreak = r.hasContent
+		r.pendingSpace = false
+	}
+
+	if isSelfClosing {
+		return
+	}
+
+	if _, isSkipped := previewSkipTags[tag]; isSkipped {
+		r.skipDepth++
+	}
+
+	if _, isBold := previewBoldTags[tag]; isBold {
+		r.boldDepth++
+	}
+
+	if _, isItalic := previewItalicTags[tag]; isItalic {
+		r.italicDepth++
+	}
+
+	if tag == "li" {
+		r.writeText("• ", useFormatting)
+	}
+}
+
+func (r *previewRenderer) handleEndTag(tag string) {
+	if _, isBlock := previewBlockTags[tag]; isBlock {
+		r.pendingBreak = r.hasContent
+		r.pendingSpace = false
+	}
+
+	if _, isSkipped := previewSkipTags[tag]; isSkipped {
+		r.skipDepth = max(r.skipDepth-1, 0)
+	}
+
+	if _, isBold := previewBoldTags[tag]; isBold {
+		r.boldDepth = max(r.boldDepth-1, 0)
+	}
+
+	if _, isItalic := previewItalicTags[tag]; isItalic {
+		r.italicDepth = max(r.italicDepth-1, 0)
+	}
+}
+
+// writeText writes the text collapsing its whitespace the way a browser would
+func (r *previewRenderer) writeText(text string, useFormatting bool) {
+	if r.skipDepth > 0 {
+		return
+	}
+
+	var (
+		startsWithSpace = strings.TrimLeft(text, " \t\r\n") != text
+		endsWithSpace   = strings.TrimRight(text, " \t\r\n") != text
+		words           = strings.Fields(text)
+	)
+	if len(words) == 0 {
+		r.pendingSpace = r.pendingSpace || (text != "" && r.hasContent && !r.pendingBreak)
+
+		return
+	}
+
+	r.flushBreak()
+	if (r.pendingSpace || startsWithSpace) && r.hasContent && !r.atLineStart {
+		r.out.WriteString(" ")
+	}
+
+	text = strings.Join(words, " ")
+	if useFormatting && (r.boldDepth > 0 || r.italicDepth > 0) {
+		text = lipgloss.NewStyle().Bold(r.boldDepth > 0).Italic(r.italicDepth > 0).Render(text)
+	}
+
+	r.out.WriteString(text)
+	r.hasContent = true
+	r.atLineStart = false
+	r.pendingSpace = endsWithSpace
+}
+
+func (r *previewRenderer) flushBreak() {
+	if !r.pendingBreak {
+		return
+	}
+
+	r.out.WriteString("\n\n")
+	r.atLineStart = true
+	r.pendingBreak = false
+	r.pendingSpace = false
+}
@@ -0,0 +1,339 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"github.com/muesli/reflow/truncate"
+	potentiallyfixableissue "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/potentially-fixable-issue"
+	suggestionmanager "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/suggestion-manager"
+)
+
+const suggestionListHeaderHeight = 3
+
+type suggestionListInfo struct {
+	isOpen bool
+	search textinput.Model
+	// issueFilter is the index in issueIndices of the issue type to list which is -1 when all issue types are listed
+	issueFilter  int
+	issueIndices []int
+	cursor       int
+	items        []suggestionmanager.SuggestionListItem
+}
+
+func newSuggestionListInfo() suggestionListInfo {
+	search := textinput.New()
+	search.Prompt = "Search: "
+	search.Placeholder = "Text in the original or suggested value"
+	search.CharLimit = 200
+
+	return suggestionListInfo{
+		search:      search,
+		issueFilter: -1,
+	}
+}
+
+// openSuggestionList gets the suggestions for all files and issue types so they can be listed with the
+// current suggestion selected
+func (m *FixableIssuesModel) openSuggestionList() (tea.Cmd, error) {
+	var (
+		suggestionManager = m.PotentiallyFixableIssuesInfo.SuggestionManager
+		list              = &m.PotentiallyFixableIssuesInfo.suggestionList
+	)
+	err := suggestionManager.LoadAllSuggestions()
+	if err != nil {
+		return nil, err
+	}
+
+	list.isOpen = true
+	list.issueIndices = suggestionManager.IssueIndicesWithSuggestions()
+	if list.issueFilter >= len(list.issueIndices) {
+		list.issueFilter = -1
+	}
+
+	m.refreshSuggestionList()
+
+	for i, item := range list.items {
+		if item.State == suggestionManager.CurrentSuggestionState {
+			list.cursor = i
+
+			break
+		}
+	}
+
+	m.recalculateElementSizes(false)
+
+	return list.search.Focus(), nil
+}
+
+func (m *FixableIssuesModel) closeSuggestionList() {
+	m.PotentiallyFixableIssuesInfo.suggestionList.isOpen = false
+	m.PotentiallyFixableIssuesInfo.suggestionList.search.Blur()
+
+	m.recalculateElementSizes(true)
+}
+
+// refreshSuggestionList gets the suggestions that match the current issue type filter and search keeping the cursor in bounds
+func (m *FixableIssuesModel) refreshSuggestionList() {
+	var (
+		list       = &m.PotentiallyFixableIssuesInfo.suggestionList
+		issueIndex = -1
+	)
+	if list.issueFilter != -1 {
+		issueIndex = list.issueIndices[list.issueFilter]
+	}
+
+	list.items = m.PotentiallyFixableIssuesInfo.SuggestionManager.ListSuggestions(suggestionmanager.SuggestionFilter{
+		IssueIndex: issueIndex,
+		Query:      list.search.Value(),
+	})
+	list.cursor = max(min(list.cursor, len(list.items)-1), 0)
+}
+
+func (m *FixableIssuesModel) handleSuggestionListMsgs(msg tea.Msg) tea.Cmd {
+	var (
+		cmd  tea.Cmd
+		list = &m.PotentiallyFixableIssuesInfo.suggestionList
+	)
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			m.closeSuggestionList()
+
+			return nil
+		case "up":
+			list.cursor = max(list.cursor-1, 0)
+
+			return nil
+		case "down":
+			list.cursor = max(min(list.cursor+1, len(list.items)-1), 0)
+
+			return nil
+		case "pgup":
+			list.cursor = m.getPreviousFileListIndex()
+
+			return nil
+		case "pgdown":
+			list.cursor = m.getNextFileListIndex()
+
+			return nil
+		case "tab":
+			list.issueFilter++
+			if list.issueFilter >= len(list.issueIndices) {
+				list.issueFilter = -1
+			}
+
+			m.refreshSuggestionList()
+
+			return nil
+		case "shift+tab":
+			list.issueFilter--
+			if list.issueFilter < -1 {
+				list.issueFilter = len(list.issueIndices) - 1
+			}
+
+			m.refreshSuggestionList()
+
+			return nil
+		case "enter":
+			if len(list.items) == 0 {
+				return nil
+			}
+
+			var item = list.items[list.cursor]
+			m.Err = m.PotentiallyFixableIssuesInfo.SuggestionManager.JumpToSuggestion(item.FileIndex, item.IssueIndex, item.SuggestionIndex)
+			if m.Err != nil {
+				return tea.Quit
+			}
+
+			m.closeSuggestionList()
+
+			return nil
+		case "ctrl+a":
+			if len(list.items) == 0 {
+				return nil
+			}
+
+			var item = list.items[list.cursor]
+			m.Err = m.acceptRemainingSuggestions(item.FileIndex, item.IssueIndex)
+			if m.Err != nil {
+				return tea.Quit
+			}
+
+			m.refreshSuggestionList()
+
+			return nil
+		case "ctrl+x":
+			if len(list.items) == 0 {
+				return nil
+			}
+
+			var item = list.items[list.cursor]
+			_, m.Err = m.PotentiallyFixableIssuesInfo.SuggestionManager.SkipRemainingSuggestions(item.FileIndex, item.IssueIndex)
+			if m.Err != nil {
+				return tea.Quit
+			}
+
+			m.refreshSuggestionList()
+
+			return nil
+		}
+	}
+
+	var oldQuery = list.search.Value()
+	list.search, cmd = list.search.Update(msg)
+	if oldQuery != list.search.Value() {
+		list.cursor = 0
+		m.refreshSuggestionList()
+	}
+
+	return cmd
+}
+
+// acceptRemainingSuggestions accepts the suggestions of the issue type in the file that have not been accepted or skipped
+// noting whether the css needs to be updated because of them
+func (m *FixableIssuesModel) acceptRemainingSuggestions(fileIndex, issueIndex int) error {
+	accepted, err := m.PotentiallyFixableIssuesInfo.SuggestionManager.AcceptRemainingSuggestions(fileIndex, issueIndex)
+	if err != nil {
+		return err
+	}
+
+	if accepted != 0 {
+		m.setCssUpdateRequirements(m.PotentiallyFixableIssuesInfo.SuggestionManager.Suggestions[issueIndex])
+	}
+
+	return nil
+}
+
+// setCssUpdateRequirements notes that the css needs to be updated when an accepted suggestion needs a css rule to exist
+func (m *FixableIssuesModel) setCssUpdateRequirements(issue potentiallyfixableissue.PotentiallyFixableIssue) {
+	if issue.AddCssSectionBreakIfMissing {
+		m.PotentiallyFixableIssuesInfo.AddCssSectionBreakIfMissing = true
+		m.PotentiallyFixableIssuesInfo.CssUpdateRequired = true
+	} else if issue.AddCssPageBreakIfMissing {
+		m.PotentiallyFixableIssuesInfo.AddCssPageBreakIfMissing = true
+		m.PotentiallyFixableIssuesInfo.CssUpdateRequired = true
+	}
+}
+
+// getPreviousFileListIndex gets the index of the first item of the file before the one the cursor is in
+// or the first item of the current file when the cursor is not already on it
+func (m FixableIssuesModel) getPreviousFileListIndex() int {
+	var list = m.PotentiallyFixableIssuesInfo.suggestionList
+	if len(list.items) == 0 {
+		return 0
+	}
+
+	var (
+		index     = list.cursor
+		fileIndex = list.items[index].FileIndex
+	)
+	for index > 0 && list.items[index-1].FileIndex == fileIndex {
+		index--
+	}
+
+	if index != list.cursor || index == 0 {
+		return index
+	}
+
+	fileIndex = list.items[index-1].FileIndex
+	for index > 0 && list.items[index-1].FileIndex == fileIndex {
+		index--
+	}
+
+	return index
+}
+
+// getNextFileListIndex gets the index of the first item of the file after the one the cursor is in
+// or the current index when there is no file after it
+func (m FixableIssuesModel) getNextFileListIndex() int {
+	var list = m.PotentiallyFixableIssuesInfo.suggestionList
+	for index := list.cursor + 1; index < len(list.items); index++ {
+		if list.items[index].FileIndex != list.items[list.cursor].FileIndex {
+			return index
+		}
+	}
+
+	return list.cursor
+}
+
+func (m FixableIssuesModel) suggestionListView() string {
+	var (
+		list              = m.PotentiallyFixableIssuesInfo.suggestionList
+		suggestionManager = m.PotentiallyFixableIssuesInfo.SuggestionManager
+		maxWidth          = max(m.body.Width(), 1)
+		issueType         = "All"
+		s                 strings.Builder
+	)
+	if list.issueFilter != -1 {
+		issueType = fmt.Sprintf("%s (%d/%d)", suggestionManager.Suggestions[list.issueIndices[list.issueFilter]].Name, list.issueFilter+1, len(list.issueIndices))
+	}
+
+	s.WriteString(list.search.View())
+	s.WriteString("\n")
+	s.WriteString(truncate.StringWithTail(sectionIcon+" "+suggestionNameStyle.Render(issueType)+inactiveStyle.Render(fmt.Sprintf(" | %d suggestion(s)", len(list.items))), uint(maxWidth), "…"))
+	s.WriteString("\n")
+	s.WriteString(hrStyle.Render(strings.Repeat("─", maxWidth)))
+
+	if len(list.items) == 0 {
+		s.WriteString("\nNo suggestions match the search and issue type.")
+
+		return s.String()
+	}
+
+	var (
+		lines      []string
+		cursorLine int
+	)
+	for i, item := range list.items {
+		if i == 0 || list.items[i-1].FileIndex != item.FileIndex {
+			lines = append(lines, truncate.StringWithTail(documentIcon+" "+fileNameStyle.Render(item.FileName), uint(maxWidth), "…"))
+		}
+
+		var (
+			line  = fmt.Sprintf("%s %s: %s → %s", getSuggestionStatusIcon(item.State), item.IssueName, getSingleLineText(item.State.Original), getSingleLineText(item.State.CurrentSuggestion))
+			style = displayStyle
+		)
+		if i == list.cursor {
+			cursorLine = len(lines)
+			line = "> " + line
+			style = activeStyle
+		} else {
+			line = "  " + line
+		}
+
+		lines = append(lines, style.Render(truncate.StringWithTail(line, uint(maxWidth), "…")))
+	}
+
+	// only show the lines that fit keeping the selected item in view
+	var (
+		height = max(m.body.Height()-suggestionListHeaderHeight, 1)
+		start  = max(min(cursorLine-height/2, len(lines)-height), 0)
+		end    = min(start+height, len(lines))
+	)
+	s.WriteString("\n")
+	s.WriteString(strings.Join(lines[start:end], "\n"))
+
+	return s.String()
+}
+
+func getSuggestionStatusIcon(state *suggestionmanager.SuggestionState) string {
+	if state.IsAccepted {
+		return acceptedIcon
+	} else if state.IsSkipped {
+		return skippedIcon
+	} else if state.IsStale {
+		return warningIcon
+	}
+
+	return pendingIcon
+}
+
+// getSingleLineText collapses the whitespace in the text so it fits on a single line and replaces the characters
+// that do not display properly in the terminal
+func getSingleLineText(text string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(text), " "), "ﾟ", "°")
+}
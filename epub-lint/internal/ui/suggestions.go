@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -9,9 +10,14 @@ import (
 	"github.com/acarl005/stripansi"
 	"github.com/atotto/clipboard"
 	"github.com/muesli/reflow/wordwrap"
+	suggestionmanager "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/suggestion-manager"
 )
 
 func (m *FixableIssuesModel) suggestionsView() string {
+	if m.PotentiallyFixableIssuesInfo.suggestionList.isOpen {
+		return m.suggestionListView()
+	}
+
 	return m.suggestionView()
 }
 
@@ -77,6 +83,18 @@ func (m *FixableIssuesModel) suggestionView() string {
 	if m.PotentiallyFixableIssuesInfo.isEditing {
 		modeIcon = editIcon
 		modeName = "Edit"
+	} else {
+		if m.PotentiallyFixableIssuesInfo.showPreview {
+			modeName = "Preview"
+		}
+
+		if m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState.IsAccepted {
+			modeName += " " + acceptedIcon + " Accepted"
+		} else if m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState.IsSkipped {
+			modeName += " " + skippedIcon + " Skipped"
+		} else if m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState.IsStale {
+			modeName += " " + warningIcon + " No longer in file"
+		}
 	}
 
 	var (
@@ -108,6 +126,10 @@ func (m *FixableIssuesModel) suggestionView() string {
 }
 
 func (m *FixableIssuesModel) handleSuggestionMsgs(msg tea.Msg) tea.Cmd {
+	if m.PotentiallyFixableIssuesInfo.suggestionList.isOpen {
+		return m.handleSuggestionListMsgs(msg)
+	}
+
 	var (
 		cmd  tea.Cmd
 		cmds []tea.Cmd
@@ -212,16 +234,34 @@ func (m *FixableIssuesModel) handleSuggestionMsgs(msg tea.Msg) tea.Cmd {
 			case "enter":
 				if !m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState.IsAccepted && m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestion != nil {
 					m.Err = m.PotentiallyFixableIssuesInfo.SuggestionManager.AcceptSuggestion()
-					if m.Err != nil {
+					if errors.Is(m.Err, suggestionmanager.ErrSuggestionIsStale) {
+						// the suggestion gets marked as stale, so it just needs to be displayed as such
+						m.Err = nil
+						m.recalculateElementSizes(false)
+
+						return tea.Batch(cmds...)
+					} else if m.Err != nil {
+						return tea.Quit
+					}
+
+					m.setCssUpdateRequirements(*m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestion)
+
+					cmd, err := m.moveToNextSuggestion()
+					if err != nil {
+						m.Err = err
+
 						return tea.Quit
 					}
 
-					if m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestion.AddCssSectionBreakIfMissing {
-						m.PotentiallyFixableIssuesInfo.AddCssSectionBreakIfMissing = true
-						m.PotentiallyFixableIssuesInfo.CssUpdateRequired = true
-					} else if m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestion.AddCssPageBreakIfMissing {
-						m.PotentiallyFixableIssuesInfo.AddCssPageBreakIfMissing = true
-						m.PotentiallyFixableIssuesInfo.CssUpdateRequired = true
+					m.recalculateElementSizes(false)
+
+					cmds = append(cmds, cmd)
+				}
+			case "s":
+				if m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState != nil && !m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState.IsAccepted {
+					m.Err = m.PotentiallyFixableIssuesInfo.SuggestionManager.SkipSuggestion()
+					if m.Err != nil {
+						return tea.Quit
 					}
 
 					cmd, err := m.moveToNextSuggestion()
@@ -235,6 +275,38 @@ func (m *FixableIssuesModel) handleSuggestionMsgs(msg tea.Msg) tea.Cmd {
 
 					cmds = append(cmds, cmd)
 				}
+			case "ctrl+a", "ctrl+x":
+				var suggestionManager = m.PotentiallyFixableIssuesInfo.SuggestionManager
+				if msg.String() == "ctrl+a" {
+					m.Err = m.acceptRemainingSuggestions(suggestionManager.CurrentFileIndex, suggestionManager.CurrentIssueIndex)
+				} else {
+					_, m.Err = suggestionManager.SkipRemainingSuggestions(suggestionManager.CurrentFileIndex, suggestionManager.CurrentIssueIndex)
+				}
+
+				if m.Err != nil {
+					return tea.Quit
+				}
+
+				cmd, err := m.handleForwardSuggestionUpdate(suggestionManager.MoveToNextIssue)
+				if err != nil {
+					m.Err = err
+
+					return tea.Quit
+				}
+
+				cmds = append(cmds, cmd)
+			case "p":
+				m.PotentiallyFixableIssuesInfo.showPreview = !m.PotentiallyFixableIssuesInfo.showPreview
+				m.recalculateElementSizes(true)
+			case "l":
+				cmd, err := m.openSuggestionList()
+				if err != nil {
+					m.Err = err
+
+					return tea.Quit
+				}
+
+				cmds = append(cmds, cmd)
 			case "e":
 				if m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState != nil && !m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState.IsAccepted {
 					m.PotentiallyFixableIssuesInfo.isEditing = true
@@ -352,7 +424,7 @@ func (m *FixableIssuesModel) setSuggestionDisplay(resetYOffset bool) {
 
 	var (
 		expectedSuggestionWidth = m.PotentiallyFixableIssuesInfo.suggestionDisplay.Width()
-		suggestion              = m.buildSuggestion(m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState.Display, expectedSuggestionWidth)
+		suggestion              = m.buildSuggestionDisplay(expectedSuggestionWidth)
 	)
 
 	m.PotentiallyFixableIssuesInfo.suggestionDisplay.SetContent(suggestion)
@@ -362,7 +434,7 @@ func (m *FixableIssuesModel) setSuggestionDisplay(resetYOffset bool) {
 		m.PotentiallyFixableIssuesInfo.suggestionDisplay.SetWidth(remainingWidth - scrollbarPadding)
 
 		expectedSuggestionWidth = m.PotentiallyFixableIssuesInfo.suggestionDisplay.Width()
-		suggestion = m.buildSuggestion(m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState.Display, expectedSuggestionWidth)
+		suggestion = m.buildSuggestionDisplay(expectedSuggestionWidth)
 
 		m.PotentiallyFixableIssuesInfo.suggestionDisplay.SetContent(suggestion)
 	}
@@ -383,6 +455,15 @@ func (m *FixableIssuesModel) setSuggestionDisplay(resetYOffset bool) {
 	m.PotentiallyFixableIssuesInfo.scrollbar, _ = m.PotentiallyFixableIssuesInfo.scrollbar.Update(HeightMsg(m.PotentiallyFixableIssuesInfo.suggestionDisplay.Height()))
 }
 
+// buildSuggestionDisplay builds either the rendered preview or the html diff of the current suggestion depending on which one is toggled on
+func (m *FixableIssuesModel) buildSuggestionDisplay(expectedSuggestionWidth int) string {
+	if m.PotentiallyFixableIssuesInfo.showPreview {
+		return m.buildPreview(expectedSuggestionWidth)
+	}
+
+	return m.buildSuggestion(m.PotentiallyFixableIssuesInfo.SuggestionManager.CurrentSuggestionState.Display, expectedSuggestionWidth)
+}
+
 func (m *FixableIssuesModel) buildSuggestion(displayText string, expectedSuggestionWidth int) string {
 	//nolint:gocritic // can include ansi escape codes so we should ignore this issue here
 	text := fmt.Sprintf(`"%s"`, displayText) // includes ANSI
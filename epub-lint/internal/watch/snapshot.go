@@ -0,0 +1,131 @@
+package watch
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"time"
+
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+)
+
+type fileState struct {
+	modified time.Time
+	size     int64
+}
+
+// Snapshot is the state of the files in a folder by their path relative to the folder
+type Snapshot map[string]fileState
+
+// TakeSnapshot gets the modification time and size of all of the files in the folder
+func TakeSnapshot(dir string) (Snapshot, error) {
+	var snapshot = make(Snapshot)
+	err := filepath.WalkDir(dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		snapshot[filepath.ToSlash(relativePath)] = fileState{
+			modified: info.ModTime(),
+			size:     info.Size(),
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the state of the files in %q: %w", dir, err)
+	}
+
+	return snapshot, nil
+}
+
+// Files gets the paths of the files in the snapshot
+func (s Snapshot) Files() map[string]struct{} {
+	var files = make(map[string]struct{}, len(s))
+	for file := range s {
+		files[file] = struct{}{}
+	}
+
+	return files
+}
+
+// GetChangedFiles gets the files that were added, removed, or modified between the previous and current snapshots in alphabetical order
+func GetChangedFiles(previous, current Snapshot) []string {
+	var changed []string
+	for file, state := range current {
+		if previousState, exists := previous[file]; !exists || previousState != state {
+			changed = append(changed, file)
+		}
+	}
+
+	for file := range previous {
+		if _, exists := current[file]; !exists {
+			changed = append(changed, file)
+		}
+	}
+
+	slices.Sort(changed)
+
+	return changed
+}
+
+// findingKey identifies a finding without its position so that a finding is still matched after lines are added or removed above it
+type findingKey struct {
+	file, rule, message string
+}
+
+// DiffFindings gets the findings that are new in the current findings and the ones that are no longer present
+// keeping the order they are in. Findings are matched by their file, rule, and message so a finding that only
+// moved is neither new nor resolved.
+func DiffFindings(previous, current []structurecheck.Finding) (added []structurecheck.Finding, resolved []structurecheck.Finding) {
+	var previousCounts = make(map[findingKey]int, len(previous))
+	for _, finding := range previous {
+		previousCounts[getFindingKey(finding)]++
+	}
+
+	var currentCounts = make(map[findingKey]int, len(current))
+	for _, finding := range current {
+		var key = getFindingKey(finding)
+		currentCounts[key]++
+
+		// only the instances of a finding beyond how many there were before are new
+		if currentCounts[key] > previousCounts[key] {
+			added = append(added, finding)
+		}
+	}
+
+	var seenCounts = make(map[findingKey]int, len(previous))
+	for _, finding := range previous {
+		var key = getFindingKey(finding)
+		seenCounts[key]++
+
+		if seenCounts[key] > currentCounts[key] {
+			resolved = append(resolved, finding)
+		}
+	}
+
+	return added, resolved
+}
+
+func getFindingKey(finding structurecheck.Finding) findingKey {
+	return findingKey{
+		file:    finding.File,
+		rule:    finding.Rule,
+		message: finding.Message,
+	}
+}
@@ -0,0 +1,83 @@
+//go:build unit
+
+package watch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	structurecheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/structure-check"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/watch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	var dir = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "OEBPS"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OEBPS/a.xhtml"), []byte("a"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OEBPS/b.xhtml"), []byte("b"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OEBPS/c.xhtml"), []byte("c"), 0644))
+
+	previous, err := watch.TakeSnapshot(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"OEBPS/a.xhtml": {}, "OEBPS/b.xhtml": {}, "OEBPS/c.xhtml": {}}, previous.Files())
+
+	unchanged, err := watch.TakeSnapshot(dir)
+	require.NoError(t, err)
+	assert.Empty(t, watch.GetChangedFiles(previous, unchanged))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OEBPS/a.xhtml"), []byte("a changed"), 0644))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "OEBPS/a.xhtml"), time.Now(), time.Now().Add(time.Minute)))
+	require.NoError(t, os.Remove(filepath.Join(dir, "OEBPS/b.xhtml")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "OEBPS/d.xhtml"), []byte("d"), 0644))
+
+	current, err := watch.TakeSnapshot(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OEBPS/a.xhtml", "OEBPS/b.xhtml", "OEBPS/d.xhtml"}, watch.GetChangedFiles(previous, current))
+}
+
+func TestDiffFindings(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a = structurecheck.Finding{File: "a.xhtml", Line: 1, Column: 1, Rule: structurecheck.MissingAlt, Message: "image is missing alt text"}
+		b = structurecheck.Finding{File: "b.xhtml", Line: 2, Column: 3, Rule: structurecheck.DuplicateId, Message: `duplicate id "b"`}
+		c = structurecheck.Finding{File: "c.xhtml", Rule: structurecheck.ManifestFile, Message: "c.xhtml must exist"}
+		d = structurecheck.Finding{File: "d.xhtml", Line: 4, Column: 1, Rule: structurecheck.BrokenLink, Message: "link is broken"}
+	)
+
+	added, resolved := watch.DiffFindings([]structurecheck.Finding{a, b, c}, []structurecheck.Finding{b, d})
+	assert.Equal(t, []structurecheck.Finding{d}, added)
+	assert.Equal(t, []structurecheck.Finding{a, c}, resolved)
+
+	t.Run("When a finding only changes position, then it is neither added nor resolved", func(t *testing.T) {
+		t.Parallel()
+
+		var movedB = b
+		movedB.Line += 3
+
+		added, resolved := watch.DiffFindings([]structurecheck.Finding{a, b}, []structurecheck.Finding{a, movedB})
+		assert.Empty(t, added)
+		assert.Empty(t, resolved)
+	})
+
+	t.Run("When there are more instances of a finding than before, then only the extra ones are added", func(t *testing.T) {
+		t.Parallel()
+
+		var secondA = a
+		secondA.Line = 10
+
+		added, resolved := watch.DiffFindings([]structurecheck.Finding{a}, []structurecheck.Finding{a, secondA})
+		assert.Equal(t, []structurecheck.Finding{secondA}, added)
+		assert.Empty(t, resolved)
+
+		added, resolved = watch.DiffFindings([]structurecheck.Finding{a, secondA}, []structurecheck.Finding{secondA})
+		assert.Empty(t, added)
+		assert.Equal(t, []structurecheck.Finding{secondA}, resolved)
+	})
+}
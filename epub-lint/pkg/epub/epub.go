@@ -0,0 +1,421 @@
+// Package epub is the public API for opening and editing epubs as well as running the lint, fix, and optimize operations
+// that epub-lint is built on. Unlike the commands, the operations here return their errors instead of exiting.
+package epub
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"slices"
+
+	epubdoc "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-doc"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+var (
+	ErrClosed       = errors.New("epub is closed")
+	ErrFileNotFound = errors.New("file not found in the epub")
+)
+
+// Epub is an opened epub file where any changes are kept in memory until it is saved
+type Epub struct {
+	// Path is the location of the epub file on disk
+	Path string
+	// OpfFile is the path of the opf file in the epub
+	OpfFile string
+	// OpfFolder is the folder the opf file is in which manifest hrefs are relative to
+	OpfFolder string
+
+	reader      *zip.ReadCloser
+	zipFiles    map[string]*zip.File
+	fileOrder   []string
+	opf         *epubdoc.Opf
+	opfContents string
+	updated     map[string]string
+	removed     map[string]struct{}
+}
+
+// ManifestItem is a file listed in the manifest of the opf file
+type ManifestItem struct {
+	Id         string
+	Href       string
+	MediaType  string
+	Properties []string
+}
+
+// SpineItem is an entry in the reading order of the opf file
+type SpineItem struct {
+	Idref  string
+	Linear bool
+}
+
+// MetadataItem is an element in the metadata of the opf file (i.e. dc:title or meta)
+type MetadataItem struct {
+	Name  string
+	Id    string
+	Value string
+	Attrs map[string]string
+}
+
+// Open opens the epub at the path for reading and editing. The epub needs to be closed once it is no longer needed.
+func Open(path string) (*Epub, error) {
+	r, zipFiles, err := filehandler.GetFilesFromZip(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zip contents for %q: %w", path, err)
+	}
+
+	epubInfo, opfFolder, err := epubhandler.GetEpubInfo(path, zipFiles)
+	if err != nil {
+		filehandler.TryClose(path, r)
+		return nil, err
+	}
+
+	opfContents, err := filehandler.ReadInZipFileContents(zipFiles[epubInfo.OpfFile])
+	if err != nil {
+		filehandler.TryClose(path, r)
+		return nil, err
+	}
+
+	opf, err := epubdoc.ParseOpf(opfContents)
+	if err != nil {
+		filehandler.TryClose(path, r)
+		return nil, fmt.Errorf("failed to parse %q for %q: %w", epubInfo.OpfFile, path, err)
+	}
+
+	var fileOrder = make([]string, 0, len(zipFiles))
+	for _, zipFile := range r.File {
+		if zipFiles[zipFile.Name] == zipFile {
+			fileOrder = append(fileOrder, zipFile.Name)
+		}
+	}
+
+	return &Epub{
+		Path:        path,
+		OpfFile:     epubInfo.OpfFile,
+		OpfFolder:   opfFolder,
+		reader:      r,
+		zipFiles:    zipFiles,
+		fileOrder:   fileOrder,
+		opf:         opf,
+		opfContents: opfContents,
+		updated:     make(map[string]string),
+		removed:     make(map[string]struct{}),
+	}, nil
+}
+
+// Close closes the epub without saving any changes that were made to it
+func (e *Epub) Close() error {
+	if e.reader == nil {
+		return nil
+	}
+
+	var err = e.reader.Close()
+	e.reader = nil
+
+	return err
+}
+
+// Save writes any changes made to the epub back to its file keeping the original with an ".original" suffix.
+// Files that were not changed are copied over as is. The epub stays open with the saved changes.
+func (e *Epub) Save() error {
+	if e.reader == nil {
+		return ErrClosed
+	}
+
+	if opfContents := e.opf.String(); opfContents != e.opfContents {
+		e.updated[e.OpfFile] = opfContents
+	}
+
+	if len(e.updated) == 0 && len(e.removed) == 0 {
+		return nil
+	}
+
+	// the epub is only closed once the changes are written so that it is still usable when saving fails
+	err := epubhandler.StreamUpdateEpub(e.Path, func(zipFiles map[string]*zip.File, _ epubhandler.EpubInfo, _ string) (epubhandler.StreamOperation, error) {
+		return epubhandler.StreamOperation{
+			RewriteFile: func(zipFile *zip.File, w *zip.Writer) (bool, error) {
+				if _, ok := e.removed[zipFile.Name]; ok {
+					return true, nil
+				}
+
+				contents, ok := e.updated[zipFile.Name]
+				if !ok {
+					return false, nil
+				}
+
+				return true, filehandler.WriteZipCompressedString(w, zipFile.Name, contents)
+			},
+			AddFiles: func(w *zip.Writer) error {
+				for _, name := range e.Files() {
+					if _, ok := zipFiles[name]; ok {
+						continue
+					}
+
+					err := filehandler.WriteZipCompressedString(w, name, e.updated[name])
+					if err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+		}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save %q: %w", e.Path, err)
+	}
+
+	err = e.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close %q after saving it: %w", e.Path, err)
+	}
+
+	saved, err := Open(e.Path)
+	if err != nil {
+		return err
+	}
+
+	*e = *saved
+
+	return nil
+}
+
+// Files gets the paths of all of the files in the epub in the order they are stored in with any new files at the end
+func (e *Epub) Files() []string {
+	var files = make([]string, 0, len(e.fileOrder)+len(e.updated))
+	for _, name := range e.fileOrder {
+		if _, ok := e.removed[name]; !ok {
+			files = append(files, name)
+		}
+	}
+
+	var newFiles []string
+	for name := range e.updated {
+		if _, ok := e.zipFiles[name]; !ok {
+			newFiles = append(newFiles, name)
+		}
+	}
+
+	slices.Sort(newFiles)
+
+	return append(files, newFiles...)
+}
+
+// HasFile returns whether the file is in the epub
+func (e *Epub) HasFile(name string) bool {
+	if _, ok := e.updated[name]; ok {
+		return true
+	}
+
+	if _, ok := e.removed[name]; ok {
+		return false
+	}
+
+	_, ok := e.zipFiles[name]
+
+	return ok
+}
+
+// ReadFile gets the contents of the file including any changes to it that have not been saved yet
+func (e *Epub) ReadFile(name string) (string, error) {
+	if name == e.OpfFile {
+		return e.opf.String(), nil
+	}
+
+	if contents, ok := e.updated[name]; ok {
+		return contents, nil
+	}
+
+	if !e.HasFile(name) {
+		return "", fmt.Errorf("%w: %q", ErrFileNotFound, name)
+	}
+
+	if e.reader == nil {
+		return "", ErrClosed
+	}
+
+	return filehandler.ReadInZipFileContents(e.zipFiles[name])
+}
+
+// WriteFile sets the contents of the file adding it to the epub if it is not already present.
+// New files are not added to the manifest which is what AddFile is for.
+func (e *Epub) WriteFile(name, contents string) error {
+	if name == e.OpfFile {
+		opf, err := epubdoc.ParseOpf(contents)
+		if err != nil {
+			return fmt.Errorf("failed to parse the new contents of %q: %w", name, err)
+		}
+
+		e.opf = opf
+
+		return nil
+	}
+
+	delete(e.removed, name)
+	e.updated[name] = contents
+
+	return nil
+}
+
+// AddFile adds the file to the epub at the href relative to the opf folder and adds it to the manifest
+func (e *Epub) AddFile(id, href, mediaType, contents string) error {
+	_, err := e.opf.AddManifestItem(id, href, mediaType)
+	if err != nil {
+		return err
+	}
+
+	return e.WriteFile(e.FilePath(href), contents)
+}
+
+// RemoveFile removes the file from the epub along with any manifest items and spine entries that reference it
+func (e *Epub) RemoveFile(name string) error {
+	if !e.HasFile(name) {
+		return fmt.Errorf("%w: %q", ErrFileNotFound, name)
+	}
+
+	delete(e.updated, name)
+	if _, ok := e.zipFiles[name]; ok {
+		e.removed[name] = struct{}{}
+	}
+
+	for _, item := range e.opf.ManifestItems() {
+		if e.FilePath(item.Href()) == name {
+			e.opf.RemoveManifestItem(item)
+		}
+	}
+
+	return nil
+}
+
+// FilePath gets the path of the file in the epub from an href in the opf file
+func (e *Epub) FilePath(href string) string {
+	return filehandler.JoinPath(e.OpfFolder, href)
+}
+
+// Version gets the major version of the epub (i.e. 2 or 3) returning 0 when the opf file does not have a valid version
+func (e *Epub) Version() int {
+	version, err := epubhandler.VersionTextToInt(e.opf.Package.AttrValue("version"))
+	if err != nil {
+		return 0
+	}
+
+	return version
+}
+
+// ContentFiles gets the paths of the content files in the epub in reading order based on the current spine and manifest
+func (e *Epub) ContentFiles() []string {
+	var files []string
+	for _, spineItem := range e.opf.SpineItems() {
+		item, ok := e.opf.ManifestItemById(spineItem.Idref())
+		if !ok {
+			continue
+		}
+
+		file, err := epubhandler.HrefToFile(item.Href())
+		if err != nil {
+			continue
+		}
+
+		files = append(files, e.FilePath(file))
+	}
+
+	return files
+}
+
+// Manifest gets the items in the manifest of the opf file in the order they are listed in
+func (e *Epub) Manifest() []ManifestItem {
+	var items []ManifestItem
+	for _, item := range e.opf.ManifestItems() {
+		items = append(items, ManifestItem{
+			Id:         item.Id(),
+			Href:       item.Href(),
+			MediaType:  item.MediaType(),
+			Properties: item.Properties(),
+		})
+	}
+
+	return items
+}
+
+// Spine gets the items in the spine of the opf file in reading order
+func (e *Epub) Spine() []SpineItem {
+	var items []SpineItem
+	for _, item := range e.opf.SpineItems() {
+		items = append(items, SpineItem{
+			Idref:  item.Idref(),
+			Linear: item.Linear(),
+		})
+	}
+
+	return items
+}
+
+// Metadata gets the elements in the metadata of the opf file in the order they are listed in
+func (e *Epub) Metadata() []MetadataItem {
+	var metadata = e.opf.Metadata()
+	if metadata == nil {
+		return nil
+	}
+
+	var items []MetadataItem
+	for _, el := range metadata.Elements() {
+		var attrs = make(map[string]string, len(el.Attrs))
+		for _, attr := range el.Attrs {
+			attrs[attr.Name] = attr.Value
+		}
+
+		items = append(items, MetadataItem{
+			Name:  el.Name,
+			Id:    el.AttrValue("id"),
+			Value: el.Text(),
+			Attrs: attrs,
+		})
+	}
+
+	return items
+}
+
+// GetMetadata gets the value of the first metadata element with the name (i.e. "dc:title") or an empty string if there is none
+func (e *Epub) GetMetadata(name string) string {
+	var elements = e.opf.MetadataElements(name)
+	if len(elements) == 0 {
+		return ""
+	}
+
+	return elements[0].Text()
+}
+
+// SetMetadata sets the value of the first metadata element with the name adding the element when there is none
+func (e *Epub) SetMetadata(name, value string) error {
+	var elements = e.opf.MetadataElements(name)
+	if len(elements) != 0 {
+		elements[0].SetText(value)
+
+		return nil
+	}
+
+	_, err := e.opf.AddMetadata(name, value)
+
+	return err
+}
+
+// Title gets the value of the first dc:title element or an empty string if there is none
+func (e *Epub) Title() string {
+	return e.GetMetadata("dc:title")
+}
+
+// Language gets the value of the first dc:language element or an empty string if there is none
+func (e *Epub) Language() string {
+	return e.GetMetadata("dc:language")
+}
+
+// Identifier gets the value of the identifier the package's unique-identifier points to
+func (e *Epub) Identifier() string {
+	var identifier = e.opf.UniqueIdentifier()
+	if identifier == nil {
+		return ""
+	}
+
+	return identifier.Text()
+}
@@ -0,0 +1,154 @@
+//go:build unit
+
+package epub_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/pkg/epub"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOpf = `<?xml version="1.0" encoding="utf-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="pub-id">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="pub-id">urn:uuid:1234</dc:identifier>
+    <dc:title>Title</dc:title>
+  </metadata>
+  <manifest>
+    <item id="chapter1" href="Text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+    <item id="chapter2" href="Text/chapter2.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine>
+    <itemref idref="chapter1"/>
+    <itemref idref="chapter2" linear="no"/>
+  </spine>
+</package>`
+
+func createTestEpub(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	var path = filepath.Join(t.TempDir(), "book.epub")
+	epubFile, err := os.Create(path)
+	require.NoError(t, err)
+	defer epubFile.Close()
+
+	var w = zip.NewWriter(epubFile)
+	require.NoError(t, filehandler.WriteZipUncompressedString(w, "mimetype", "application/epub+zip"))
+
+	for _, name := range []string{"META-INF/container.xml", "OEBPS/content.opf", "OEBPS/Text/chapter1.xhtml", "OEBPS/Text/chapter2.xhtml", "OEBPS/extra.txt"} {
+		contents, ok := files[name]
+		if !ok {
+			continue
+		}
+
+		require.NoError(t, filehandler.WriteZipCompressedString(w, name, contents))
+	}
+
+	require.NoError(t, w.Close())
+
+	return path
+}
+
+func getTestEpubFiles(chapter1, chapter2 string) map[string]string {
+	return map[string]string{
+		"META-INF/container.xml":    "<container/>",
+		"OEBPS/content.opf":         testOpf,
+		"OEBPS/Text/chapter1.xhtml": chapter1,
+		"OEBPS/Text/chapter2.xhtml": chapter2,
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	e, err := epub.Open(createTestEpub(t, getTestEpubFiles("<html>1</html>", "<html>2</html>")))
+	require.NoError(t, err)
+	defer e.Close()
+
+	assert.Equal(t, "OEBPS/content.opf", e.OpfFile)
+	assert.Equal(t, "OEBPS", e.OpfFolder)
+	assert.Equal(t, 3, e.Version())
+	assert.Equal(t, "Title", e.Title())
+	assert.Equal(t, "urn:uuid:1234", e.Identifier())
+	assert.Empty(t, e.Language())
+	assert.Equal(t, []string{"OEBPS/Text/chapter1.xhtml", "OEBPS/Text/chapter2.xhtml"}, e.ContentFiles())
+	assert.Equal(t, []epub.ManifestItem{
+		{Id: "chapter1", Href: "Text/chapter1.xhtml", MediaType: "application/xhtml+xml", Properties: []string{}},
+		{Id: "chapter2", Href: "Text/chapter2.xhtml", MediaType: "application/xhtml+xml", Properties: []string{}},
+	}, e.Manifest())
+	assert.Equal(t, []epub.SpineItem{{Idref: "chapter1", Linear: true}, {Idref: "chapter2", Linear: false}}, e.Spine())
+
+	var metadata = e.Metadata()
+	require.Len(t, metadata, 2)
+	assert.Equal(t, epub.MetadataItem{Name: "dc:identifier", Id: "pub-id", Value: "urn:uuid:1234", Attrs: map[string]string{"id": "pub-id"}}, metadata[0])
+
+	contents, err := e.ReadFile("OEBPS/Text/chapter2.xhtml")
+	require.NoError(t, err)
+	assert.Equal(t, "<html>2</html>", contents)
+
+	_, err = e.ReadFile("OEBPS/Text/missing.xhtml")
+	assert.ErrorIs(t, err, epub.ErrFileNotFound)
+}
+
+func TestOpfChangesAreUsedForTheReadingOrder(t *testing.T) {
+	t.Parallel()
+
+	e, err := epub.Open(createTestEpub(t, getTestEpubFiles("<html>1</html>", "<html>2</html>")))
+	require.NoError(t, err)
+	defer e.Close()
+
+	require.NoError(t, e.RemoveFile("OEBPS/Text/chapter1.xhtml"))
+	assert.Equal(t, []string{"OEBPS/Text/chapter2.xhtml"}, e.ContentFiles())
+
+	for _, file := range e.ContentFiles() {
+		_, err = e.ReadFile(file)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, e.WriteFile(e.OpfFile, strings.NewReplacer(`version="3.0"`, `version="2.0"`, `<itemref idref="chapter1"/>`, "").Replace(testOpf)))
+	assert.Equal(t, 2, e.Version())
+	assert.Equal(t, []string{"OEBPS/Text/chapter2.xhtml"}, e.ContentFiles())
+}
+
+func TestSave(t *testing.T) {
+	t.Parallel()
+
+	var path = createTestEpub(t, getTestEpubFiles("<html>1</html>", "<html>2</html>"))
+	e, err := epub.Open(path)
+	require.NoError(t, err)
+	defer e.Close()
+
+	require.NoError(t, e.SetMetadata("dc:language", "en"))
+	require.NoError(t, e.SetMetadata("dc:title", "New Title"))
+	require.NoError(t, e.WriteFile("OEBPS/Text/chapter1.xhtml", "<html>updated 1</html>"))
+	require.NoError(t, e.RemoveFile("OEBPS/Text/chapter2.xhtml"))
+	require.NoError(t, e.AddFile("chapter3", "Text/chapter3.xhtml", "application/xhtml+xml", "<html>3</html>"))
+	require.NoError(t, e.Save())
+
+	assert.Equal(t, []string{"mimetype", "META-INF/container.xml", "OEBPS/content.opf", "OEBPS/Text/chapter1.xhtml", "OEBPS/Text/chapter3.xhtml"}, e.Files())
+	assert.Equal(t, "en", e.Language())
+	assert.Equal(t, "New Title", e.Title())
+	assert.Equal(t, []epub.SpineItem{{Idref: "chapter1", Linear: true}}, e.Spine())
+
+	var manifest = e.Manifest()
+	require.Len(t, manifest, 2)
+	assert.Equal(t, "chapter3", manifest[1].Id)
+
+	contents, err := e.ReadFile("OEBPS/Text/chapter1.xhtml")
+	require.NoError(t, err)
+	assert.Equal(t, "<html>updated 1</html>", contents)
+
+	contents, err = e.ReadFile("OEBPS/Text/chapter3.xhtml")
+	require.NoError(t, err)
+	assert.Equal(t, "<html>3</html>", contents)
+
+	_, err = os.Stat(path + ".original")
+	assert.NoError(t, err)
+}
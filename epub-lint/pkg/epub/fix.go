@@ -0,0 +1,135 @@
+package epub
+
+import (
+	"archive/zip"
+	"fmt"
+	"path/filepath"
+
+	epubcheck "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-check"
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/jnovels"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+type FixOptions struct {
+	// CleanupJNovels is whether or not to remove the JNovels info files and pages when they are present
+	CleanupJNovels bool
+}
+
+// FixResult is the number of validation issues for each code in the EPUBCheck output split by whether or not there are fixes for the code
+type FixResult struct {
+	HandledCodes   map[string]int
+	UnhandledCodes map[string]int
+}
+
+// FixValidationIssues fixes the issues in the EPUBCheck output for the epub that can be fixed without the user making any changes
+func FixValidationIssues(path, epubCheckOutput string, options FixOptions) (FixResult, error) {
+	var result FixResult
+	validationErrors, err := epubcheck.ParseEPUBCheckOutput(epubCheckOutput)
+	if err != nil {
+		return result, err
+	}
+
+	var summary = validationErrors.SummarizeCodes()
+	result.HandledCodes = summary.Handled
+	result.UnhandledCodes = summary.Unhandled
+
+	validationErrors.Sort()
+
+	err = epubhandler.UpdateEpub(path, func(zipFiles map[string]*zip.File, w *zip.Writer, epubInfo epubhandler.EpubInfo, opfFolder string) ([]string, error) {
+		var (
+			opfFilename = epubInfo.OpfFile
+			opfFile     = zipFiles[opfFilename]
+		)
+
+		opfFileContents, err := filehandler.ReadInZipFileContents(opfFile)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			ncxFilename           string
+			nameToUpdatedContents = map[string]string{
+				opfFilename: opfFileContents,
+			}
+		)
+		// epub 3 files do not need an ncx file, so it is only used when there is one
+		if epubInfo.NcxFile != "" {
+			if ncxFile, ok := zipFiles[filepath.Join(opfFolder, epubInfo.NcxFile)]; ok {
+				ncxFilename = ncxFile.Name
+
+				nameToUpdatedContents[ncxFilename], err = filehandler.ReadInZipFileContents(ncxFile)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		var basenameToFilePaths = make(map[string][]string)
+		for filename := range zipFiles {
+			var basename = filepath.Base(filename)
+			basenameToFilePaths[basename] = append(basenameToFilePaths[basename], filename)
+		}
+
+		var (
+			handledFiles          []string
+			getFileContentsByName = func(filename string) (string, error) {
+				fileContents, ok := nameToUpdatedContents[filename]
+				if !ok {
+					zipFile, ok := zipFiles[filename]
+					if !ok {
+						return "", fmt.Errorf("failed to find %q in the epub", filename)
+					}
+
+					fileContents, err = filehandler.ReadInZipFileContents(zipFile)
+					if err != nil {
+						return "", err
+					}
+				}
+
+				return fileContents, nil
+			}
+		)
+		err = epubcheck.HandleValidationErrors(opfFolder, ncxFilename, opfFilename, nameToUpdatedContents, basenameToFilePaths, &validationErrors, getFileContentsByName, epubInfo.FilePathsInSpineOrder)
+		if err != nil {
+			return nil, err
+		}
+
+		if options.CleanupJNovels {
+			handledFiles, err = jnovels.CleanupJNovelsFiles(jnovels.JNovelsCleanupContext{
+				EpubInfo:            epubInfo,
+				OpfFolder:           opfFolder,
+				OpfFileName:         opfFilename,
+				NcxFileName:         ncxFilename,
+				FileBasenameMap:     basenameToFilePaths,
+				UpdatedFileContents: nameToUpdatedContents,
+				GetFileContents:     getFileContentsByName,
+			})
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for filename, updatedContents := range nameToUpdatedContents {
+			var name = filepath.Base(filename)
+			if options.CleanupJNovels && (name == jnovels.JnovelsFile || name == jnovels.JnovelsImage) {
+				continue
+			}
+
+			handledFiles = append(handledFiles, filename)
+
+			err = filehandler.WriteZipCompressedString(w, filename, updatedContents)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return handledFiles, nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to fix validation issues in %q: %w", path, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,31 @@
+//go:build unit
+
+package epub_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/pkg/epub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixValidationIssuesWithoutNcx(t *testing.T) {
+	t.Parallel()
+
+	var path = createTestEpub(t, getTestEpubFiles(`<html><body><p id="dup">One</p><p id="dup">Two</p></body></html>`, `<html><body><p>Chapter 2</p></body></html>`))
+	result, err := epub.FixValidationIssues(path, `ERROR(RSC-005): /home/user/Documents/book.epub/OEBPS/Text/chapter1.xhtml(1,38): Error while parsing file: Duplicate ID "dup"`, epub.FixOptions{
+		CleanupJNovels: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"RSC-005": 1}, result.HandledCodes)
+
+	e, err := epub.Open(path)
+	require.NoError(t, err)
+	defer e.Close()
+
+	contents, err := e.ReadFile("OEBPS/Text/chapter1.xhtml")
+	require.NoError(t, err)
+	assert.Equal(t, `<html><body><p id="dup">One</p><p id="dup_2">Two</p></body></html>`, contents)
+	assert.False(t, e.HasFile(""), "expected no file to be written for the missing ncx file")
+}
@@ -0,0 +1,255 @@
+package epub
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+	potentiallyfixableissue "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/potentially-fixable-issue"
+)
+
+// Check is one of the content checks that Lint can run which are named the same as the flags of the fix content command
+type Check string
+
+const (
+	CheckBrokenLines              Check = "broken-lines"
+	CheckSectionBreaks            Check = "section-breaks"
+	CheckPageBreaks               Check = "page-breaks"
+	CheckOxfordCommas             Check = "oxford-commas"
+	CheckLackingSubordinateClause Check = "lacking-subordinate-clause"
+	CheckThoughts                 Check = "thoughts"
+	CheckConversation             Check = "conversation"
+	CheckNecessaryWords           Check = "necessary-words"
+	CheckSingleQuotes             Check = "single-quotes"
+	CheckDialoguePunctuation      Check = "dialogue-punctuation"
+	CheckNameConsistency          Check = "name-consistency"
+)
+
+// AllChecks are all of the checks Lint can run in the order they are run in
+var AllChecks = []Check{
+	CheckConversation,
+	CheckNecessaryWords,
+	CheckBrokenLines,
+	CheckSingleQuotes,
+	CheckSectionBreaks,
+	CheckPageBreaks,
+	CheckOxfordCommas,
+	CheckLackingSubordinateClause,
+	CheckThoughts,
+	CheckDialoguePunctuation,
+	CheckNameConsistency,
+}
+
+var checkDescriptions = map[Check]string{
+	CheckBrokenLines:              "Potentially broken line",
+	CheckSectionBreaks:            "Potential section break",
+	CheckPageBreaks:               "Potential page break",
+	CheckOxfordCommas:             "Potentially missing oxford comma",
+	CheckLackingSubordinateClause: "Potentially lacking subordinate clause",
+	CheckThoughts:                 "Potential thought in parentheses",
+	CheckConversation:             "Potential conversation in square brackets",
+	CheckNecessaryWords:           "Potentially necessary words in square brackets",
+	CheckSingleQuotes:             "Potentially incorrect single quotes",
+	CheckDialoguePunctuation:      "Potential dialogue punctuation issue",
+	CheckNameConsistency:          "Potential name inconsistency",
+}
+
+var checkNames = map[Check]string{
+	CheckBrokenLines:              "Potential Broken Lines",
+	CheckSectionBreaks:            "Potential Section Breaks",
+	CheckPageBreaks:               "Potential Page Breaks",
+	CheckOxfordCommas:             "Potential Missing Oxford Commas",
+	CheckLackingSubordinateClause: "Potentially Lacking Subordinate Clause Instances",
+	CheckThoughts:                 "Potential Thought Instances",
+	CheckConversation:             "Potential Conversation Instances",
+	CheckNecessaryWords:           "Potential Necessary Word Omission Instances",
+	CheckSingleQuotes:             "Potential Incorrect Single Quotes",
+	CheckDialoguePunctuation:      "Potential Dialogue Punctuation Issues",
+	CheckNameConsistency:          "Potential Name Inconsistencies",
+}
+
+// Description describes what the issues found by the check are
+func (c Check) Description() string {
+	if description, ok := checkDescriptions[c]; ok {
+		return description
+	}
+
+	return string(c)
+}
+
+type LintOptions struct {
+	// Checks are the checks to run which is all of them when empty
+	Checks []Check
+	// SectionBreakIndicator is the text that marks a section break (i.e. "* * *") which the section break check needs to run
+	SectionBreakIndicator string
+	// NameSourceFiles are the files to get the names from for name consistency which defaults to the files being linted.
+	// This allows linting only some of the files of an epub while still comparing the names to the ones in all of its files.
+	NameSourceFiles []ContentFile
+}
+
+// Issue is a possible problem found in a content file along with the suggested fix for it
+type Issue struct {
+	Check     Check
+	File      string
+	Original  string
+	Suggested string
+}
+
+// ContentFile is a content file to lint by its path and text
+type ContentFile struct {
+	Path string
+	Text string
+}
+
+// Lint runs the content checks against each of the content files of the epub in reading order returning the issues that were found.
+// Checks only run on files in the languages they apply to and nothing in the epub is changed.
+func Lint(path string, options LintOptions) ([]Issue, error) {
+	e, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer e.Close()
+
+	var (
+		contentFiles = e.ContentFiles()
+		files        = make([]ContentFile, 0, len(contentFiles))
+	)
+	for _, file := range contentFiles {
+		text, err := e.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, ContentFile{
+			Path: file,
+			Text: text,
+		})
+	}
+
+	return LintFiles(files, options)
+}
+
+// LintFiles runs the content checks against the files in the order they are provided in which is useful for checking files that are not in an epub yet
+// like the files of an unpacked epub. Name consistency is based on the names in all of the files unless there are name source files.
+func LintFiles(files []ContentFile, options LintOptions) ([]Issue, error) {
+	var checks = options.Checks
+	if len(checks) == 0 {
+		checks = AllChecks
+	}
+
+	for _, check := range checks {
+		if !slices.Contains(AllChecks, check) {
+			return nil, fmt.Errorf("unknown check %q", check)
+		}
+	}
+
+	var nameSourceFiles = options.NameSourceFiles
+	if nameSourceFiles == nil {
+		nameSourceFiles = files
+	}
+
+	var fixableIssues = getFixableIssues(checks, options.SectionBreakIndicator, nameSourceFiles)
+
+	var issues []Issue
+	for _, file := range files {
+		var lang = linter.GetLanguage(file.Text)
+		for _, check := range checks {
+			fixableIssue, ok := fixableIssues[check]
+			if !ok || !linter.LanguageMatches(lang, fixableIssue.Languages) {
+				continue
+			}
+
+			suggestions, err := fixableIssue.GetSuggestions(file.Text)
+			if err != nil {
+				return nil, fmt.Errorf("failed to run %q on %q: %w", check, file.Path, err)
+			}
+
+			for _, original := range slices.Sorted(maps.Keys(suggestions)) {
+				issues = append(issues, Issue{
+					Check:     check,
+					File:      file.Path,
+					Original:  original,
+					Suggested: suggestions[original],
+				})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// getFixableIssues gets the detectors for the checks where the section break check is skipped when there is no indicator for it
+// and name consistency is based on the names in all of the name source files
+func getFixableIssues(checks []Check, sectionBreakIndicator string, nameSourceFiles []ContentFile) map[Check]potentiallyfixableissue.PotentiallyFixableIssue {
+	var nameVariants = potentiallyfixableissue.NewNameVariantTable()
+	if slices.Contains(checks, CheckNameConsistency) {
+		for _, file := range nameSourceFiles {
+			nameVariants.AddText(file.Text)
+		}
+	}
+
+	var fixableIssues = make(map[Check]potentiallyfixableissue.PotentiallyFixableIssue, len(checks))
+	for _, check := range checks {
+		if check == CheckSectionBreaks && sectionBreakIndicator == "" {
+			continue
+		}
+
+		fixableIssues[check] = NewFixableIssue(check, func() string {
+			return sectionBreakIndicator
+		}, nameVariants)
+	}
+
+	return fixableIssues
+}
+
+// NewFixableIssue creates the detector for the check along with the languages it applies to and how its fixes get applied.
+// The section break indicator is gotten when suggestions are requested so it can be determined after the detector is created
+// and name consistency uses whatever names have been added to the name variants by then.
+func NewFixableIssue(check Check, getSectionBreakIndicator func() string, nameVariants *potentiallyfixableissue.NameVariantTable) potentiallyfixableissue.PotentiallyFixableIssue {
+	var fixableIssue = potentiallyfixableissue.PotentiallyFixableIssue{
+		Name: checkNames[check],
+	}
+
+	switch check {
+	case CheckConversation:
+		fixableIssue.GetSuggestions = potentiallyfixableissue.GetPotentialSquareBracketConversationInstances
+		fixableIssue.Languages = linter.LatinScriptLanguages
+	case CheckNecessaryWords:
+		fixableIssue.GetSuggestions = potentiallyfixableissue.GetPotentialSquareBracketNecessaryWords
+		fixableIssue.Languages = linter.EnglishLanguages
+	case CheckBrokenLines:
+		fixableIssue.GetSuggestions = potentiallyfixableissue.GetPotentiallyBrokenLines
+		fixableIssue.Languages = linter.LatinScriptLanguages
+	case CheckSingleQuotes:
+		fixableIssue.GetSuggestions = potentiallyfixableissue.GetPotentialIncorrectSingleQuotes
+		fixableIssue.Languages = linter.EnglishLanguages
+	case CheckSectionBreaks:
+		fixableIssue.GetSuggestions = func(text string) (map[string]string, error) {
+			return potentiallyfixableissue.GetPotentialSectionBreaks(text, getSectionBreakIndicator())
+		}
+		fixableIssue.UpdateAllInstances = true
+		fixableIssue.AddCssSectionBreakIfMissing = true
+	case CheckPageBreaks:
+		fixableIssue.GetSuggestions = potentiallyfixableissue.GetPotentialPageBreaks
+		fixableIssue.UpdateAllInstances = true
+		fixableIssue.AddCssPageBreakIfMissing = true
+	case CheckOxfordCommas:
+		fixableIssue.GetSuggestions = potentiallyfixableissue.GetPotentialMissingOxfordCommas
+		fixableIssue.Languages = linter.EnglishLanguages
+	case CheckLackingSubordinateClause:
+		fixableIssue.GetSuggestions = potentiallyfixableissue.GetPotentiallyLackingSubordinateClauseInstances
+		fixableIssue.Languages = linter.EnglishLanguages
+	case CheckThoughts:
+		fixableIssue.GetSuggestions = potentiallyfixableissue.GetPotentialThoughtInstances
+		fixableIssue.Languages = linter.LatinScriptLanguages
+	case CheckDialoguePunctuation:
+		fixableIssue.GetSuggestions = potentiallyfixableissue.GetPotentialDialoguePunctuationIssues
+		fixableIssue.Languages = linter.EnglishLanguages
+	case CheckNameConsistency:
+		fixableIssue.GetSuggestions = nameVariants.GetPotentialNameInconsistencies
+		fixableIssue.Languages = linter.LatinScriptLanguages
+	}
+
+	return fixableIssue
+}
@@ -0,0 +1,82 @@
+//go:build unit
+
+package epub_test
+
+import (
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/pkg/epub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	var path = createTestEpub(t, getTestEpubFiles(`<html lang="en">
+<p>I like apples, pears and oranges.</p>
+</html>`, `<html lang="ja">
+<p>I like apples, pears and oranges.</p>
+</html>`))
+
+	issues, err := epub.Lint(path, epub.LintOptions{
+		Checks: []epub.Check{epub.CheckOxfordCommas},
+	})
+	require.NoError(t, err)
+
+	// the check only applies to English so the Japanese file is skipped
+	assert.Equal(t, []epub.Issue{
+		{
+			Check:     epub.CheckOxfordCommas,
+			File:      "OEBPS/Text/chapter1.xhtml",
+			Original:  "<p>I like apples, pears and oranges.</p>",
+			Suggested: "<p>I like apples, pears, and oranges.</p>",
+		},
+	}, issues)
+
+	_, err = epub.Lint(path, epub.LintOptions{
+		Checks: []epub.Check{"missing-check"},
+	})
+	assert.Error(t, err)
+}
+
+func TestLintFilesWithNameSourceFiles(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chapter1 = epub.ContentFile{
+			Path: "OEBPS/Text/chapter1.xhtml",
+			Text: `<html lang="en">
+<p>Ryuu walked into the room.</p>
+<p>"Good morning," said Ryuu.</p>
+</html>`,
+		}
+		chapter2 = epub.ContentFile{
+			Path: "OEBPS/Text/chapter2.xhtml",
+			Text: `<html lang="en">
+<p>Later that day, Ryu went home.</p>
+</html>`,
+		}
+		options = epub.LintOptions{
+			Checks: []epub.Check{epub.CheckNameConsistency},
+		}
+	)
+
+	issues, err := epub.LintFiles([]epub.ContentFile{chapter2}, options)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	// only the second chapter is linted, but the names from the first chapter are used
+	options.NameSourceFiles = []epub.ContentFile{chapter1, chapter2}
+
+	issues, err = epub.LintFiles([]epub.ContentFile{chapter2}, options)
+	require.NoError(t, err)
+	assert.Equal(t, []epub.Issue{
+		{
+			Check:     epub.CheckNameConsistency,
+			File:      "OEBPS/Text/chapter2.xhtml",
+			Original:  "<p>Later that day, Ryu went home.</p>",
+			Suggested: "<p>Later that day, Ryuu went home.</p>",
+		},
+	}, issues)
+}
@@ -0,0 +1,285 @@
+package epub
+
+import (
+	"archive/zip"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	epubhandler "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/epub-handler"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/images"
+	langdetect "github.com/pjkaufman/go-go-gadgets/epub-lint/internal/lang-detect"
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/internal/linter"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+)
+
+// AutoLanguage is the language to use to have the language of the book and each of its content files detected from their text
+const AutoLanguage = "auto"
+
+type OptimizeOptions struct {
+	// Language is the language to set on content files that do not have one or AutoLanguage to detect it
+	Language string
+	// CompressImages is whether or not to compress the images in the epub
+	CompressImages bool
+	// RemovableFileExts are the extensions of files to remove when they are not in the manifest (i.e. ".txt")
+	RemovableFileExts []string
+}
+
+type OptimizeResult struct {
+	// Language is the detected language of the book which is only set when the language is AutoLanguage and it could be detected
+	Language string
+	// RemovedFiles are the files that were removed from the epub since they were not in the manifest
+	RemovedFiles []string
+	// Changes describe the changes that were made beyond the usual linting (i.e. converting a file to utf-8)
+	Changes []string
+	// Warnings describe possible problems that were found but left as is (i.e. a declared language not matching the text)
+	Warnings []string
+}
+
+// Optimize lints the content files of the epub making sure they are utf-8 and have a language set, optionally compresses its images,
+// and removes files that are not in the manifest. The epub is rewritten one file at a time with any file that does not need to change
+// copied over as is so the memory used stays proportional to the largest file in the epub.
+func Optimize(path string, options OptimizeOptions) (OptimizeResult, error) {
+	var result OptimizeResult
+	err := epubhandler.StreamUpdateEpub(path, func(zipFiles map[string]*zip.File, epubInfo epubhandler.EpubInfo, opfFolder string) (epubhandler.StreamOperation, error) {
+		var operation epubhandler.StreamOperation
+		err := epubhandler.ValidateFilesExist(opfFolder, zipFiles, epubInfo.HtmlFiles, epubInfo.ImagesFiles, epubInfo.OtherFiles)
+		if err != nil {
+			return operation, err
+		}
+
+		var (
+			manifestFiles = make(map[string]struct{}, len(epubInfo.HtmlFiles)+len(epubInfo.ImagesFiles)+len(epubInfo.CssFiles)+len(epubInfo.OtherFiles))
+			htmlFiles     = epubhandler.GetFilePaths(opfFolder, epubInfo.HtmlFiles)
+			imageFiles    = epubhandler.GetFilePaths(opfFolder, epubInfo.ImagesFiles)
+			// the css and ncx files are made utf-8 as well
+			textFiles = epubhandler.GetFilePaths(opfFolder, epubInfo.CssFiles)
+		)
+		if epubInfo.NcxFile != "" {
+			textFiles[epubhandler.GetFilePath(opfFolder, epubInfo.NcxFile)] = struct{}{}
+		}
+
+		for _, files := range []map[string]struct{}{htmlFiles, imageFiles, textFiles, epubhandler.GetFilePaths(opfFolder, epubInfo.OtherFiles)} {
+			maps.Copy(manifestFiles, files)
+		}
+
+		var bookLang = options.Language
+		if options.Language == AutoLanguage {
+			bookLang, err = detectBookLanguage(zipFiles, htmlFiles)
+			if err != nil {
+				return operation, err
+			}
+
+			if bookLang == "" {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("The language of %q could not be detected", path))
+			}
+
+			result.Language = bookLang
+		}
+
+		// handle the files that are present in the epub, but not present in the actual manifest
+		if len(options.RemovableFileExts) != 0 {
+			result.RemovedFiles = epubhandler.RemoveUnusedFiles(nil, zipFiles, manifestFiles, options.RemovableFileExts, false)
+		}
+
+		operation.RewriteFile = func(zipFile *zip.File, w *zip.Writer) (bool, error) {
+			var filePath = zipFile.Name
+			if slices.Contains(result.RemovedFiles, filePath) {
+				return true, nil
+			}
+
+			if _, ok := htmlFiles[filePath]; ok {
+				data, err := filehandler.ReadInZipFileBytes(zipFile)
+				if err != nil {
+					return false, err
+				}
+
+				fileText, err := transcodeToUtf8(filePath, data, &result)
+				if err != nil {
+					return false, err
+				}
+
+				var fileLang = bookLang
+				if options.Language == AutoLanguage {
+					fileLang = detectFileLanguage(filePath, fileText, bookLang, &result)
+				}
+
+				var newText = linter.EnsureEncodingIsPresent(fileText)
+				newText = linter.ApplyToLanguage(newText, fileLang, linter.LatinScriptLanguages, linter.CommonStringReplace)
+
+				if fileLang != "" {
+					newText = linter.EnsureLanguageIsSet(newText, fileLang)
+				}
+
+				return writeIfChanged(w, filePath, data, newText)
+			}
+
+			if _, ok := textFiles[filePath]; ok {
+				data, err := filehandler.ReadInZipFileBytes(zipFile)
+				if err != nil {
+					return false, err
+				}
+
+				fileText, err := transcodeToUtf8(filePath, data, &result)
+				if err != nil {
+					return false, err
+				}
+
+				return writeIfChanged(w, filePath, data, fileText)
+			}
+
+			if _, ok := imageFiles[filePath]; ok && options.CompressImages {
+				data, err := filehandler.ReadInZipFileBytes(zipFile)
+				if err != nil {
+					return false, err
+				}
+
+				newData, err := images.CompressImage(filePath, data)
+				if err != nil {
+					return false, err
+				}
+
+				return true, filehandler.WriteZipCompressedBytes(w, filePath, newData)
+			}
+
+			if filePath == epubInfo.OpfFile && options.Language == AutoLanguage && bookLang != "" {
+				return updateOpfLanguage(zipFile, w, bookLang, &result)
+			}
+
+			return false, nil
+		}
+
+		return operation, nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to update epub %q: %w", path, err)
+	}
+
+	return result, nil
+}
+
+// detectBookLanguage detects the language of the book from the text of its content files stopping once there
+// are enough letters for detection since the rest of the text would not be used
+func detectBookLanguage(zipFiles map[string]*zip.File, htmlFiles map[string]struct{}) (string, error) {
+	var (
+		text    strings.Builder
+		letters int
+	)
+	for _, filePath := range slices.Sorted(maps.Keys(htmlFiles)) {
+		if letters > langdetect.MaxLetters {
+			break
+		}
+
+		data, err := filehandler.ReadInZipFileBytes(zipFiles[filePath])
+		if err != nil {
+			return "", err
+		}
+
+		fileText, _, err := linter.DecodeToUtf8(data)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert %q to utf-8: %w", filePath, err)
+		}
+
+		var htmlText = langdetect.GetHtmlText(fileText)
+		text.WriteString(htmlText)
+		text.WriteString(" ")
+
+		for _, r := range htmlText {
+			if unicode.IsLetter(r) {
+				letters++
+			}
+		}
+	}
+
+	return langdetect.Detect(text.String()), nil
+}
+
+// detectFileLanguage detects the language of the content file falling back to the language of the book when it cannot be detected.
+// The language the file declares is kept when it has one, but a warning is given when it does not match the detected one.
+func detectFileLanguage(filePath, fileText, bookLang string, result *OptimizeResult) string {
+	var (
+		declaredLang = linter.GetLanguage(fileText)
+		detectedLang = langdetect.DetectHtml(fileText)
+	)
+	if declaredLang != "" {
+		if detectedLang != "" && !linter.LanguageMatches(declaredLang, []string{detectedLang}) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%q is declared as %q, but its text looks like %q", filePath, declaredLang, detectedLang))
+		}
+
+		return declaredLang
+	}
+
+	if detectedLang == "" {
+		return bookLang
+	}
+
+	return detectedLang
+}
+
+// updateOpfLanguage sets the dc:language of the opf file to the detected language when it is missing
+// and warns when the declared language does not match the detected one returning whether the opf file was written
+func updateOpfLanguage(zipFile *zip.File, w *zip.Writer, detectedLang string, result *OptimizeResult) (bool, error) {
+	var opfFile = zipFile.Name
+	opfContents, err := filehandler.ReadInZipFileContents(zipFile)
+	if err != nil {
+		return false, err
+	}
+
+	var declaredLang = linter.GetOpfLanguage(opfContents)
+	if declaredLang != "" {
+		if !linter.LanguageMatches(declaredLang, []string{detectedLang}) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%q is declared as %q, but the text of the book looks like %q", opfFile, declaredLang, detectedLang))
+		}
+
+		return false, nil
+	}
+
+	var newOpfContents = linter.EnsureOpfLanguageIsSet(opfContents, detectedLang)
+	if newOpfContents == opfContents {
+		return false, nil
+	}
+
+	err = filehandler.WriteZipCompressedString(w, opfFile, newOpfContents)
+	if err != nil {
+		return false, err
+	}
+
+	result.Changes = append(result.Changes, fmt.Sprintf("Set the language of %q to %q", opfFile, detectedLang))
+
+	return true, nil
+}
+
+// writeIfChanged writes the new text to the epub when it is different from the original contents of the file returning
+// whether it was written so files that stay the same get copied over as is instead of being recompressed
+func writeIfChanged(w *zip.Writer, filePath string, original []byte, newText string) (bool, error) {
+	if newText == string(original) {
+		return false, nil
+	}
+
+	return true, filehandler.WriteZipCompressedString(w, filePath, newText)
+}
+
+// transcodeToUtf8 converts the file contents to utf-8 from the encoding they are actually in, repairs any mojibake in them
+// when there are clear signs of it, and then sets the encoding declarations to utf-8 to match
+func transcodeToUtf8(filePath string, data []byte, result *OptimizeResult) (string, error) {
+	text, encoding, err := linter.DecodeToUtf8(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert %q to utf-8: %w", filePath, err)
+	}
+
+	if encoding != linter.Utf8Encoding {
+		result.Changes = append(result.Changes, fmt.Sprintf("Converted %q from %s to utf-8", filePath, encoding))
+	}
+
+	if linter.HasMojibake(text) {
+		var repairs int
+		text, repairs = linter.RepairMojibake(text)
+		if repairs != 0 {
+			result.Changes = append(result.Changes, fmt.Sprintf("Repaired %d mojibake sequence(s) in %q", repairs, filePath))
+		}
+	}
+
+	return linter.SetUtf8EncodingDeclarations(text), nil
+}
@@ -0,0 +1,117 @@
+//go:build unit
+
+package epub_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pjkaufman/go-go-gadgets/epub-lint/pkg/epub"
+	filehandler "github.com/pjkaufman/go-go-gadgets/pkg/file-handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimize(t *testing.T) {
+	t.Parallel()
+
+	var files = getTestEpubFiles(`<html><body><p>Chapter 1</p></body></html>`, `<html lang="fr"><body><p>Chapter 2</p></body></html>`)
+	files["OEBPS/extra.txt"] = "not in the manifest"
+
+	var path = createTestEpub(t, files)
+	result, err := epub.Optimize(path, epub.OptimizeOptions{
+		Language:          "en",
+		RemovableFileExts: []string{".txt"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OEBPS/extra.txt"}, result.RemovedFiles)
+	assert.Empty(t, result.Warnings)
+
+	e, err := epub.Open(path)
+	require.NoError(t, err)
+	defer e.Close()
+
+	assert.False(t, e.HasFile("OEBPS/extra.txt"))
+
+	contents, err := e.ReadFile("OEBPS/Text/chapter1.xhtml")
+	require.NoError(t, err)
+	assert.Contains(t, contents, `lang="en"`)
+
+	contents, err = e.ReadFile("OEBPS/Text/chapter2.xhtml")
+	require.NoError(t, err)
+	assert.Contains(t, contents, `lang="fr"`)
+}
+
+func TestOptimizeOnlyRepairsFilesWithClearMojibake(t *testing.T) {
+	t.Parallel()
+
+	var path = createTestEpub(t, getTestEpubFiles(`<html lang="en"><body><p>cafÃ© â€œquoteâ€</p></body></html>`, `<html lang="en"><body><p>TÃ­a</p></body></html>`))
+	result, err := epub.Optimize(path, epub.OptimizeOptions{
+		Language: "en",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Changes, `Repaired 3 mojibake sequence(s) in "OEBPS/Text/chapter1.xhtml"`)
+
+	e, err := epub.Open(path)
+	require.NoError(t, err)
+	defer e.Close()
+
+	contents, err := e.ReadFile("OEBPS/Text/chapter1.xhtml")
+	require.NoError(t, err)
+	assert.Contains(t, contents, `café`)
+
+	contents, err = e.ReadFile("OEBPS/Text/chapter2.xhtml")
+	require.NoError(t, err)
+	assert.Contains(t, contents, `TÃ­a`)
+}
+
+func TestOptimizeCopiesUnchangedFilesAsIs(t *testing.T) {
+	t.Parallel()
+
+	var path = createTestEpub(t, getTestEpubFiles(`<html><body><p>Chapter 1</p></body></html>`, `<html><body><p>Chapter 2</p></body></html>`))
+	_, err := epub.Optimize(path, epub.OptimizeOptions{
+		Language: "en",
+	})
+	require.NoError(t, err)
+
+	e, err := epub.Open(path)
+	require.NoError(t, err)
+
+	optimizedChapter, err := e.ReadFile("OEBPS/Text/chapter1.xhtml")
+	require.NoError(t, err)
+	require.NoError(t, e.Close())
+
+	// the files are stored without compression so that any file that gets rewritten ends up compressed
+	var files = getTestEpubFiles(optimizedChapter, `<html><body><p>Chapter 2</p></body></html>`)
+	path = filepath.Join(t.TempDir(), "stored.epub")
+	epubFile, err := os.Create(path)
+	require.NoError(t, err)
+
+	var w = zip.NewWriter(epubFile)
+	require.NoError(t, filehandler.WriteZipUncompressedString(w, "mimetype", "application/epub+zip"))
+	for _, name := range []string{"META-INF/container.xml", "OEBPS/content.opf", "OEBPS/Text/chapter1.xhtml", "OEBPS/Text/chapter2.xhtml"} {
+		require.NoError(t, filehandler.WriteZipUncompressedString(w, name, files[name]))
+	}
+
+	require.NoError(t, w.Close())
+	require.NoError(t, epubFile.Close())
+
+	_, err = epub.Optimize(path, epub.OptimizeOptions{
+		Language: "en",
+	})
+	require.NoError(t, err)
+
+	r, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var methods = make(map[string]uint16)
+	for _, zipFile := range r.File {
+		methods[zipFile.Name] = zipFile.Method
+	}
+
+	assert.Equal(t, zip.Store, methods["OEBPS/Text/chapter1.xhtml"], "expected the unchanged file to be copied over as is")
+	assert.Equal(t, zip.Deflate, methods["OEBPS/Text/chapter2.xhtml"], "expected the changed file to be rewritten")
+}
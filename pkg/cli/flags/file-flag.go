@@ -42,7 +42,7 @@ func (s FileFlag) Validate() error {
 		}
 	}
 
-	if len(s.Extensions) != 0 && s.Value != nil {
+	if len(s.Extensions) != 0 && s.Value != nil && strings.TrimSpace(*s.Value) != "" {
 		var ext = strings.TrimPrefix(filepath.Ext(strings.TrimSpace(*s.Value)), ".")
 		if !slices.Contains(s.Extensions, ext) {
 			return fmt.Errorf("%s has extension %q, must have one of the following extensions: %s", s.Name, ext, strings.Join(s.Extensions, ", "))
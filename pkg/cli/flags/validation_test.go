@@ -80,6 +80,9 @@ var fileFlagValidationTestCases = map[string]validationFlagTestCase{
 	"A file flag that is not required and is whitespace should not return an error": {
 		flag: flags.NewFileFlag(false, false, createStringPointer("  "), "test flag", "f", "", "", nil, false),
 	},
+	"A file flag that is not required, is whitespace, and has a required extension should not return an error": {
+		flag: flags.NewFileFlag(false, false, createStringPointer("  "), "test flag", "f", "", "", []string{"txt"}, true),
+	},
 	"A file flag that is not required, is not whitespace, and has a value should not return an error": {
 		flag: flags.NewFileFlag(false, false, createStringPointer("file.txt"), "test flag", "f", "", "", nil, false),
 	},
@@ -10,6 +10,8 @@ import (
 	"strings"
 )
 
+const maxInitialZipFileBufferSize = 64 << 20
+
 func GetFilesFromZip(src string) (*zip.ReadCloser, map[string]*zip.File, error) {
 	r, err := zip.OpenReader(src)
 	if err != nil {
@@ -46,6 +48,11 @@ func WriteZipCompressedFile(w *zip.Writer, zipFile *zip.File) error {
 	return compressedWriteToZip(w, file, zipFile.Name)
 }
 
+// CopyZipFile copies the file into the zip as it is stored in its source zip without decompressing and recompressing it
+func CopyZipFile(w *zip.Writer, zipFile *zip.File) error {
+	return w.Copy(zipFile)
+}
+
 func WriteZipCompressedBytes(w *zip.Writer, filename string, data []byte) error {
 	return compressedWriteToZip(w, bytes.NewReader(data), filename)
 }
@@ -67,7 +74,7 @@ func ReadInZipFileContents(zipFile *zip.File) (string, error) {
 	}
 	defer TryClose(zipFile.Name, file)
 
-	var fileBytes = &bytes.Buffer{}
+	var fileBytes = newZipFileBuffer(zipFile)
 	_, err = io.Copy(fileBytes, file)
 	if err != nil {
 		return "", fmt.Errorf(`could not read in zip file contents for %q: %w`, zipFile.Name, err)
@@ -83,7 +90,7 @@ func ReadInZipFileBytes(zipFile *zip.File) ([]byte, error) {
 	}
 	defer TryClose(zipFile.Name, file)
 
-	var fileBytes = &bytes.Buffer{}
+	var fileBytes = newZipFileBuffer(zipFile)
 	_, err = io.Copy(fileBytes, file)
 	if err != nil {
 		return nil, fmt.Errorf(`could not read in zip file bytes for %q: %w`, zipFile.Name, err)
@@ -92,6 +99,13 @@ func ReadInZipFileBytes(zipFile *zip.File) ([]byte, error) {
 	return fileBytes.Bytes(), nil
 }
 
+// newZipFileBuffer creates a buffer that is already big enough for the file's contents so reading it in
+// does not hold onto multiple copies while the buffer grows. The size in the zip is only trusted up to a limit
+// since it can be wrong.
+func newZipFileBuffer(zipFile *zip.File) *bytes.Buffer {
+	return bytes.NewBuffer(make([]byte, 0, min(zipFile.UncompressedSize64, maxInitialZipFileBufferSize)))
+}
+
 func compressedWriteToZip(w *zip.Writer, reader io.Reader, filename string) error {
 	f, err := w.Create(filename)
 	if err != nil {
@@ -0,0 +1,162 @@
+package stringdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+type DiffHunk struct {
+	OriginalStart, OriginalLines int
+	UpdatedStart, UpdatedLines   int
+	// Lines are the lines of the hunk prefixed with " " for unchanged lines, "-" for removed lines, and "+" for added lines
+	Lines []string
+}
+
+func (h DiffHunk) Header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OriginalStart, h.OriginalLines, h.UpdatedStart, h.UpdatedLines)
+}
+
+// GetDiffHunks gets the line based differences between the original and updated text grouped into hunks
+// that include up to the specified number of unchanged lines before and after each change.
+func GetDiffHunks(original, updated string, contextLines int) []DiffHunk {
+	if original == updated {
+		return nil
+	}
+
+	var (
+		lines        = getLineDiff(original, updated)
+		hunks        []DiffHunk
+		current      *DiffHunk
+		originalLine = 1
+		updatedLine  = 1
+		// the index of the last changed line so that we know when the current hunk has enough trailing context
+		lastChange = -1
+	)
+	for i, line := range lines {
+		var isChange = strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+")
+		if isChange {
+			if current == nil {
+				var start = max(i-contextLines, 0)
+				if len(hunks) != 0 && lastChange+contextLines+1 >= start {
+					// the leading context overlaps with the previous hunk, so they should be combined
+					var previous = hunks[len(hunks)-1]
+					current = &previous
+					hunks = hunks[:len(hunks)-1]
+					start = lastChange + contextLines + 1
+				} else {
+					current = &DiffHunk{
+						OriginalStart: originalLine - (i - start),
+						UpdatedStart:  updatedLine - (i - start),
+					}
+				}
+
+				for _, contextLine := range lines[start:i] {
+					current.addLine(contextLine)
+				}
+			}
+
+			current.addLine(line)
+			lastChange = i
+		} else if current != nil {
+			if i-lastChange <= contextLines {
+				current.addLine(line)
+			}
+
+			if i-lastChange >= contextLines {
+				hunks = append(hunks, *current)
+				current = nil
+			}
+		}
+
+		switch {
+		case strings.HasPrefix(line, "-"):
+			originalLine++
+		case strings.HasPrefix(line, "+"):
+			updatedLine++
+		default:
+			originalLine++
+			updatedLine++
+		}
+	}
+
+	if current != nil {
+		hunks = append(hunks, *current)
+	}
+
+	return hunks
+}
+
+// GetUnifiedDiff gets the unified diff of the original and updated text using the provided names
+// for the original and updated file headers
+func GetUnifiedDiff(originalName, updatedName, original, updated string, contextLines int) string {
+	var hunks = GetDiffHunks(original, updated, contextLines)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var unifiedDiff strings.Builder
+	fmt.Fprintf(&unifiedDiff, "--- %s\n+++ %s\n", originalName, updatedName)
+	for _, hunk := range hunks {
+		unifiedDiff.WriteString(hunk.Header())
+		unifiedDiff.WriteString("\n")
+
+		for _, line := range hunk.Lines {
+			unifiedDiff.WriteString(line)
+			unifiedDiff.WriteString("\n")
+		}
+	}
+
+	return unifiedDiff.String()
+}
+
+// getLineDiff gets the lines of the original and updated text prefixed with " " for unchanged lines, "-" for removed lines, and "+" for added lines
+func getLineDiff(original, updated string) []string {
+	// make sure that the last line is compared the same way as the other lines
+	if !strings.HasSuffix(original, "\n") {
+		original += "\n"
+	}
+
+	if !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+
+	var (
+		dmp                                    = diffmatchpatch.New()
+		originalChars, updatedChars, lineArray = dmp.DiffLinesToChars(original, updated)
+		diffs                                  = dmp.DiffCharsToLines(dmp.DiffMain(originalChars, updatedChars, false), lineArray)
+		lines                                  []string
+		prefix                                 string
+	)
+	for _, lineDiff := range diffs {
+		switch lineDiff.Type {
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		default:
+			prefix = " "
+		}
+
+		for line := range strings.Lines(lineDiff.Text) {
+			lines = append(lines, prefix+strings.TrimSuffix(line, "\n"))
+		}
+	}
+
+	return lines
+}
+
+func (h *DiffHunk) addLine(line string) {
+	h.Lines = append(h.Lines, line)
+
+	switch {
+	case strings.HasPrefix(line, "-"):
+		h.OriginalLines++
+	case strings.HasPrefix(line, "+"):
+		h.UpdatedLines++
+	default:
+		h.OriginalLines++
+		h.UpdatedLines++
+	}
+}
@@ -0,0 +1,90 @@
+//go:build unit
+
+package stringdiff_test
+
+import (
+	"testing"
+
+	stringdiff "github.com/pjkaufman/go-go-gadgets/pkg/string-diff"
+	"github.com/stretchr/testify/assert"
+)
+
+type unifiedDiffTestCase struct {
+	inputOriginal  string
+	inputNew       string
+	expectedOutput string
+}
+
+var unifiedDiffTestCases = map[string]unifiedDiffTestCase{
+	"identical text should have no diff": {
+		inputOriginal:  "line 1\nline 2",
+		inputNew:       "line 1\nline 2",
+		expectedOutput: "",
+	},
+	"a single changed line should include the surrounding context": {
+		inputOriginal: "line 1\nline 2\nline 3\nline 4\nline 5",
+		inputNew:      "line 1\nline 2\nline three\nline 4\nline 5",
+		expectedOutput: `--- a.xhtml
++++ b.xhtml
+@@ -2,3 +2,3 @@
+ line 2
+-line 3
++line three
+ line 4
+`,
+	},
+	"changes that are far apart should be in separate hunks": {
+		inputOriginal: "line 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7",
+		inputNew:      "line one\nline 2\nline 3\nline 4\nline 5\nline 6\nline seven",
+		expectedOutput: `--- a.xhtml
++++ b.xhtml
+@@ -1,2 +1,2 @@
+-line 1
++line one
+ line 2
+@@ -6,2 +6,2 @@
+ line 6
+-line 7
++line seven
+`,
+	},
+	"changes with overlapping context should be combined into a single hunk": {
+		inputOriginal: "line 1\nline 2\nline 3\nline 4",
+		inputNew:      "line one\nline 2\nline 3\nline four",
+		expectedOutput: `--- a.xhtml
++++ b.xhtml
+@@ -1,4 +1,4 @@
+-line 1
++line one
+ line 2
+ line 3
+-line 4
++line four
+`,
+	},
+	"added and removed lines should have the correct line counts": {
+		inputOriginal: "line 1\nline 2\nline 3",
+		inputNew:      "line 1\nline 1.5\nline 2",
+		expectedOutput: `--- a.xhtml
++++ b.xhtml
+@@ -1,3 +1,3 @@
+ line 1
++line 1.5
+ line 2
+-line 3
+`,
+	},
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	for name, args := range unifiedDiffTestCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := stringdiff.GetUnifiedDiff("a.xhtml", "b.xhtml", args.inputOriginal, args.inputNew, 1)
+			assert.Equal(t, args.expectedOutput, actual)
+		})
+	}
+}